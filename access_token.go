@@ -0,0 +1,66 @@
+package activitypub
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/sfomuseum/go-activitypub/id"
+)
+
+// AccessToken is a per-account API token used to authenticate client-to-server (C2S) requests, for example
+// activities posted to an account's outbox. Only a SHA-256 hash of the token is stored.
+type AccessToken struct {
+	// The unique ID of the access token.
+	Id int64 `json:"id"`
+	// The unique ID of the account the access token authenticates.
+	AccountId int64 `json:"account_id"`
+	// The (hex-encoded) SHA-256 hash of the token.
+	Token string `json:"token"`
+	// An optional label describing the client (or person) using the token.
+	Label string `json:"label,omitempty"`
+	// The Unix timestamp when the access token was created.
+	Created int64 `json:"created"`
+}
+
+// NewAccessToken returns a new `AccessToken` instance for 'acct' and the plain-text token it was derived from. The
+// plain-text token is not stored anywhere so it is the responsibility of the caller to hand it to the client.
+func NewAccessToken(ctx context.Context, acct *Account) (*AccessToken, string, error) {
+
+	token_id, err := id.NewId()
+
+	if err != nil {
+		return nil, "", fmt.Errorf("Failed to create new access token ID, %w", err)
+	}
+
+	b := make([]byte, 32)
+
+	_, err = rand.Read(b)
+
+	if err != nil {
+		return nil, "", fmt.Errorf("Failed to generate access token, %w", err)
+	}
+
+	token := hex.EncodeToString(b)
+
+	now := time.Now()
+	ts := now.Unix()
+
+	t := &AccessToken{
+		Id:        token_id,
+		AccountId: acct.Id,
+		Token:     HashAccessToken(token),
+		Created:   ts,
+	}
+
+	return t, token, nil
+}
+
+// HashAccessToken returns the (hex-encoded) SHA-256 hash of 'token'.
+func HashAccessToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
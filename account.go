@@ -73,7 +73,7 @@ type Account struct {
 	PublicKeyURI string `json:"public_key_uri"`
 	// PublicKeyURI is a valid `gocloud.dev/runtimevar` referencing the PEM-encoded private key for the account.
 	PrivateKeyURI string `json:"private_key_uri"`
-	// ManuallyApproveFollowers is a boolean flag signaling that follower requests need to be manually approved. Note: There are currently no tools or interfaces for handling those approvals.
+	// ManuallyApproveFollowers is a boolean flag signaling that follower requests need to be manually approved. Pending requests are stored in a `FollowRequestsDatabase` and can be approved (or rejected) using the `approve-follow-request` tool.
 	ManuallyApproveFollowers bool `json:"manually_approve_followers"`
 	// Discoverable is a boolean flag signaling that the account is discoverable.
 	Discoverable bool `json:"discoverable"`
@@ -111,6 +111,10 @@ func (a *Account) InboxURL(ctx context.Context, uris_table *uris.URIs) *url.URL
 	return uris.NewURL(uris_table, inbox_path)
 }
 
+func (a *Account) SharedInboxURL(ctx context.Context, uris_table *uris.URIs) *url.URL {
+	return uris.NewURL(uris_table, uris_table.SharedInbox)
+}
+
 func (a *Account) ProfileURL(ctx context.Context, uris_table *uris.URIs) *url.URL {
 
 	account_path := uris.AssignResource(uris_table.Account, fmt.Sprintf("@%s", a.Name))
@@ -124,6 +128,13 @@ func (a *Account) PostURL(ctx context.Context, uris_table *uris.URIs, post *Post
 	return uris.NewURL(uris_table, post_path)
 }
 
+func (a *Account) MediaURL(ctx context.Context, uris_table *uris.URIs, m *Media) *url.URL {
+
+	account_path := uris.AssignResource(uris_table.Media, fmt.Sprintf("@%s", a.Name))
+	media_path := uris.AssignId(account_path, strconv.FormatInt(m.Id, 10))
+	return uris.NewURL(uris_table, media_path)
+}
+
 func (a *Account) WebfingerURL(ctx context.Context, uris_table *uris.URIs) *url.URL {
 
 	address := a.Address(uris_table.Hostname)
@@ -189,6 +200,7 @@ func (a *Account) ProfileResource(ctx context.Context, uris_table *uris.URIs) (*
 
 	inbox_url := a.InboxURL(ctx, uris_table)
 	outbox_url := a.OutboxURL(ctx, uris_table)
+	shared_inbox_url := a.SharedInboxURL(ctx, uris_table)
 
 	icon_path := uris.AssignResource(uris_table.Icon, a.Name)
 	icon_url := uris.NewURL(uris_table, icon_path)
@@ -224,27 +236,29 @@ func (a *Account) ProfileResource(ctx context.Context, uris_table *uris.URIs) (*
 
 	// read from prefs or something...
 	discoverable := true
-	// manually_approve := false
 
 	now := time.Now()
 
 	pr := &ap.Actor{
-		Context:           context,
-		Id:                account_url.String(),
-		Type:              a.AccountType.String(),
-		Name:              a.DisplayName, // name is display name
-		PreferredUsername: a.Name,        // preferred username is account (user)name
-		Summary:           a.Blurb,
-		URL:               a.URL,
-		Followers:         followers_url.String(),
-		Following:         following_url.String(),
-		// ManuallyApprovesFollowers: manually_approve,
-		Discoverable: discoverable,
-		Inbox:        inbox_url.String(),
-		Outbox:       outbox_url.String(),
-		PublicKey:    pub_key,
-		Icon:         icon,
-		Published:    now.Format(time.RFC3339),
+		Context:                   context,
+		Id:                        account_url.String(),
+		Type:                      a.AccountType.String(),
+		Name:                      a.DisplayName, // name is display name
+		PreferredUsername:         a.Name,        // preferred username is account (user)name
+		Summary:                   a.Blurb,
+		URL:                       a.URL,
+		Followers:                 followers_url.String(),
+		Following:                 following_url.String(),
+		ManuallyApprovesFollowers: a.ManuallyApproveFollowers,
+		Discoverable:              discoverable,
+		Inbox:                     inbox_url.String(),
+		Outbox:                    outbox_url.String(),
+		PublicKey:                 pub_key,
+		Icon:                      icon,
+		Published:                 now.Format(time.RFC3339),
+		Endpoints: &ap.Endpoints{
+			SharedInbox: shared_inbox_url.String(),
+		},
 	}
 
 	return pr, nil
@@ -15,6 +15,12 @@ const (
 	UndefinedActivityType ActivityType = iota
 	PostActivityType
 	BoostActivityType
+	LikeActivityType
+	FollowActivityType
+	// UpdateActivityType is an update to (an edit of) a post. Its activity type ID is the ID of the post revision created by the edit.
+	UpdateActivityType
+	// DeleteActivityType is the deletion of a post. Its activity type ID is the ID of the tombstone created for the deleted post.
+	DeleteActivityType
 )
 
 type ActivityType int
@@ -0,0 +1,72 @@
+package activitypub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sfomuseum/go-activitypub/ap"
+)
+
+// Actor is a locally cached copy of a remote ActivityPub actor (profile) document.
+type Actor struct {
+	// The unique URI of the actor.
+	Id string `json:"id"`
+	// The "@name@host" address of the actor.
+	Address string `json:"address"`
+	// The JSON-encoded body of the actor document.
+	Body string `json:"body"`
+	// The Unix timestamp when the actor was first cached.
+	Created int64 `json:"created"`
+	// The Unix timestamp when the actor document was last retrieved.
+	LastModified int64 `json:"lastmodified"`
+}
+
+// NewActor returns a new `Actor` instance for 'ap_actor' whose address is 'address'.
+func NewActor(ctx context.Context, address string, ap_actor *ap.Actor) (*Actor, error) {
+
+	enc_actor, err := json.Marshal(ap_actor)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal actor, %w", err)
+	}
+
+	now := time.Now()
+	ts := now.Unix()
+
+	a := &Actor{
+		Id:           ap_actor.Id,
+		Address:      address,
+		Body:         string(enc_actor),
+		Created:      ts,
+		LastModified: ts,
+	}
+
+	return a, nil
+}
+
+// UnmarshalActor returns the unmarshaled `*ap.Actor` that is encapsulated by 'a'.
+func (a *Actor) UnmarshalActor() (*ap.Actor, error) {
+
+	var ap_actor *ap.Actor
+	err := json.Unmarshal([]byte(a.Body), &ap_actor)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal actor, %w", err)
+	}
+
+	return ap_actor, nil
+}
+
+// IsExpired returns a boolean value indicating whether 'a' was last retrieved more than 'ttl' ago. A 'ttl' value
+// of zero (or less) means that 'a' never expires.
+func (a *Actor) IsExpired(ttl time.Duration) bool {
+
+	if ttl <= 0 {
+		return false
+	}
+
+	last_mod := time.Unix(a.LastModified, 0)
+	return time.Since(last_mod) > ttl
+}
@@ -0,0 +1,31 @@
+package activitypub
+
+import (
+	"testing"
+	"time"
+)
+
+func TestActorIsExpired(t *testing.T) {
+
+	now := time.Now()
+
+	a := &Actor{
+		Id:           "https://example.com/users/bob",
+		Address:      "bob@example.com",
+		LastModified: now.Add(-2 * time.Hour).Unix(),
+	}
+
+	tests := map[time.Duration]bool{
+		0:              false,
+		time.Hour:      true,
+		3 * time.Hour:  false,
+		-1 * time.Hour: false,
+	}
+
+	for ttl, expected := range tests {
+
+		if a.IsExpired(ttl) != expected {
+			t.Fatalf("Unexpected expiry for TTL '%v', expected '%t'", ttl, expected)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+// Package actors provides methods for retrieving remote ActivityPub actors using a local cache.
+package actors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/database"
+)
+
+// RetrieveActorOptions defines configuration options for retrieving remote actors.
+type RetrieveActorOptions struct {
+	// ActorsDatabase is the `database.ActorsDatabase` instance used to cache remote actors. If nil then
+	// actors will always be retrieved from their remote hosts.
+	ActorsDatabase database.ActorsDatabase
+	// TTL is the amount of time that a cached actor is considered valid. If zero then cached actors never expire.
+	TTL time.Duration
+	// Insecure is a boolean flag indicating whether remote hosts should be queried using HTTP rather than HTTPS (webfinger lookups only).
+	Insecure bool
+	// Refresh is a boolean flag to force actors to be retrieved from their remote hosts, and the cache updated, regardless of TTL.
+	Refresh bool
+	// AllowGone is a boolean flag indicating that if the remote host reports that an actor has been deleted (HTTP 410 Gone) the cached
+	// copy of that actor, if present, should be returned regardless of TTL or the value of Refresh. This is necessary to verify the
+	// "Delete" activities that actors send (and sign) after their profile has been removed.
+	AllowGone bool
+}
+
+// RetrieveActor returns the `ap.Actor` instance associated with the "@name@host" 'address'. If 'address' is present in the cache and
+// has not expired the cached actor is returned, otherwise the actor is retrieved from its remote host (using webfinger) and cached.
+func RetrieveActor(ctx context.Context, opts *RetrieveActorOptions, address string) (*ap.Actor, error) {
+
+	logger := slog.Default()
+	logger = logger.With("address", address)
+
+	var db_actor *activitypub.Actor
+
+	if opts.ActorsDatabase != nil {
+
+		a, err := opts.ActorsDatabase.GetActorWithAddress(ctx, address)
+
+		if err != nil && err != activitypub.ErrNotFound {
+			return nil, fmt.Errorf("Failed to retrieve cached actor for %s, %w", address, err)
+		}
+
+		db_actor = a
+	}
+
+	ap_actor, err := cachedActor(db_actor, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if ap_actor != nil {
+		logger.Debug("Return cached actor")
+		return ap_actor, nil
+	}
+
+	ap_actor, err = ap.RetrieveActor(ctx, address, opts.Insecure)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve actor for %s, %w", address, err)
+	}
+
+	err = cacheActor(ctx, opts, db_actor, address, ap_actor)
+
+	if err != nil {
+		logger.Warn("Failed to cache actor", "error", err)
+	}
+
+	return ap_actor, nil
+}
+
+// RetrieveActorWithProfileURL returns the `ap.Actor` instance associated with 'profile_url'. If 'profile_url' is present in the cache and
+// has not expired the cached actor is returned, otherwise the actor is retrieved from its remote host and cached. Any fragment in 'profile_url'
+// (for example a public key ID like "https://example.com/users/bob#main-key") is removed before the cache is consulted. Actors whose ID does
+// not match 'profile_url' (see `ap.RetrieveActorWithProfileURL`) are rejected and never cached.
+func RetrieveActorWithProfileURL(ctx context.Context, opts *RetrieveActorOptions, profile_url string) (*ap.Actor, error) {
+
+	u, err := url.Parse(profile_url)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse profile URL, %w", err)
+	}
+
+	u.Fragment = ""
+	actor_id := u.String()
+
+	logger := slog.Default()
+	logger = logger.With("actor id", actor_id)
+
+	var db_actor *activitypub.Actor
+
+	if opts.ActorsDatabase != nil {
+
+		a, err := opts.ActorsDatabase.GetActorWithId(ctx, actor_id)
+
+		if err != nil && err != activitypub.ErrNotFound {
+			return nil, fmt.Errorf("Failed to retrieve cached actor for %s, %w", actor_id, err)
+		}
+
+		db_actor = a
+	}
+
+	ap_actor, err := cachedActor(db_actor, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if ap_actor != nil {
+		logger.Debug("Return cached actor")
+		return ap_actor, nil
+	}
+
+	ap_actor, err = ap.RetrieveActorWithProfileURL(ctx, profile_url)
+
+	if err != nil {
+
+		if errors.Is(err, ap.ErrGone) && opts.AllowGone && db_actor != nil {
+			logger.Debug("Actor is gone, return cached actor")
+			return db_actor.UnmarshalActor()
+		}
+
+		return nil, fmt.Errorf("Failed to retrieve actor for %s, %w", profile_url, err)
+	}
+
+	address := fmt.Sprintf("%s@%s", ap_actor.PreferredUsername, u.Host)
+
+	err = cacheActor(ctx, opts, db_actor, address, ap_actor)
+
+	if err != nil {
+		logger.Warn("Failed to cache actor", "error", err)
+	}
+
+	return ap_actor, nil
+}
+
+// CacheActor adds, or updates, the cached copy of 'ap_actor' whose address is 'address' in 'db'.
+func CacheActor(ctx context.Context, db database.ActorsDatabase, address string, ap_actor *ap.Actor) error {
+
+	db_actor, err := db.GetActorWithId(ctx, ap_actor.Id)
+
+	if err != nil && err != activitypub.ErrNotFound {
+		return fmt.Errorf("Failed to retrieve cached actor for %s, %w", ap_actor.Id, err)
+	}
+
+	opts := &RetrieveActorOptions{
+		ActorsDatabase: db,
+	}
+
+	return cacheActor(ctx, opts, db_actor, address, ap_actor)
+}
+
+// RemoveActor removes the cached copy of the actor whose URI is 'actor_id' from 'db', if present.
+func RemoveActor(ctx context.Context, db database.ActorsDatabase, actor_id string) error {
+
+	db_actor, err := db.GetActorWithId(ctx, actor_id)
+
+	if err != nil {
+
+		if err == activitypub.ErrNotFound {
+			return nil
+		}
+
+		return fmt.Errorf("Failed to retrieve cached actor for %s, %w", actor_id, err)
+	}
+
+	err = db.RemoveActor(ctx, db_actor)
+
+	if err != nil {
+		return fmt.Errorf("Failed to remove cached actor for %s, %w", actor_id, err)
+	}
+
+	return nil
+}
+
+// cachedActor returns the `ap.Actor` instance encapsulated by 'db_actor' if it is not nil, has not expired and 'opts.Refresh' is false.
+// Otherwise it returns nil.
+func cachedActor(db_actor *activitypub.Actor, opts *RetrieveActorOptions) (*ap.Actor, error) {
+
+	if db_actor == nil || opts.Refresh || db_actor.IsExpired(opts.TTL) {
+		return nil, nil
+	}
+
+	ap_actor, err := db_actor.UnmarshalActor()
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal cached actor, %w", err)
+	}
+
+	return ap_actor, nil
+}
+
+// cacheActor adds a new cached copy of 'ap_actor' to 'opts.ActorsDatabase', or updates 'db_actor' if it is not nil.
+func cacheActor(ctx context.Context, opts *RetrieveActorOptions, db_actor *activitypub.Actor, address string, ap_actor *ap.Actor) error {
+
+	if opts.ActorsDatabase == nil {
+		return nil
+	}
+
+	new_actor, err := activitypub.NewActor(ctx, address, ap_actor)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create new actor, %w", err)
+	}
+
+	// Actor URIs are the primary key so if the URI has changed the old record is replaced
+
+	if db_actor != nil && db_actor.Id != new_actor.Id {
+
+		err = opts.ActorsDatabase.RemoveActor(ctx, db_actor)
+
+		if err != nil {
+			return fmt.Errorf("Failed to remove actor, %w", err)
+		}
+
+		db_actor = nil
+	}
+
+	if db_actor == nil {
+
+		err = opts.ActorsDatabase.AddActor(ctx, new_actor)
+
+		if err != nil {
+			return fmt.Errorf("Failed to add actor, %w", err)
+		}
+
+		return nil
+	}
+
+	new_actor.Created = db_actor.Created
+
+	err = opts.ActorsDatabase.UpdateActor(ctx, new_actor)
+
+	if err != nil {
+		return fmt.Errorf("Failed to update actor, %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package actors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/database"
+)
+
+type testActorsDatabase struct {
+	database.ActorsDatabase
+	actors map[string]*activitypub.Actor
+}
+
+func (db *testActorsDatabase) GetActorWithId(ctx context.Context, id string) (*activitypub.Actor, error) {
+
+	a, exists := db.actors[id]
+
+	if !exists {
+		return nil, activitypub.ErrNotFound
+	}
+
+	return a, nil
+}
+
+func (db *testActorsDatabase) AddActor(ctx context.Context, a *activitypub.Actor) error {
+	db.actors[a.Id] = a
+	return nil
+}
+
+func (db *testActorsDatabase) UpdateActor(ctx context.Context, a *activitypub.Actor) error {
+	db.actors[a.Id] = a
+	return nil
+}
+
+func (db *testActorsDatabase) RemoveActor(ctx context.Context, a *activitypub.Actor) error {
+	delete(db.actors, a.Id)
+	return nil
+}
+
+func TestRetrieveActorWithProfileURLGone(t *testing.T) {
+
+	ctx := context.Background()
+
+	gone_handler := func(rsp http.ResponseWriter, req *http.Request) {
+		http.Error(rsp, "Gone", http.StatusGone)
+	}
+
+	s := httptest.NewServer(http.HandlerFunc(gone_handler))
+	defer s.Close()
+
+	actor_id := s.URL + "/users/bob"
+
+	ap_actor := &ap.Actor{
+		Id:                actor_id,
+		Type:              "Person",
+		PreferredUsername: "bob",
+		PublicKey: ap.PublicKey{
+			Id:    actor_id + "#main-key",
+			Owner: actor_id,
+		},
+	}
+
+	db_actor, err := activitypub.NewActor(ctx, "bob@example.com", ap_actor)
+
+	if err != nil {
+		t.Fatalf("Failed to create actor, %v", err)
+	}
+
+	db := &testActorsDatabase{
+		actors: map[string]*activitypub.Actor{
+			actor_id: db_actor,
+		},
+	}
+
+	opts := &RetrieveActorOptions{
+		ActorsDatabase: db,
+		Refresh:        true,
+	}
+
+	_, err = RetrieveActorWithProfileURL(ctx, opts, ap_actor.PublicKey.Id)
+
+	if !errors.Is(err, ap.ErrGone) {
+		t.Fatalf("Expected ErrGone retrieving deleted actor, got %v", err)
+	}
+
+	opts.AllowGone = true
+
+	a, err := RetrieveActorWithProfileURL(ctx, opts, ap_actor.PublicKey.Id)
+
+	if err != nil {
+		t.Fatalf("Expected cached actor for deleted actor, %v", err)
+	}
+
+	if a.Id != actor_id {
+		t.Fatalf("Unexpected actor ID, %s", a.Id)
+	}
+
+	delete(db.actors, actor_id)
+
+	_, err = RetrieveActorWithProfileURL(ctx, opts, ap_actor.PublicKey.Id)
+
+	if !errors.Is(err, ap.ErrGone) {
+		t.Fatalf("Expected ErrGone retrieving deleted actor with no cached copy, got %v", err)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/go-fed/httpsig"
+	"github.com/sfomuseum/go-activitypub/signatures"
 	"github.com/sfomuseum/iso8601duration"
 )
 
@@ -39,6 +40,12 @@ type Activity struct {
 	Audience string `json:"audience,omitempty"`
 	// Object is body of the activity itself.
 	Object interface{} `json:"object,omitempty"`
+	// Target is the (URI of the) indirect object of the activity, for example the new actor in a "Move" activity.
+	Target interface{} `json:"target,omitempty"`
+	// Content is an optional (HTML) comment describing the activity, for example the reason given for a "Flag" activity.
+	Content string `json:"content,omitempty"`
+	// Tags is the list of tags associated with the activity, for example the "Emoji" tag for custom emoji reactions.
+	Tags []*Tag `json:"tag,omitempty"`
 	// The RFC3339 date that the activity was published.
 	Published string `json:"published,omitempty"`
 }
@@ -59,7 +66,10 @@ type PostToInboxOptions struct {
 	LogResponseOnError bool
 }
 
-// PostToInbox delivers an Activity message to a specific inbox.
+// PostToInbox delivers an Activity message to a specific inbox. Messages are signed using either RFC 9421 HTTP message signatures
+// or draft-cavage HTTP signatures. The scheme tried first is the one most recently recorded as having worked for the inbox host
+// (see `signatures.SchemesForHost`); if the inbox responds with a HTTP 401 Unauthorized status the message is signed and delivered
+// again using the other scheme ("double-knocking"). The scheme that works is recorded for subsequent deliveries to that host.
 func (activity *Activity) PostToInbox(ctx context.Context, key_id string, private_key *rsa.PrivateKey, inbox_uri string) error {
 
 	logger := slog.Default()
@@ -77,11 +87,46 @@ func (activity *Activity) PostToInbox(ctx context.Context, key_id string, privat
 		return fmt.Errorf("Failed to marshal follow activity request, %w", err)
 	}
 
+	inbox_u, err := url.Parse(inbox_uri)
+
+	if err != nil {
+		logger.Error("Failed to parse inbox URI", "error", err)
+		return fmt.Errorf("Failed to parse inbox URL, %w", err)
+	}
+
+	schemes := signatures.SchemesForHost(inbox_u.Host)
+
+	for idx, scheme := range schemes {
+
+		status, err := postSignedRequest(ctx, key_id, private_key, inbox_u, enc_req, scheme, logger.With("signature scheme", scheme))
+
+		if err == nil {
+			signatures.SetSchemeForHost(inbox_u.Host, scheme)
+			return nil
+		}
+
+		if status == http.StatusUnauthorized && idx < len(schemes)-1 {
+			logger.Info("Inbox rejected signature, retry with other signature scheme", "signature scheme", scheme)
+			continue
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// postSignedRequest posts 'enc_req' to 'inbox_u' signed, using 'scheme', with 'private_key'. It returns the HTTP status code of
+// the response, or zero if the request was not sent.
+func postSignedRequest(ctx context.Context, key_id string, private_key *rsa.PrivateKey, inbox_u *url.URL, enc_req []byte, scheme signatures.Scheme, logger *slog.Logger) (int, error) {
+
+	inbox_uri := inbox_u.String()
+
 	http_req, err := http.NewRequestWithContext(ctx, "POST", inbox_uri, bytes.NewBuffer(enc_req))
 
 	if err != nil {
 		logger.Error("Failed to create new request for activity", "error", err)
-		return fmt.Errorf("Failed to create new request to %s, %w", inbox_uri, err)
+		return 0, fmt.Errorf("Failed to create new request to %s, %w", inbox_uri, err)
 	}
 
 	now := time.Now()
@@ -92,13 +137,6 @@ func (activity *Activity) PostToInbox(ctx context.Context, key_id string, privat
 	http_req.Header.Set("Date", now.Format(http.TimeFormat))
 
 	// START OF this is necessary for HTTP signature hoohah...
-	inbox_u, err := url.Parse(inbox_uri)
-
-	if err != nil {
-		logger.Error("Failed to parse inbox URI", "error", err)
-		return fmt.Errorf("Failed to parse inbox URL, %w", err)
-	}
-
 	http_req.Header.Set("Host", inbox_u.Host)
 	// END OF this is necessary for HTTP signature hoohah...
 
@@ -108,51 +146,64 @@ func (activity *Activity) PostToInbox(ctx context.Context, key_id string, privat
 	d, err := duration.FromString(str_ttl)
 
 	if err != nil {
-		return fmt.Errorf("Failed to derive duration, %w", err)
+		return 0, fmt.Errorf("Failed to derive duration, %w", err)
 	}
 
-	ttl := int64(d.ToDuration().Seconds())
+	switch scheme {
+	case signatures.RFC9421Scheme:
 
-	// Created and Expires headers are important for posting to Mastodon
-	// https://github.com/mastodon/mastodon/blob/main/app/controllers/concerns/signature_verification.rb#L183
+		err = signatures.SignRFC9421(http_req, enc_req, key_id, private_key, d.ToDuration())
 
-	created := now.Unix()
-	expires := created + ttl
+		if err != nil {
+			logger.Error("Failed to sign request", "error", err)
+			return 0, fmt.Errorf("Failed to sign request, %w", err)
+		}
 
-	http_req.Header.Set("Created", strconv.FormatInt(created, 10))
-	http_req.Header.Set("Expires", strconv.FormatInt(expires, 10))
+	default:
 
-	// https://datatracker.ietf.org/doc/html/draft-cavage-http-signatures#section-1.1
-	// https://pkg.go.dev/github.com/go-fed/httpsig
+		ttl := int64(d.ToDuration().Seconds())
 
-	// prefs := []httpsig.Algorithm{httpsig.RSA_SHA512, httpsig.RSA_SHA256}
+		// Created and Expires headers are important for posting to Mastodon
+		// https://github.com/mastodon/mastodon/blob/main/app/controllers/concerns/signature_verification.rb#L183
 
-	prefs := []httpsig.Algorithm{httpsig.RSA_SHA256}
-	digestAlgorithm := httpsig.DigestSha256
+		created := now.Unix()
+		expires := created + ttl
 
-	headersToSign := []string{
-		httpsig.RequestTarget,
-		"Host",
-		"Date",
-		"Digest",
-		// See the way this is "(created)" and not "Created". That's a go-fed/httpsig thing... or maybe it's a spec thing?
-		// https://github.com/go-fed/httpsig/blob/master/signing.go#L220-L229
-		"(created)",
-		"(expires)",
-	}
+		http_req.Header.Set("Created", strconv.FormatInt(created, 10))
+		http_req.Header.Set("Expires", strconv.FormatInt(expires, 10))
 
-	signer, _, err := httpsig.NewSigner(prefs, digestAlgorithm, headersToSign, httpsig.Signature, ttl)
+		// https://datatracker.ietf.org/doc/html/draft-cavage-http-signatures#section-1.1
+		// https://pkg.go.dev/github.com/go-fed/httpsig
 
-	if err != nil {
-		logger.Error("Failed to create new HTTP signer", "error", err)
-		return fmt.Errorf("Failed to create new signer, %w", err)
-	}
+		// prefs := []httpsig.Algorithm{httpsig.RSA_SHA512, httpsig.RSA_SHA256}
 
-	err = signer.SignRequest(private_key, key_id, http_req, enc_req)
+		prefs := []httpsig.Algorithm{httpsig.RSA_SHA256}
+		digestAlgorithm := httpsig.DigestSha256
 
-	if err != nil {
-		logger.Error("Failed to sign request", "error", err)
-		return fmt.Errorf("Failed to sign request, %w", err)
+		headersToSign := []string{
+			httpsig.RequestTarget,
+			"Host",
+			"Date",
+			"Digest",
+			// See the way this is "(created)" and not "Created". That's a go-fed/httpsig thing... or maybe it's a spec thing?
+			// https://github.com/go-fed/httpsig/blob/master/signing.go#L220-L229
+			"(created)",
+			"(expires)",
+		}
+
+		signer, _, err := httpsig.NewSigner(prefs, digestAlgorithm, headersToSign, httpsig.Signature, ttl)
+
+		if err != nil {
+			logger.Error("Failed to create new HTTP signer", "error", err)
+			return 0, fmt.Errorf("Failed to create new signer, %w", err)
+		}
+
+		err = signer.SignRequest(private_key, key_id, http_req, enc_req)
+
+		if err != nil {
+			logger.Error("Failed to sign request", "error", err)
+			return 0, fmt.Errorf("Failed to sign request, %w", err)
+		}
 	}
 
 	// https://pkg.go.dev/net/http/httputil#DumpRequest
@@ -174,7 +225,7 @@ func (activity *Activity) PostToInbox(ctx context.Context, key_id string, privat
 	http_rsp, err := http_cl.Do(http_req)
 
 	if err != nil {
-		return fmt.Errorf("Failed to execute post to inbox request, %w", err)
+		return 0, fmt.Errorf("Failed to execute post to inbox request, %w", err)
 	}
 
 	defer http_rsp.Body.Close()
@@ -186,7 +237,7 @@ func (activity *Activity) PostToInbox(ctx context.Context, key_id string, privat
 	switch http_rsp.StatusCode {
 	// HTTP 200, 201, 202, 204
 	case http.StatusOK, http.StatusCreated, http.StatusAccepted, http.StatusNoContent:
-		return nil
+		return http_rsp.StatusCode, nil
 	default:
 
 		/*
@@ -203,5 +254,5 @@ func (activity *Activity) PostToInbox(ctx context.Context, key_id string, privat
 		*/
 	}
 
-	return fmt.Errorf("Follow request failed %d, %s", http_rsp.StatusCode, http_rsp.Status)
+	return http_rsp.StatusCode, fmt.Errorf("Follow request failed %d, %s", http_rsp.StatusCode, http_rsp.Status)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/rsa"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -13,6 +14,9 @@ import (
 	"github.com/sfomuseum/go-activitypub/webfinger"
 )
 
+// ErrGone is an error indicating that a remote host has reported that an actor has been deleted (HTTP 410 Gone).
+var ErrGone = errors.New("Gone")
+
 // https://www.w3.org/TR/activitystreams-vocabulary/#actor-types
 
 type Actor struct {
@@ -31,11 +35,23 @@ type Actor struct {
 	Summary   string `json:"summary,omitempty"`
 	URL       string `json:"url,omitempty"`
 	// Don't omitempty because if you do then false values are omitted
-	// ManuallyApprovesFollowers bool   `json:"manuallyApprovesFollowers"`
-	Discoverable bool          `json:"discoverable,omitempty"`
-	Published    string        `json:"published,omitempty"`
-	Icon         Icon          `json:"icon,omitempty"`
-	Attachments  []*Attachment `json:"attachment,omitempty"` // Is this just a Mastodon-ism?
+	ManuallyApprovesFollowers bool          `json:"manuallyApprovesFollowers"`
+	Discoverable              bool          `json:"discoverable,omitempty"`
+	Published                 string        `json:"published,omitempty"`
+	Icon                      Icon          `json:"icon,omitempty"`
+	Attachments               []*Attachment `json:"attachment,omitempty"` // Is this just a Mastodon-ism?
+	Endpoints                 *Endpoints    `json:"endpoints,omitempty"`
+	// AlsoKnownAs is the list of other actor URIs that this actor claims to be. An actor that an account has moved to
+	// must list the URI of the old account here.
+	AlsoKnownAs []string `json:"alsoKnownAs,omitempty"`
+	// MovedTo is the URI of the actor that this actor has moved to.
+	MovedTo string `json:"movedTo,omitempty"`
+}
+
+// Endpoints defines additional (optional) endpoints associated with an actor.
+type Endpoints struct {
+	// SharedInbox is the URL of an inbox, shared by all the actors on a server, where activities can be delivered once for multiple recipients.
+	SharedInbox string `json:"sharedInbox,omitempty"`
 }
 
 func (a *Actor) Address() (string, error) {
@@ -144,9 +160,96 @@ func RetrieveActor(ctx context.Context, id string, insecure bool) (*Actor, error
 	return RetrieveActorWithProfileURL(ctx, profile_url)
 }
 
+// RetrieveActorWithProfileURL returns the actor published at 'profile_url'. The ID of the actor must match 'profile_url', ignoring
+// any fragment (for example a public key ID like "https://example.com/users/bob#main-key"). If it does not, but both share the same
+// origin (for example a public key ID like "https://example.com/users/bob/main-key"), the actor is retrieved again from its own ID which
+// must then match. Actors whose ID has a different origin than 'profile_url' are always rejected.
 func RetrieveActorWithProfileURL(ctx context.Context, profile_url string) (*Actor, error) {
 
-	// slog.Info(profile_url)
+	actor, err := fetchActorWithProfileURL(ctx, profile_url)
+
+	if err != nil {
+		return nil, err
+	}
+
+	is_match, err := isActorIdForURL(actor.Id, profile_url)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if is_match {
+		return actor, nil
+	}
+
+	is_same, err := isSameOrigin(actor.Id, profile_url)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !is_same {
+		return nil, fmt.Errorf("Actor ID (%s) does not share the same origin as profile URL (%s)", actor.Id, profile_url)
+	}
+
+	canonical_actor, err := fetchActorWithProfileURL(ctx, actor.Id)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve actor from its ID, %w", err)
+	}
+
+	if canonical_actor.Id != actor.Id {
+		return nil, fmt.Errorf("Actor ID (%s) does not match the URL it was retrieved from (%s)", canonical_actor.Id, actor.Id)
+	}
+
+	return canonical_actor, nil
+}
+
+// isActorIdForURL returns a boolean value indicating whether 'actor_id' and 'profile_url' are the same URL, ignoring any fragments.
+func isActorIdForURL(actor_id string, profile_url string) (bool, error) {
+
+	actor_u, err := url.Parse(actor_id)
+
+	if err != nil {
+		return false, fmt.Errorf("Failed to parse actor ID, %w", err)
+	}
+
+	profile_u, err := url.Parse(profile_url)
+
+	if err != nil {
+		return false, fmt.Errorf("Failed to parse profile URL, %w", err)
+	}
+
+	actor_u.Fragment = ""
+	profile_u.Fragment = ""
+
+	return actor_u.String() == profile_u.String(), nil
+}
+
+// isSameOrigin returns a boolean value indicating whether 'uri_a' and 'uri_b' have the same scheme and host.
+func isSameOrigin(uri_a string, uri_b string) (bool, error) {
+
+	u_a, err := url.Parse(uri_a)
+
+	if err != nil {
+		return false, fmt.Errorf("Failed to parse %s, %w", uri_a, err)
+	}
+
+	u_b, err := url.Parse(uri_b)
+
+	if err != nil {
+		return false, fmt.Errorf("Failed to parse %s, %w", uri_b, err)
+	}
+
+	if u_a.Host == "" || u_a.Scheme != u_b.Scheme || u_a.Host != u_b.Host {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// fetchActorWithProfileURL retrieves and decodes the actor published at 'profile_url'.
+func fetchActorWithProfileURL(ctx context.Context, profile_url string) (*Actor, error) {
 
 	logger := slog.Default()
 	logger = logger.With("profile url", profile_url)
@@ -171,6 +274,10 @@ func RetrieveActorWithProfileURL(ctx context.Context, profile_url string) (*Acto
 
 	defer profile_rsp.Body.Close()
 
+	if profile_rsp.StatusCode == http.StatusGone {
+		return nil, fmt.Errorf("Remote endpoint did not return successfully %d, %w", profile_rsp.StatusCode, ErrGone)
+	}
+
 	if profile_rsp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("Remote endpoint did not return successfully %d, %s", profile_rsp.StatusCode, profile_rsp.Status)
 	}
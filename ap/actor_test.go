@@ -2,6 +2,10 @@ package ap
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -61,3 +65,78 @@ func TestRetrieveActorWithProfileURL(t *testing.T) {
 		}
 	}
 }
+
+func TestRetrieveActorWithProfileURLId(t *testing.T) {
+
+	ctx := context.Background()
+
+	var server_url string
+
+	ids := map[string]string{
+		"/users/bob":          "/users/bob",
+		"/users/bob/main-key": "/users/bob",
+		"/users/carol":        "/users/dave",
+		"/users/dave":         "/users/erin",
+		"/users/mallory":      "https://evil.example/users/bob",
+	}
+
+	handler := func(rsp http.ResponseWriter, req *http.Request) {
+
+		id, exists := ids[req.URL.Path]
+
+		if !exists {
+			http.Error(rsp, "Not found", http.StatusNotFound)
+			return
+		}
+
+		if strings.HasPrefix(id, "/") {
+			id = server_url + id
+		}
+
+		actor := &Actor{
+			Id:                id,
+			Type:              "Person",
+			PreferredUsername: "bob",
+		}
+
+		rsp.Header().Set("Content-Type", ACTIVITY_LD_CONTENT_TYPE)
+		json.NewEncoder(rsp).Encode(actor)
+	}
+
+	s := httptest.NewServer(http.HandlerFunc(handler))
+	defer s.Close()
+
+	server_url = s.URL
+
+	tests := map[string]string{
+		"/users/bob":          "/users/bob",
+		"/users/bob#main-key": "/users/bob",
+		"/users/bob/main-key": "/users/bob",
+		// ID does not match the URL it was retrieved from when fetched again
+		"/users/carol": "",
+		// ID has a different origin
+		"/users/mallory": "",
+	}
+
+	for path, expected := range tests {
+
+		actor, err := RetrieveActorWithProfileURL(ctx, server_url+path)
+
+		if expected == "" {
+
+			if err == nil {
+				t.Fatalf("Expected actor for %s to be rejected", path)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("Failed to retrieve actor for %s, %v", path, err)
+		}
+
+		if actor.Id != server_url+expected {
+			t.Fatalf("Unexpected actor ID for %s, %s", path, actor.Id)
+		}
+	}
+}
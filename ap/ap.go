@@ -15,4 +15,12 @@ const ACCEPT_ACTIVITY string = "Accept"
 
 const CREATE_ACTIVITY string = "Create"
 
+const DELETE_ACTIVITY string = "Delete"
+
+const FLAG_ACTIVITY string = "Flag"
+
 const FOLLOW_ACTIVITY string = "Follow"
+
+const REJECT_ACTIVITY string = "Reject"
+
+const UPDATE_ACTIVITY string = "Update"
@@ -1,9 +1,13 @@
 package ap
 
 type Attachment struct {
-	Type      string `json:"type"`
-	MediaType string `json:"mediaType"`
-	Name      string `json:"name"`
-	Value     string `json:"value,omitempty"`
-	URL       string `json:"url"`
+	Type       string    `json:"type"`
+	MediaType  string    `json:"mediaType"`
+	Name       string    `json:"name"`
+	Value      string    `json:"value,omitempty"`
+	URL        string    `json:"url"`
+	Width      int       `json:"width,omitempty"`
+	Height     int       `json:"height,omitempty"`
+	Blurhash   string    `json:"blurhash,omitempty"`
+	FocalPoint []float64 `json:"focalPoint,omitempty"`
 }
@@ -7,5 +7,21 @@ type OrderedCollection struct {
 	Summary     string         `json:"summary,omitempty"`
 	Type        string         `json:"type"`
 	TotalItems  int            `json:"totalItems"`
+	First       string         `json:"first,omitempty"`
+	Last        string         `json:"last,omitempty"`
 	OrderedItem []*interface{} `json:"orderedItems,omitempty"`
 }
+
+// OrderedCollectionPage is a single page of items in an `OrderedCollection`.
+type OrderedCollectionPage struct {
+	Context []interface{} `json:"@context"`
+	Id      string        `json:"id"`
+	Type    string        `json:"type"`
+	// PartOf is the URI of the `OrderedCollection` that the page is part of.
+	PartOf string `json:"partOf"`
+	// Next is the URI of the next (older) page of items, if there is one.
+	Next string `json:"next,omitempty"`
+	// Prev is the URI of the previous (newer) page of items, if there is one.
+	Prev         string        `json:"prev,omitempty"`
+	OrderedItems []interface{} `json:"orderedItems"`
+}
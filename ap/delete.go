@@ -0,0 +1,25 @@
+package ap
+
+import (
+	"context"
+
+	"github.com/sfomuseum/go-activitypub/uris"
+)
+
+// NewDeleteActivity returns a new `Activity` instance of type "Delete".
+func NewDeleteActivity(ctx context.Context, uris_table *uris.URIs, from string, to []string, object interface{}) (*Activity, error) {
+
+	ap_id := NewId(uris_table, "delete")
+
+	req := &Activity{
+		Context: ACTIVITYSTREAMS_CONTEXT,
+		Id:      ap_id,
+		Type:    DELETE_ACTIVITY,
+		Actor:   from,
+		To:      to,
+		Object:  object,
+	}
+
+	return req, nil
+
+}
@@ -0,0 +1,42 @@
+package ap
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sfomuseum/go-activitypub/uris"
+)
+
+// https://www.w3.org/TR/activitystreams-vocabulary/#dfn-flag
+
+// NewFlagActivity returns a new `Activity` instance of type "Flag" reporting the actor 'actor_uri', and optionally
+// the objects (posts) in 'object_uris', on behalf of 'from' with an optional comment 'content'. The Flag activity
+// "indicates that the actor is "flagging" the object. Flagging is defined in the sense common to many social platforms
+// as reporting content as being inappropriate for any number of reasons."
+func NewFlagActivity(ctx context.Context, uris_table *uris.URIs, from string, actor_uri string, object_uris []string, content string) (*Activity, error) {
+
+	if actor_uri == "" {
+		return nil, fmt.Errorf("Missing actor to flag")
+	}
+
+	ap_id := NewId(uris_table, "flag")
+
+	objects := []interface{}{
+		actor_uri,
+	}
+
+	for _, uri := range object_uris {
+		objects = append(objects, uri)
+	}
+
+	req := &Activity{
+		Context: ACTIVITYSTREAMS_CONTEXT,
+		Id:      ap_id,
+		Type:    FLAG_ACTIVITY,
+		Actor:   from,
+		Object:  objects,
+		Content: content,
+	}
+
+	return req, nil
+}
@@ -0,0 +1,27 @@
+package ap
+
+import (
+	"context"
+	"time"
+
+	"github.com/sfomuseum/go-activitypub/uris"
+)
+
+// NewLikeActivity returns a new `Activity` instance of type "Like" from 'from' for 'object_uri'.
+func NewLikeActivity(ctx context.Context, uris_table *uris.URIs, from string, object_uri string) (*Activity, error) {
+
+	ap_id := NewId(uris_table, "like")
+
+	now := time.Now()
+
+	activity := &Activity{
+		Context:   ACTIVITYSTREAMS_CONTEXT,
+		Id:        ap_id,
+		Type:      "Like",
+		Actor:     from,
+		Object:    object_uri,
+		Published: now.Format(time.RFC3339),
+	}
+
+	return activity, nil
+}
@@ -0,0 +1,250 @@
+package ap
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// NoteObjectTypes is the list of ActivityStreams object types that are accepted in "Create" (and "Update") activities
+// and stored as notes. The original type of the object is preserved in the note's body.
+var NoteObjectTypes = []string{
+	"Note",
+	"Article",
+	"Page",
+	"Question",
+	"Video",
+}
+
+// IsNoteObjectType returns a boolean value indicating whether 'object_type' is one of the types in `NoteObjectTypes`.
+func IsNoteObjectType(object_type string) bool {
+	return slices.Contains(NoteObjectTypes, object_type)
+}
+
+// NormalizeNoteObject normalizes the properties of the JSON-encoded object 'enc_obj', which is expected to be one of
+// the types in `NoteObjectTypes`, so that notes can be treated uniformly regardless of the software that created them.
+// Specifically:
+//   - If the "name", "summary" or "content" properties are absent they are derived from the first (sorted by language)
+//     value of their corresponding "nameMap", "summaryMap" or "contentMap" properties.
+//   - If the "content" property is still empty it is derived from the "summary" or, failing that, the "name" property.
+//     For example, a PeerTube "Video" or a blog "Article" with no body.
+//   - The "url" property, which may be a string, a "Link" object or a list of strings and "Link" objects, is reduced to
+//     a single string. Links with a "text/html" media type are preferred. If there is no URL the object's "id" is used.
+//   - The "attributedTo" property, which may be a string, an embedded object or a list of either (for example a PeerTube
+//     "Video" is attributed to both a "Person" and the "Group" representing its channel), is reduced to a single string. If
+//     'actor_id' is one of the object's attributions (see `ObjectAttributions`) then it is used, otherwise the first attribution
+//     is used. If there are no attributions the property is removed.
+//   - The "to" and "cc" properties are converted to lists if they are strings.
+//
+// All other properties, including "type", are left as-is.
+func NormalizeNoteObject(enc_obj []byte, actor_id string) ([]byte, error) {
+
+	var obj map[string]interface{}
+
+	err := json.Unmarshal(enc_obj, &obj)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal object, %w", err)
+	}
+
+	for _, k := range []string{"name", "summary", "content"} {
+
+		v, _ := obj[k].(string)
+
+		if v != "" {
+			continue
+		}
+
+		v = firstMapValue(obj[k+"Map"])
+
+		if v != "" {
+			obj[k] = v
+		}
+	}
+
+	content, _ := obj["content"].(string)
+
+	if content == "" {
+
+		for _, k := range []string{"summary", "name"} {
+
+			v, _ := obj[k].(string)
+
+			if v != "" {
+				obj["content"] = v
+				break
+			}
+		}
+	}
+
+	_, has_attributions := obj["attributedTo"]
+
+	if has_attributions {
+
+		authors := attributions(obj["attributedTo"])
+
+		switch {
+		case actor_id != "" && slices.Contains(authors, actor_id):
+			obj["attributedTo"] = actor_id
+		case len(authors) > 0:
+			obj["attributedTo"] = authors[0]
+		default:
+			delete(obj, "attributedTo")
+		}
+	}
+
+	for _, k := range []string{"to", "cc"} {
+
+		v, ok := obj[k].(string)
+
+		if ok {
+			obj[k] = []string{v}
+		}
+	}
+
+	url := normalizeURL(obj["url"])
+
+	if url == "" {
+		url, _ = obj["id"].(string)
+	}
+
+	if url != "" {
+		obj["url"] = url
+	}
+
+	return json.Marshal(obj)
+}
+
+// ObjectAttributions returns the list of actor URIs that the JSON-encoded object 'enc_obj' is attributed to. The "attributedTo"
+// property may be a URI, an embedded object or a list of either. Embedded objects whose type is not "Person" (for example the
+// "Group" representing the channel a PeerTube "Video" was published to) are not included.
+func ObjectAttributions(enc_obj []byte) ([]string, error) {
+
+	var obj map[string]interface{}
+
+	err := json.Unmarshal(enc_obj, &obj)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal object, %w", err)
+	}
+
+	return attributions(obj["attributedTo"]), nil
+}
+
+// attributions returns the list of actor URIs derived from the value of an object's "attributedTo" property.
+func attributions(v interface{}) []string {
+
+	authors := make([]string, 0)
+
+	items, ok := v.([]interface{})
+
+	if !ok {
+		items = []interface{}{v}
+	}
+
+	for _, i := range items {
+
+		switch a := i.(type) {
+		case string:
+
+			if a != "" {
+				authors = append(authors, a)
+			}
+
+		case map[string]interface{}:
+
+			a_type, _ := a["type"].(string)
+
+			if a_type != "" && a_type != "Person" {
+				continue
+			}
+
+			a_id, _ := a["id"].(string)
+
+			if a_id != "" {
+				authors = append(authors, a_id)
+			}
+		}
+	}
+
+	return authors
+}
+
+// firstMapValue returns the first non-empty string value, sorted by key, of a language map (for example "contentMap").
+func firstMapValue(v interface{}) string {
+
+	m, ok := v.(map[string]interface{})
+
+	if !ok {
+		return ""
+	}
+
+	keys := make([]string, 0)
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+
+		str_v, _ := m[k].(string)
+
+		if str_v != "" {
+			return str_v
+		}
+	}
+
+	return ""
+}
+
+// normalizeURL reduces the value of an object's "url" property to a single string.
+func normalizeURL(v interface{}) string {
+
+	switch u := v.(type) {
+	case string:
+		return u
+	case map[string]interface{}:
+		href, _ := u["href"].(string)
+		return href
+	case []interface{}:
+
+		first := ""
+
+		for _, candidate := range u {
+
+			switch c := candidate.(type) {
+			case string:
+
+				if first == "" {
+					first = c
+				}
+
+			case map[string]interface{}:
+
+				href, _ := c["href"].(string)
+
+				if href == "" {
+					continue
+				}
+
+				media_type, _ := c["mediaType"].(string)
+
+				if media_type == "text/html" {
+					return href
+				}
+
+				if first == "" {
+					first = href
+				}
+			}
+		}
+
+		return first
+
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,143 @@
+package ap
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestNormalizeNoteObject(t *testing.T) {
+
+	tests := map[string]*Note{
+		`{"type":"Note","id":"https://example.com/notes/1","url":"https://example.com/@bob/1","content":"Hello world"}`: &Note{
+			Type:    "Note",
+			URL:     "https://example.com/@bob/1",
+			Content: "Hello world",
+		},
+		`{"type":"Article","id":"https://example.com/articles/1","name":"Title","contentMap":{"fr":"Bonjour","en":"Hello"}}`: &Note{
+			Type:    "Article",
+			Name:    "Title",
+			URL:     "https://example.com/articles/1",
+			Content: "Hello",
+		},
+		`{"type":"Video","id":"https://example.com/videos/1","name":"A video","url":[{"type":"Link","mediaType":"video/mp4","href":"https://example.com/1.mp4"},{"type":"Link","mediaType":"text/html","href":"https://example.com/w/1"}]}`: &Note{
+			Type:    "Video",
+			Name:    "A video",
+			URL:     "https://example.com/w/1",
+			Content: "A video",
+		},
+		`{"type":"Page","id":"https://example.com/pages/1","summary":"About","url":{"type":"Link","href":"https://example.com/about"}}`: &Note{
+			Type:    "Page",
+			Summary: "About",
+			URL:     "https://example.com/about",
+			Content: "About",
+		},
+	}
+
+	for enc_obj, expected := range tests {
+
+		enc_norm, err := NormalizeNoteObject([]byte(enc_obj), "")
+
+		if err != nil {
+			t.Fatalf("Failed to normalize %s, %v", enc_obj, err)
+		}
+
+		var n *Note
+
+		err = json.Unmarshal(enc_norm, &n)
+
+		if err != nil {
+			t.Fatalf("Failed to unmarshal normalized object %s, %v", enc_norm, err)
+		}
+
+		if n.Type != expected.Type || n.Name != expected.Name || n.Summary != expected.Summary || n.URL != expected.URL || n.Content != expected.Content {
+			t.Fatalf("Unexpected normalized object for %s, %s", enc_obj, enc_norm)
+		}
+	}
+}
+
+func TestNormalizeNoteObjectAttributions(t *testing.T) {
+
+	tests := map[string]string{
+		`{"type":"Note","id":"https://example.com/notes/1","attributedTo":"https://example.com/users/bob"}`:                                                                            "https://example.com/users/bob",
+		`{"type":"Note","id":"https://example.com/notes/1","attributedTo":{"type":"Person","id":"https://example.com/users/bob"}}`:                                                     "https://example.com/users/bob",
+		`{"type":"Note","id":"https://example.com/notes/1","attributedTo":["https://example.com/users/carol","https://example.com/users/bob"]}`:                                        "https://example.com/users/bob",
+		`{"type":"Note","id":"https://example.com/notes/1","attributedTo":[{"type":"Group","id":"https://example.com/c/1"},{"type":"Person","id":"https://example.com/users/carol"}]}`: "https://example.com/users/carol",
+		`{"type":"Note","id":"https://example.com/notes/1","attributedTo":[{"type":"Group","id":"https://example.com/users/bob"}]}`:                                                    "",
+	}
+
+	for enc_obj, expected := range tests {
+
+		enc_norm, err := NormalizeNoteObject([]byte(enc_obj), "https://example.com/users/bob")
+
+		if err != nil {
+			t.Fatalf("Failed to normalize %s, %v", enc_obj, err)
+		}
+
+		var n *Note
+
+		err = json.Unmarshal(enc_norm, &n)
+
+		if err != nil {
+			t.Fatalf("Failed to unmarshal normalized object %s, %v", enc_norm, err)
+		}
+
+		if n.AttributedTo != expected {
+			t.Fatalf("Unexpected attribution for %s, expected '%s' but got '%s'", enc_obj, expected, n.AttributedTo)
+		}
+	}
+}
+
+func TestNormalizeNoteObjectPeerTube(t *testing.T) {
+
+	enc_obj, err := os.ReadFile("../fixtures/objects/peertube_video.json")
+
+	if err != nil {
+		t.Fatalf("Failed to read fixture, %v", err)
+	}
+
+	authors, err := ObjectAttributions(enc_obj)
+
+	if err != nil {
+		t.Fatalf("Failed to derive attributions, %v", err)
+	}
+
+	if !slices.Equal(authors, []string{"https://video.example/accounts/carol"}) {
+		t.Fatalf("Unexpected attributions, %v", authors)
+	}
+
+	enc_norm, err := NormalizeNoteObject(enc_obj, "https://video.example/accounts/carol")
+
+	if err != nil {
+		t.Fatalf("Failed to normalize fixture, %v", err)
+	}
+
+	var n *Note
+
+	err = json.Unmarshal(enc_norm, &n)
+
+	if err != nil {
+		t.Fatalf("Failed to unmarshal normalized object, %v", err)
+	}
+
+	if n.Type != "Video" {
+		t.Fatalf("Unexpected type, %s", n.Type)
+	}
+
+	if n.AttributedTo != "https://video.example/accounts/carol" {
+		t.Fatalf("Unexpected attribution, %s", n.AttributedTo)
+	}
+
+	if n.URL != "https://video.example/w/kR5XyZeaJeeXVfhDAkg4Xv" {
+		t.Fatalf("Unexpected URL, %s", n.URL)
+	}
+
+	if !slices.Equal(n.To, []string{"https://www.w3.org/ns/activitystreams#Public"}) {
+		t.Fatalf("Unexpected to, %v", n.To)
+	}
+
+	if !slices.Equal(n.Cc, []string{"https://video.example/accounts/carol/followers"}) {
+		t.Fatalf("Unexpected cc, %v", n.Cc)
+	}
+}
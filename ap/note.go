@@ -4,11 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 )
 
 type Note struct {
-	// Type is the type of the note (aka "Note").
+	// Type is the type of the note (aka "Note"). Other types of objects, listed in `NoteObjectTypes`, are also stored as notes.
 	Type string `json:"type"`
 	// Id is the unique identifier for the note.
 	Id string `json:"id"`
@@ -22,17 +23,24 @@ type Note struct {
 	To []string `json:"to"`
 	// CC is the list of URIs the activity should be copied to.
 	Cc []string `json:"cc,omitempty"`
+	// The title of the note, if present. For example the title of an "Article" or "Video" object.
+	Name string `json:"name,omitempty"`
+	// A summary of the note, if present. For "Note" objects this is typically used as a content warning.
+	Summary string `json:"summary,omitempty"`
 	// The body of the note.
 	Content string `json:"content"`
 	// The permanent URL of the post.
 	URL string `json:"url"`
 	// The RFC3339 date that the activity was published.
 	Published string `json:"published"`
+	// The date that the note was last updated (edited), if it has been.
+	Updated string `json:"updated,omitempty"`
 	// Zero or more attachments to include with the note.
 	Attachments []*Attachment `json:"attachment,omitempty"`
 }
 
-// Retrieve note fetches and unmarshals the "application/activity+json" representation of 'uri'.
+// Retrieve note fetches and unmarshals the "application/activity+json" representation of 'uri'. The object may be any of the
+// types in `NoteObjectTypes` and is normalized, using `NormalizeNoteObject`, before it is unmarshaled.
 func RetrieveNote(ctx context.Context, uri string) (*Note, error) {
 
 	req, err := http.NewRequestWithContext(ctx, "GET", uri, nil)
@@ -57,16 +65,30 @@ func RetrieveNote(ctx context.Context, uri string) (*Note, error) {
 		return nil, fmt.Errorf("Note returned unexpected status, %d (%s)", rsp.StatusCode, rsp.Status)
 	}
 
+	body, err := io.ReadAll(rsp.Body)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read note, %w", err)
+	}
+
+	// Remote servers may attribute notes to a list of actors (or objects) and address
+	// them using strings rather than lists so normalize the note before decoding it.
+
+	body, err = NormalizeNoteObject(body, "")
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to normalize note, %w", err)
+	}
+
 	var n *Note
 
-	dec := json.NewDecoder(rsp.Body)
-	err = dec.Decode(&n)
+	err = json.Unmarshal(body, &n)
 
 	if err != nil {
 		return nil, fmt.Errorf("Failed to decode note, %w", err)
 	}
 
-	if n.Type != "Note" {
+	if !IsNoteObjectType(n.Type) {
 		return nil, fmt.Errorf("Unexpected type, %s", n.Type)
 	}
 
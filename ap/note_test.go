@@ -2,6 +2,8 @@ package ap
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -26,3 +28,65 @@ func TestRetrieveNote(t *testing.T) {
 		}
 	}
 }
+
+func TestRetrieveNoteWithAttributionList(t *testing.T) {
+
+	enc_note := `{"type":"Note","id":"https://example.com/notes/1","attributedTo":[{"type":"Person","id":"https://example.com/users/bob"},"https://example.com/users/carol"],"to":"https://www.w3.org/ns/activitystreams#Public","content":"Hello world"}`
+
+	handler := func(rsp http.ResponseWriter, req *http.Request) {
+		rsp.Header().Set("Content-Type", ACTIVITY_CONTENT_TYPE)
+		rsp.Write([]byte(enc_note))
+	}
+
+	s := httptest.NewServer(http.HandlerFunc(handler))
+	defer s.Close()
+
+	ctx := context.Background()
+
+	n, err := RetrieveNote(ctx, s.URL)
+
+	if err != nil {
+		t.Fatalf("Failed to fetch note, %v", err)
+	}
+
+	if n.AttributedTo != "https://example.com/users/bob" {
+		t.Fatalf("Unexpected attribution, %s", n.AttributedTo)
+	}
+
+	if len(n.To) != 1 || n.To[0] != ACTIVITYSTREAMS_CONTEXT_PUBLIC {
+		t.Fatalf("Unexpected to, %v", n.To)
+	}
+}
+
+func TestRetrieveNoteWithArticle(t *testing.T) {
+
+	enc_article := `{"type":"Article","id":"https://example.com/articles/1","attributedTo":"https://example.com/users/bob","name":"Hello","contentMap":{"en":"Hello world"}}`
+
+	handler := func(rsp http.ResponseWriter, req *http.Request) {
+		rsp.Header().Set("Content-Type", ACTIVITY_CONTENT_TYPE)
+		rsp.Write([]byte(enc_article))
+	}
+
+	s := httptest.NewServer(http.HandlerFunc(handler))
+	defer s.Close()
+
+	ctx := context.Background()
+
+	n, err := RetrieveNote(ctx, s.URL)
+
+	if err != nil {
+		t.Fatalf("Failed to fetch article, %v", err)
+	}
+
+	if n.Type != "Article" {
+		t.Fatalf("Unexpected type, %s", n.Type)
+	}
+
+	if n.Content != "Hello world" {
+		t.Fatalf("Unexpected content, %s", n.Content)
+	}
+
+	if n.AttributedTo != "https://example.com/users/bob" {
+		t.Fatalf("Unexpected attribution, %s", n.AttributedTo)
+	}
+}
@@ -0,0 +1,28 @@
+package ap
+
+import (
+	"context"
+
+	"github.com/sfomuseum/go-activitypub/uris"
+)
+
+// https://www.w3.org/TR/activitystreams-vocabulary/#dfn-reject
+
+// NewRejectActvity returns a new `Activity` instance of type "Reject".
+// The Reject activity "indicates that the actor is rejecting the object. The target and origin typically have no defined meaning."
+func NewRejectActivity(ctx context.Context, uris_table *uris.URIs, from string, object interface{}) (*Activity, error) {
+
+	ap_id := NewId(uris_table, "reject")
+
+	req := &Activity{
+		Context: []interface{}{
+			ACTIVITYSTREAMS_CONTEXT,
+		},
+		Id:     ap_id,
+		Type:   REJECT_ACTIVITY,
+		Actor:  from,
+		Object: object,
+	}
+
+	return req, nil
+}
@@ -5,4 +5,6 @@ type Tag struct {
 	Href string `json:"href"`
 	Name string `json:"name"`
 	Type string `json:"type"`
+	// Icon is the image for custom "Emoji" tags.
+	Icon *Icon `json:"icon,omitempty"`
 }
@@ -0,0 +1,17 @@
+package ap
+
+// Tombstone is an object that replaces an object that has been deleted.
+type Tombstone struct {
+	// The JSON-LD context for the tombstone. This is only necessary when the tombstone is not the object of another activity.
+	Context interface{} `json:"@context,omitempty"`
+	// Id is the unique identifier of the object that was deleted.
+	Id string `json:"id"`
+	// Type is the type of the object (aka "Tombstone").
+	Type string `json:"type"`
+	// The type of the object that was deleted, for example "Note".
+	FormerType string `json:"formerType,omitempty"`
+	// The date that the deleted object was published.
+	Published string `json:"published,omitempty"`
+	// The date that the object was deleted.
+	Deleted string `json:"deleted,omitempty"`
+}
@@ -0,0 +1,25 @@
+package ap
+
+import (
+	"context"
+
+	"github.com/sfomuseum/go-activitypub/uris"
+)
+
+// NewUpdateActivity returns a new `Activity` instance of type "Update".
+func NewUpdateActivity(ctx context.Context, uris_table *uris.URIs, from string, to []string, object interface{}) (*Activity, error) {
+
+	ap_id := NewId(uris_table, "update")
+
+	req := &Activity{
+		Context: ACTIVITYSTREAMS_CONTEXT,
+		Id:      ap_id,
+		Type:    UPDATE_ACTIVITY,
+		Actor:   from,
+		To:      to,
+		Object:  object,
+	}
+
+	return req, nil
+
+}
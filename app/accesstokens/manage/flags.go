@@ -0,0 +1,44 @@
+package manage
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sfomuseum/go-flags/flagset"
+	"github.com/sfomuseum/go-flags/multi"
+)
+
+var accounts_database_uri string
+var access_tokens_database_uri string
+
+var mode string
+var account_name string
+var label string
+var token_ids multi.MultiInt64
+
+var verbose bool
+
+func DefaultFlagSet() *flag.FlagSet {
+
+	fs := flagset.NewFlagSet("access-tokens")
+
+	fs.StringVar(&accounts_database_uri, "accounts-database-uri", "", "A known sfomuseum/go-activitypub/AccountsDatabase URI.")
+	fs.StringVar(&access_tokens_database_uri, "access-tokens-database-uri", "", "A known sfomuseum/go-activitypub/AccessTokensDatabase URI.")
+
+	fs.StringVar(&mode, "mode", "list", "The operation to perform. Valid options are: add, list, remove.")
+	fs.StringVar(&account_name, "account-name", "", "The name of the go-activitypub account whose access tokens are being managed.")
+	fs.StringVar(&label, "label", "", "An optional label describing the client (or person) using a new access token.")
+	fs.Var(&token_ids, "id", "One or more unique access token IDs to remove.")
+
+	fs.BoolVar(&verbose, "verbose", false, "Enable verbose (debug) logging.")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Add, list and remove the API tokens used to authenticate client-to-server (C2S) requests, like posting activities to an outbox, for registered go-activitypub accounts.\n")
+		fmt.Fprintf(os.Stderr, "Usage:\n\t %s [options]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Valid options are:\n")
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
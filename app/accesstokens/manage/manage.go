@@ -0,0 +1,137 @@
+package manage
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/database"
+)
+
+func Run(ctx context.Context) error {
+	fs := DefaultFlagSet()
+	return RunWithFlagSet(ctx, fs)
+}
+
+func RunWithFlagSet(ctx context.Context, fs *flag.FlagSet) error {
+
+	opts, err := OptionsFromFlagSet(ctx, fs)
+
+	if err != nil {
+		return fmt.Errorf("Failed to derive options from flagset, %w", err)
+	}
+
+	return RunWithOptions(ctx, opts)
+}
+
+func RunWithOptions(ctx context.Context, opts *RunOptions) error {
+
+	if opts.Verbose {
+		slog.SetLogLoggerLevel(slog.LevelDebug)
+		slog.Debug("Verbose logging enabled")
+	}
+
+	logger := slog.Default()
+
+	accounts_db, err := database.NewAccountsDatabase(ctx, opts.AccountsDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to initialize accounts database, %w", err)
+	}
+
+	defer accounts_db.Close(ctx)
+
+	access_tokens_db, err := database.NewAccessTokensDatabase(ctx, opts.AccessTokensDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to initialize access tokens database, %w", err)
+	}
+
+	defer access_tokens_db.Close(ctx)
+
+	if opts.AccountName == "" {
+		return fmt.Errorf("Missing account name")
+	}
+
+	acct, err := accounts_db.GetAccountWithName(ctx, opts.AccountName)
+
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve account %s, %w", opts.AccountName, err)
+	}
+
+	logger = logger.With("account", acct.Name)
+
+	switch opts.Mode {
+	case "add":
+
+		t, token, err := activitypub.NewAccessToken(ctx, acct)
+
+		if err != nil {
+			return fmt.Errorf("Failed to create new access token, %w", err)
+		}
+
+		t.Label = opts.Label
+
+		err = access_tokens_db.AddAccessToken(ctx, t)
+
+		if err != nil {
+			return fmt.Errorf("Failed to add access token, %w", err)
+		}
+
+		logger.Info("Added access token", "id", t.Id)
+
+		// The plain-text token is only ever displayed once, here
+
+		fmt.Fprintln(os.Stdout, token)
+
+	case "list":
+
+		tokens_cb := func(ctx context.Context, t *activitypub.AccessToken) error {
+			created := time.Unix(t.Created, 0)
+			fmt.Fprintf(os.Stdout, "%d\t%s\t%s\n", t.Id, created.Format(time.RFC3339), t.Label)
+			return nil
+		}
+
+		err := access_tokens_db.GetAccessTokensForAccount(ctx, acct.Id, tokens_cb)
+
+		if err != nil {
+			return fmt.Errorf("Failed to list access tokens for account %s, %w", opts.AccountName, err)
+		}
+
+	case "remove":
+
+		if len(opts.TokenIds) == 0 {
+			return fmt.Errorf("No access tokens to remove")
+		}
+
+		for _, id := range opts.TokenIds {
+
+			t, err := access_tokens_db.GetAccessTokenWithId(ctx, id)
+
+			if err != nil {
+				return fmt.Errorf("Failed to retrieve access token %d, %w", id, err)
+			}
+
+			if t.AccountId != acct.Id {
+				return fmt.Errorf("Access token %d does not belong to account %s", id, opts.AccountName)
+			}
+
+			err = access_tokens_db.RemoveAccessToken(ctx, t)
+
+			if err != nil {
+				return fmt.Errorf("Failed to remove access token %d, %w", id, err)
+			}
+
+			logger.Info("Removed access token", "id", id)
+		}
+
+	default:
+		return fmt.Errorf("Invalid or unsupported mode, %s", opts.Mode)
+	}
+
+	return nil
+}
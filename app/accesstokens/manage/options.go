@@ -0,0 +1,42 @@
+package manage
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/sfomuseum/go-flags/flagset"
+)
+
+type RunOptions struct {
+	AccountsDatabaseURI     string
+	AccessTokensDatabaseURI string
+	Mode                    string
+	AccountName             string
+	Label                   string
+	TokenIds                []int64
+	Verbose                 bool
+}
+
+func OptionsFromFlagSet(ctx context.Context, fs *flag.FlagSet) (*RunOptions, error) {
+
+	flagset.Parse(fs)
+
+	err := flagset.SetFlagsFromEnvVars(fs, "ACTIVITYPUB")
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive flags from environment variables, %w", err)
+	}
+
+	opts := &RunOptions{
+		AccountsDatabaseURI:     accounts_database_uri,
+		AccessTokensDatabaseURI: access_tokens_database_uri,
+		Mode:                    mode,
+		AccountName:             account_name,
+		Label:                   label,
+		TokenIds:                token_ids,
+		Verbose:                 verbose,
+	}
+
+	return opts, nil
+}
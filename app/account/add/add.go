@@ -207,16 +207,17 @@ func RunWithOptions(ctx context.Context, opts *RunOptions) error {
 	}
 
 	a := &activitypub.Account{
-		Id:            account_id,
-		Name:          opts.AccountName,
-		AccountType:   account_type,
-		DisplayName:   opts.DisplayName,
-		Blurb:         opts.Blurb,
-		Discoverable:  opts.Discoverable,
-		URL:           opts.URL,
-		PrivateKeyURI: private_key_uri,
-		PublicKeyURI:  public_key_uri,
-		IconURI:       icon_uri,
+		Id:                       account_id,
+		Name:                     opts.AccountName,
+		AccountType:              account_type,
+		DisplayName:              opts.DisplayName,
+		Blurb:                    opts.Blurb,
+		Discoverable:             opts.Discoverable,
+		ManuallyApproveFollowers: opts.ManuallyApproveFollowers,
+		URL:                      opts.URL,
+		PrivateKeyURI:            private_key_uri,
+		PublicKeyURI:             public_key_uri,
+		IconURI:                  icon_uri,
 	}
 
 	a, err = accounts.AddAccount(ctx, accounts_db, a)
@@ -29,6 +29,7 @@ var allow_remote_icon_uri bool
 var embed_icon_uri bool
 
 var discoverable bool
+var manually_approve_followers bool
 
 var aliases_list multi.MultiString
 var properties_kv multi.KeyValueString
@@ -52,6 +53,7 @@ func DefaultFlagSet() *flag.FlagSet {
 	fs.StringVar(&account_type, "account-type", "Person", "The type of account being created. Valid options are: Person, Service.")
 
 	fs.BoolVar(&discoverable, "discoverable", true, "Boolean flag indicating whether the account should be discoverable.")
+	fs.BoolVar(&manually_approve_followers, "manually-approve-followers", false, "Boolean flag indicating whether requests to follow the account need to be manually approved.")
 
 	fs.StringVar(&public_key_uri, "public-key-uri", "", "A valid `gocloud.dev/runtimevar` referencing the PEM-encoded public key for the account.")
 	fs.StringVar(&private_key_uri, "private-key-uri", "", "A valid `gocloud.dev/runtimevar` referencing the PEM-encoded private key for the account.")
@@ -9,23 +9,24 @@ import (
 )
 
 type RunOptions struct {
-	AccountsDatabaseURI   string
-	AliasesDatabaseURI    string
-	PropertiesDatabaseURI string
-	AccountId             int64
-	AccountName           string
-	Aliases               []string
-	AccountType           string
-	Discoverable          bool
-	DisplayName           string
-	Blurb                 string
-	URL                   string
-	PublicKeyURI          string
-	PrivateKeyURI         string
-	AccountIconURI        string
-	AllowRemoteIconURI    bool
-	EmbedIconURI          bool
-	Properties            map[string]string
+	AccountsDatabaseURI      string
+	AliasesDatabaseURI       string
+	PropertiesDatabaseURI    string
+	AccountId                int64
+	AccountName              string
+	Aliases                  []string
+	AccountType              string
+	Discoverable             bool
+	ManuallyApproveFollowers bool
+	DisplayName              string
+	Blurb                    string
+	URL                      string
+	PublicKeyURI             string
+	PrivateKeyURI            string
+	AccountIconURI           string
+	AllowRemoteIconURI       bool
+	EmbedIconURI             bool
+	Properties               map[string]string
 }
 
 func OptionsFromFlagSet(ctx context.Context, fs *flag.FlagSet) (*RunOptions, error) {
@@ -45,23 +46,24 @@ func OptionsFromFlagSet(ctx context.Context, fs *flag.FlagSet) (*RunOptions, err
 	}
 
 	opts := &RunOptions{
-		AccountsDatabaseURI:   accounts_database_uri,
-		AliasesDatabaseURI:    aliases_database_uri,
-		PropertiesDatabaseURI: properties_database_uri,
-		AccountId:             account_id,
-		AccountName:           account_name,
-		Aliases:               aliases_list,
-		AccountType:           account_type,
-		AccountIconURI:        account_icon_uri,
-		AllowRemoteIconURI:    allow_remote_icon_uri,
-		EmbedIconURI:          embed_icon_uri,
-		Discoverable:          discoverable,
-		DisplayName:           display_name,
-		Blurb:                 blurb,
-		URL:                   account_url,
-		PublicKeyURI:          public_key_uri,
-		PrivateKeyURI:         private_key_uri,
-		Properties:            properties_map,
+		AccountsDatabaseURI:      accounts_database_uri,
+		AliasesDatabaseURI:       aliases_database_uri,
+		PropertiesDatabaseURI:    properties_database_uri,
+		AccountId:                account_id,
+		AccountName:              account_name,
+		Aliases:                  aliases_list,
+		AccountType:              account_type,
+		AccountIconURI:           account_icon_uri,
+		AllowRemoteIconURI:       allow_remote_icon_uri,
+		EmbedIconURI:             embed_icon_uri,
+		Discoverable:             discoverable,
+		ManuallyApproveFollowers: manually_approve_followers,
+		DisplayName:              display_name,
+		Blurb:                    blurb,
+		URL:                      account_url,
+		PublicKeyURI:             public_key_uri,
+		PrivateKeyURI:            private_key_uri,
+		Properties:               properties_map,
 	}
 
 	return opts, nil
@@ -6,14 +6,16 @@ import (
 	"flag"
 	"fmt"
 	"log/slog"
+	"slices"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/sfomuseum/go-activitypub"
-	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/actors"
 	"github.com/sfomuseum/go-activitypub/database"
 	"github.com/sfomuseum/go-activitypub/deliver"
 	"github.com/sfomuseum/go-activitypub/followers"
+	"github.com/sfomuseum/go-activitypub/posts"
 	"github.com/sfomuseum/go-activitypub/queue"
 	"github.com/sfomuseum/go-pubsub/subscriber"
 )
@@ -91,6 +93,20 @@ func RunWithOptions(ctx context.Context, opts *RunOptions) error {
 
 	defer followers_db.Close(ctx)
 
+	actors_db, err := database.NewActorsDatabase(ctx, opts.ActorsDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to instantiate actors database, %w", err)
+	}
+
+	defer actors_db.Close(ctx)
+
+	actor_opts := &actors.RetrieveActorOptions{
+		ActorsDatabase: actors_db,
+		TTL:            opts.ActorsTTL,
+		Insecure:       opts.URIs.Insecure,
+	}
+
 	// START OF...
 
 	deliverActivityTo := func(ctx context.Context, activity_id int64, recipient string) error {
@@ -152,7 +168,7 @@ func RunWithOptions(ctx context.Context, opts *RunOptions) error {
 
 				logger.Debug("Check to see whether recipient is listed in post tags", "count tags", len(mentions))
 
-				r_actor, err := ap.RetrieveActor(ctx, recipient, opts.URIs.Insecure)
+				r_actor, err := actors.RetrieveActor(ctx, actor_opts, recipient)
 
 				if err != nil {
 					logger.Warn("Failed to retrieve actor record for recipient", "error", err)
@@ -199,6 +215,70 @@ func RunWithOptions(ctx context.Context, opts *RunOptions) error {
 				}
 			}
 
+		case activitypub.UpdateActivityType:
+
+			// Updates (edits) to posts are delivered to everyone who received the original post
+
+			if !is_allowed {
+
+				ap_activity, err := activity.UnmarshalActivity()
+
+				if err != nil {
+					logger.Error("Failed to unmarshal activity", "error", err)
+					return fmt.Errorf("Failed to unmarshal activity, %w", err)
+				}
+
+				object_uri := ""
+
+				switch obj := ap_activity.Object.(type) {
+				case map[string]interface{}:
+					object_uri, _ = obj["id"].(string)
+				case string:
+					object_uri = obj
+				}
+
+				post, err := posts.GetPostFromObjectURI(ctx, opts.URIs, posts_db, object_uri)
+
+				if err != nil {
+					logger.Error("Failed to derive post from update activity", "object", object_uri, "error", err)
+					return fmt.Errorf("Failed to derive post from update activity, %w", err)
+				}
+
+				create_activity, err := activities_db.GetActivityWithActivityTypeAndId(ctx, activitypub.PostActivityType, post.Id)
+
+				if err != nil && err != activitypub.ErrNotFound {
+					logger.Error("Failed to retrieve activity for post", "post id", post.Id, "error", err)
+					return fmt.Errorf("Failed to retrieve activity for post, %w", err)
+				}
+
+				if err == nil {
+
+					recipients, err := queue.ActivityRecipients(ctx, deliveries_db, create_activity.Id)
+
+					if err != nil {
+						logger.Error("Failed to derive recipients for post", "post id", post.Id, "error", err)
+						return fmt.Errorf("Failed to derive recipients for post, %w", err)
+					}
+
+					if slices.Contains(recipients, recipient) {
+						logger.Info("Recipient is not allowed/followed but received the original post", "post id", post.Id)
+						is_allowed = true
+					}
+				}
+			}
+
+		case activitypub.DeleteActivityType:
+
+			// Deletions are delivered to everyone who received the original post (or any updates to it).
+			// By the time a "Delete" activity is queued the post and its activities have already been
+			// removed so there is nothing left to check against; the list of recipients was derived
+			// from the deliveries database before the post was deleted.
+
+			if !is_allowed {
+				logger.Info("Recipient is not allowed/followed but received the deleted post")
+				is_allowed = true
+			}
+
 		default:
 			// pass
 		}
@@ -217,6 +297,8 @@ func RunWithOptions(ctx context.Context, opts *RunOptions) error {
 			DeliveriesDatabase: deliveries_db,
 			URIs:               opts.URIs,
 			MaxAttempts:        opts.MaxAttempts,
+			ActorsDatabase:     actors_db,
+			ActorsTTL:          opts.ActorsTTL,
 		}
 
 		logger.Debug("Deliver activity")
@@ -275,7 +357,7 @@ func RunWithOptions(ctx context.Context, opts *RunOptions) error {
 
 			logger = logger.With("post id", post.Id)
 
-			activity, err := posts.ActivityFromPost(ctx, opts.URIs, acct, post, mentions)
+			activity, err := posts.ActivityFromPost(ctx, opts.URIs, acct, post, mentions, nil)
 
 			if err != nil {
 				return fmt.Errorf("Failed to create new (create) activity, %w", err)
@@ -293,6 +375,8 @@ func RunWithOptions(ctx context.Context, opts *RunOptions) error {
 				Mentions:           mentions,
 				URIs:               opts.URIs,
 				MaxAttempts:        opts.MaxAttempts,
+				ActorsDatabase:     actors_db,
+				ActorsTTL:          opts.ActorsTTL,
 			}
 
 			logger.Debug("Deliver activity")
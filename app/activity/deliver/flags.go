@@ -14,6 +14,9 @@ var followers_database_uri string
 var posts_database_uri string
 var post_tags_database_uri string
 var deliveries_database_uri string
+var actors_database_uri string
+
+var actors_ttl int
 
 var subscriber_uri string
 
@@ -40,6 +43,8 @@ func DefaultFlagSet() *flag.FlagSet {
 	fs.StringVar(&posts_database_uri, "posts-database-uri", "", "A registered sfomuseum/go-activitypub/database.PostsDatabase URI.")
 	fs.StringVar(&post_tags_database_uri, "post-tags-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.PostTagsDatabase URI.")
 	fs.StringVar(&deliveries_database_uri, "deliveries-database-uri", "", "A registered sfomuseum/go-activitypub/database.DeliveriesDatabase URI.")
+	fs.StringVar(&actors_database_uri, "actors-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.ActorsDatabase URI used to cache remote actors.")
+	fs.IntVar(&actors_ttl, "actors-ttl", 86400, "The number of seconds that cached remote actors are considered valid. If 0 then cached actors never expire.")
 
 	fs.BoolVar(&allow_mentions, "allow-mentions", true, "Enable support for processing mentions in (post) activities. This enabled posts to accounts not followed by author but where account is mentioned in post.")
 	fs.StringVar(&delivery_queue_uri, "delivery-queue-uri", "synchronous://", "A registered sfomuseum/go-activitypub/queue.DeliveryQueue URI.")
@@ -4,6 +4,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"time"
 
 	"github.com/sfomuseum/go-activitypub/uris"
 	"github.com/sfomuseum/go-flags/flagset"
@@ -16,6 +17,8 @@ type RunOptions struct {
 	PostsDatabaseURI      string
 	PostTagsDatabaseURI   string
 	DeliveriesDatabaseURI string
+	ActorsDatabaseURI     string
+	ActorsTTL             time.Duration
 	DeliveryQueueURI      string
 	SubscriberURI         string
 	URIs                  *uris.URIs
@@ -48,6 +51,8 @@ func OptionsFromFlagSet(ctx context.Context, fs *flag.FlagSet) (*RunOptions, err
 		PostsDatabaseURI:      posts_database_uri,
 		PostTagsDatabaseURI:   post_tags_database_uri,
 		DeliveriesDatabaseURI: deliveries_database_uri,
+		ActorsDatabaseURI:     actors_database_uri,
+		ActorsTTL:             time.Duration(actors_ttl) * time.Second,
 		DeliveryQueueURI:      delivery_queue_uri,
 		MaxAttempts:           max_attempts,
 		Mode:                  mode,
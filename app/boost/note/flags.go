@@ -12,9 +12,13 @@ var accounts_database_uri string
 var activities_database_uri string
 var followers_database_uri string
 var deliveries_database_uri string
+var domain_blocks_database_uri string
+var domain_allows_database_uri string
 
 var delivery_queue_uri string
 
+var allowlist_mode bool
+
 var account_name string
 var note_uri string
 
@@ -30,9 +34,13 @@ func DefaultFlagSet() *flag.FlagSet {
 	fs.StringVar(&activities_database_uri, "activities-database-uri", "", "A known sfomuseum/go-activitypub/ActivitiesDatabase URI.")
 	fs.StringVar(&followers_database_uri, "followers-database-uri", "", "A known sfomuseum/go-activitypub/FollowersDatabase URI.")
 	fs.StringVar(&deliveries_database_uri, "deliveries-database-uri", "", "A known sfomuseum/go-activitypub/DeliveriesDatabase URI.")
+	fs.StringVar(&domain_blocks_database_uri, "domain-blocks-database-uri", "null://", "A known sfomuseum/go-activitypub/DomainBlocksDatabase URI. Boosts are not delivered to followers on suspended domains.")
+	fs.StringVar(&domain_allows_database_uri, "domain-allows-database-uri", "null://", "A known sfomuseum/go-activitypub/DomainAllowsDatabase URI. Only used if the -allowlist-mode flag is enabled.")
 
 	fs.StringVar(&delivery_queue_uri, "delivery-queue-uri", "synchronous://", "A known sfomuseum/go-activitypub/queue.DeliveryQueue URI.")
 
+	fs.BoolVar(&allowlist_mode, "allowlist-mode", false, "Only deliver boosts to followers on domains listed in the -domain-allows-database-uri database.")
+
 	fs.StringVar(&account_name, "account-name", "", "The account doing the boosting.")
 	fs.StringVar(&hostname, "hostname", "localhost:8080", "The hostname (domain) for the account doing the boosting.")
 	fs.BoolVar(&insecure, "insecure", false, "A boolean flag indicating the ActivityPub server delivering activities is insecure (not using TLS).")
@@ -70,6 +70,29 @@ func RunWithOptions(ctx context.Context, opts *RunOptions) error {
 
 	defer deliveries_db.Close(ctx)
 
+	domain_blocks_db, err := database.NewDomainBlocksDatabase(ctx, opts.DomainBlocksDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create instantiate domain blocks database, %w", err)
+	}
+
+	defer domain_blocks_db.Close(ctx)
+
+	// If running in allowlist mode activities are only delivered to followers on allowed domains
+
+	var domain_allows_db database.DomainAllowsDatabase
+
+	if opts.AllowlistMode {
+
+		domain_allows_db, err = database.NewDomainAllowsDatabase(ctx, opts.DomainAllowsDatabaseURI)
+
+		if err != nil {
+			return fmt.Errorf("Failed to create instantiate domain allows database, %w", err)
+		}
+
+		defer domain_allows_db.Close(ctx)
+	}
+
 	delivery_q, err := queue.NewDeliveryQueue(ctx, opts.DeliveryQueueURI)
 
 	if err != nil {
@@ -120,12 +143,14 @@ func RunWithOptions(ctx context.Context, opts *RunOptions) error {
 	}
 
 	deliver_opts := &queue.DeliverActivityToFollowersOptions{
-		AccountsDatabase:   accounts_db,
-		FollowersDatabase:  followers_db,
-		DeliveriesDatabase: deliveries_db,
-		DeliveryQueue:      delivery_q,
-		Activity:           activity,
-		URIs:               opts.URIs,
+		AccountsDatabase:     accounts_db,
+		FollowersDatabase:    followers_db,
+		DeliveriesDatabase:   deliveries_db,
+		DeliveryQueue:        delivery_q,
+		Activity:             activity,
+		URIs:                 opts.URIs,
+		DomainBlocksDatabase: domain_blocks_db,
+		DomainAllowsDatabase: domain_allows_db,
 	}
 
 	logger.Debug("Deliver activity")
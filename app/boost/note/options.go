@@ -10,15 +10,18 @@ import (
 )
 
 type RunOptions struct {
-	AccountsDatabaseURI   string
-	ActivitiesDatabaseURI string
-	FollowersDatabaseURI  string
-	DeliveriesDatabaseURI string
-	DeliveryQueueURI      string
-	AccountName           string
-	NoteURI               string
-	URIs                  *uris.URIs
-	Verbose               bool
+	AccountsDatabaseURI     string
+	ActivitiesDatabaseURI   string
+	FollowersDatabaseURI    string
+	DeliveriesDatabaseURI   string
+	DomainBlocksDatabaseURI string
+	DomainAllowsDatabaseURI string
+	DeliveryQueueURI        string
+	AllowlistMode           bool
+	AccountName             string
+	NoteURI                 string
+	URIs                    *uris.URIs
+	Verbose                 bool
 }
 
 func OptionsFromFlagSet(ctx context.Context, fs *flag.FlagSet) (*RunOptions, error) {
@@ -36,15 +39,18 @@ func OptionsFromFlagSet(ctx context.Context, fs *flag.FlagSet) (*RunOptions, err
 	uris_table.Insecure = insecure
 
 	opts := &RunOptions{
-		AccountsDatabaseURI:   accounts_database_uri,
-		ActivitiesDatabaseURI: activities_database_uri,
-		FollowersDatabaseURI:  followers_database_uri,
-		DeliveriesDatabaseURI: deliveries_database_uri,
-		DeliveryQueueURI:      delivery_queue_uri,
-		AccountName:           account_name,
-		NoteURI:               note_uri,
-		URIs:                  uris_table,
-		Verbose:               verbose,
+		AccountsDatabaseURI:     accounts_database_uri,
+		ActivitiesDatabaseURI:   activities_database_uri,
+		FollowersDatabaseURI:    followers_database_uri,
+		DeliveriesDatabaseURI:   deliveries_database_uri,
+		DomainBlocksDatabaseURI: domain_blocks_database_uri,
+		DomainAllowsDatabaseURI: domain_allows_database_uri,
+		AllowlistMode:           allowlist_mode,
+		DeliveryQueueURI:        delivery_queue_uri,
+		AccountName:             account_name,
+		NoteURI:                 note_uri,
+		URIs:                    uris_table,
+		Verbose:                 verbose,
 	}
 
 	return opts, nil
@@ -0,0 +1,135 @@
+package allowlist
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/database"
+)
+
+func Run(ctx context.Context) error {
+	fs := DefaultFlagSet()
+	return RunWithFlagSet(ctx, fs)
+}
+
+func RunWithFlagSet(ctx context.Context, fs *flag.FlagSet) error {
+
+	opts, err := OptionsFromFlagSet(ctx, fs)
+
+	if err != nil {
+		return fmt.Errorf("Failed to derive options from flagset, %w", err)
+	}
+
+	return RunWithOptions(ctx, opts)
+}
+
+func RunWithOptions(ctx context.Context, opts *RunOptions) error {
+
+	if opts.Verbose {
+		slog.SetLogLoggerLevel(slog.LevelDebug)
+		slog.Debug("Verbose logging enabled")
+	}
+
+	logger := slog.Default()
+
+	domain_allows_db, err := database.NewDomainAllowsDatabase(ctx, opts.DomainAllowsDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to initialize domain allows database, %w", err)
+	}
+
+	defer domain_allows_db.Close(ctx)
+
+	switch opts.Mode {
+	case "list":
+
+		allows_cb := func(ctx context.Context, a *activitypub.DomainAllow) error {
+			fmt.Fprintf(os.Stdout, "%s\t%s\n", a.Domain, a.Comment)
+			return nil
+		}
+
+		err := domain_allows_db.GetDomainAllows(ctx, allows_cb)
+
+		if err != nil {
+			return fmt.Errorf("Failed to list domain allows, %w", err)
+		}
+
+	case "add":
+
+		if len(opts.Domains) == 0 {
+			return fmt.Errorf("No domains to add")
+		}
+
+		for _, domain := range opts.Domains {
+
+			domain = strings.ToLower(strings.TrimSpace(domain))
+
+			_, err := domain_allows_db.GetDomainAllowWithDomain(ctx, domain)
+
+			if err == nil {
+				logger.Info("Domain is already allowed, skipping", "domain", domain)
+				continue
+			}
+
+			if err != activitypub.ErrNotFound {
+				return fmt.Errorf("Failed to retrieve domain allow for %s, %w", domain, err)
+			}
+
+			a, err := activitypub.NewDomainAllow(ctx, domain)
+
+			if err != nil {
+				return fmt.Errorf("Failed to create domain allow for %s, %w", domain, err)
+			}
+
+			a.Comment = opts.Comment
+
+			err = domain_allows_db.AddDomainAllow(ctx, a)
+
+			if err != nil {
+				return fmt.Errorf("Failed to add domain allow for %s, %w", domain, err)
+			}
+
+			logger.Info("Added domain to allowlist", "domain", domain)
+		}
+
+	case "remove":
+
+		if len(opts.Domains) == 0 {
+			return fmt.Errorf("No domains to remove")
+		}
+
+		for _, domain := range opts.Domains {
+
+			domain = strings.ToLower(strings.TrimSpace(domain))
+
+			a, err := domain_allows_db.GetDomainAllowWithDomain(ctx, domain)
+
+			if err == activitypub.ErrNotFound {
+				logger.Info("Domain is not allowed, skipping", "domain", domain)
+				continue
+			}
+
+			if err != nil {
+				return fmt.Errorf("Failed to retrieve domain allow for %s, %w", domain, err)
+			}
+
+			err = domain_allows_db.RemoveDomainAllow(ctx, a)
+
+			if err != nil {
+				return fmt.Errorf("Failed to remove domain allow for %s, %w", domain, err)
+			}
+
+			logger.Info("Removed domain from allowlist", "domain", domain)
+		}
+
+	default:
+		return fmt.Errorf("Invalid or unsupported mode, %s", opts.Mode)
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package allowlist
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sfomuseum/go-flags/flagset"
+	"github.com/sfomuseum/go-flags/multi"
+)
+
+var domain_allows_database_uri string
+
+var mode string
+var domains multi.MultiString
+var comment string
+
+var verbose bool
+
+func DefaultFlagSet() *flag.FlagSet {
+
+	fs := flagset.NewFlagSet("allowlist")
+
+	fs.StringVar(&domain_allows_database_uri, "domain-allows-database-uri", "", "A known sfomuseum/go-activitypub/DomainAllowsDatabase URI.")
+
+	fs.StringVar(&mode, "mode", "list", "The operation to perform. Valid options are: add, remove, list.")
+	fs.Var(&domains, "domain", "One or more domains to add to (or remove from) the allowlist. Allowing a domain also allows all of its subdomains.")
+	fs.StringVar(&comment, "comment", "", "An optional comment to associate with domains added to the allowlist.")
+
+	fs.BoolVar(&verbose, "verbose", false, "Enable verbose (debug) logging.")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Manage the list of remote domains a go-activitypub server, running in allowlist mode, is allowed to federate with.\n")
+		fmt.Fprintf(os.Stderr, "Usage:\n\t %s [options]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Valid options are:\n")
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
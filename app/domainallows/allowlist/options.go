@@ -0,0 +1,38 @@
+package allowlist
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/sfomuseum/go-flags/flagset"
+)
+
+type RunOptions struct {
+	DomainAllowsDatabaseURI string
+	Mode                    string
+	Domains                 []string
+	Comment                 string
+	Verbose                 bool
+}
+
+func OptionsFromFlagSet(ctx context.Context, fs *flag.FlagSet) (*RunOptions, error) {
+
+	flagset.Parse(fs)
+
+	err := flagset.SetFlagsFromEnvVars(fs, "ACTIVITYPUB")
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive flags from environment variables, %w", err)
+	}
+
+	opts := &RunOptions{
+		DomainAllowsDatabaseURI: domain_allows_database_uri,
+		Mode:                    mode,
+		Domains:                 domains,
+		Comment:                 comment,
+		Verbose:                 verbose,
+	}
+
+	return opts, nil
+}
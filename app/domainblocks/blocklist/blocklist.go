@@ -0,0 +1,104 @@
+package blocklist
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/sfomuseum/go-activitypub/blocks"
+	"github.com/sfomuseum/go-activitypub/database"
+)
+
+func Run(ctx context.Context) error {
+	fs := DefaultFlagSet()
+	return RunWithFlagSet(ctx, fs)
+}
+
+func RunWithFlagSet(ctx context.Context, fs *flag.FlagSet) error {
+
+	opts, err := OptionsFromFlagSet(ctx, fs)
+
+	if err != nil {
+		return fmt.Errorf("Failed to derive options from flagset, %w", err)
+	}
+
+	return RunWithOptions(ctx, opts)
+}
+
+func RunWithOptions(ctx context.Context, opts *RunOptions) error {
+
+	if opts.Verbose {
+		slog.SetLogLoggerLevel(slog.LevelDebug)
+		slog.Debug("Verbose logging enabled")
+	}
+
+	logger := slog.Default()
+
+	domain_blocks_db, err := database.NewDomainBlocksDatabase(ctx, opts.DomainBlocksDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to initialize domain blocks database, %w", err)
+	}
+
+	defer domain_blocks_db.Close(ctx)
+
+	switch opts.Mode {
+	case "import":
+
+		var r io.Reader
+
+		if opts.Path == "-" {
+			r = os.Stdin
+		} else {
+
+			fh, err := os.Open(opts.Path)
+
+			if err != nil {
+				return fmt.Errorf("Failed to open %s for reading, %w", opts.Path, err)
+			}
+
+			defer fh.Close()
+			r = fh
+		}
+
+		count, err := blocks.ImportMastodonBlocklist(ctx, domain_blocks_db, r, opts.Overwrite)
+
+		if err != nil {
+			return fmt.Errorf("Failed to import blocklist, %w", err)
+		}
+
+		logger.Info("Imported domain blocks", "count", count)
+
+	case "export":
+
+		var wr io.Writer
+
+		if opts.Path == "-" {
+			wr = os.Stdout
+		} else {
+
+			fh, err := os.Create(opts.Path)
+
+			if err != nil {
+				return fmt.Errorf("Failed to open %s for writing, %w", opts.Path, err)
+			}
+
+			defer fh.Close()
+			wr = fh
+		}
+
+		err := blocks.ExportMastodonBlocklist(ctx, domain_blocks_db, wr)
+
+		if err != nil {
+			return fmt.Errorf("Failed to export blocklist, %w", err)
+		}
+
+	default:
+		return fmt.Errorf("Invalid or unsupported mode, %s", opts.Mode)
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package blocklist
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sfomuseum/go-flags/flagset"
+)
+
+var domain_blocks_database_uri string
+
+var mode string
+var path string
+var overwrite bool
+
+var verbose bool
+
+func DefaultFlagSet() *flag.FlagSet {
+
+	fs := flagset.NewFlagSet("blocklist")
+
+	fs.StringVar(&domain_blocks_database_uri, "domain-blocks-database-uri", "", "A known sfomuseum/go-activitypub/DomainBlocksDatabase URI.")
+
+	fs.StringVar(&mode, "mode", "export", "The operation to perform. Valid options are: import, export.")
+	fs.StringVar(&path, "path", "-", "The path of the CSV file to import from (or export to). If \"-\" then data will be read from STDIN (or written to STDOUT).")
+	fs.BoolVar(&overwrite, "overwrite", false, "When importing, update domain blocks that already exist rather than skipping them.")
+
+	fs.BoolVar(&verbose, "verbose", false, "Enable verbose (debug) logging.")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Import (or export) server-wide domain blocks using the CSV blocklist format shared by Mastodon administrators (#domain,#severity,#reject_media,#reject_reports,#public_comment,#obfuscate).\n")
+		fmt.Fprintf(os.Stderr, "Usage:\n\t %s [options]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Valid options are:\n")
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
@@ -0,0 +1,38 @@
+package blocklist
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/sfomuseum/go-flags/flagset"
+)
+
+type RunOptions struct {
+	DomainBlocksDatabaseURI string
+	Mode                    string
+	Path                    string
+	Overwrite               bool
+	Verbose                 bool
+}
+
+func OptionsFromFlagSet(ctx context.Context, fs *flag.FlagSet) (*RunOptions, error) {
+
+	flagset.Parse(fs)
+
+	err := flagset.SetFlagsFromEnvVars(fs, "ACTIVITYPUB")
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive flags from environment variables, %w", err)
+	}
+
+	opts := &RunOptions{
+		DomainBlocksDatabaseURI: domain_blocks_database_uri,
+		Mode:                    mode,
+		Path:                    path,
+		Overwrite:               overwrite,
+		Verbose:                 verbose,
+	}
+
+	return opts, nil
+}
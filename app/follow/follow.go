@@ -5,6 +5,7 @@ import (
 	"flag"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/sfomuseum/go-activitypub"
 	"github.com/sfomuseum/go-activitypub/ap"
@@ -101,6 +102,55 @@ func RunWithOptions(ctx context.Context, opts *RunOptions) error {
 		return fmt.Errorf("Failed to create follow activity, %w", err)
 	}
 
+	// Register (or update) the following record, in a pending state, before the follow
+	// activity is sent since the remote server may respond with an "Accept" activity
+	// before SendActivity returns. The record will be updated when that "Accept" (or
+	// "Reject") activity is received.
+
+	if !undo {
+
+		f, err := following_db.GetFollowing(ctx, follower_id, following_address)
+
+		switch {
+		case err == activitypub.ErrNotFound:
+
+			f, err = activitypub.NewFollowing(ctx, follower_id, following_address)
+
+			if err != nil {
+				return fmt.Errorf("Failed to create new following, %w", err)
+			}
+
+			f.ActivityId = activity.Id
+
+			err = following_db.AddFollowing(ctx, f)
+
+			if err != nil {
+				return fmt.Errorf("Failed to register following locally, %w", err)
+			}
+
+		case err != nil:
+			return fmt.Errorf("Failed to retrieve following, %w", err)
+		default:
+
+			if f.IsAccepted() {
+				logger.Info("Already following")
+				return nil
+			}
+
+			f.ActivityId = activity.Id
+			f.State = activitypub.PendingFollowingState
+			f.LastModified = time.Now().Unix()
+
+			err = following_db.UpdateFollowing(ctx, f)
+
+			if err != nil {
+				return fmt.Errorf("Failed to update following locally, %w", err)
+			}
+		}
+
+		logger = logger.With("following id", f.Id)
+	}
+
 	err = follower_acct.SendActivity(ctx, opts.URIs, following_inbox, activity)
 
 	if err != nil {
@@ -132,18 +182,6 @@ func RunWithOptions(ctx context.Context, opts *RunOptions) error {
 		return nil
 	}
 
-	f, err := activitypub.NewFollowing(ctx, follower_id, following_address)
-
-	if err != nil {
-		return fmt.Errorf("Failed to create new following, %w", err)
-	}
-
-	err = following_db.AddFollowing(ctx, f)
-
-	if err != nil {
-		return fmt.Errorf("Follow request was successful but unable to register following locally, %w", err)
-	}
-
-	logger.Info("Following successful")
+	logger.Info("Follow request successful, waiting for remote server to accept or reject request")
 	return nil
 }
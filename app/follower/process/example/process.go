@@ -120,7 +120,7 @@ func RunWithOptions(ctx context.Context, opts *RunOptions) error {
 
 		logger.Info("Process follow")
 
-		// Message is the thing which was dispatched by the process message queue (in www/inbox_post.go)
+		// Message is the thing which was dispatched by the process message queue (in www/inbox_follow.go)
 
 		f, err := followers_db.GetFollowerWithId(ctx, follower_id)
 
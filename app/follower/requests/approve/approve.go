@@ -0,0 +1,156 @@
+package approve
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+
+	"github.com/sfomuseum/go-activitypub/database"
+	"github.com/sfomuseum/go-activitypub/followers"
+	"github.com/sfomuseum/go-activitypub/queue"
+)
+
+func Run(ctx context.Context) error {
+	fs := DefaultFlagSet()
+	return RunWithFlagSet(ctx, fs)
+}
+
+func RunWithFlagSet(ctx context.Context, fs *flag.FlagSet) error {
+
+	opts, err := OptionsFromFlagSet(ctx, fs)
+
+	if err != nil {
+		return fmt.Errorf("Failed to derive options from flagset, %w", err)
+	}
+
+	return RunWithOptions(ctx, opts)
+}
+
+func RunWithOptions(ctx context.Context, opts *RunOptions) error {
+
+	if opts.Verbose {
+		slog.SetLogLoggerLevel(slog.LevelDebug)
+		slog.Debug("Verbose logging enabled")
+	}
+
+	logger := slog.Default()
+
+	accounts_db, err := database.NewAccountsDatabase(ctx, opts.AccountsDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to initialize accounts database, %w", err)
+	}
+
+	defer accounts_db.Close(ctx)
+
+	followers_db, err := database.NewFollowersDatabase(ctx, opts.FollowersDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to initialize followers database, %w", err)
+	}
+
+	defer followers_db.Close(ctx)
+
+	follow_requests_db, err := database.NewFollowRequestsDatabase(ctx, opts.FollowRequestsDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to initialize follow requests database, %w", err)
+	}
+
+	defer follow_requests_db.Close(ctx)
+
+	domain_blocks_db, err := database.NewDomainBlocksDatabase(ctx, opts.DomainBlocksDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to initialize domain blocks database, %w", err)
+	}
+
+	defer domain_blocks_db.Close(ctx)
+
+	deliveries_db, err := database.NewDeliveriesDatabase(ctx, opts.DeliveriesDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to initialize deliveries database, %w", err)
+	}
+
+	defer deliveries_db.Close(ctx)
+
+	// If running in allowlist mode activities are only delivered to actors on allowed domains
+
+	var domain_allows_db database.DomainAllowsDatabase
+
+	if opts.AllowlistMode {
+
+		domain_allows_db, err = database.NewDomainAllowsDatabase(ctx, opts.DomainAllowsDatabaseURI)
+
+		if err != nil {
+			return fmt.Errorf("Failed to initialize domain allows database, %w", err)
+		}
+
+		defer domain_allows_db.Close(ctx)
+	}
+
+	process_follower_queue, err := queue.NewProcessFollowerQueue(ctx, opts.ProcessFollowerQueueURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to initialize process follower queue, %w", err)
+	}
+
+	delivery_q, err := queue.NewDeliveryQueue(ctx, opts.DeliveryQueueURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to initialize delivery queue, %w", err)
+	}
+
+	logger = logger.With("account", opts.AccountName)
+	logger = logger.With("request id", opts.RequestId)
+
+	acct, err := accounts_db.GetAccountWithName(ctx, opts.AccountName)
+
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve account %s, %w", opts.AccountName, err)
+	}
+
+	r, err := follow_requests_db.GetFollowRequestWithId(ctx, opts.RequestId)
+
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve follow request %d, %w", opts.RequestId, err)
+	}
+
+	logger = logger.With("follower", r.FollowerAddress)
+
+	follow_opts := &followers.FollowRequestOptions{
+		FollowersDatabase:      followers_db,
+		FollowRequestsDatabase: follow_requests_db,
+		ProcessFollowerQueue:   process_follower_queue,
+		URIs:                   opts.URIs,
+		DomainBlocksDatabase:   domain_blocks_db,
+		DomainAllowsDatabase:   domain_allows_db,
+		AccountsDatabase:       accounts_db,
+		DeliveriesDatabase:     deliveries_db,
+		DeliveryQueue:          delivery_q,
+		MaxAttempts:            opts.MaxAttempts,
+	}
+
+	if opts.Reject {
+
+		err = followers.RejectFollowRequest(ctx, follow_opts, acct, r)
+
+		if err != nil {
+			return fmt.Errorf("Failed to reject follow request, %w", err)
+		}
+
+		logger.Info("Follow request rejected")
+		return nil
+	}
+
+	err = followers.ApproveFollowRequest(ctx, follow_opts, acct, r)
+
+	if err != nil {
+		return fmt.Errorf("Failed to approve follow request, %w", err)
+	}
+
+	logger.Info("Follow request approved")
+	return nil
+}
@@ -0,0 +1,69 @@
+package approve
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sfomuseum/go-flags/flagset"
+)
+
+var hostname string
+var insecure bool
+
+var accounts_database_uri string
+var followers_database_uri string
+var follow_requests_database_uri string
+var domain_blocks_database_uri string
+var domain_allows_database_uri string
+var deliveries_database_uri string
+
+var process_follower_queue_uri string
+var delivery_queue_uri string
+
+var allowlist_mode bool
+var max_attempts int
+
+var account_name string
+var request_id int64
+
+var reject bool
+var verbose bool
+
+func DefaultFlagSet() *flag.FlagSet {
+
+	fs := flagset.NewFlagSet("approve")
+
+	fs.StringVar(&accounts_database_uri, "accounts-database-uri", "", "A registered sfomuseum/go-activitypub/AccountsDatabase URI.")
+	fs.StringVar(&followers_database_uri, "followers-database-uri", "", "A registered sfomuseum/go-activitypub/FollowersDatabase URI.")
+	fs.StringVar(&follow_requests_database_uri, "follow-requests-database-uri", "", "A registered sfomuseum/go-activitypub/FollowRequestsDatabase URI.")
+	fs.StringVar(&domain_blocks_database_uri, "domain-blocks-database-uri", "null://", "A registered sfomuseum/go-activitypub/DomainBlocksDatabase URI. Follow requests from suspended domains can not be approved.")
+
+	fs.StringVar(&domain_allows_database_uri, "domain-allows-database-uri", "null://", "A registered sfomuseum/go-activitypub/DomainAllowsDatabase URI. Only used if the -allowlist-mode flag is enabled.")
+	fs.StringVar(&deliveries_database_uri, "deliveries-database-uri", "null://", "A registered sfomuseum/go-activitypub/DeliveriesDatabase URI.")
+
+	fs.StringVar(&process_follower_queue_uri, "process-follower-queue-uri", "null://", "A registered sfomuseum/go-activitypub/queue.ProcessFollowerQueue URI.")
+	fs.StringVar(&delivery_queue_uri, "delivery-queue-uri", "synchronous://", "A registered sfomuseum/go-activitypub/queue.DeliveryQueue URI used to deliver \"Accept\" and \"Reject\" activities.")
+
+	fs.BoolVar(&allowlist_mode, "allowlist-mode", false, "Only deliver \"Accept\" and \"Reject\" activities to actors on domains listed in the -domain-allows-database-uri database.")
+	fs.IntVar(&max_attempts, "max-attempts", 5, "The maximum number of attempts to deliver the \"Accept\" or \"Reject\" activity.")
+
+	fs.StringVar(&account_name, "account-name", "", "The name of the account that received the follow request.")
+	fs.Int64Var(&request_id, "request-id", 0, "The unique ID of the pending follow request to approve.")
+
+	fs.BoolVar(&reject, "reject", false, "Reject (rather than approve) the follow request defined by the -request-id flag.")
+
+	fs.StringVar(&hostname, "hostname", "localhost:8080", "The hostname (domain) of the ActivityPub server delivering activities.")
+	fs.BoolVar(&insecure, "insecure", false, "A boolean flag indicating the ActivityPub server delivering activities is insecure (not using TLS).")
+
+	fs.BoolVar(&verbose, "verbose", false, "Enable verbose (debug) logging.")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Approve (or reject) a pending follow request for a registered go-activitypub account that manually approves followers.\n")
+		fmt.Fprintf(os.Stderr, "Usage:\n\t %s [options]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Valid options are:\n")
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
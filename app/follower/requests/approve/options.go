@@ -0,0 +1,63 @@
+package approve
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/sfomuseum/go-activitypub/uris"
+	"github.com/sfomuseum/go-flags/flagset"
+)
+
+type RunOptions struct {
+	AccountsDatabaseURI       string
+	FollowersDatabaseURI      string
+	FollowRequestsDatabaseURI string
+	DomainBlocksDatabaseURI   string
+	DomainAllowsDatabaseURI   string
+	DeliveriesDatabaseURI     string
+	ProcessFollowerQueueURI   string
+	DeliveryQueueURI          string
+	AllowlistMode             bool
+	MaxAttempts               int
+	AccountName               string
+	RequestId                 int64
+	Reject                    bool
+	URIs                      *uris.URIs
+	Verbose                   bool
+}
+
+func OptionsFromFlagSet(ctx context.Context, fs *flag.FlagSet) (*RunOptions, error) {
+
+	flagset.Parse(fs)
+
+	err := flagset.SetFlagsFromEnvVars(fs, "ACTIVITYPUB")
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive flags from environment variables, %w", err)
+	}
+
+	uris_table := uris.DefaultURIs()
+	uris_table.Hostname = hostname
+	uris_table.Insecure = insecure
+
+	opts := &RunOptions{
+		AccountsDatabaseURI:       accounts_database_uri,
+		FollowersDatabaseURI:      followers_database_uri,
+		FollowRequestsDatabaseURI: follow_requests_database_uri,
+		DomainBlocksDatabaseURI:   domain_blocks_database_uri,
+		DomainAllowsDatabaseURI:   domain_allows_database_uri,
+		DeliveriesDatabaseURI:     deliveries_database_uri,
+		ProcessFollowerQueueURI:   process_follower_queue_uri,
+		DeliveryQueueURI:          delivery_queue_uri,
+		AllowlistMode:             allowlist_mode,
+		MaxAttempts:               max_attempts,
+		AccountName:               account_name,
+		RequestId:                 request_id,
+		Reject:                    reject,
+		URIs:                      uris_table,
+		Verbose:                   verbose,
+	}
+
+	return opts, nil
+}
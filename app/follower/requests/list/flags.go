@@ -0,0 +1,37 @@
+package list
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sfomuseum/go-flags/flagset"
+)
+
+var accounts_database_uri string
+var follow_requests_database_uri string
+
+var account_name string
+
+var verbose bool
+
+func DefaultFlagSet() *flag.FlagSet {
+
+	fs := flagset.NewFlagSet("requests")
+
+	fs.StringVar(&accounts_database_uri, "accounts-database-uri", "", "A registered sfomuseum/go-activitypub/AccountsDatabase URI.")
+	fs.StringVar(&follow_requests_database_uri, "follow-requests-database-uri", "", "A registered sfomuseum/go-activitypub/FollowRequestsDatabase URI.")
+
+	fs.StringVar(&account_name, "account-name", "", "The name of the account whose pending follow requests you want to list.")
+
+	fs.BoolVar(&verbose, "verbose", false, "Enable verbose (debug) logging.")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "List all the pending follow requests for a registered go-activitypub account that manually approves followers.\n")
+		fmt.Fprintf(os.Stderr, "Usage:\n\t %s [options]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Valid options are:\n")
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
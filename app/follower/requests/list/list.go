@@ -0,0 +1,75 @@
+package list
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/database"
+)
+
+func Run(ctx context.Context) error {
+	fs := DefaultFlagSet()
+	return RunWithFlagSet(ctx, fs)
+}
+
+func RunWithFlagSet(ctx context.Context, fs *flag.FlagSet) error {
+
+	opts, err := OptionsFromFlagSet(ctx, fs)
+
+	if err != nil {
+		return fmt.Errorf("Failed to derive options from flagset, %w", err)
+	}
+
+	return RunWithOptions(ctx, opts)
+}
+
+func RunWithOptions(ctx context.Context, opts *RunOptions) error {
+
+	if opts.Verbose {
+		slog.SetLogLoggerLevel(slog.LevelDebug)
+		slog.Debug("Verbose logging enabled")
+	}
+
+	logger := slog.Default()
+
+	accounts_db, err := database.NewAccountsDatabase(ctx, opts.AccountsDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to initialize accounts database, %w", err)
+	}
+
+	defer accounts_db.Close(ctx)
+
+	follow_requests_db, err := database.NewFollowRequestsDatabase(ctx, opts.FollowRequestsDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to initialize follow requests database, %w", err)
+	}
+
+	defer follow_requests_db.Close(ctx)
+
+	logger = logger.With("account", opts.AccountName)
+
+	acct, err := accounts_db.GetAccountWithName(ctx, opts.AccountName)
+
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve account %s, %w", opts.AccountName, err)
+	}
+
+	cb := func(ctx context.Context, r *activitypub.FollowRequest) error {
+
+		logger.Info("Follow request", "id", r.Id, "follower", r.FollowerAddress, "activity", r.ActivityId, "created", r.Created)
+		return nil
+	}
+
+	err = follow_requests_db.GetFollowRequestsForAccount(ctx, acct.Id, cb)
+
+	if err != nil {
+		return fmt.Errorf("Failed to get follow requests, %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package process
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sfomuseum/go-flags/flagset"
+	"github.com/sfomuseum/go-flags/multi"
+)
+
+var mode string
+var queued_activity_ids multi.MultiInt64
+var subscriber_uri string
+
+var accounts_database_uri string
+var followers_database_uri string
+var following_database_uri string
+var notes_database_uri string
+var messages_database_uri string
+var posts_database_uri string
+var blocks_database_uri string
+var domain_blocks_database_uri string
+var likes_database_uri string
+var boosts_database_uri string
+var follow_requests_database_uri string
+var actors_database_uri string
+var received_activities_database_uri string
+var reports_database_uri string
+var queued_activities_database_uri string
+var deliveries_database_uri string
+
+var actors_ttl int
+
+var process_message_queue_uri string
+var process_follower_queue_uri string
+var delivery_queue_uri string
+
+var max_attempts int
+
+var allow_follow bool
+var allow_create bool
+var allow_likes bool
+var allow_boosts bool
+
+// Allows posts to accounts not followed by author but where account is mentioned in post
+var allow_mentions bool
+
+var hostname string
+var insecure bool
+var verbose bool
+
+func DefaultFlagSet() *flag.FlagSet {
+
+	fs := flagset.NewFlagSet("activitypub")
+
+	fs.StringVar(&mode, "mode", "cli", "The operation mode for processing queued activities. Valid options are: cli, lambda, pubsub.")
+	fs.Var(&queued_activity_ids, "id", "Zero or more unique queued activity IDs to process (if -mode=cli). If empty then all the activities in the queued activities database will be processed.")
+	fs.StringVar(&subscriber_uri, "subscriber-uri", "", "A valid sfomuseum/go-pubsub/subscriber URI. Required if -mode parameter is 'pubsub'.")
+
+	fs.StringVar(&accounts_database_uri, "accounts-database-uri", "", "A registered sfomuseum/go-activitypub/database.AccountsDatabase URI.")
+	fs.StringVar(&followers_database_uri, "followers-database-uri", "", "A registered sfomuseum/go-activitypub/database.FollowersDatabase URI.")
+	fs.StringVar(&following_database_uri, "following-database-uri", "", "A registered sfomuseum/go-activitypub/database.FollowingDatabase URI.")
+	fs.StringVar(&notes_database_uri, "notes-database-uri", "", "A registered sfomuseum/go-activitypub/database.NotesDatabase URI.")
+	fs.StringVar(&messages_database_uri, "messages-database-uri", "", "A registered sfomuseum/go-activitypub/database.MessagesDatabase URI.")
+	fs.StringVar(&posts_database_uri, "posts-database-uri", "", "A registered sfomuseum/go-activitypub/database.PostsDatabase URI.")
+	fs.StringVar(&blocks_database_uri, "blocks-database-uri", "", "A registered sfomuseum/go-activitypub/database.BlocksDatabase URI.")
+	fs.StringVar(&domain_blocks_database_uri, "domain-blocks-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.DomainBlocksDatabase URI used to store server-wide blocks for remote domains.")
+	fs.StringVar(&likes_database_uri, "likes-database-uri", "", "A registered sfomuseum/go-activitypub/database.LikesDatabase URI.")
+	fs.StringVar(&boosts_database_uri, "boosts-database-uri", "", "A registered sfomuseum/go-activitypub/database.BoostsDatabase URI.")
+	fs.StringVar(&follow_requests_database_uri, "follow-requests-database-uri", "", "A registered sfomuseum/go-activitypub/database.FollowRequestsDatabase URI.")
+	fs.StringVar(&actors_database_uri, "actors-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.ActorsDatabase URI used to cache remote actors.")
+	fs.StringVar(&received_activities_database_uri, "received-activities-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.ReceivedActivitiesDatabase URI used to log activities posted to account inboxes and to ignore activities that have already been accepted.")
+	fs.StringVar(&reports_database_uri, "reports-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.ReportsDatabase URI used to store moderation reports (\"Flag\" activities) received about local accounts.")
+	fs.StringVar(&queued_activities_database_uri, "queued-activities-database-uri", "", "A registered sfomuseum/go-activitypub/database.QueuedActivitiesDatabase URI where activities queued by the server tool are stored.")
+	fs.StringVar(&deliveries_database_uri, "deliveries-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.DeliveriesDatabase URI used to log the delivery of activities sent while processing inbox activities (for example the \"Follow\" activity sent when a followed actor moves).")
+	fs.IntVar(&actors_ttl, "actors-ttl", 86400, "The number of seconds that cached remote actors are considered valid. If 0 then cached actors never expire.")
+
+	fs.StringVar(&process_message_queue_uri, "process-message-queue-uri", "null://", "A registered go-activitypub/queue.ProcessMessageQueue URI.")
+	fs.StringVar(&process_follower_queue_uri, "process-follower-queue-uri", "null://", "A registered go-activitypub/queue.ProcessFollowerQueue URI.")
+	fs.StringVar(&delivery_queue_uri, "delivery-queue-uri", "synchronous://", "A registered sfomuseum/go-activitypub/queue.DeliveryQueue URI used to deliver activities sent while processing inbox activities.")
+	fs.IntVar(&max_attempts, "max-attempts", 5, "The maximum number of attempts to deliver activities sent while processing inbox activities.")
+
+	fs.BoolVar(&allow_follow, "allow-follow", true, "Enable support for ActivityPub \"Follow\" and \"Move\" (account migration) activities.")
+	fs.BoolVar(&allow_create, "allow-create", false, "Enable support for ActivityPub \"Create\" activities.")
+	fs.BoolVar(&allow_likes, "allow-likes", true, "Enable support for ActivityPub \"Like\" and \"EmojiReact\" (emoji reaction) activities.")
+	fs.BoolVar(&allow_boosts, "allow-boosts", true, "Enable support for ActivityPub \"Announce\" (boost) activities.")
+	fs.BoolVar(&allow_mentions, "allow-mentions", true, "If enabled allows posts (\"Create\" activities) to accounts not followed by author but where account is mentioned in post.")
+
+	fs.StringVar(&hostname, "hostname", "localhost:8080", "The hostname (domain) of the ActivityPub server that queued the activities.")
+	fs.BoolVar(&insecure, "insecure", false, "A boolean flag indicating the ActivityPub server that queued the activities is insecure (not using TLS).")
+	fs.BoolVar(&verbose, "verbose", false, "Enable verbose (debug) logging.")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Process activities posted to account inboxes that have been queued by the server tool (using its -inbox-queue-uri flag).\n")
+		fmt.Fprintf(os.Stderr, "Usage:\n\t %s [options]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Valid options are:\n")
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
@@ -0,0 +1,97 @@
+package process
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/sfomuseum/go-activitypub/uris"
+	"github.com/sfomuseum/go-flags/flagset"
+)
+
+type RunOptions struct {
+	Mode                          string
+	QueuedActivityIds             []int64
+	SubscriberURI                 string
+	AccountsDatabaseURI           string
+	FollowersDatabaseURI          string
+	FollowingDatabaseURI          string
+	NotesDatabaseURI              string
+	MessagesDatabaseURI           string
+	PostsDatabaseURI              string
+	BlocksDatabaseURI             string
+	DomainBlocksDatabaseURI       string
+	LikesDatabaseURI              string
+	BoostsDatabaseURI             string
+	FollowRequestsDatabaseURI     string
+	ActorsDatabaseURI             string
+	ReceivedActivitiesDatabaseURI string
+	ReportsDatabaseURI            string
+	QueuedActivitiesDatabaseURI   string
+	DeliveriesDatabaseURI         string
+	ActorsTTL                     time.Duration
+	ProcessMessageQueueURI        string
+	ProcessFollowerQueueURI       string
+	DeliveryQueueURI              string
+	MaxAttempts                   int
+	AllowFollow                   bool
+	AllowCreate                   bool
+	AllowLikes                    bool
+	AllowBoosts                   bool
+	// Allows posts to accounts not followed by author but where account is mentioned in post
+	AllowMentions bool
+	URIs          *uris.URIs
+	Verbose       bool
+}
+
+func OptionsFromFlagSet(ctx context.Context, fs *flag.FlagSet) (*RunOptions, error) {
+
+	flagset.Parse(fs)
+
+	err := flagset.SetFlagsFromEnvVars(fs, "ACTIVITYPUB")
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive flags from environment variables, %w", err)
+	}
+
+	uris_table := uris.DefaultURIs()
+	uris_table.Hostname = hostname
+	uris_table.Insecure = insecure
+
+	opts := &RunOptions{
+		Mode:                          mode,
+		QueuedActivityIds:             queued_activity_ids,
+		SubscriberURI:                 subscriber_uri,
+		AccountsDatabaseURI:           accounts_database_uri,
+		FollowersDatabaseURI:          followers_database_uri,
+		FollowingDatabaseURI:          following_database_uri,
+		NotesDatabaseURI:              notes_database_uri,
+		MessagesDatabaseURI:           messages_database_uri,
+		PostsDatabaseURI:              posts_database_uri,
+		BlocksDatabaseURI:             blocks_database_uri,
+		DomainBlocksDatabaseURI:       domain_blocks_database_uri,
+		LikesDatabaseURI:              likes_database_uri,
+		BoostsDatabaseURI:             boosts_database_uri,
+		FollowRequestsDatabaseURI:     follow_requests_database_uri,
+		ActorsDatabaseURI:             actors_database_uri,
+		ReceivedActivitiesDatabaseURI: received_activities_database_uri,
+		ReportsDatabaseURI:            reports_database_uri,
+		QueuedActivitiesDatabaseURI:   queued_activities_database_uri,
+		DeliveriesDatabaseURI:         deliveries_database_uri,
+		ActorsTTL:                     time.Duration(actors_ttl) * time.Second,
+		ProcessMessageQueueURI:        process_message_queue_uri,
+		ProcessFollowerQueueURI:       process_follower_queue_uri,
+		DeliveryQueueURI:              delivery_queue_uri,
+		MaxAttempts:                   max_attempts,
+		AllowFollow:                   allow_follow,
+		AllowCreate:                   allow_create,
+		AllowLikes:                    allow_likes,
+		AllowBoosts:                   allow_boosts,
+		AllowMentions:                 allow_mentions,
+		URIs:                          uris_table,
+		Verbose:                       verbose,
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,359 @@
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/database"
+	"github.com/sfomuseum/go-activitypub/queue"
+	"github.com/sfomuseum/go-activitypub/www"
+	"github.com/sfomuseum/go-pubsub/subscriber"
+)
+
+func Run(ctx context.Context) error {
+	fs := DefaultFlagSet()
+	return RunWithFlagSet(ctx, fs)
+}
+
+func RunWithFlagSet(ctx context.Context, fs *flag.FlagSet) error {
+
+	opts, err := OptionsFromFlagSet(ctx, fs)
+
+	if err != nil {
+		return fmt.Errorf("Failed to derive options from flagset, %w", err)
+	}
+
+	return RunWithOptions(ctx, opts)
+}
+
+func RunWithOptions(ctx context.Context, opts *RunOptions) error {
+
+	if opts.Verbose {
+		slog.SetLogLoggerLevel(slog.LevelDebug)
+		slog.Debug("Verbose logging enabled")
+	}
+
+	accounts_db, err := database.NewAccountsDatabase(ctx, opts.AccountsDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create accounts database, %w", err)
+	}
+
+	defer accounts_db.Close(ctx)
+
+	followers_db, err := database.NewFollowersDatabase(ctx, opts.FollowersDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create followers database, %w", err)
+	}
+
+	defer followers_db.Close(ctx)
+
+	following_db, err := database.NewFollowingDatabase(ctx, opts.FollowingDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create following database, %w", err)
+	}
+
+	defer following_db.Close(ctx)
+
+	notes_db, err := database.NewNotesDatabase(ctx, opts.NotesDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create notes database, %w", err)
+	}
+
+	defer notes_db.Close(ctx)
+
+	messages_db, err := database.NewMessagesDatabase(ctx, opts.MessagesDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create messages database, %w", err)
+	}
+
+	defer messages_db.Close(ctx)
+
+	posts_db, err := database.NewPostsDatabase(ctx, opts.PostsDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create posts database, %w", err)
+	}
+
+	defer posts_db.Close(ctx)
+
+	blocks_db, err := database.NewBlocksDatabase(ctx, opts.BlocksDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create blocks database, %w", err)
+	}
+
+	defer blocks_db.Close(ctx)
+
+	domain_blocks_db, err := database.NewDomainBlocksDatabase(ctx, opts.DomainBlocksDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create domain blocks database, %w", err)
+	}
+
+	defer domain_blocks_db.Close(ctx)
+
+	likes_db, err := database.NewLikesDatabase(ctx, opts.LikesDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create likes database, %w", err)
+	}
+
+	defer likes_db.Close(ctx)
+
+	boosts_db, err := database.NewBoostsDatabase(ctx, opts.BoostsDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create boosts database, %w", err)
+	}
+
+	defer boosts_db.Close(ctx)
+
+	follow_requests_db, err := database.NewFollowRequestsDatabase(ctx, opts.FollowRequestsDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create follow requests database, %w", err)
+	}
+
+	defer follow_requests_db.Close(ctx)
+
+	actors_db, err := database.NewActorsDatabase(ctx, opts.ActorsDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create actors database, %w", err)
+	}
+
+	defer actors_db.Close(ctx)
+
+	received_activities_db, err := database.NewReceivedActivitiesDatabase(ctx, opts.ReceivedActivitiesDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create received activities database, %w", err)
+	}
+
+	defer received_activities_db.Close(ctx)
+
+	reports_db, err := database.NewReportsDatabase(ctx, opts.ReportsDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create reports database, %w", err)
+	}
+
+	defer reports_db.Close(ctx)
+
+	queued_activities_db, err := database.NewQueuedActivitiesDatabase(ctx, opts.QueuedActivitiesDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create queued activities database, %w", err)
+	}
+
+	defer queued_activities_db.Close(ctx)
+
+	deliveries_db, err := database.NewDeliveriesDatabase(ctx, opts.DeliveriesDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create deliveries database, %w", err)
+	}
+
+	defer deliveries_db.Close(ctx)
+
+	process_message_queue, err := queue.NewProcessMessageQueue(ctx, opts.ProcessMessageQueueURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create process message queue, %w", err)
+	}
+
+	defer process_message_queue.Close(ctx)
+
+	process_follower_queue, err := queue.NewProcessFollowerQueue(ctx, opts.ProcessFollowerQueueURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create process follower queue, %w", err)
+	}
+
+	defer process_follower_queue.Close(ctx)
+
+	delivery_queue, err := queue.NewDeliveryQueue(ctx, opts.DeliveryQueueURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create delivery queue, %w", err)
+	}
+
+	defer delivery_queue.Close(ctx)
+
+	inbox_opts := &www.InboxPostHandlerOptions{
+		AccountsDatabase:           accounts_db,
+		FollowersDatabase:          followers_db,
+		FollowingDatabase:          following_db,
+		NotesDatabase:              notes_db,
+		MessagesDatabase:           messages_db,
+		PostsDatabase:              posts_db,
+		BlocksDatabase:             blocks_db,
+		DomainBlocksDatabase:       domain_blocks_db,
+		LikesDatabase:              likes_db,
+		BoostsDatabase:             boosts_db,
+		FollowRequestsDatabase:     follow_requests_db,
+		ActorsDatabase:             actors_db,
+		ActorsTTL:                  opts.ActorsTTL,
+		ReceivedActivitiesDatabase: received_activities_db,
+		ReportsDatabase:            reports_db,
+		QueuedActivitiesDatabase:   queued_activities_db,
+		DeliveriesDatabase:         deliveries_db,
+		DeliveryQueue:              delivery_queue,
+		MaxAttempts:                opts.MaxAttempts,
+		ProcessMessageQueue:        process_message_queue,
+		ProcessFollowerQueue:       process_follower_queue,
+		URIs:                       opts.URIs,
+		AllowFollow:                opts.AllowFollow,
+		AllowCreate:                opts.AllowCreate,
+		AllowLikes:                 opts.AllowLikes,
+		AllowBoosts:                opts.AllowBoosts,
+		AllowMentions:              opts.AllowMentions,
+	}
+
+	process_func, err := www.NewProcessQueuedInboxActivityFunc(inbox_opts)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create process queued inbox activity function, %w", err)
+	}
+
+	processQueuedActivity := func(ctx context.Context, queued_activity_id int64) {
+
+		logger := slog.Default()
+		logger = logger.With("queued activity id", queued_activity_id)
+
+		logger.Debug("Process queued activity")
+
+		err := process_func(ctx, queued_activity_id)
+
+		if err != nil {
+			logger.Error("Failed to process queued activity", "error", err)
+			return
+		}
+
+		logger.Info("Processed queued activity")
+	}
+
+	switch opts.Mode {
+	case "cli":
+
+		ids := opts.QueuedActivityIds
+
+		if len(ids) == 0 {
+
+			// Collect the IDs first since processing an activity removes it from the database
+
+			queued_cb := func(ctx context.Context, q *activitypub.QueuedActivity) error {
+				ids = append(ids, q.Id)
+				return nil
+			}
+
+			err := queued_activities_db.GetQueuedActivities(ctx, queued_cb)
+
+			if err != nil {
+				return fmt.Errorf("Failed to retrieve queued activities, %w", err)
+			}
+		}
+
+		for _, id := range ids {
+			processQueuedActivity(ctx, id)
+		}
+
+	case "lambda":
+
+		// For processing activities queued by the server application using an AWS SQS queue
+
+		handler := func(ctx context.Context, sqsEvent events.SQSEvent) error {
+
+			slog.Info("Process queued activities", "count", len(sqsEvent.Records))
+
+			for _, message := range sqsEvent.Records {
+
+				logger := slog.Default()
+				logger = logger.With("message (sqs) id", message.MessageId)
+
+				var queued_activity_id int64
+
+				err := json.Unmarshal([]byte(message.Body), &queued_activity_id)
+
+				if err != nil {
+					logger.Error("Failed to unmarshal queued activity ID", "error", err)
+					continue
+				}
+
+				processQueuedActivity(ctx, queued_activity_id)
+
+				// Remember: Don't return here. It's a loop.
+			}
+
+			return nil
+		}
+
+		lambda.Start(handler)
+		return nil
+
+	case "pubsub":
+
+		// For processing activities in a shared "pubsub" environment, for example Redis. This is mostly
+		// for being able to debug the "lambda" handler in local dev setup.
+
+		sub, err := subscriber.NewSubscriber(ctx, opts.SubscriberURI)
+
+		if err != nil {
+			return fmt.Errorf("Failed to create new subscriber, %w", err)
+		}
+
+		defer sub.Close()
+
+		msg_ch := make(chan string)
+		done_ch := make(chan bool)
+
+		go func() {
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-done_ch:
+					return
+				case msg := <-msg_ch:
+
+					var queued_activity_id int64
+
+					err := json.Unmarshal([]byte(msg), &queued_activity_id)
+
+					if err != nil {
+						slog.Error("Failed to unmarshal queued activity ID", "error", err)
+						continue
+					}
+
+					processQueuedActivity(ctx, queued_activity_id)
+				}
+			}
+		}()
+
+		slog.Info("Listening for queued activities on pubsub channel")
+		err = sub.Listen(ctx, msg_ch)
+
+		done_ch <- true
+
+		if err != nil {
+			return fmt.Errorf("Failed to listen, %v", err)
+		}
+
+	default:
+		return fmt.Errorf("Invalid or unsupported mode")
+	}
+
+	return nil
+}
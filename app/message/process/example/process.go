@@ -122,7 +122,7 @@ func RunWithOptions(ctx context.Context, opts *RunOptions) error {
 
 		logger.Info("Process message")
 
-		// Message is the thing which was dispatched by the process message queue (in www/inbox_post.go)
+		// Message is the thing which was dispatched by the process message queue (in www/inbox_create.go)
 
 		m, err := messages_db.GetMessageWithId(ctx, message_id)
 
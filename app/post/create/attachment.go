@@ -0,0 +1,37 @@
+package create
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Attachment is a media file to attach to a new post.
+type Attachment struct {
+	// The path to the media file on the local filesystem.
+	Path string
+	// A description (alt text) of the media file.
+	Description string
+}
+
+// ParseAttachment parses 'str' in the form of "{PATH}:{DESCRIPTION}" in to a new `Attachment` instance.
+// The description is optional and may itself contain colons.
+func ParseAttachment(str string) (*Attachment, error) {
+
+	parts := strings.SplitN(str, ":", 2)
+
+	path := strings.TrimSpace(parts[0])
+
+	if path == "" {
+		return nil, fmt.Errorf("Missing path")
+	}
+
+	a := &Attachment{
+		Path: path,
+	}
+
+	if len(parts) == 2 {
+		a.Description = strings.TrimSpace(parts[1])
+	}
+
+	return a, nil
+}
@@ -7,12 +7,14 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"strings"
 
 	aa_lambda "github.com/aaronland/go-aws/v3/lambda"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/sfomuseum/go-activitypub"
 	"github.com/sfomuseum/go-activitypub/database"
+	"github.com/sfomuseum/go-activitypub/media"
 	"github.com/sfomuseum/go-activitypub/posts"
 	"github.com/sfomuseum/go-activitypub/queue"
 )
@@ -103,6 +105,37 @@ func RunWithOptionsAndResponse(ctx context.Context, opts *RunOptions) (string, e
 
 	defer deliveries_db.Close(ctx)
 
+	domain_blocks_db, err := database.NewDomainBlocksDatabase(ctx, opts.DomainBlocksDatabaseURI)
+
+	if err != nil {
+		return "", fmt.Errorf("Failed to create instantiate domain blocks database, %w", err)
+	}
+
+	defer domain_blocks_db.Close(ctx)
+
+	// If running in allowlist mode activities are only delivered to followers on allowed domains
+
+	var domain_allows_db database.DomainAllowsDatabase
+
+	if opts.AllowlistMode {
+
+		domain_allows_db, err = database.NewDomainAllowsDatabase(ctx, opts.DomainAllowsDatabaseURI)
+
+		if err != nil {
+			return "", fmt.Errorf("Failed to create instantiate domain allows database, %w", err)
+		}
+
+		defer domain_allows_db.Close(ctx)
+	}
+
+	media_db, err := database.NewMediaDatabase(ctx, opts.MediaDatabaseURI)
+
+	if err != nil {
+		return "", fmt.Errorf("Failed to create instantiate media database, %w", err)
+	}
+
+	defer media_db.Close(ctx)
+
 	delivery_q, err := queue.NewDeliveryQueue(ctx, opts.DeliveryQueueURI)
 
 	if err != nil {
@@ -128,6 +161,34 @@ func RunWithOptionsAndResponse(ctx context.Context, opts *RunOptions) (string, e
 
 		logger = logger.With("account id", acct.Id)
 
+		// Open every attachment before the post is created so that a missing or
+		// unreadable file doesn't leave behind a post without its media.
+
+		attachment_readers := make([]*os.File, len(opts.Attachments))
+
+		for idx, a := range opts.Attachments {
+
+			r, err := os.Open(a.Path)
+
+			if err != nil {
+				return "", fmt.Errorf("Failed to open attachment %s, %w", a.Path, err)
+			}
+
+			defer r.Close()
+
+			info, err := r.Stat()
+
+			if err != nil {
+				return "", fmt.Errorf("Failed to stat attachment %s, %w", a.Path, err)
+			}
+
+			if !info.Mode().IsRegular() {
+				return "", fmt.Errorf("Attachment %s is not a regular file", a.Path)
+			}
+
+			attachment_readers[idx] = r
+		}
+
 		post_opts := &posts.AddPostOptions{
 			URIs:          opts.URIs,
 			PostsDatabase: posts_db,
@@ -149,7 +210,36 @@ func RunWithOptionsAndResponse(ctx context.Context, opts *RunOptions) (string, e
 
 		logger = logger.With("post id", post.Id)
 
-		ap_activity, err := posts.ActivityFromPost(ctx, opts.URIs, acct, post, mentions)
+		post_media := make([]*activitypub.Media, len(opts.Attachments))
+
+		media_opts := &media.AddMediaOptions{
+			BucketURI:     opts.MediaBucketURI,
+			MediaDatabase: media_db,
+		}
+
+		for idx, a := range opts.Attachments {
+
+			logger.Debug("Add media", "path", a.Path)
+
+			m, err := media.AddMediaWithReader(ctx, media_opts, post, attachment_readers[idx], filepath.Base(a.Path), a.Description)
+
+			if err != nil {
+
+				// The post has not been delivered to anyone yet so remove it rather than publishing it without all of its media
+
+				remove_err := removeUnpublishedPost(ctx, posts_db, post_tags_db, media_db, post, mentions, post_media[:idx])
+
+				if remove_err != nil {
+					logger.Error("Failed to remove post after failing to add media", "error", remove_err)
+				}
+
+				return "", fmt.Errorf("Failed to add media %s, %w", a.Path, err)
+			}
+
+			post_media[idx] = m
+		}
+
+		ap_activity, err := posts.ActivityFromPost(ctx, opts.URIs, acct, post, mentions, post_media)
 
 		if err != nil {
 			return "", fmt.Errorf("Failed to create new (create) activity, %w", err)
@@ -176,14 +266,16 @@ func RunWithOptionsAndResponse(ctx context.Context, opts *RunOptions) (string, e
 		logger = logger.With("activity id", activity.Id)
 
 		deliver_opts := &queue.DeliverActivityToFollowersOptions{
-			AccountsDatabase:   accounts_db,
-			FollowersDatabase:  followers_db,
-			DeliveriesDatabase: deliveries_db,
-			DeliveryQueue:      delivery_q,
-			Activity:           activity,
-			Mentions:           mentions,
-			URIs:               opts.URIs,
-			MaxAttempts:        opts.MaxAttempts,
+			AccountsDatabase:     accounts_db,
+			FollowersDatabase:    followers_db,
+			DeliveriesDatabase:   deliveries_db,
+			DeliveryQueue:        delivery_q,
+			Activity:             activity,
+			Mentions:             mentions,
+			URIs:                 opts.URIs,
+			MaxAttempts:          opts.MaxAttempts,
+			DomainBlocksDatabase: domain_blocks_db,
+			DomainAllowsDatabase: domain_allows_db,
 		}
 
 		logger.Debug("Deliver activity")
@@ -278,3 +370,34 @@ func RunWithOptionsAndResponse(ctx context.Context, opts *RunOptions) (string, e
 
 	return "", nil
 }
+
+// removeUnpublishedPost removes 'post', its mentions and the media in 'post_media' from their respective databases. It is used
+// to clean up posts which could not be completed (for example because an attachment could not be stored) before they are delivered.
+func removeUnpublishedPost(ctx context.Context, posts_db database.PostsDatabase, post_tags_db database.PostTagsDatabase, media_db database.MediaDatabase, post *activitypub.Post, mentions []*activitypub.PostTag, post_media []*activitypub.Media) error {
+
+	for _, m := range post_media {
+
+		err := media.RemoveMedia(ctx, media_db, m)
+
+		if err != nil {
+			return fmt.Errorf("Failed to remove media %d, %w", m.Id, err)
+		}
+	}
+
+	for _, t := range mentions {
+
+		err := post_tags_db.RemovePostTag(ctx, t)
+
+		if err != nil {
+			return fmt.Errorf("Failed to remove post tag %d, %w", t.Id, err)
+		}
+	}
+
+	err := posts_db.RemovePost(ctx, post)
+
+	if err != nil {
+		return fmt.Errorf("Failed to remove post %d, %w", post.Id, err)
+	}
+
+	return nil
+}
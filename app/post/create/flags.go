@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/sfomuseum/go-flags/flagset"
+	"github.com/sfomuseum/go-flags/multi"
 )
 
 var accounts_database_uri string
@@ -14,12 +15,20 @@ var followers_database_uri string
 var posts_database_uri string
 var post_tags_database_uri string
 var deliveries_database_uri string
+var domain_blocks_database_uri string
+var domain_allows_database_uri string
+var media_database_uri string
+
+var media_bucket_uri string
 
 var delivery_queue_uri string
 
+var allowlist_mode bool
+
 var account_name string
 var message string
 var in_reply_to string
+var attachments multi.MultiString
 
 var max_attempts int
 
@@ -43,9 +52,16 @@ func DefaultFlagSet() *flag.FlagSet {
 	fs.StringVar(&posts_database_uri, "posts-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.PostsDatabase URI.")
 	fs.StringVar(&post_tags_database_uri, "post-tags-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.PostTagsDatabase URI.")
 	fs.StringVar(&deliveries_database_uri, "deliveries-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.DeliveriesDatabase URI.")
+	fs.StringVar(&domain_blocks_database_uri, "domain-blocks-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.DomainBlocksDatabase URI. Posts are not delivered to followers on suspended domains.")
+	fs.StringVar(&domain_allows_database_uri, "domain-allows-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.DomainAllowsDatabase URI. Only used if the -allowlist-mode flag is enabled.")
+	fs.StringVar(&media_database_uri, "media-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.MediaDatabase URI.")
+
+	fs.StringVar(&media_bucket_uri, "media-bucket-uri", "", "A valid gocloud.dev/blob bucket URI where media attachments are stored. Required if one or more -attachment flags are present.")
 
 	fs.StringVar(&delivery_queue_uri, "delivery-queue-uri", "synchronous://", "A registered sfomuseum/go-activitypub/queue/DeliveryQueue URI.")
 
+	fs.BoolVar(&allowlist_mode, "allowlist-mode", false, "Only deliver posts to followers on domains listed in the -domain-allows-database-uri database.")
+
 	fs.StringVar(&account_name, "account-name", "", "The name of the go-activitypub account creating the post.")
 	fs.StringVar(&hostname, "hostname", "localhost:8080", "The hostname (domain) of the ActivityPub server delivering activities.")
 	fs.BoolVar(&insecure, "insecure", false, "A boolean flag indicating the ActivityPub server delivering activities is insecure (not using TLS).")
@@ -53,6 +69,7 @@ func DefaultFlagSet() *flag.FlagSet {
 	fs.IntVar(&max_attempts, "max-attempts", 5, "The maximum number of attempts to deliver the activity.")
 	fs.StringVar(&message, "message", "", "The body (content) of the message to post.")
 	fs.StringVar(&in_reply_to, "in-reply-to", "", "The URI of that the post is in reply to (optional).")
+	fs.Var(&attachments, "attachment", "Zero or more media files to attach to the post in the form of \"{PATH}:{DESCRIPTION}\" where {DESCRIPTION} is the (optional) alt text for the file.")
 
 	fs.BoolVar(&verbose, "verbose", false, "Enable verbose (debug) logging.")
 
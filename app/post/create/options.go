@@ -22,14 +22,26 @@ type RunOptions struct {
 	PostTagsDatabaseURI string
 	// A registered sfomuseum/go-activitypub/database.DeliveriesDatabase URI.
 	DeliveriesDatabaseURI string
+	// A registered sfomuseum/go-activitypub/database.DomainBlocksDatabase URI.
+	DomainBlocksDatabaseURI string
+	// A registered sfomuseum/go-activitypub/database.DomainAllowsDatabase URI.
+	DomainAllowsDatabaseURI string
+	// A registered sfomuseum/go-activitypub/database.MediaDatabase URI.
+	MediaDatabaseURI string
+	// A valid gocloud.dev/blob bucket URI where media attachments are stored.
+	MediaBucketURI string
 	// A registered sfomuseum/go-activitypub/queue/DeliveryQueue URI.
 	DeliveryQueueURI string
+	// Only deliver posts to followers on domains listed in the domain allows database.
+	AllowlistMode bool
 	// The name of the go-activitypub account creating the post.
 	AccountName string
 	// The body (content) of the message to post.
 	Message string
 	// The URI of that the post is in reply to (optional).
 	InReplyTo string
+	// Zero or more media files to attach to the post.
+	Attachments []*Attachment
 	// The maximum number of attempts to deliver the activity.
 	MaxAttempts int
 	// The operating mode for creating new posts. Valid options are: cli, lambda and invoke, where "lambda"
@@ -57,26 +69,53 @@ func OptionsFromFlagSet(ctx context.Context, fs *flag.FlagSet) (*RunOptions, err
 		return nil, fmt.Errorf("Empty -lambda-function-uri flag")
 	}
 
+	if mode == "invoke" && len(attachments) > 0 {
+		return nil, fmt.Errorf("The -attachment flag is not supported when -mode is \"invoke\"")
+	}
+
+	if len(attachments) > 0 && media_bucket_uri == "" {
+		return nil, fmt.Errorf("Empty -media-bucket-uri flag")
+	}
+
+	post_attachments := make([]*Attachment, len(attachments))
+
+	for idx, str_attachment := range attachments {
+
+		a, err := ParseAttachment(str_attachment)
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid -attachment flag, %w", err)
+		}
+
+		post_attachments[idx] = a
+	}
+
 	uris_table := uris.DefaultURIs()
 	uris_table.Hostname = hostname
 	uris_table.Insecure = insecure
 
 	opts := &RunOptions{
-		AccountsDatabaseURI:   accounts_database_uri,
-		ActivitiesDatabaseURI: activities_database_uri,
-		FollowersDatabaseURI:  followers_database_uri,
-		PostsDatabaseURI:      posts_database_uri,
-		PostTagsDatabaseURI:   post_tags_database_uri,
-		DeliveriesDatabaseURI: deliveries_database_uri,
-		DeliveryQueueURI:      delivery_queue_uri,
-		AccountName:           account_name,
-		Message:               message,
-		InReplyTo:             in_reply_to,
-		URIs:                  uris_table,
-		Verbose:               verbose,
-		Mode:                  mode,
-		LambdaFunctionURI:     lambda_function_uri,
-		MaxAttempts:           max_attempts,
+		AccountsDatabaseURI:     accounts_database_uri,
+		ActivitiesDatabaseURI:   activities_database_uri,
+		FollowersDatabaseURI:    followers_database_uri,
+		PostsDatabaseURI:        posts_database_uri,
+		PostTagsDatabaseURI:     post_tags_database_uri,
+		DeliveriesDatabaseURI:   deliveries_database_uri,
+		DomainBlocksDatabaseURI: domain_blocks_database_uri,
+		DomainAllowsDatabaseURI: domain_allows_database_uri,
+		MediaDatabaseURI:        media_database_uri,
+		MediaBucketURI:          media_bucket_uri,
+		AllowlistMode:           allowlist_mode,
+		DeliveryQueueURI:        delivery_queue_uri,
+		AccountName:             account_name,
+		Message:                 message,
+		InReplyTo:               in_reply_to,
+		Attachments:             post_attachments,
+		URIs:                    uris_table,
+		Verbose:                 verbose,
+		Mode:                    mode,
+		LambdaFunctionURI:       lambda_function_uri,
+		MaxAttempts:             max_attempts,
 	}
 
 	return opts, nil
@@ -0,0 +1,282 @@
+package delete
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/database"
+	"github.com/sfomuseum/go-activitypub/posts"
+	"github.com/sfomuseum/go-activitypub/queue"
+)
+
+func Run(ctx context.Context) error {
+	fs := DefaultFlagSet()
+	return RunWithFlagSet(ctx, fs)
+}
+
+func RunWithFlagSet(ctx context.Context, fs *flag.FlagSet) error {
+
+	opts, err := OptionsFromFlagSet(ctx, fs)
+
+	if err != nil {
+		return fmt.Errorf("Failed to derive options from flagset, %w", err)
+	}
+
+	return RunWithOptions(ctx, opts)
+}
+
+func RunWithOptions(ctx context.Context, opts *RunOptions) error {
+
+	if opts.Verbose {
+		slog.SetLogLoggerLevel(slog.LevelDebug)
+		slog.Debug("Verbose logging enabled")
+	}
+
+	accounts_db, err := database.NewAccountsDatabase(ctx, opts.AccountsDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create accounts database, %w", err)
+	}
+
+	defer accounts_db.Close(ctx)
+
+	activities_db, err := database.NewActivitiesDatabase(ctx, opts.ActivitiesDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create activities database, %w", err)
+	}
+
+	defer activities_db.Close(ctx)
+
+	posts_db, err := database.NewPostsDatabase(ctx, opts.PostsDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create instantiate posts database, %w", err)
+	}
+
+	defer posts_db.Close(ctx)
+
+	post_tags_db, err := database.NewPostTagsDatabase(ctx, opts.PostTagsDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create instantiate post tags database, %w", err)
+	}
+
+	defer post_tags_db.Close(ctx)
+
+	post_revisions_db, err := database.NewPostRevisionsDatabase(ctx, opts.PostRevisionsDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create instantiate post revisions database, %w", err)
+	}
+
+	defer post_revisions_db.Close(ctx)
+
+	likes_db, err := database.NewLikesDatabase(ctx, opts.LikesDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create instantiate likes database, %w", err)
+	}
+
+	defer likes_db.Close(ctx)
+
+	boosts_db, err := database.NewBoostsDatabase(ctx, opts.BoostsDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create instantiate boosts database, %w", err)
+	}
+
+	defer boosts_db.Close(ctx)
+
+	tombstones_db, err := database.NewTombstonesDatabase(ctx, opts.TombstonesDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create instantiate tombstones database, %w", err)
+	}
+
+	defer tombstones_db.Close(ctx)
+
+	media_db, err := database.NewMediaDatabase(ctx, opts.MediaDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create instantiate media database, %w", err)
+	}
+
+	defer media_db.Close(ctx)
+
+	deliveries_db, err := database.NewDeliveriesDatabase(ctx, opts.DeliveriesDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create instantiate deliveries database, %w", err)
+	}
+
+	defer deliveries_db.Close(ctx)
+
+	domain_blocks_db, err := database.NewDomainBlocksDatabase(ctx, opts.DomainBlocksDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create instantiate domain blocks database, %w", err)
+	}
+
+	defer domain_blocks_db.Close(ctx)
+
+	// If running in allowlist mode activities are only delivered to recipients on allowed domains
+
+	var domain_allows_db database.DomainAllowsDatabase
+
+	if opts.AllowlistMode {
+
+		domain_allows_db, err = database.NewDomainAllowsDatabase(ctx, opts.DomainAllowsDatabaseURI)
+
+		if err != nil {
+			return fmt.Errorf("Failed to create instantiate domain allows database, %w", err)
+		}
+
+		defer domain_allows_db.Close(ctx)
+	}
+
+	delivery_q, err := queue.NewDeliveryQueue(ctx, opts.DeliveryQueueURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create new delivery queue, %w", err)
+	}
+
+	logger := slog.Default()
+	logger = logger.With("account", opts.AccountName)
+	logger = logger.With("post id", opts.PostId)
+
+	acct, err := accounts_db.GetAccountWithName(ctx, opts.AccountName)
+
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve account %s, %w", opts.AccountName, err)
+	}
+
+	logger = logger.With("account id", acct.Id)
+
+	// If the post has already been removed then this is a retry of a deletion whose activity
+	// could not be delivered. Otherwise delete the post, recording who received it with its
+	// tombstone before its activities are removed.
+
+	var tombstone *activitypub.Tombstone
+
+	post, err := posts_db.GetPostWithId(ctx, opts.PostId)
+
+	switch {
+	case err == activitypub.ErrNotFound:
+
+		tombstone, err = tombstones_db.GetTombstoneWithPostId(ctx, opts.PostId)
+
+		if err != nil {
+			return fmt.Errorf("Failed to retrieve post or tombstone for %d, %w", opts.PostId, err)
+		}
+
+		if tombstone.AccountId != acct.Id {
+			return fmt.Errorf("Post %d does not belong to account %s", tombstone.PostId, opts.AccountName)
+		}
+
+		logger.Debug("Post has already been deleted, resume delivery")
+
+	case err != nil:
+		return fmt.Errorf("Failed to retrieve post %d, %w", opts.PostId, err)
+	default:
+
+		if post.AccountId != acct.Id {
+			return fmt.Errorf("Post %d does not belong to account %s", post.Id, opts.AccountName)
+		}
+
+		delete_opts := &posts.DeletePostOptions{
+			PostsDatabase:         posts_db,
+			PostTagsDatabase:      post_tags_db,
+			PostRevisionsDatabase: post_revisions_db,
+			LikesDatabase:         likes_db,
+			BoostsDatabase:        boosts_db,
+			ActivitiesDatabase:    activities_db,
+			DeliveriesDatabase:    deliveries_db,
+			TombstonesDatabase:    tombstones_db,
+			MediaDatabase:         media_db,
+		}
+
+		logger.Debug("Delete post")
+
+		tombstone, err = posts.DeletePost(ctx, delete_opts, acct, post)
+
+		if err != nil {
+			return fmt.Errorf("Failed to delete post, %w", err)
+		}
+	}
+
+	logger = logger.With("tombstone id", tombstone.Id)
+
+	recipients := tombstone.Recipients
+
+	if len(recipients) == 0 {
+		logger.Warn("Unable to find any recipients for post, deletion will not be delivered to anyone")
+	}
+
+	// Reuse the "Delete" activity if a previous attempt got as far as recording it
+
+	activity, err := activities_db.GetActivityWithActivityTypeAndId(ctx, activitypub.DeleteActivityType, tombstone.Id)
+
+	switch {
+	case err == activitypub.ErrNotFound:
+
+		ap_activity, err := posts.DeleteActivityFromTombstone(ctx, opts.URIs, acct, tombstone)
+
+		if err != nil {
+			return fmt.Errorf("Failed to create new (delete) activity, %w", err)
+		}
+
+		activity, err = activitypub.NewActivity(ctx, ap_activity)
+
+		if err != nil {
+			return fmt.Errorf("Failed to create new AP wrapper, %w", err)
+		}
+
+		activity.ActivityType = activitypub.DeleteActivityType
+		activity.ActivityTypeId = tombstone.Id
+		activity.AccountId = acct.Id
+
+		err = activities_db.AddActivity(ctx, activity)
+
+		if err != nil {
+			return fmt.Errorf("Failed to add activity, %w", err)
+		}
+
+	case err != nil:
+		return fmt.Errorf("Failed to retrieve delete activity for tombstone, %w", err)
+	}
+
+	logger = logger.With("activity id", activity.Id)
+
+	deliver_opts := &queue.DeliverActivityToRecipientsOptions{
+		AccountsDatabase:     accounts_db,
+		DeliveriesDatabase:   deliveries_db,
+		DeliveryQueue:        delivery_q,
+		Activity:             activity,
+		Recipients:           recipients,
+		URIs:                 opts.URIs,
+		MaxAttempts:          opts.MaxAttempts,
+		DomainBlocksDatabase: domain_blocks_db,
+		DomainAllowsDatabase: domain_allows_db,
+	}
+
+	logger.Debug("Deliver activity", "recipients", len(recipients))
+
+	err = queue.DeliverActivityToRecipients(ctx, deliver_opts)
+
+	if err != nil {
+		return fmt.Errorf("Failed to deliver deletion, %w", err)
+	}
+
+	post = &activitypub.Post{
+		Id: tombstone.PostId,
+	}
+
+	post_url := acct.PostURL(ctx, opts.URIs, post).String()
+
+	slog.Info("Delivered deletion", "post url", post_url, "recipients", len(recipients))
+	return nil
+}
@@ -0,0 +1,75 @@
+package delete
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sfomuseum/go-flags/flagset"
+)
+
+var accounts_database_uri string
+var activities_database_uri string
+var posts_database_uri string
+var post_tags_database_uri string
+var post_revisions_database_uri string
+var likes_database_uri string
+var boosts_database_uri string
+var tombstones_database_uri string
+var media_database_uri string
+var deliveries_database_uri string
+var domain_blocks_database_uri string
+var domain_allows_database_uri string
+
+var delivery_queue_uri string
+
+var allowlist_mode bool
+
+var account_name string
+var post_id int64
+
+var max_attempts int
+
+var hostname string
+var insecure bool
+var verbose bool
+
+func DefaultFlagSet() *flag.FlagSet {
+
+	fs := flagset.NewFlagSet("delete")
+
+	fs.StringVar(&accounts_database_uri, "accounts-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.AccountsDatabase URI.")
+	fs.StringVar(&activities_database_uri, "activities-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.ActivitiesDatabase URI.")
+	fs.StringVar(&posts_database_uri, "posts-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.PostsDatabase URI.")
+	fs.StringVar(&post_tags_database_uri, "post-tags-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.PostTagsDatabase URI.")
+	fs.StringVar(&post_revisions_database_uri, "post-revisions-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.PostRevisionsDatabase URI.")
+	fs.StringVar(&likes_database_uri, "likes-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.LikesDatabase URI.")
+	fs.StringVar(&boosts_database_uri, "boosts-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.BoostsDatabase URI.")
+	fs.StringVar(&tombstones_database_uri, "tombstones-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.TombstonesDatabase URI.")
+	fs.StringVar(&media_database_uri, "media-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.MediaDatabase URI.")
+	fs.StringVar(&deliveries_database_uri, "deliveries-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.DeliveriesDatabase URI.")
+	fs.StringVar(&domain_blocks_database_uri, "domain-blocks-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.DomainBlocksDatabase URI. Deletions are not delivered to recipients on suspended domains.")
+	fs.StringVar(&domain_allows_database_uri, "domain-allows-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.DomainAllowsDatabase URI. Only used if the -allowlist-mode flag is enabled.")
+
+	fs.StringVar(&delivery_queue_uri, "delivery-queue-uri", "synchronous://", "A registered sfomuseum/go-activitypub/queue/DeliveryQueue URI.")
+
+	fs.BoolVar(&allowlist_mode, "allowlist-mode", false, "Only deliver deletions to recipients on domains listed in the -domain-allows-database-uri database.")
+
+	fs.StringVar(&account_name, "account-name", "", "The name of the go-activitypub account that created the post.")
+	fs.Int64Var(&post_id, "post-id", 0, "The unique ID of the post to delete.")
+	fs.StringVar(&hostname, "hostname", "localhost:8080", "The hostname (domain) of the ActivityPub server delivering activities.")
+	fs.BoolVar(&insecure, "insecure", false, "A boolean flag indicating the ActivityPub server delivering activities is insecure (not using TLS).")
+
+	fs.IntVar(&max_attempts, "max-attempts", 5, "The maximum number of attempts to deliver the activity.")
+
+	fs.BoolVar(&verbose, "verbose", false, "Enable verbose (debug) logging.")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Delete an existing post on behalf of a registered go-activitypub account, leaving a tombstone in its place, and schedule a \"Delete\" activity for delivery to everyone who received the original post. If the deletion fails part way through the command can be run again to resume it.\n")
+		fmt.Fprintf(os.Stderr, "Usage:\n\t %s [options]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Valid options are:\n")
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
@@ -0,0 +1,89 @@
+package delete
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/sfomuseum/go-activitypub/uris"
+	"github.com/sfomuseum/go-flags/flagset"
+)
+
+type RunOptions struct {
+	// A registered sfomuseum/go-activitypub/database.AccountsDatabase URI.
+	AccountsDatabaseURI string
+	// A registered sfomuseum/go-activitypub/database.ActivitiesDatabase URI.
+	ActivitiesDatabaseURI string
+	// A registered sfomuseum/go-activitypub/database.PostsDatabase URI.
+	PostsDatabaseURI string
+	// A registered sfomuseum/go-activitypub/database.PostTagsDatabase URI.
+	PostTagsDatabaseURI string
+	// A registered sfomuseum/go-activitypub/database.PostRevisionsDatabase URI.
+	PostRevisionsDatabaseURI string
+	// A registered sfomuseum/go-activitypub/database.LikesDatabase URI.
+	LikesDatabaseURI string
+	// A registered sfomuseum/go-activitypub/database.BoostsDatabase URI.
+	BoostsDatabaseURI string
+	// A registered sfomuseum/go-activitypub/database.TombstonesDatabase URI.
+	TombstonesDatabaseURI string
+	// A registered sfomuseum/go-activitypub/database.MediaDatabase URI.
+	MediaDatabaseURI string
+	// A registered sfomuseum/go-activitypub/database.DeliveriesDatabase URI.
+	DeliveriesDatabaseURI string
+	// A registered sfomuseum/go-activitypub/database.DomainBlocksDatabase URI.
+	DomainBlocksDatabaseURI string
+	// A registered sfomuseum/go-activitypub/database.DomainAllowsDatabase URI.
+	DomainAllowsDatabaseURI string
+	// A registered sfomuseum/go-activitypub/queue/DeliveryQueue URI.
+	DeliveryQueueURI string
+	// Only deliver deletions to recipients on domains listed in the domain allows database.
+	AllowlistMode bool
+	// The name of the go-activitypub account that created the post.
+	AccountName string
+	// The unique ID of the post to delete.
+	PostId int64
+	// The maximum number of attempts to deliver the activity.
+	MaxAttempts int
+	// Enable verbose (debug) logging.
+	Verbose bool
+	URIs    *uris.URIs
+}
+
+func OptionsFromFlagSet(ctx context.Context, fs *flag.FlagSet) (*RunOptions, error) {
+
+	flagset.Parse(fs)
+
+	err := flagset.SetFlagsFromEnvVars(fs, "ACTIVITYPUB")
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive flags from environment variables, %w", err)
+	}
+
+	uris_table := uris.DefaultURIs()
+	uris_table.Hostname = hostname
+	uris_table.Insecure = insecure
+
+	opts := &RunOptions{
+		AccountsDatabaseURI:      accounts_database_uri,
+		ActivitiesDatabaseURI:    activities_database_uri,
+		PostsDatabaseURI:         posts_database_uri,
+		PostTagsDatabaseURI:      post_tags_database_uri,
+		PostRevisionsDatabaseURI: post_revisions_database_uri,
+		LikesDatabaseURI:         likes_database_uri,
+		BoostsDatabaseURI:        boosts_database_uri,
+		TombstonesDatabaseURI:    tombstones_database_uri,
+		MediaDatabaseURI:         media_database_uri,
+		DeliveriesDatabaseURI:    deliveries_database_uri,
+		DomainBlocksDatabaseURI:  domain_blocks_database_uri,
+		DomainAllowsDatabaseURI:  domain_allows_database_uri,
+		AllowlistMode:            allowlist_mode,
+		DeliveryQueueURI:         delivery_queue_uri,
+		AccountName:              account_name,
+		PostId:                   post_id,
+		URIs:                     uris_table,
+		Verbose:                  verbose,
+		MaxAttempts:              max_attempts,
+	}
+
+	return opts, nil
+}
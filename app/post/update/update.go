@@ -0,0 +1,266 @@
+package update
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/database"
+	"github.com/sfomuseum/go-activitypub/media"
+	"github.com/sfomuseum/go-activitypub/posts"
+	"github.com/sfomuseum/go-activitypub/queue"
+)
+
+func Run(ctx context.Context) error {
+	fs := DefaultFlagSet()
+	return RunWithFlagSet(ctx, fs)
+}
+
+func RunWithFlagSet(ctx context.Context, fs *flag.FlagSet) error {
+
+	opts, err := OptionsFromFlagSet(ctx, fs)
+
+	if err != nil {
+		return fmt.Errorf("Failed to derive options from flagset, %w", err)
+	}
+
+	return RunWithOptions(ctx, opts)
+}
+
+func RunWithOptions(ctx context.Context, opts *RunOptions) error {
+
+	if opts.Verbose {
+		slog.SetLogLoggerLevel(slog.LevelDebug)
+		slog.Debug("Verbose logging enabled")
+	}
+
+	message := opts.Message
+
+	if message == "-" {
+
+		message = ""
+
+		scanner := bufio.NewScanner(os.Stdin)
+
+		for scanner.Scan() {
+			message = fmt.Sprintf("%s %s", message, scanner.Text())
+		}
+
+		err := scanner.Err()
+
+		if err != nil {
+			return fmt.Errorf("Failed to scan input, %w", err)
+		}
+	}
+
+	if message == "" {
+		return fmt.Errorf("Empty message string")
+	}
+
+	accounts_db, err := database.NewAccountsDatabase(ctx, opts.AccountsDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create accounts database, %w", err)
+	}
+
+	defer accounts_db.Close(ctx)
+
+	activities_db, err := database.NewActivitiesDatabase(ctx, opts.ActivitiesDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create activities database, %w", err)
+	}
+
+	defer activities_db.Close(ctx)
+
+	posts_db, err := database.NewPostsDatabase(ctx, opts.PostsDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create instantiate posts database, %w", err)
+	}
+
+	defer posts_db.Close(ctx)
+
+	post_tags_db, err := database.NewPostTagsDatabase(ctx, opts.PostTagsDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create instantiate post tags database, %w", err)
+	}
+
+	defer post_tags_db.Close(ctx)
+
+	post_revisions_db, err := database.NewPostRevisionsDatabase(ctx, opts.PostRevisionsDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create instantiate post revisions database, %w", err)
+	}
+
+	defer post_revisions_db.Close(ctx)
+
+	media_db, err := database.NewMediaDatabase(ctx, opts.MediaDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create instantiate media database, %w", err)
+	}
+
+	defer media_db.Close(ctx)
+
+	deliveries_db, err := database.NewDeliveriesDatabase(ctx, opts.DeliveriesDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create instantiate deliveries database, %w", err)
+	}
+
+	defer deliveries_db.Close(ctx)
+
+	domain_blocks_db, err := database.NewDomainBlocksDatabase(ctx, opts.DomainBlocksDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create instantiate domain blocks database, %w", err)
+	}
+
+	defer domain_blocks_db.Close(ctx)
+
+	// If running in allowlist mode activities are only delivered to recipients on allowed domains
+
+	var domain_allows_db database.DomainAllowsDatabase
+
+	if opts.AllowlistMode {
+
+		domain_allows_db, err = database.NewDomainAllowsDatabase(ctx, opts.DomainAllowsDatabaseURI)
+
+		if err != nil {
+			return fmt.Errorf("Failed to create instantiate domain allows database, %w", err)
+		}
+
+		defer domain_allows_db.Close(ctx)
+	}
+
+	delivery_q, err := queue.NewDeliveryQueue(ctx, opts.DeliveryQueueURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create new delivery queue, %w", err)
+	}
+
+	logger := slog.Default()
+	logger = logger.With("account", opts.AccountName)
+	logger = logger.With("post id", opts.PostId)
+
+	acct, err := accounts_db.GetAccountWithName(ctx, opts.AccountName)
+
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve account %s, %w", opts.AccountName, err)
+	}
+
+	logger = logger.With("account id", acct.Id)
+
+	post, err := posts_db.GetPostWithId(ctx, opts.PostId)
+
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve post %d, %w", opts.PostId, err)
+	}
+
+	if post.AccountId != acct.Id {
+		return fmt.Errorf("Post %d does not belong to account %s", post.Id, opts.AccountName)
+	}
+
+	// Determine who received the original post, and any previous updates, before anything changes.
+	// It is not an error for there to be no recipients (for example posts that were written directly
+	// to the posts database) but there will be no one to update.
+
+	recipients, err := posts.PostRecipients(ctx, activities_db, post_revisions_db, deliveries_db, post)
+
+	if err != nil {
+		return fmt.Errorf("Failed to derive recipients for post, %w", err)
+	}
+
+	if len(recipients) == 0 {
+		logger.Warn("Unable to find recipients for post or previous updates, update will only be delivered to mentions")
+	}
+
+	edit_opts := &posts.EditPostOptions{
+		URIs:                  opts.URIs,
+		PostsDatabase:         posts_db,
+		PostTagsDatabase:      post_tags_db,
+		PostRevisionsDatabase: post_revisions_db,
+	}
+
+	logger.Debug("Edit post", "message", message)
+
+	rev, mentions, err := posts.EditPost(ctx, edit_opts, acct, post, message)
+
+	if err != nil {
+		return fmt.Errorf("Failed to edit post, %w", err)
+	}
+
+	logger = logger.With("revision id", rev.Id)
+
+	attachments, err := media.GetMediaForPost(ctx, media_db, post.Id)
+
+	if err != nil {
+		return err
+	}
+
+	ap_activity, err := posts.UpdateActivityFromPost(ctx, opts.URIs, acct, post, mentions, attachments)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create new (update) activity, %w", err)
+	}
+
+	activity, err := activitypub.NewActivity(ctx, ap_activity)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create new AP wrapper, %w", err)
+	}
+
+	activity.ActivityType = activitypub.UpdateActivityType
+	activity.ActivityTypeId = rev.Id
+	activity.AccountId = acct.Id
+
+	err = activities_db.AddActivity(ctx, activity)
+
+	if err != nil {
+		return fmt.Errorf("Failed to add activity, %w", err)
+	}
+
+	logger = logger.With("activity id", activity.Id)
+
+	// Include everyone mentioned in the updated post, some of whom may not have received the original
+
+	for _, t := range mentions {
+
+		if t.Type == "Mention" && !slices.Contains(recipients, t.Name) {
+			recipients = append(recipients, t.Name)
+		}
+	}
+
+	deliver_opts := &queue.DeliverActivityToRecipientsOptions{
+		AccountsDatabase:     accounts_db,
+		DeliveriesDatabase:   deliveries_db,
+		DeliveryQueue:        delivery_q,
+		Activity:             activity,
+		Recipients:           recipients,
+		URIs:                 opts.URIs,
+		MaxAttempts:          opts.MaxAttempts,
+		DomainBlocksDatabase: domain_blocks_db,
+		DomainAllowsDatabase: domain_allows_db,
+	}
+
+	logger.Debug("Deliver activity", "recipients", len(recipients))
+
+	err = queue.DeliverActivityToRecipients(ctx, deliver_opts)
+
+	if err != nil {
+		return fmt.Errorf("Failed to deliver update, %w", err)
+	}
+
+	post_url := acct.PostURL(ctx, opts.URIs, post).String()
+
+	slog.Info("Delivered update", "post url", post_url, "recipients", len(recipients))
+	return nil
+}
@@ -0,0 +1,43 @@
+package list
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sfomuseum/go-flags/flagset"
+)
+
+var accounts_database_uri string
+var received_activities_database_uri string
+
+var account_name string
+var activity_id string
+var actor string
+var outcome string
+
+var verbose bool
+
+func DefaultFlagSet() *flag.FlagSet {
+
+	fs := flagset.NewFlagSet("list")
+
+	fs.StringVar(&accounts_database_uri, "accounts-database-uri", "", "A known sfomuseum/go-activitypub/AccountsDatabase URI. Required if the -account-name flag is set.")
+	fs.StringVar(&received_activities_database_uri, "received-activities-database-uri", "", "A known sfomuseum/go-activitypub/ReceivedActivitiesDatabase URI.")
+
+	fs.StringVar(&account_name, "account-name", "", "If not empty, only list the activities posted to the inbox of this account.")
+	fs.StringVar(&activity_id, "activity-id", "", "If not empty, only list the activities with this ActivityPub activity ID.")
+	fs.StringVar(&actor, "actor", "", "If not empty, only list the activities performed by this actor.")
+	fs.StringVar(&outcome, "outcome", "", "If not empty, only list the activities with this outcome. Valid options are: accepted, rejected, failed.")
+
+	fs.BoolVar(&verbose, "verbose", false, "Enable verbose (debug) logging.")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "List the (ActivityPub) activities that have been posted to account inboxes, and the outcome of processing them.\n")
+		fmt.Fprintf(os.Stderr, "Usage:\n\t %s [options]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Valid options are:\n")
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
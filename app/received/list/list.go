@@ -0,0 +1,95 @@
+package list
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/database"
+)
+
+func Run(ctx context.Context) error {
+	fs := DefaultFlagSet()
+	return RunWithFlagSet(ctx, fs)
+}
+
+func RunWithFlagSet(ctx context.Context, fs *flag.FlagSet) error {
+
+	opts, err := OptionsFromFlagSet(ctx, fs)
+
+	if err != nil {
+		return fmt.Errorf("Failed to derive options from flagset, %w", err)
+	}
+
+	return RunWithOptions(ctx, opts)
+}
+
+func RunWithOptions(ctx context.Context, opts *RunOptions) error {
+
+	if opts.Verbose {
+		slog.SetLogLoggerLevel(slog.LevelDebug)
+		slog.Debug("Verbose logging enabled")
+	}
+
+	received_db, err := database.NewReceivedActivitiesDatabase(ctx, opts.ReceivedActivitiesDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create received activities database, %w", err)
+	}
+
+	defer received_db.Close(ctx)
+
+	var acct *activitypub.Account
+
+	if opts.AccountName != "" {
+
+		accounts_db, err := database.NewAccountsDatabase(ctx, opts.AccountsDatabaseURI)
+
+		if err != nil {
+			return fmt.Errorf("Failed to create accounts database, %w", err)
+		}
+
+		defer accounts_db.Close(ctx)
+
+		acct, err = accounts_db.GetAccountWithName(ctx, opts.AccountName)
+
+		if err != nil {
+			return fmt.Errorf("Failed to retrieve account %s, %w", opts.AccountName, err)
+		}
+	}
+
+	cb := func(ctx context.Context, r *activitypub.ReceivedActivity) error {
+
+		if opts.ActivityId != "" && r.ActivityPubId != opts.ActivityId {
+			return nil
+		}
+
+		if opts.Actor != "" && r.Actor != opts.Actor {
+			return nil
+		}
+
+		if opts.Outcome != "" && r.Outcome != opts.Outcome {
+			return nil
+		}
+
+		slog.Info("Received activity", "id", r.Id, "activity id", r.ActivityPubId, "account id", r.AccountId, "actor", r.Actor, "type", r.ActivityType, "status", r.Status, "outcome", r.Outcome, "received", r.Received)
+		return nil
+	}
+
+	switch {
+	case acct != nil && opts.ActivityId != "":
+		err = received_db.GetReceivedActivitiesWithActivityPubIdAndAccount(ctx, opts.ActivityId, acct.Id, cb)
+	case acct != nil:
+		err = received_db.GetReceivedActivitiesForAccount(ctx, acct.Id, cb)
+	default:
+		err = received_db.GetReceivedActivities(ctx, cb)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed to get received activities, %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package list
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/sfomuseum/go-flags/flagset"
+)
+
+type RunOptions struct {
+	AccountsDatabaseURI           string
+	ReceivedActivitiesDatabaseURI string
+	AccountName                   string
+	ActivityId                    string
+	Actor                         string
+	Outcome                       string
+	Verbose                       bool
+}
+
+func OptionsFromFlagSet(ctx context.Context, fs *flag.FlagSet) (*RunOptions, error) {
+
+	flagset.Parse(fs)
+
+	err := flagset.SetFlagsFromEnvVars(fs, "ACTIVITYPUB")
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive flags from environment variables, %w", err)
+	}
+
+	opts := &RunOptions{
+		AccountsDatabaseURI:           accounts_database_uri,
+		ReceivedActivitiesDatabaseURI: received_activities_database_uri,
+		AccountName:                   account_name,
+		ActivityId:                    activity_id,
+		Actor:                         actor,
+		Outcome:                       outcome,
+		Verbose:                       verbose,
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,44 @@
+package manage
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sfomuseum/go-flags/flagset"
+	"github.com/sfomuseum/go-flags/multi"
+)
+
+var accounts_database_uri string
+var reports_database_uri string
+
+var mode string
+var account_name string
+var report_ids multi.MultiInt64
+var unresolved bool
+
+var verbose bool
+
+func DefaultFlagSet() *flag.FlagSet {
+
+	fs := flagset.NewFlagSet("manage")
+
+	fs.StringVar(&accounts_database_uri, "accounts-database-uri", "", "A known sfomuseum/go-activitypub/AccountsDatabase URI.")
+	fs.StringVar(&reports_database_uri, "reports-database-uri", "", "A known sfomuseum/go-activitypub/ReportsDatabase URI.")
+
+	fs.StringVar(&mode, "mode", "list", "The operation to perform. Valid options are: list, resolve, unresolve.")
+	fs.StringVar(&account_name, "account-name", "", "Only list reports for this account. If empty then reports for all accounts are listed.")
+	fs.Var(&report_ids, "id", "One or more unique report IDs to resolve (or unresolve).")
+	fs.BoolVar(&unresolved, "unresolved", false, "Only list reports that have not been resolved.")
+
+	fs.BoolVar(&verbose, "verbose", false, "Enable verbose (debug) logging.")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "List and resolve moderation reports (\"Flag\" activities) received about registered go-activitypub accounts.\n")
+		fmt.Fprintf(os.Stderr, "Usage:\n\t %s [options]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Valid options are:\n")
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
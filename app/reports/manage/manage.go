@@ -0,0 +1,182 @@
+package manage
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/database"
+)
+
+func Run(ctx context.Context) error {
+	fs := DefaultFlagSet()
+	return RunWithFlagSet(ctx, fs)
+}
+
+func RunWithFlagSet(ctx context.Context, fs *flag.FlagSet) error {
+
+	opts, err := OptionsFromFlagSet(ctx, fs)
+
+	if err != nil {
+		return fmt.Errorf("Failed to derive options from flagset, %w", err)
+	}
+
+	return RunWithOptions(ctx, opts)
+}
+
+func RunWithOptions(ctx context.Context, opts *RunOptions) error {
+
+	if opts.Verbose {
+		slog.SetLogLoggerLevel(slog.LevelDebug)
+		slog.Debug("Verbose logging enabled")
+	}
+
+	logger := slog.Default()
+
+	accounts_db, err := database.NewAccountsDatabase(ctx, opts.AccountsDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to initialize accounts database, %w", err)
+	}
+
+	defer accounts_db.Close(ctx)
+
+	reports_db, err := database.NewReportsDatabase(ctx, opts.ReportsDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to initialize reports database, %w", err)
+	}
+
+	defer reports_db.Close(ctx)
+
+	switch opts.Mode {
+	case "list":
+
+		// Account names are looked up (and cached) because reports only store account IDs
+
+		account_names := make(map[int64]string)
+
+		reports_cb := func(ctx context.Context, r *activitypub.Report) error {
+
+			if opts.Unresolved && r.IsResolved() {
+				return nil
+			}
+
+			name, exists := account_names[r.AccountId]
+
+			if !exists {
+
+				acct, err := accounts_db.GetAccountWithId(ctx, r.AccountId)
+
+				switch {
+				case err == activitypub.ErrNotFound:
+					name = strconv.FormatInt(r.AccountId, 10)
+				case err != nil:
+					return fmt.Errorf("Failed to retrieve account %d, %w", r.AccountId, err)
+				default:
+					name = acct.Name
+				}
+
+				account_names[r.AccountId] = name
+			}
+
+			status := "unresolved"
+
+			if r.IsResolved() {
+				status = "resolved"
+			}
+
+			str_post_ids := make([]string, len(r.PostIds))
+
+			for idx, id := range r.PostIds {
+				str_post_ids[idx] = strconv.FormatInt(id, 10)
+			}
+
+			created := time.Unix(r.Created, 0)
+
+			fmt.Fprintf(os.Stdout, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", r.Id, created.Format(time.RFC3339), status, name, r.ReporterActor, strings.Join(str_post_ids, ","), r.Comment)
+			return nil
+		}
+
+		if opts.AccountName != "" {
+
+			acct, err := accounts_db.GetAccountWithName(ctx, opts.AccountName)
+
+			if err != nil {
+				return fmt.Errorf("Failed to retrieve account %s, %w", opts.AccountName, err)
+			}
+
+			err = reports_db.GetReportsForAccount(ctx, acct.Id, reports_cb)
+
+			if err != nil {
+				return fmt.Errorf("Failed to list reports for account %s, %w", opts.AccountName, err)
+			}
+
+			return nil
+		}
+
+		err := reports_db.GetReports(ctx, reports_cb)
+
+		if err != nil {
+			return fmt.Errorf("Failed to list reports, %w", err)
+		}
+
+	case "resolve", "unresolve":
+
+		if len(opts.ReportIds) == 0 {
+			return fmt.Errorf("No reports to %s", opts.Mode)
+		}
+
+		for _, id := range opts.ReportIds {
+
+			r, err := reports_db.GetReportWithId(ctx, id)
+
+			if err != nil {
+				return fmt.Errorf("Failed to retrieve report %d, %w", id, err)
+			}
+
+			now := time.Now()
+
+			switch opts.Mode {
+			case "resolve":
+
+				if r.IsResolved() {
+					logger.Info("Report has already been resolved, skipping", "id", id)
+					continue
+				}
+
+				r.Resolved = now.Unix()
+
+			default:
+
+				if !r.IsResolved() {
+					logger.Info("Report has not been resolved, skipping", "id", id)
+					continue
+				}
+
+				r.Resolved = 0
+			}
+
+			r.LastModified = now.Unix()
+
+			err = reports_db.UpdateReport(ctx, r)
+
+			if err != nil {
+				return fmt.Errorf("Failed to update report %d, %w", id, err)
+			}
+
+			logger.Info("Updated report", "id", id, "resolved", r.IsResolved())
+		}
+
+	default:
+		return fmt.Errorf("Invalid or unsupported mode, %s", opts.Mode)
+	}
+
+	return nil
+}
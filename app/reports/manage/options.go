@@ -0,0 +1,42 @@
+package manage
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/sfomuseum/go-flags/flagset"
+)
+
+type RunOptions struct {
+	AccountsDatabaseURI string
+	ReportsDatabaseURI  string
+	Mode                string
+	AccountName         string
+	ReportIds           []int64
+	Unresolved          bool
+	Verbose             bool
+}
+
+func OptionsFromFlagSet(ctx context.Context, fs *flag.FlagSet) (*RunOptions, error) {
+
+	flagset.Parse(fs)
+
+	err := flagset.SetFlagsFromEnvVars(fs, "ACTIVITYPUB")
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive flags from environment variables, %w", err)
+	}
+
+	opts := &RunOptions{
+		AccountsDatabaseURI: accounts_database_uri,
+		ReportsDatabaseURI:  reports_database_uri,
+		Mode:                mode,
+		AccountName:         account_name,
+		ReportIds:           report_ids,
+		Unresolved:          unresolved,
+		Verbose:             verbose,
+	}
+
+	return opts, nil
+}
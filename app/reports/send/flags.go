@@ -0,0 +1,48 @@
+package send
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sfomuseum/go-flags/flagset"
+	"github.com/sfomuseum/go-flags/multi"
+)
+
+var hostname string
+var insecure bool
+
+var accounts_database_uri string
+
+var account_name string
+var report_address string
+var object_uris multi.MultiString
+var comment string
+
+var verbose bool
+
+func DefaultFlagSet() *flag.FlagSet {
+
+	fs := flagset.NewFlagSet("send")
+
+	fs.StringVar(&accounts_database_uri, "accounts-database-uri", "", "A known sfomuseum/go-activitypub/AccountsDatabase URI.")
+
+	fs.StringVar(&account_name, "account-name", "", "The name of the account sending the report.")
+	fs.StringVar(&report_address, "report", "", "The ActivityPub @user@host address of the (remote) actor being reported.")
+	fs.Var(&object_uris, "object", "Zero or more URIs of posts by the actor being reported to include with the report.")
+	fs.StringVar(&comment, "comment", "", "An optional comment describing the reason for the report.")
+
+	fs.StringVar(&hostname, "hostname", "localhost:8080", "The hostname (domain) of the ActivityPub server delivering activities.")
+	fs.BoolVar(&insecure, "insecure", false, "A boolean flag indicating the ActivityPub server delivering activities is insecure (not using TLS).")
+
+	fs.BoolVar(&verbose, "verbose", false, "Enable verbose (debug) logging.")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Send a moderation report (\"Flag\" activity) about a remote @user@host ActivityPub actor to that actor's server on behalf of a registered go-activitypub account.\n")
+		fmt.Fprintf(os.Stderr, "Usage:\n\t %s [options]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Valid options are:\n")
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
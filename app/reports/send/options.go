@@ -0,0 +1,47 @@
+package send
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/sfomuseum/go-activitypub/uris"
+	"github.com/sfomuseum/go-flags/flagset"
+)
+
+type RunOptions struct {
+	AccountsDatabaseURI string
+	AccountName         string
+	ReportAddress       string
+	ObjectURIs          []string
+	Comment             string
+	URIs                *uris.URIs
+	Verbose             bool
+}
+
+func OptionsFromFlagSet(ctx context.Context, fs *flag.FlagSet) (*RunOptions, error) {
+
+	flagset.Parse(fs)
+
+	err := flagset.SetFlagsFromEnvVars(fs, "ACTIVITYPUB")
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive flags from environment variables, %w", err)
+	}
+
+	uris_table := uris.DefaultURIs()
+	uris_table.Hostname = hostname
+	uris_table.Insecure = insecure
+
+	opts := &RunOptions{
+		AccountsDatabaseURI: accounts_database_uri,
+		AccountName:         account_name,
+		ReportAddress:       report_address,
+		ObjectURIs:          object_uris,
+		Comment:             comment,
+		URIs:                uris_table,
+		Verbose:             verbose,
+	}
+
+	return opts, nil
+}
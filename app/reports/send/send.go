@@ -0,0 +1,92 @@
+package send
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/database"
+)
+
+func Run(ctx context.Context) error {
+	fs := DefaultFlagSet()
+	return RunWithFlagSet(ctx, fs)
+}
+
+func RunWithFlagSet(ctx context.Context, fs *flag.FlagSet) error {
+
+	opts, err := OptionsFromFlagSet(ctx, fs)
+
+	if err != nil {
+		return fmt.Errorf("Failed to derive options from flagset, %w", err)
+	}
+
+	return RunWithOptions(ctx, opts)
+}
+
+func RunWithOptions(ctx context.Context, opts *RunOptions) error {
+
+	if opts.Verbose {
+		slog.SetLogLoggerLevel(slog.LevelDebug)
+		slog.Debug("Verbose logging enabled")
+	}
+
+	logger := slog.Default()
+
+	if opts.ReportAddress == "" {
+		return fmt.Errorf("Missing address of actor to report")
+	}
+
+	accounts_db, err := database.NewAccountsDatabase(ctx, opts.AccountsDatabaseURI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to initialize accounts database, %w", err)
+	}
+
+	defer accounts_db.Close(ctx)
+
+	acct, err := accounts_db.GetAccountWithName(ctx, opts.AccountName)
+
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve account %s, %w", opts.AccountName, err)
+	}
+
+	logger = logger.With("account", acct.Name)
+	logger = logger.With("report", opts.ReportAddress)
+
+	reported_actor, err := ap.RetrieveActor(ctx, opts.ReportAddress, opts.URIs.Insecure)
+
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve actor for %s, %w", opts.ReportAddress, err)
+	}
+
+	// Reports are handled by the remote server rather than the actor being reported so
+	// prefer the server's shared inbox, if present.
+
+	inbox := reported_actor.Inbox
+
+	if reported_actor.Endpoints != nil && reported_actor.Endpoints.SharedInbox != "" {
+		inbox = reported_actor.Endpoints.SharedInbox
+	}
+
+	logger = logger.With("inbox", inbox)
+
+	acct_url := acct.AccountURL(ctx, opts.URIs)
+
+	activity, err := ap.NewFlagActivity(ctx, opts.URIs, acct_url.String(), reported_actor.Id, opts.ObjectURIs, opts.Comment)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create flag activity, %w", err)
+	}
+
+	err = acct.SendActivity(ctx, opts.URIs, inbox, activity)
+
+	if err != nil {
+		return fmt.Errorf("Failed to deliver flag activity, %w", err)
+	}
+
+	logger.Info("Report sent", "activity id", activity.Id)
+	return nil
+}
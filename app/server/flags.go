@@ -18,11 +18,37 @@ var posts_database_uri string
 var post_tags_database_uri string
 var properties_database_uri string
 var blocks_database_uri string
+var domain_blocks_database_uri string
+var domain_allows_database_uri string
 var likes_database_uri string
 var boosts_database_uri string
+var follow_requests_database_uri string
+var actors_database_uri string
+var received_activities_database_uri string
+var reports_database_uri string
+var queued_activities_database_uri string
+var activities_database_uri string
+var access_tokens_database_uri string
+var deliveries_database_uri string
+var tombstones_database_uri string
+var media_database_uri string
+
+var actors_ttl int
+
+var signature_clock_skew int
+
+var rate_limiter_uri string
+var rate_limit_host int
+var rate_limit_host_burst int
+var rate_limit_actor int
+var rate_limit_actor_burst int
 
 var process_message_queue_uri string
 var process_follower_queue_uri string
+var inbox_queue_uri string
+var delivery_queue_uri string
+
+var max_attempts int
 
 var server_uri string
 var hostname string
@@ -38,6 +64,8 @@ var allow_mentions bool
 
 var allow_remote_icon_uri bool
 
+var allowlist_mode bool
+
 var disabled bool
 var verbose bool
 
@@ -52,16 +80,30 @@ func DefaultFlagSet() *flag.FlagSet {
 	fs.StringVar(&notes_database_uri, "notes-database-uri", "", "A registered sfomuseum/go-activitypub/database.NotesDatabase URI.")
 	fs.StringVar(&messages_database_uri, "messages-database-uri", "", "A registered sfomuseum/go-activitypub/database.MessagesDatabase URI.")
 	fs.StringVar(&blocks_database_uri, "blocks-database-uri", "", "A registered sfomuseum/go-activitypub/database.BlocksDatabase URI.")
+	fs.StringVar(&domain_blocks_database_uri, "domain-blocks-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.DomainBlocksDatabase URI used to store server-wide blocks for remote domains.")
+	fs.StringVar(&domain_allows_database_uri, "domain-allows-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.DomainAllowsDatabase URI used to store the remote domains the server is allowed to federate with. Only used if the -allowlist-mode flag is enabled.")
 	fs.StringVar(&posts_database_uri, "posts-database-uri", "", "A registered sfomuseum/go-activitypub/database.PostsDatabase URI.")
 	fs.StringVar(&post_tags_database_uri, "post-tags-database-uri", "", "A registered sfomuseum/go-activitypub/database.PostTagsDatabase URI.")
 	fs.StringVar(&properties_database_uri, "properties-database-uri", "", "A registered sfomuseum/go-activitypub/database.PropertiesDatabase URI.")
 	fs.StringVar(&likes_database_uri, "likes-database-uri", "", "A registered sfomuseum/go-activitypub/database.LikesDatabase URI.")
 	fs.StringVar(&boosts_database_uri, "boosts-database-uri", "", "A registered sfomuseum/go-activitypub/database.BoostsDatabase URI.")
-
-	fs.BoolVar(&allow_follow, "allow-follow", true, "Enable support for ActivityPub \"Follow\" activities.")
+	fs.StringVar(&follow_requests_database_uri, "follow-requests-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.FollowRequestsDatabase URI.")
+	fs.StringVar(&actors_database_uri, "actors-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.ActorsDatabase URI used to cache remote actors.")
+	fs.StringVar(&received_activities_database_uri, "received-activities-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.ReceivedActivitiesDatabase URI used to log activities posted to account inboxes and to ignore activities that have already been accepted.")
+	fs.StringVar(&reports_database_uri, "reports-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.ReportsDatabase URI used to store moderation reports (\"Flag\" activities) received about local accounts.")
+	fs.StringVar(&queued_activities_database_uri, "queued-activities-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.QueuedActivitiesDatabase URI used to store verified activities posted to account inboxes until they have been processed. Required if the -inbox-queue-uri flag is set.")
+	fs.StringVar(&activities_database_uri, "activities-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.ActivitiesDatabase URI used to serve the (public) activities delivered by accounts in their outboxes.")
+	fs.StringVar(&access_tokens_database_uri, "access-tokens-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.AccessTokensDatabase URI used to authenticate client-to-server (C2S) requests posting activities to account outboxes.")
+	fs.StringVar(&deliveries_database_uri, "deliveries-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.DeliveriesDatabase URI used to log the delivery of activities posted to account outboxes and sent while processing inbox activities (for example the \"Follow\" activity sent when a followed actor moves).")
+	fs.StringVar(&tombstones_database_uri, "tombstones-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.TombstonesDatabase URI used to return \"410 Gone\" responses (and \"Tombstone\" objects) for deleted posts.")
+	fs.StringVar(&media_database_uri, "media-database-uri", "null://", "A registered sfomuseum/go-activitypub/database.MediaDatabase URI used to serve (and include) media attachments for posts.")
+	fs.IntVar(&actors_ttl, "actors-ttl", 86400, "The number of seconds that cached remote actors are considered valid. If 0 then cached actors never expire.")
+
+	fs.BoolVar(&allow_follow, "allow-follow", true, "Enable support for ActivityPub \"Follow\" and \"Move\" (account migration) activities.")
 	fs.BoolVar(&allow_create, "allow-create", false, "Enable support for ActivityPub \"Create\" activities.")
-	fs.BoolVar(&allow_likes, "allow-likes", true, "Enable support for ActivityPub \"Like\" activities.")
+	fs.BoolVar(&allow_likes, "allow-likes", true, "Enable support for ActivityPub \"Like\" and \"EmojiReact\" (emoji reaction) activities.")
 	fs.BoolVar(&allow_boosts, "allow-boosts", true, "Enable support for ActivityPub \"Announce\" (boost) activities.")
+	fs.BoolVar(&allowlist_mode, "allowlist-mode", false, "Only federate with hosts listed in the -domain-allows-database-uri database. Activities posted to inboxes, ActivityPub (actor, outbox, followers and following) requests and deliveries are refused for all other hosts. Since GET requests have no actor the requesting host is derived from the key used to sign the request, before the HTTP signature is verified, so unsigned requests are refused. Webfinger requests are not signed and are not restricted.")
 	fs.BoolVar(&allow_mentions, "allow-mentions", true, "If enabled allows posts (\"Create\" activities) to accounts not followed by author but where account is mentioned in post.")
 
 	fs.StringVar(&server_uri, "server-uri", "http://localhost:8080", "A registered aaronland/go-http-server/server.Server URI.")
@@ -70,6 +112,17 @@ func DefaultFlagSet() *flag.FlagSet {
 
 	fs.StringVar(&process_message_queue_uri, "process-message-queue-uri", "null://", "A registered go-activitypub/queue.ProcessMessageQueue URI.")
 	fs.StringVar(&process_follower_queue_uri, "process-follower-queue-uri", "null://", "A registered go-activitypub/queue.ProcessFollowerQueue URI.")
+	fs.StringVar(&inbox_queue_uri, "inbox-queue-uri", "", "An optional registered go-activitypub/queue.InboxQueue URI. If set, activities posted to inboxes are verified, stored in the -queued-activities-database-uri database and dispatched to this queue, and a 202 Accepted response is returned immediately. The activities are then processed by the process-inbox tool (or immediately, in the same process, if the URI is synchronous://). If empty activities are processed while the remote server waits for a response.")
+	fs.StringVar(&delivery_queue_uri, "delivery-queue-uri", "synchronous://", "A registered sfomuseum/go-activitypub/queue.DeliveryQueue URI used to deliver activities posted to account outboxes and sent while processing inbox activities.")
+	fs.IntVar(&max_attempts, "max-attempts", 5, "The maximum number of attempts to deliver activities posted to account outboxes and sent while processing inbox activities.")
+
+	fs.StringVar(&rate_limiter_uri, "rate-limiter-uri", "null://", "A registered sfomuseum/go-activitypub/ratelimit.RateLimiter URI used to store rate limit counters. Use a shared store (for example redis:// or awsdynamodb://) when running multiple server instances.")
+	fs.IntVar(&rate_limit_host, "rate-limit-host", 300, "The number of requests per minute allowed from a given remote host to inboxes, webfinger and actor endpoints. Until a request's signature has been verified the host is its remote address. If 0 then requests are not limited by host.")
+	fs.IntVar(&rate_limit_host_burst, "rate-limit-host-burst", 100, "The maximum number of requests that may be made in a burst from a given remote host.")
+	fs.IntVar(&rate_limit_actor, "rate-limit-actor", 60, "The number of requests per minute allowed from a given (verified signing) remote actor to inboxes. If 0 then requests are not limited by actor.")
+	fs.IntVar(&rate_limit_actor_burst, "rate-limit-actor-burst", 20, "The maximum number of requests that may be made in a burst by a given remote actor.")
+
+	fs.IntVar(&signature_clock_skew, "signature-clock-skew", 3600, "The maximum number of seconds that the Date header (and the created and expires signature parameters) of signed requests may differ from the current time. If 0 then these values are not checked.")
 
 	fs.BoolVar(&allow_remote_icon_uri, "allow-remote-icon-uri", false, "Allow account icons hosted on a remote host.")
 	fs.BoolVar(&verbose, "verbose", false, "Enable verbose (debug) logging.")
@@ -7,6 +7,7 @@ import (
 	"net/http"
 
 	"github.com/rs/cors"
+	"github.com/sfomuseum/go-activitypub/queue"
 	"github.com/sfomuseum/go-activitypub/www"
 )
 
@@ -38,12 +39,43 @@ func webfingerHandlerFunc(ctx context.Context) (http.Handler, error) {
 		return nil, err
 	}
 
+	// Webfinger requests are not checked against the allowlist because they are not signed.
+
+	wf_handler, err = rateLimitHandler(wf_handler)
+
+	if err != nil {
+		return nil, err
+	}
+
 	wf_handler = cors.Default().Handler(wf_handler)
 	return wf_handler, nil
 }
 
 func inboxPostHandlerFunc(ctx context.Context) (http.Handler, error) {
 
+	opts, err := inboxPostHandlerOptions(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return www.InboxPostHandler(opts)
+}
+
+func sharedInboxPostHandlerFunc(ctx context.Context) (http.Handler, error) {
+
+	opts, err := inboxPostHandlerOptions(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return www.SharedInboxPostHandler(opts)
+}
+
+// inboxPostHandlerOptions returns the `www.InboxPostHandlerOptions` shared by the account-specific and shared inbox handlers.
+func inboxPostHandlerOptions(ctx context.Context) (*www.InboxPostHandlerOptions, error) {
+
 	// START OF do this concurrently?
 	// Probably only marginally faster at the expense of hard-to-follow code...
 
@@ -110,6 +142,55 @@ func inboxPostHandlerFunc(ctx context.Context) (http.Handler, error) {
 		return nil, fmt.Errorf("Failed to set up follower database configuration, %w", setupPostsDatabaseError)
 	}
 
+	setupFollowRequestsDatabaseOnce.Do(setupFollowRequestsDatabase)
+
+	if setupFollowRequestsDatabaseError != nil {
+		slog.Error("Failed to set up follow requests database configuration", "error", setupFollowRequestsDatabaseError)
+		return nil, fmt.Errorf("Failed to set up follow requests database configuration, %w", setupFollowRequestsDatabaseError)
+	}
+
+	setupActorsDatabaseOnce.Do(setupActorsDatabase)
+
+	if setupActorsDatabaseError != nil {
+		slog.Error("Failed to set up actors database configuration", "error", setupActorsDatabaseError)
+		return nil, fmt.Errorf("Failed to set up actors database configuration, %w", setupActorsDatabaseError)
+	}
+
+	setupDomainBlocksDatabaseOnce.Do(setupDomainBlocksDatabase)
+
+	if setupDomainBlocksDatabaseError != nil {
+		slog.Error("Failed to set up domain blocks database configuration", "error", setupDomainBlocksDatabaseError)
+		return nil, fmt.Errorf("Failed to set up domain blocks database configuration, %w", setupDomainBlocksDatabaseError)
+	}
+
+	setupDomainAllowsDatabaseOnce.Do(setupDomainAllowsDatabase)
+
+	if setupDomainAllowsDatabaseError != nil {
+		slog.Error("Failed to set up domain allows database configuration", "error", setupDomainAllowsDatabaseError)
+		return nil, fmt.Errorf("Failed to set up domain allows database configuration, %w", setupDomainAllowsDatabaseError)
+	}
+
+	setupRateLimiterOnce.Do(setupRateLimiter)
+
+	if setupRateLimiterError != nil {
+		slog.Error("Failed to set up rate limiter", "error", setupRateLimiterError)
+		return nil, fmt.Errorf("Failed to set up rate limiter, %w", setupRateLimiterError)
+	}
+
+	setupReportsDatabaseOnce.Do(setupReportsDatabase)
+
+	if setupReportsDatabaseError != nil {
+		slog.Error("Failed to set up reports database configuration", "error", setupReportsDatabaseError)
+		return nil, fmt.Errorf("Failed to set up reports database configuration, %w", setupReportsDatabaseError)
+	}
+
+	setupReceivedActivitiesDatabaseOnce.Do(setupReceivedActivitiesDatabase)
+
+	if setupReceivedActivitiesDatabaseError != nil {
+		slog.Error("Failed to set up received activities database configuration", "error", setupReceivedActivitiesDatabaseError)
+		return nil, fmt.Errorf("Failed to set up received activities database configuration, %w", setupReceivedActivitiesDatabaseError)
+	}
+
 	setupProcessMessageQueueOnce.Do(setupProcessMessageQueue)
 
 	if setupProcessMessageQueueError != nil {
@@ -124,29 +205,106 @@ func inboxPostHandlerFunc(ctx context.Context) (http.Handler, error) {
 		return nil, fmt.Errorf("Failed to set up process follow queue, %w", setupProcessFollowerQueueError)
 	}
 
+	setupDeliveriesDatabaseOnce.Do(setupDeliveriesDatabase)
+
+	if setupDeliveriesDatabaseError != nil {
+		slog.Error("Failed to set up deliveries database configuration", "error", setupDeliveriesDatabaseError)
+		return nil, fmt.Errorf("Failed to set up deliveries database configuration, %w", setupDeliveriesDatabaseError)
+	}
+
+	setupDeliveryQueueOnce.Do(setupDeliveryQueue)
+
+	if setupDeliveryQueueError != nil {
+		slog.Error("Failed to set up delivery queue configuration", "error", setupDeliveryQueueError)
+		return nil, fmt.Errorf("Failed to set up delivery queue configuration, %w", setupDeliveryQueueError)
+	}
+
 	// END OF do this concurrently?
 
 	opts := &www.InboxPostHandlerOptions{
-		AccountsDatabase:     accounts_db,
-		FollowersDatabase:    followers_db,
-		FollowingDatabase:    following_db,
-		NotesDatabase:        notes_db,
-		MessagesDatabase:     messages_db,
-		BlocksDatabase:       blocks_db,
-		PostsDatabase:        posts_db,
-		LikesDatabase:        likes_db,
-		BoostsDatabase:       boosts_db,
-		URIs:                 run_opts.URIs,
-		AllowFollow:          run_opts.AllowFollow,
-		AllowCreate:          run_opts.AllowCreate,
-		AllowLikes:           run_opts.AllowLikes,
-		AllowBoosts:          run_opts.AllowBoosts,
-		AllowMentions:        run_opts.AllowMentions,
-		ProcessMessageQueue:  process_message_queue,
-		ProcessFollowerQueue: process_follower_queue,
+		AccountsDatabase:           accounts_db,
+		FollowersDatabase:          followers_db,
+		FollowingDatabase:          following_db,
+		NotesDatabase:              notes_db,
+		MessagesDatabase:           messages_db,
+		BlocksDatabase:             blocks_db,
+		DomainBlocksDatabase:       domain_blocks_db,
+		DomainAllowsDatabase:       domain_allows_db,
+		PostsDatabase:              posts_db,
+		LikesDatabase:              likes_db,
+		BoostsDatabase:             boosts_db,
+		FollowRequestsDatabase:     follow_requests_db,
+		ActorsDatabase:             actors_db,
+		ActorsTTL:                  run_opts.ActorsTTL,
+		ReceivedActivitiesDatabase: received_activities_db,
+		ReportsDatabase:            reports_db,
+		DeliveriesDatabase:         deliveries_db,
+		DeliveryQueue:              delivery_queue,
+		MaxAttempts:                run_opts.MaxAttempts,
+		SignatureClockSkew:         run_opts.SignatureClockSkew,
+		RateLimiter:                rate_limiter,
+		HostRateLimit:              run_opts.HostRateLimit,
+		ActorRateLimit:             run_opts.ActorRateLimit,
+		URIs:                       run_opts.URIs,
+		AllowFollow:                run_opts.AllowFollow,
+		AllowCreate:                run_opts.AllowCreate,
+		AllowLikes:                 run_opts.AllowLikes,
+		AllowBoosts:                run_opts.AllowBoosts,
+		AllowMentions:              run_opts.AllowMentions,
+		ProcessMessageQueue:        process_message_queue,
+		ProcessFollowerQueue:       process_follower_queue,
 	}
 
-	return www.InboxPostHandler(opts)
+	if run_opts.InboxActivityHandlers != nil {
+
+		activity_handlers, err := run_opts.InboxActivityHandlers(opts)
+
+		if err != nil {
+			slog.Error("Failed to derive custom inbox activity handlers", "error", err)
+			return nil, fmt.Errorf("Failed to derive custom inbox activity handlers, %w", err)
+		}
+
+		opts.Activities = activity_handlers
+	}
+
+	if run_opts.InboxQueueURI != "" {
+
+		setupQueuedActivitiesDatabaseOnce.Do(setupQueuedActivitiesDatabase)
+
+		if setupQueuedActivitiesDatabaseError != nil {
+			slog.Error("Failed to set up queued activities database configuration", "error", setupQueuedActivitiesDatabaseError)
+			return nil, fmt.Errorf("Failed to set up queued activities database configuration, %w", setupQueuedActivitiesDatabaseError)
+		}
+
+		setupInboxQueueOnce.Do(setupInboxQueue)
+
+		if setupInboxQueueError != nil {
+			slog.Error("Failed to set up inbox queue", "error", setupInboxQueueError)
+			return nil, fmt.Errorf("Failed to set up inbox queue, %w", setupInboxQueueError)
+		}
+
+		opts.QueuedActivitiesDatabase = queued_activities_db
+		opts.InboxQueue = inbox_queue
+
+		// Synchronous queues process activities in this process so they need to be
+		// told how to do that
+
+		sync_queue, ok := inbox_queue.(*queue.SynchronousInboxQueue)
+
+		if ok {
+
+			process_func, err := www.NewProcessQueuedInboxActivityFunc(opts)
+
+			if err != nil {
+				slog.Error("Failed to create process queued inbox activity function", "error", err)
+				return nil, fmt.Errorf("Failed to create process queued inbox activity function, %w", err)
+			}
+
+			sync_queue.SetProcessInboxActivityFunc(process_func)
+		}
+	}
+
+	return opts, nil
 }
 
 func outboxGetHandlerFunc(ctx context.Context) (http.Handler, error) {
@@ -165,13 +323,27 @@ func outboxGetHandlerFunc(ctx context.Context) (http.Handler, error) {
 		return nil, fmt.Errorf("Failed to set up follower database configuration, %w", setupPostsDatabaseError)
 	}
 
+	setupActivitiesDatabaseOnce.Do(setupActivitiesDatabase)
+
+	if setupActivitiesDatabaseError != nil {
+		slog.Error("Failed to set up activities database configuration", "error", setupActivitiesDatabaseError)
+		return nil, fmt.Errorf("Failed to set up activities database configuration, %w", setupActivitiesDatabaseError)
+	}
+
 	opts := &www.OutboxGetHandlerOptions{
-		AccountsDatabase: accounts_db,
-		PostsDatabase:    posts_db,
-		URIs:             run_opts.URIs,
+		AccountsDatabase:   accounts_db,
+		PostsDatabase:      posts_db,
+		ActivitiesDatabase: activities_db,
+		URIs:               run_opts.URIs,
+	}
+
+	h, err := www.OutboxGetHandler(opts)
+
+	if err != nil {
+		return nil, err
 	}
 
-	return www.OutboxGetHandler(opts)
+	return allowlistHandler(h, false)
 }
 
 func outboxPostHandlerFunc(ctx context.Context) (http.Handler, error) {
@@ -183,17 +355,90 @@ func outboxPostHandlerFunc(ctx context.Context) (http.Handler, error) {
 		return nil, fmt.Errorf("Failed to set up account database configuration, %w", setupAccountsDatabaseError)
 	}
 
+	setupAccessTokensDatabaseOnce.Do(setupAccessTokensDatabase)
+
+	if setupAccessTokensDatabaseError != nil {
+		slog.Error("Failed to set up access tokens database configuration", "error", setupAccessTokensDatabaseError)
+		return nil, fmt.Errorf("Failed to set up access tokens database configuration, %w", setupAccessTokensDatabaseError)
+	}
+
+	setupActivitiesDatabaseOnce.Do(setupActivitiesDatabase)
+
+	if setupActivitiesDatabaseError != nil {
+		slog.Error("Failed to set up activities database configuration", "error", setupActivitiesDatabaseError)
+		return nil, fmt.Errorf("Failed to set up activities database configuration, %w", setupActivitiesDatabaseError)
+	}
+
 	setupPostsDatabaseOnce.Do(setupPostsDatabase)
 
 	if setupPostsDatabaseError != nil {
-		slog.Error("Failed to set up follower database configuration", "error", setupPostsDatabaseError)
-		return nil, fmt.Errorf("Failed to set up follower database configuration, %w", setupPostsDatabaseError)
+		slog.Error("Failed to set up posts database configuration", "error", setupPostsDatabaseError)
+		return nil, fmt.Errorf("Failed to set up posts database configuration, %w", setupPostsDatabaseError)
+	}
+
+	setupPostTagsDatabaseOnce.Do(setupPostTagsDatabase)
+
+	if setupPostTagsDatabaseError != nil {
+		slog.Error("Failed to set up post tags database configuration", "error", setupPostTagsDatabaseError)
+		return nil, fmt.Errorf("Failed to set up post tags database configuration, %w", setupPostTagsDatabaseError)
+	}
+
+	setupFollowersDatabaseOnce.Do(setupFollowersDatabase)
+
+	if setupFollowersDatabaseError != nil {
+		slog.Error("Failed to set up followers database configuration", "error", setupFollowersDatabaseError)
+		return nil, fmt.Errorf("Failed to set up followers database configuration, %w", setupFollowersDatabaseError)
+	}
+
+	setupFollowingDatabaseOnce.Do(setupFollowingDatabase)
+
+	if setupFollowingDatabaseError != nil {
+		slog.Error("Failed to set up following database configuration", "error", setupFollowingDatabaseError)
+		return nil, fmt.Errorf("Failed to set up following database configuration, %w", setupFollowingDatabaseError)
+	}
+
+	setupDeliveriesDatabaseOnce.Do(setupDeliveriesDatabase)
+
+	if setupDeliveriesDatabaseError != nil {
+		slog.Error("Failed to set up deliveries database configuration", "error", setupDeliveriesDatabaseError)
+		return nil, fmt.Errorf("Failed to set up deliveries database configuration, %w", setupDeliveriesDatabaseError)
+	}
+
+	setupDomainBlocksDatabaseOnce.Do(setupDomainBlocksDatabase)
+
+	if setupDomainBlocksDatabaseError != nil {
+		slog.Error("Failed to set up domain blocks database configuration", "error", setupDomainBlocksDatabaseError)
+		return nil, fmt.Errorf("Failed to set up domain blocks database configuration, %w", setupDomainBlocksDatabaseError)
+	}
+
+	setupDomainAllowsDatabaseOnce.Do(setupDomainAllowsDatabase)
+
+	if setupDomainAllowsDatabaseError != nil {
+		slog.Error("Failed to set up domain allows database configuration", "error", setupDomainAllowsDatabaseError)
+		return nil, fmt.Errorf("Failed to set up domain allows database configuration, %w", setupDomainAllowsDatabaseError)
+	}
+
+	setupDeliveryQueueOnce.Do(setupDeliveryQueue)
+
+	if setupDeliveryQueueError != nil {
+		slog.Error("Failed to set up delivery queue configuration", "error", setupDeliveryQueueError)
+		return nil, fmt.Errorf("Failed to set up delivery queue configuration, %w", setupDeliveryQueueError)
 	}
 
 	opts := &www.OutboxPostHandlerOptions{
-		AccountsDatabase: accounts_db,
-		PostsDatabase:    posts_db,
-		URIs:             run_opts.URIs,
+		AccountsDatabase:     accounts_db,
+		AccessTokensDatabase: access_tokens_db,
+		ActivitiesDatabase:   activities_db,
+		PostsDatabase:        posts_db,
+		PostTagsDatabase:     post_tags_db,
+		FollowersDatabase:    followers_db,
+		FollowingDatabase:    following_db,
+		DeliveriesDatabase:   deliveries_db,
+		DomainBlocksDatabase: domain_blocks_db,
+		DomainAllowsDatabase: domain_allows_db,
+		DeliveryQueue:        delivery_queue,
+		MaxAttempts:          run_opts.MaxAttempts,
+		URIs:                 run_opts.URIs,
 	}
 
 	return www.OutboxPostHandler(opts)
@@ -217,6 +462,31 @@ func iconHandlerFunc(ctx context.Context) (http.Handler, error) {
 	return www.IconHandler(opts)
 }
 
+func mediaHandlerFunc(ctx context.Context) (http.Handler, error) {
+
+	setupAccountsDatabaseOnce.Do(setupAccountsDatabase)
+
+	if setupAccountsDatabaseError != nil {
+		slog.Error("Failed to set up account database configuration", "error", setupAccountsDatabaseError)
+		return nil, fmt.Errorf("Failed to set up account database configuration, %w", setupAccountsDatabaseError)
+	}
+
+	setupMediaDatabaseOnce.Do(setupMediaDatabase)
+
+	if setupMediaDatabaseError != nil {
+		slog.Error("Failed to set up media database configuration", "error", setupMediaDatabaseError)
+		return nil, fmt.Errorf("Failed to set up media database configuration, %w", setupMediaDatabaseError)
+	}
+
+	opts := &www.MediaHandlerOptions{
+		AccountsDatabase: accounts_db,
+		MediaDatabase:    media_db,
+		URIs:             run_opts.URIs,
+	}
+
+	return www.MediaHandler(opts)
+}
+
 func followingHandlerFunc(ctx context.Context) (http.Handler, error) {
 
 	setupAccountsDatabaseOnce.Do(setupAccountsDatabase)
@@ -239,7 +509,13 @@ func followingHandlerFunc(ctx context.Context) (http.Handler, error) {
 		URIs:              run_opts.URIs,
 	}
 
-	return www.FollowingHandler(opts)
+	h, err := www.FollowingHandler(opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return allowlistHandler(h, false)
 }
 
 func followersHandlerFunc(ctx context.Context) (http.Handler, error) {
@@ -264,5 +540,60 @@ func followersHandlerFunc(ctx context.Context) (http.Handler, error) {
 		URIs:              run_opts.URIs,
 	}
 
-	return www.FollowersHandler(opts)
+	h, err := www.FollowersHandler(opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return allowlistHandler(h, false)
+}
+
+// allowlistHandler wraps 'h' in a `www.AllowlistHandler` handler that, if the server is running in allowlist mode,
+// refuses requests that are not signed by actors on hosts in the allowlist. If 'activitystreams_only' is true then only requests for
+// ActivityStreams documents are checked.
+func allowlistHandler(h http.Handler, activitystreams_only bool) (http.Handler, error) {
+
+	setupDomainAllowsDatabaseOnce.Do(setupDomainAllowsDatabase)
+
+	if setupDomainAllowsDatabaseError != nil {
+		slog.Error("Failed to set up domain allows database configuration", "error", setupDomainAllowsDatabaseError)
+		return nil, fmt.Errorf("Failed to set up domain allows database configuration, %w", setupDomainAllowsDatabaseError)
+	}
+
+	setupActorsDatabaseOnce.Do(setupActorsDatabase)
+
+	if setupActorsDatabaseError != nil {
+		slog.Error("Failed to set up actors database configuration", "error", setupActorsDatabaseError)
+		return nil, fmt.Errorf("Failed to set up actors database configuration, %w", setupActorsDatabaseError)
+	}
+
+	opts := &www.AllowlistHandlerOptions{
+		DomainAllowsDatabase: domain_allows_db,
+		ActivityStreamsOnly:  activitystreams_only,
+		ActorsDatabase:       actors_db,
+		ActorsTTL:            run_opts.ActorsTTL,
+		SignatureClockSkew:   run_opts.SignatureClockSkew,
+		URIs:                 run_opts.URIs,
+	}
+
+	return www.AllowlistHandler(opts, h), nil
+}
+
+// rateLimitHandler wraps 'h' in a `www.RateLimitHandler` handler that applies the server's per-host rate limit.
+func rateLimitHandler(h http.Handler) (http.Handler, error) {
+
+	setupRateLimiterOnce.Do(setupRateLimiter)
+
+	if setupRateLimiterError != nil {
+		slog.Error("Failed to set up rate limiter", "error", setupRateLimiterError)
+		return nil, fmt.Errorf("Failed to set up rate limiter, %w", setupRateLimiterError)
+	}
+
+	opts := &www.RateLimitHandlerOptions{
+		RateLimiter: rate_limiter,
+		HostLimit:   run_opts.HostRateLimit,
+	}
+
+	return www.RateLimitHandler(opts, h), nil
 }
@@ -46,6 +46,18 @@ func accountHandlerFunc(ctx context.Context) (http.Handler, error) {
 		return nil, fmt.Errorf("Failed to create account handler, %w", err)
 	}
 
+	h, err = allowlistHandler(h, true)
+
+	if err != nil {
+		return nil, err
+	}
+
+	h, err = rateLimitHandler(h)
+
+	if err != nil {
+		return nil, err
+	}
+
 	if run_opts.AccountHandlerMiddleware != nil {
 		h = run_opts.AccountHandlerMiddleware(h)
 	}
@@ -76,12 +88,36 @@ func postHandlerFunc(ctx context.Context) (http.Handler, error) {
 		return nil, fmt.Errorf("Failed to set up post tags database configuration, %w", setupPostTagsDatabaseError)
 	}
 
+	setupLikesDatabaseOnce.Do(setupLikesDatabase)
+
+	if setupLikesDatabaseError != nil {
+		slog.Error("Failed to set up likes database configuration", "error", setupLikesDatabaseError)
+		return nil, fmt.Errorf("Failed to set up likes database configuration, %w", setupLikesDatabaseError)
+	}
+
+	setupTombstonesDatabaseOnce.Do(setupTombstonesDatabase)
+
+	if setupTombstonesDatabaseError != nil {
+		slog.Error("Failed to set up tombstones database configuration", "error", setupTombstonesDatabaseError)
+		return nil, fmt.Errorf("Failed to set up tombstones database configuration, %w", setupTombstonesDatabaseError)
+	}
+
+	setupMediaDatabaseOnce.Do(setupMediaDatabase)
+
+	if setupMediaDatabaseError != nil {
+		slog.Error("Failed to set up media database configuration", "error", setupMediaDatabaseError)
+		return nil, fmt.Errorf("Failed to set up media database configuration, %w", setupMediaDatabaseError)
+	}
+
 	opts := &www.PostHandlerOptions{
-		AccountsDatabase: accounts_db,
-		PostsDatabase:    posts_db,
-		PostTagsDatabase: post_tags_db,
-		URIs:             run_opts.URIs,
-		Templates:        run_opts.Templates,
+		AccountsDatabase:   accounts_db,
+		PostsDatabase:      posts_db,
+		PostTagsDatabase:   post_tags_db,
+		LikesDatabase:      likes_db,
+		TombstonesDatabase: tombstones_db,
+		MediaDatabase:      media_db,
+		URIs:               run_opts.URIs,
+		Templates:          run_opts.Templates,
 	}
 
 	h, err := www.PostHandler(opts)
@@ -90,6 +126,12 @@ func postHandlerFunc(ctx context.Context) (http.Handler, error) {
 		return nil, fmt.Errorf("Failed to create post handler, %w", err)
 	}
 
+	h, err = allowlistHandler(h, true)
+
+	if err != nil {
+		return nil, err
+	}
+
 	if run_opts.AccountHandlerMiddleware != nil {
 		h = run_opts.AccountHandlerMiddleware(h)
 	}
@@ -7,10 +7,13 @@ import (
 	"html/template"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/mitchellh/copystructure"
+	"github.com/sfomuseum/go-activitypub/ratelimit"
 	"github.com/sfomuseum/go-activitypub/templates/html"
 	"github.com/sfomuseum/go-activitypub/uris"
+	"github.com/sfomuseum/go-activitypub/www"
 	"github.com/sfomuseum/go-flags/flagset"
 )
 
@@ -22,25 +25,48 @@ type MiddlewareFunc func(http.Handler) http.Handler
 // to a [http.ServeMux] instance.
 type CustomHandlersFunc func(*http.ServeMux) error
 
+// InboxActivityHandlersFunc is an optional function for assigning custom, activity-specific
+// handlers to the inbox POST handler. The handlers it returns will replace, or supplement,
+// those returned by [www.DefaultInboxActivityHandlers].
+type InboxActivityHandlersFunc func(*www.InboxPostHandlerOptions) (map[string]http.Handler, error)
+
 type RunOptions struct {
-	ServerURI             string
-	URIs                  *uris.URIs
-	AccountsDatabaseURI   string
-	AliasesDatabaseURI    string
-	FollowersDatabaseURI  string
-	FollowingDatabaseURI  string
-	NotesDatabaseURI      string
-	MessagesDatabaseURI   string
-	BlocksDatabaseURI     string
-	PostsDatabaseURI      string
-	PostTagsDatabaseURI   string
-	PropertiesDatabaseURI string
-	LikesDatabaseURI      string
-	BoostsDatabaseURI     string
-	AllowFollow           bool
-	AllowCreate           bool
-	AllowLikes            bool
-	AllowBoosts           bool
+	ServerURI                     string
+	URIs                          *uris.URIs
+	AccountsDatabaseURI           string
+	AliasesDatabaseURI            string
+	FollowersDatabaseURI          string
+	FollowingDatabaseURI          string
+	NotesDatabaseURI              string
+	MessagesDatabaseURI           string
+	BlocksDatabaseURI             string
+	DomainBlocksDatabaseURI       string
+	DomainAllowsDatabaseURI       string
+	PostsDatabaseURI              string
+	PostTagsDatabaseURI           string
+	PropertiesDatabaseURI         string
+	LikesDatabaseURI              string
+	BoostsDatabaseURI             string
+	FollowRequestsDatabaseURI     string
+	ActorsDatabaseURI             string
+	ReceivedActivitiesDatabaseURI string
+	ReportsDatabaseURI            string
+	QueuedActivitiesDatabaseURI   string
+	ActivitiesDatabaseURI         string
+	AccessTokensDatabaseURI       string
+	DeliveriesDatabaseURI         string
+	TombstonesDatabaseURI         string
+	MediaDatabaseURI              string
+	ActorsTTL                     time.Duration
+	SignatureClockSkew            time.Duration
+	RateLimiterURI                string
+	HostRateLimit                 *ratelimit.Limit
+	ActorRateLimit                *ratelimit.Limit
+	AllowFollow                   bool
+	AllowCreate                   bool
+	AllowLikes                    bool
+	AllowBoosts                   bool
+	AllowlistMode                 bool
 	// Allows posts to accounts not followed by author but where account is mentioned in post
 	AllowMentions            bool
 	AllowRemoteIconURI       bool
@@ -50,8 +76,12 @@ type RunOptions struct {
 	AccountHandlerMiddleware MiddlewareFunc
 	PostHandlerMiddleware    MiddlewareFunc
 	CustomHandlers           CustomHandlersFunc
+	InboxActivityHandlers    InboxActivityHandlersFunc
 	ProcessMessageQueueURI   string
 	ProcessFollowerQueueURI  string
+	InboxQueueURI            string
+	DeliveryQueueURI         string
+	MaxAttempts              int
 }
 
 func OptionsFromFlagSet(ctx context.Context, fs *flag.FlagSet) (*RunOptions, error) {
@@ -86,31 +116,52 @@ func OptionsFromFlagSet(ctx context.Context, fs *flag.FlagSet) (*RunOptions, err
 	}
 
 	opts := &RunOptions{
-		AccountsDatabaseURI:     accounts_database_uri,
-		AliasesDatabaseURI:      aliases_database_uri,
-		FollowersDatabaseURI:    followers_database_uri,
-		FollowingDatabaseURI:    following_database_uri,
-		NotesDatabaseURI:        notes_database_uri,
-		MessagesDatabaseURI:     messages_database_uri,
-		PostsDatabaseURI:        posts_database_uri,
-		PostTagsDatabaseURI:     post_tags_database_uri,
-		BlocksDatabaseURI:       blocks_database_uri,
-		LikesDatabaseURI:        likes_database_uri,
-		BoostsDatabaseURI:       boosts_database_uri,
-		PropertiesDatabaseURI:   properties_database_uri,
-		ServerURI:               server_uri,
-		URIs:                    uris_table,
-		AllowFollow:             allow_follow,
-		AllowCreate:             allow_create,
-		AllowBoosts:             allow_boosts,
-		AllowMentions:           allow_mentions,
-		AllowLikes:              allow_likes,
-		AllowRemoteIconURI:      allow_remote_icon_uri,
-		Verbose:                 verbose,
-		Disabled:                disabled,
-		Templates:               t,
-		ProcessMessageQueueURI:  process_message_queue_uri,
-		ProcessFollowerQueueURI: process_follower_queue_uri,
+		AccountsDatabaseURI:           accounts_database_uri,
+		AliasesDatabaseURI:            aliases_database_uri,
+		FollowersDatabaseURI:          followers_database_uri,
+		FollowingDatabaseURI:          following_database_uri,
+		NotesDatabaseURI:              notes_database_uri,
+		MessagesDatabaseURI:           messages_database_uri,
+		PostsDatabaseURI:              posts_database_uri,
+		PostTagsDatabaseURI:           post_tags_database_uri,
+		BlocksDatabaseURI:             blocks_database_uri,
+		DomainBlocksDatabaseURI:       domain_blocks_database_uri,
+		DomainAllowsDatabaseURI:       domain_allows_database_uri,
+		LikesDatabaseURI:              likes_database_uri,
+		BoostsDatabaseURI:             boosts_database_uri,
+		FollowRequestsDatabaseURI:     follow_requests_database_uri,
+		ActorsDatabaseURI:             actors_database_uri,
+		ReceivedActivitiesDatabaseURI: received_activities_database_uri,
+		ReportsDatabaseURI:            reports_database_uri,
+		QueuedActivitiesDatabaseURI:   queued_activities_database_uri,
+		ActivitiesDatabaseURI:         activities_database_uri,
+		AccessTokensDatabaseURI:       access_tokens_database_uri,
+		DeliveriesDatabaseURI:         deliveries_database_uri,
+		TombstonesDatabaseURI:         tombstones_database_uri,
+		MediaDatabaseURI:              media_database_uri,
+		ActorsTTL:                     time.Duration(actors_ttl) * time.Second,
+		SignatureClockSkew:            time.Duration(signature_clock_skew) * time.Second,
+		RateLimiterURI:                rate_limiter_uri,
+		HostRateLimit:                 ratelimit.PerMinute(rate_limit_host, rate_limit_host_burst),
+		ActorRateLimit:                ratelimit.PerMinute(rate_limit_actor, rate_limit_actor_burst),
+		PropertiesDatabaseURI:         properties_database_uri,
+		ServerURI:                     server_uri,
+		URIs:                          uris_table,
+		AllowFollow:                   allow_follow,
+		AllowCreate:                   allow_create,
+		AllowBoosts:                   allow_boosts,
+		AllowlistMode:                 allowlist_mode,
+		AllowMentions:                 allow_mentions,
+		AllowLikes:                    allow_likes,
+		AllowRemoteIconURI:            allow_remote_icon_uri,
+		Verbose:                       verbose,
+		Disabled:                      disabled,
+		Templates:                     t,
+		ProcessMessageQueueURI:        process_message_queue_uri,
+		ProcessFollowerQueueURI:       process_follower_queue_uri,
+		InboxQueueURI:                 inbox_queue_uri,
+		DeliveryQueueURI:              delivery_queue_uri,
+		MaxAttempts:                   max_attempts,
 	}
 
 	return opts, nil
@@ -46,8 +46,11 @@ func RunWithOptions(ctx context.Context, opts *RunOptions) error {
 	webfinger_get := fmt.Sprintf("GET %s", webfinger.Endpoint)
 	account_get := fmt.Sprintf("GET %s", run_opts.URIs.Account)
 	inbox_post := fmt.Sprintf("POST %s", run_opts.URIs.Inbox)
+	shared_inbox_post := fmt.Sprintf("POST %s", run_opts.URIs.SharedInbox)
 	outbox_get := fmt.Sprintf("GET %s", run_opts.URIs.Outbox)
+	outbox_post := fmt.Sprintf("POST %s", run_opts.URIs.Outbox)
 	post_get := fmt.Sprintf("GET %s", run_opts.URIs.Post)
+	media_get := fmt.Sprintf("GET %s", run_opts.URIs.Media)
 
 	route_handlers := map[string]handlers.RouteHandlerFunc{
 
@@ -55,14 +58,15 @@ func RunWithOptions(ctx context.Context, opts *RunOptions) error {
 		account_get: accountHandlerFunc,
 		post_get:    postHandlerFunc,
 
-		// This needs to be fixed in aaronland/go-http-server/handler
-		// outbox_post:              outboxPostHandlerFunc,
 		run_opts.URIs.Icon:      iconHandlerFunc,
+		media_get:               mediaHandlerFunc,
 		run_opts.URIs.Following: followingHandlerFunc,
 		run_opts.URIs.Followers: followersHandlerFunc,
 		webfinger_get:           webfingerHandlerFunc,
 		inbox_post:              inboxPostHandlerFunc,
+		shared_inbox_post:       sharedInboxPostHandlerFunc,
 		outbox_get:              outboxGetHandlerFunc,
+		outbox_post:             outboxPostHandlerFunc,
 	}
 
 	route_handler_opts := &handlers.RouteHandlerOptions{
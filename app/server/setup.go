@@ -6,6 +6,7 @@ import (
 
 	"github.com/sfomuseum/go-activitypub/database"
 	"github.com/sfomuseum/go-activitypub/queue"
+	"github.com/sfomuseum/go-activitypub/ratelimit"
 )
 
 func setupAccountsDatabase() {
@@ -162,6 +163,192 @@ func setupBoostsDatabase() {
 	}
 }
 
+func setupFollowRequestsDatabase() {
+
+	ctx := context.Background()
+	var err error
+
+	// defined in vars.go
+	follow_requests_db, err = database.NewFollowRequestsDatabase(ctx, run_opts.FollowRequestsDatabaseURI)
+
+	if err != nil {
+		setupFollowRequestsDatabaseError = fmt.Errorf("Failed to set up follow requests database, %w", err)
+		return
+	}
+}
+
+func setupActorsDatabase() {
+
+	ctx := context.Background()
+	var err error
+
+	// defined in vars.go
+	actors_db, err = database.NewActorsDatabase(ctx, run_opts.ActorsDatabaseURI)
+
+	if err != nil {
+		setupActorsDatabaseError = fmt.Errorf("Failed to set up actors database, %w", err)
+		return
+	}
+}
+
+func setupDomainBlocksDatabase() {
+
+	ctx := context.Background()
+	var err error
+
+	// defined in vars.go
+	domain_blocks_db, err = database.NewDomainBlocksDatabase(ctx, run_opts.DomainBlocksDatabaseURI)
+
+	if err != nil {
+		setupDomainBlocksDatabaseError = fmt.Errorf("Failed to set up domain blocks database, %w", err)
+		return
+	}
+}
+
+func setupDomainAllowsDatabase() {
+
+	if !run_opts.AllowlistMode {
+		return
+	}
+
+	ctx := context.Background()
+	var err error
+
+	// defined in vars.go
+	domain_allows_db, err = database.NewDomainAllowsDatabase(ctx, run_opts.DomainAllowsDatabaseURI)
+
+	if err != nil {
+		setupDomainAllowsDatabaseError = fmt.Errorf("Failed to set up domain allows database, %w", err)
+		return
+	}
+}
+
+func setupRateLimiter() {
+
+	ctx := context.Background()
+	var err error
+
+	// defined in vars.go
+	rate_limiter, err = ratelimit.NewRateLimiter(ctx, run_opts.RateLimiterURI)
+
+	if err != nil {
+		setupRateLimiterError = fmt.Errorf("Failed to set up rate limiter, %w", err)
+		return
+	}
+}
+
+func setupReportsDatabase() {
+
+	ctx := context.Background()
+	var err error
+
+	// defined in vars.go
+	reports_db, err = database.NewReportsDatabase(ctx, run_opts.ReportsDatabaseURI)
+
+	if err != nil {
+		setupReportsDatabaseError = fmt.Errorf("Failed to set up reports database, %w", err)
+		return
+	}
+}
+
+func setupQueuedActivitiesDatabase() {
+
+	ctx := context.Background()
+	var err error
+
+	// defined in vars.go
+	queued_activities_db, err = database.NewQueuedActivitiesDatabase(ctx, run_opts.QueuedActivitiesDatabaseURI)
+
+	if err != nil {
+		setupQueuedActivitiesDatabaseError = fmt.Errorf("Failed to set up queued activities database, %w", err)
+		return
+	}
+}
+
+func setupActivitiesDatabase() {
+
+	ctx := context.Background()
+	var err error
+
+	// defined in vars.go
+	activities_db, err = database.NewActivitiesDatabase(ctx, run_opts.ActivitiesDatabaseURI)
+
+	if err != nil {
+		setupActivitiesDatabaseError = fmt.Errorf("Failed to set up activities database, %w", err)
+		return
+	}
+}
+
+func setupAccessTokensDatabase() {
+
+	ctx := context.Background()
+	var err error
+
+	// defined in vars.go
+	access_tokens_db, err = database.NewAccessTokensDatabase(ctx, run_opts.AccessTokensDatabaseURI)
+
+	if err != nil {
+		setupAccessTokensDatabaseError = fmt.Errorf("Failed to set up access tokens database, %w", err)
+		return
+	}
+}
+
+func setupDeliveriesDatabase() {
+
+	ctx := context.Background()
+	var err error
+
+	// defined in vars.go
+	deliveries_db, err = database.NewDeliveriesDatabase(ctx, run_opts.DeliveriesDatabaseURI)
+
+	if err != nil {
+		setupDeliveriesDatabaseError = fmt.Errorf("Failed to set up deliveries database, %w", err)
+		return
+	}
+}
+
+func setupTombstonesDatabase() {
+
+	ctx := context.Background()
+	var err error
+
+	// defined in vars.go
+	tombstones_db, err = database.NewTombstonesDatabase(ctx, run_opts.TombstonesDatabaseURI)
+
+	if err != nil {
+		setupTombstonesDatabaseError = fmt.Errorf("Failed to set up tombstones database, %w", err)
+		return
+	}
+}
+
+func setupMediaDatabase() {
+
+	ctx := context.Background()
+	var err error
+
+	// defined in vars.go
+	media_db, err = database.NewMediaDatabase(ctx, run_opts.MediaDatabaseURI)
+
+	if err != nil {
+		setupMediaDatabaseError = fmt.Errorf("Failed to set up media database, %w", err)
+		return
+	}
+}
+
+func setupReceivedActivitiesDatabase() {
+
+	ctx := context.Background()
+	var err error
+
+	// defined in vars.go
+	received_activities_db, err = database.NewReceivedActivitiesDatabase(ctx, run_opts.ReceivedActivitiesDatabaseURI)
+
+	if err != nil {
+		setupReceivedActivitiesDatabaseError = fmt.Errorf("Failed to set up received activities database, %w", err)
+		return
+	}
+}
+
 func setupPropertiesDatabase() {
 
 	ctx := context.Background()
@@ -203,3 +390,29 @@ func setupProcessFollowerQueue() {
 	}
 
 }
+
+func setupInboxQueue() {
+
+	ctx := context.Background()
+	var err error
+
+	inbox_queue, err = queue.NewInboxQueue(ctx, run_opts.InboxQueueURI)
+
+	if err != nil {
+		setupInboxQueueError = fmt.Errorf("Failed to create inbox queue, %w", err)
+		return
+	}
+}
+
+func setupDeliveryQueue() {
+
+	ctx := context.Background()
+	var err error
+
+	delivery_queue, err = queue.NewDeliveryQueue(ctx, run_opts.DeliveryQueueURI)
+
+	if err != nil {
+		setupDeliveryQueueError = fmt.Errorf("Failed to create delivery queue, %w", err)
+		return
+	}
+}
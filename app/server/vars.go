@@ -5,6 +5,7 @@ import (
 
 	"github.com/sfomuseum/go-activitypub/database"
 	"github.com/sfomuseum/go-activitypub/queue"
+	"github.com/sfomuseum/go-activitypub/ratelimit"
 )
 
 var run_opts *RunOptions
@@ -53,6 +54,59 @@ var boosts_db database.BoostsDatabase
 var setupBoostsDatabaseOnce sync.Once
 var setupBoostsDatabaseError error
 
+var follow_requests_db database.FollowRequestsDatabase
+var setupFollowRequestsDatabaseOnce sync.Once
+var setupFollowRequestsDatabaseError error
+
+var actors_db database.ActorsDatabase
+var setupActorsDatabaseOnce sync.Once
+var setupActorsDatabaseError error
+
+var domain_blocks_db database.DomainBlocksDatabase
+var setupDomainBlocksDatabaseOnce sync.Once
+var setupDomainBlocksDatabaseError error
+
+// domain_allows_db is only assigned when the server is running in allowlist mode
+var domain_allows_db database.DomainAllowsDatabase
+var setupDomainAllowsDatabaseOnce sync.Once
+var setupDomainAllowsDatabaseError error
+
+var rate_limiter ratelimit.RateLimiter
+var setupRateLimiterOnce sync.Once
+var setupRateLimiterError error
+
+var reports_db database.ReportsDatabase
+var setupReportsDatabaseOnce sync.Once
+var setupReportsDatabaseError error
+
+var queued_activities_db database.QueuedActivitiesDatabase
+var setupQueuedActivitiesDatabaseOnce sync.Once
+var setupQueuedActivitiesDatabaseError error
+
+var activities_db database.ActivitiesDatabase
+var setupActivitiesDatabaseOnce sync.Once
+var setupActivitiesDatabaseError error
+
+var access_tokens_db database.AccessTokensDatabase
+var setupAccessTokensDatabaseOnce sync.Once
+var setupAccessTokensDatabaseError error
+
+var deliveries_db database.DeliveriesDatabase
+var setupDeliveriesDatabaseOnce sync.Once
+var setupDeliveriesDatabaseError error
+
+var tombstones_db database.TombstonesDatabase
+var setupTombstonesDatabaseOnce sync.Once
+var setupTombstonesDatabaseError error
+
+var media_db database.MediaDatabase
+var setupMediaDatabaseOnce sync.Once
+var setupMediaDatabaseError error
+
+var received_activities_db database.ReceivedActivitiesDatabase
+var setupReceivedActivitiesDatabaseOnce sync.Once
+var setupReceivedActivitiesDatabaseError error
+
 var properties_db database.PropertiesDatabase
 var setupPropertiesDatabaseOnce sync.Once
 var setupPropertiesDatabaseError error
@@ -64,3 +118,11 @@ var setupProcessMessageQueueError error
 var process_follower_queue queue.ProcessFollowerQueue
 var setupProcessFollowerQueueOnce sync.Once
 var setupProcessFollowerQueueError error
+
+var inbox_queue queue.InboxQueue
+var setupInboxQueueOnce sync.Once
+var setupInboxQueueError error
+
+var delivery_queue queue.DeliveryQueue
+var setupDeliveryQueueOnce sync.Once
+var setupDeliveryQueueError error
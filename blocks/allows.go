@@ -0,0 +1,51 @@
+package blocks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/database"
+)
+
+// GetDomainAllowForHost returns the `activitypub.DomainAllow` instance that applies to 'host'. Domain allowances apply to
+// the domain being allowed and all of its subdomains so if 'host' is not allowed explicitly each of its parent domains
+// will be checked in turn, stopping at the first match. If no allowance applies then `activitypub.ErrNotFound` is returned.
+func GetDomainAllowForHost(ctx context.Context, db database.DomainAllowsDatabase, host string) (*activitypub.DomainAllow, error) {
+
+	for _, domain := range candidateDomains(host) {
+
+		a, err := db.GetDomainAllowWithDomain(ctx, domain)
+
+		if err == nil {
+			return a, nil
+		}
+
+		if err != activitypub.ErrNotFound {
+			return nil, fmt.Errorf("Failed to retrieve domain allow for %s, %w", domain, err)
+		}
+	}
+
+	return nil, activitypub.ErrNotFound
+}
+
+// IsAllowedHost returns a boolean value indicating whether the server is allowed to federate with 'host'. If 'db' is nil
+// then the server is not running in allowlist mode and all hosts are allowed.
+func IsAllowedHost(ctx context.Context, db database.DomainAllowsDatabase, host string) (bool, error) {
+
+	if db == nil {
+		return true, nil
+	}
+
+	_, err := GetDomainAllowForHost(ctx, db, host)
+
+	if err == activitypub.ErrNotFound {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,210 @@
+package blocks
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/database"
+)
+
+// MastodonBlocklistHeader is the list of columns used by Mastodon (and compatible) servers when exporting domain blocks as CSV data.
+var MastodonBlocklistHeader = []string{
+	"#domain",
+	"#severity",
+	"#reject_media",
+	"#reject_reports",
+	"#public_comment",
+	"#obfuscate",
+}
+
+// ImportMastodonBlocklist reads Mastodon-style CSV blocklist data from 'r' and adds a domain block for each row to 'db'. If the first row
+// is a header (for example "#domain,#severity,#reject_media,...") it is used to determine the order of the columns, otherwise the
+// columns defined in `MastodonBlocklistHeader` are assumed. Rows for domains that are already blocked are skipped unless 'overwrite'
+// is true in which case the existing block is updated. Obfuscated domains (containing "*") can not be matched and are skipped.
+// The number of domain blocks added or updated is returned.
+func ImportMastodonBlocklist(ctx context.Context, db database.DomainBlocksDatabase, r io.Reader, overwrite bool) (int, error) {
+
+	logger := slog.Default()
+
+	csv_r := csv.NewReader(r)
+	csv_r.FieldsPerRecord = -1
+	csv_r.TrimLeadingSpace = true
+
+	columns := make(map[string]int)
+
+	for idx, col := range MastodonBlocklistHeader {
+		columns[strings.TrimLeft(col, "#")] = idx
+	}
+
+	count := 0
+	first := true
+
+	for {
+
+		row, err := csv_r.Read()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return count, fmt.Errorf("Failed to read CSV row, %w", err)
+		}
+
+		if first {
+
+			first = false
+
+			if len(row) > 0 && strings.TrimLeft(strings.ToLower(row[0]), "#") == "domain" {
+
+				columns = make(map[string]int)
+
+				for idx, col := range row {
+					columns[strings.TrimLeft(strings.ToLower(strings.TrimSpace(col)), "#")] = idx
+				}
+
+				continue
+			}
+		}
+
+		value := func(col string) string {
+
+			idx, ok := columns[col]
+
+			if !ok || idx >= len(row) {
+				return ""
+			}
+
+			return strings.TrimSpace(row[idx])
+		}
+
+		domain := strings.ToLower(value("domain"))
+
+		if domain == "" {
+			continue
+		}
+
+		if strings.Contains(domain, "*") {
+			logger.Warn("Domain is obfuscated, skipping", "domain", domain)
+			continue
+		}
+
+		severity := strings.ToLower(value("severity"))
+
+		if severity == "" {
+			severity = activitypub.DomainBlockSuspend
+		}
+
+		if !activitypub.IsValidDomainBlockSeverity(severity) {
+			return count, fmt.Errorf("Invalid severity '%s' for domain %s", severity, domain)
+		}
+
+		b, err := db.GetDomainBlockWithDomain(ctx, domain)
+		is_new := false
+
+		switch {
+		case err == activitypub.ErrNotFound:
+
+			b, err = activitypub.NewDomainBlock(ctx, domain, severity)
+
+			if err != nil {
+				return count, fmt.Errorf("Failed to create domain block for %s, %w", domain, err)
+			}
+
+			is_new = true
+
+		case err != nil:
+			return count, fmt.Errorf("Failed to retrieve domain block for %s, %w", domain, err)
+		default:
+
+			if !overwrite {
+				logger.Debug("Domain is already blocked, skipping", "domain", domain)
+				continue
+			}
+
+			b.Severity = severity
+			b.LastModified = time.Now().Unix()
+		}
+
+		b.RejectMedia = parseBlocklistBool(value("reject_media"))
+		b.RejectReports = parseBlocklistBool(value("reject_reports"))
+		b.PublicComment = value("public_comment")
+		b.Obfuscate = parseBlocklistBool(value("obfuscate"))
+
+		if is_new {
+			err = db.AddDomainBlock(ctx, b)
+		} else {
+			err = db.UpdateDomainBlock(ctx, b)
+		}
+
+		if err != nil {
+			return count, fmt.Errorf("Failed to store domain block for %s, %w", domain, err)
+		}
+
+		logger.Debug("Imported domain block", "domain", domain, "severity", severity)
+		count += 1
+	}
+
+	return count, nil
+}
+
+// ExportMastodonBlocklist writes all the domain blocks in 'db' to 'wr' as Mastodon-style CSV blocklist data, using the columns
+// defined in `MastodonBlocklistHeader`.
+func ExportMastodonBlocklist(ctx context.Context, db database.DomainBlocksDatabase, wr io.Writer) error {
+
+	csv_wr := csv.NewWriter(wr)
+
+	err := csv_wr.Write(MastodonBlocklistHeader)
+
+	if err != nil {
+		return fmt.Errorf("Failed to write CSV header, %w", err)
+	}
+
+	blocks_cb := func(ctx context.Context, b *activitypub.DomainBlock) error {
+
+		row := []string{
+			b.Domain,
+			b.Severity,
+			strconv.FormatBool(b.RejectMedia),
+			strconv.FormatBool(b.RejectReports),
+			b.PublicComment,
+			strconv.FormatBool(b.Obfuscate),
+		}
+
+		return csv_wr.Write(row)
+	}
+
+	err = db.GetDomainBlocks(ctx, blocks_cb)
+
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve domain blocks, %w", err)
+	}
+
+	csv_wr.Flush()
+
+	err = csv_wr.Error()
+
+	if err != nil {
+		return fmt.Errorf("Failed to write CSV data, %w", err)
+	}
+
+	return nil
+}
+
+func parseBlocklistBool(v string) bool {
+
+	b, err := strconv.ParseBool(strings.ToLower(v))
+
+	if err != nil {
+		return false
+	}
+
+	return b
+}
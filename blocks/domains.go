@@ -0,0 +1,115 @@
+package blocks
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/database"
+)
+
+// GetDomainBlockForHost returns the `activitypub.DomainBlock` instance that applies to 'host'. Domain blocks apply to
+// the domain being blocked and all of its subdomains so if 'host' is not blocked explicitly each of its parent domains
+// will be checked in turn, stopping at the first match. If no block applies, or 'db' is nil, then `activitypub.ErrNotFound`
+// is returned.
+func GetDomainBlockForHost(ctx context.Context, db database.DomainBlocksDatabase, host string) (*activitypub.DomainBlock, error) {
+
+	if db == nil {
+		return nil, activitypub.ErrNotFound
+	}
+
+	for _, domain := range candidateDomains(host) {
+
+		b, err := db.GetDomainBlockWithDomain(ctx, domain)
+
+		if err == nil {
+			return b, nil
+		}
+
+		if err != activitypub.ErrNotFound {
+			return nil, fmt.Errorf("Failed to retrieve domain block for %s, %w", domain, err)
+		}
+	}
+
+	return nil, activitypub.ErrNotFound
+}
+
+// IsSuspendedHost returns a boolean value indicating whether 'host' is suspended by a domain block.
+func IsSuspendedHost(ctx context.Context, db database.DomainBlocksDatabase, host string) (bool, error) {
+
+	b, err := GetDomainBlockForHost(ctx, db, host)
+
+	if err == activitypub.ErrNotFound {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return b.IsSuspended(), nil
+}
+
+// HostFromAddress returns the host associated with 'address' which may be either an actor URI or a "@name@host" address.
+func HostFromAddress(address string) (string, error) {
+
+	if strings.HasPrefix(address, "http") {
+
+		u, err := url.Parse(address)
+
+		if err != nil {
+			return "", fmt.Errorf("Failed to parse address, %w", err)
+		}
+
+		return u.Host, nil
+	}
+
+	_, host, err := ap.ParseAddress(address)
+
+	if err != nil {
+		return "", fmt.Errorf("Failed to parse address, %w", err)
+	}
+
+	return host, nil
+}
+
+// normalizeHost returns a lower-cased copy of 'host' with any port number removed.
+func normalizeHost(host string) string {
+
+	host = strings.ToLower(strings.TrimSpace(host))
+
+	h, _, err := net.SplitHostPort(host)
+
+	if err == nil {
+		host = h
+	}
+
+	return strings.TrimSuffix(host, ".")
+}
+
+// candidateDomains returns the list of domains that a server-wide domain block (or allowance) for 'host' may be
+// recorded under: 'host' itself followed by each of its parent domains, stopping before the top-level domain.
+func candidateDomains(host string) []string {
+
+	domains := make([]string, 0)
+	domain := normalizeHost(host)
+
+	for domain != "" {
+
+		domains = append(domains, domain)
+
+		_, parent, ok := strings.Cut(domain, ".")
+
+		if !ok || !strings.Contains(parent, ".") {
+			break
+		}
+
+		domain = parent
+	}
+
+	return domains
+}
@@ -0,0 +1,139 @@
+package blocks
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/database"
+)
+
+type testDomainBlocksDatabase struct {
+	database.DomainBlocksDatabase
+	blocks map[string]*activitypub.DomainBlock
+}
+
+func (db *testDomainBlocksDatabase) GetDomainBlockWithDomain(ctx context.Context, domain string) (*activitypub.DomainBlock, error) {
+
+	b, ok := db.blocks[domain]
+
+	if !ok {
+		return nil, activitypub.ErrNotFound
+	}
+
+	return b, nil
+}
+
+func (db *testDomainBlocksDatabase) GetDomainBlocks(ctx context.Context, cb database.GetDomainBlocksCallbackFunc) error {
+
+	for _, b := range db.blocks {
+
+		err := cb(ctx, b)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *testDomainBlocksDatabase) AddDomainBlock(ctx context.Context, b *activitypub.DomainBlock) error {
+	db.blocks[b.Domain] = b
+	return nil
+}
+
+func (db *testDomainBlocksDatabase) UpdateDomainBlock(ctx context.Context, b *activitypub.DomainBlock) error {
+	db.blocks[b.Domain] = b
+	return nil
+}
+
+func TestImportMastodonBlocklist(t *testing.T) {
+
+	ctx := context.Background()
+
+	db := &testDomainBlocksDatabase{
+		blocks: make(map[string]*activitypub.DomainBlock),
+	}
+
+	csv_data := `#domain,#severity,#reject_media,#reject_reports,#public_comment,#obfuscate
+example.com,suspend,true,true,Spam,false
+silenced.org,silence,false,false,,false
+media.net,noop,True,false,,false
+exa*le.biz,suspend,false,false,,true`
+
+	count, err := ImportMastodonBlocklist(ctx, db, strings.NewReader(csv_data), false)
+
+	if err != nil {
+		t.Fatalf("Failed to import blocklist, %v", err)
+	}
+
+	if count != 3 {
+		t.Fatalf("Expected 3 domain blocks to be imported but got %d", count)
+	}
+
+	tests := map[string]string{
+		"example.com":          activitypub.DomainBlockSuspend,
+		"social.example.com":   activitypub.DomainBlockSuspend,
+		"a.b.example.com:8080": activitypub.DomainBlockSuspend,
+		"SILENCED.org":         activitypub.DomainBlockSilence,
+		"media.net":            activitypub.DomainBlockNoop,
+		"notexample.com":       "",
+		"com":                  "",
+		"exa*le.biz":           "",
+	}
+
+	for host, expected := range tests {
+
+		b, err := GetDomainBlockForHost(ctx, db, host)
+
+		if expected == "" {
+
+			if err != activitypub.ErrNotFound {
+				t.Fatalf("Expected %s not to be blocked, %v", host, err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("Failed to retrieve domain block for %s, %v", host, err)
+		}
+
+		if b.Severity != expected {
+			t.Fatalf("Unexpected severity for %s, expected '%s' but got '%s'", host, expected, b.Severity)
+		}
+	}
+
+	if !db.blocks["media.net"].RejectsMedia() {
+		t.Fatalf("Expected media.net to reject media")
+	}
+
+	var buf bytes.Buffer
+
+	err = ExportMastodonBlocklist(ctx, db, &buf)
+
+	if err != nil {
+		t.Fatalf("Failed to export blocklist, %v", err)
+	}
+
+	roundtrip := &testDomainBlocksDatabase{
+		blocks: make(map[string]*activitypub.DomainBlock),
+	}
+
+	count, err = ImportMastodonBlocklist(ctx, roundtrip, &buf, false)
+
+	if err != nil {
+		t.Fatalf("Failed to re-import blocklist, %v", err)
+	}
+
+	if count != 3 {
+		t.Fatalf("Expected 3 domain blocks to be re-imported but got %d", count)
+	}
+
+	if roundtrip.blocks["example.com"].PublicComment != "Spam" {
+		t.Fatalf("Unexpected public comment for example.com after round trip")
+	}
+}
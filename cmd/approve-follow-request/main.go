@@ -0,0 +1,19 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/sfomuseum/go-activitypub/app/follower/requests/approve"
+)
+
+func main() {
+
+	ctx := context.Background()
+	err := approve.Run(ctx)
+
+	if err != nil {
+		log.Fatalf("Failed to approve follow request, %v", err)
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"log"
 
+	_ "github.com/aaronland/gocloud/blob/s3"
 	_ "github.com/mattn/go-sqlite3"
+	_ "gocloud.dev/blob/fileblob"
+
 	"github.com/sfomuseum/go-activitypub/app/post/create"
 )
 
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	_ "github.com/aaronland/gocloud/blob/s3"
+	_ "github.com/mattn/go-sqlite3"
+	_ "gocloud.dev/blob/fileblob"
+
+	"github.com/sfomuseum/go-activitypub/app/post/delete"
+)
+
+func main() {
+
+	ctx := context.Background()
+	err := delete.Run(ctx)
+
+	if err != nil {
+		log.Fatalf("Failed to delete post, %v", err)
+	}
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/sfomuseum/go-activitypub/app/domainallows/allowlist"
+)
+
+func main() {
+
+	ctx := context.Background()
+	err := allowlist.Run(ctx)
+
+	if err != nil {
+		log.Fatalf("Failed to manage domain allowlist, %v", err)
+	}
+}
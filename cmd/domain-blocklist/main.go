@@ -0,0 +1,20 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/sfomuseum/go-activitypub/app/domainblocks/blocklist"
+)
+
+func main() {
+
+	ctx := context.Background()
+	err := blocklist.Run(ctx)
+
+	if err != nil {
+		log.Fatalf("Failed to import or export domain blocklist, %v", err)
+	}
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/sfomuseum/go-activitypub/app/received/list"
+)
+
+func main() {
+
+	ctx := context.Background()
+	err := list.Run(ctx)
+
+	if err != nil {
+		log.Fatalf("Failed to list received activities, %v", err)
+	}
+}
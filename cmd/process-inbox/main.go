@@ -0,0 +1,19 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/sfomuseum/go-activitypub/app/inbox/process"
+)
+
+func main() {
+
+	ctx := context.Background()
+	err := process.Run(ctx)
+
+	if err != nil {
+		log.Fatalf("Failed to process queued inbox activities, %v", err)
+	}
+}
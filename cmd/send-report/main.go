@@ -0,0 +1,20 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/sfomuseum/go-activitypub/app/reports/send"
+)
+
+func main() {
+
+	ctx := context.Background()
+	err := send.Run(ctx)
+
+	if err != nil {
+		log.Fatalf("Failed to send report, %v", err)
+	}
+}
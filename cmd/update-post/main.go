@@ -0,0 +1,19 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/sfomuseum/go-activitypub/app/post/update"
+)
+
+func main() {
+
+	ctx := context.Background()
+	err := update.Run(ctx)
+
+	if err != nil {
+		log.Fatalf("Failed to update post, %v", err)
+	}
+}
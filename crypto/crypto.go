@@ -2,6 +2,7 @@
 package crypto
 
 import (
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
@@ -40,6 +41,25 @@ func GenerateKeyPair(sz int) ([]byte, []byte, error) {
 // RSAPublicKeyFromPEM returns a new `rsa.PublicKey` instance derived from 'str_pem'.
 func RSAPublicKeyFromPEM(str_pem string) (*rsa.PublicKey, error) {
 
+	public_key, err := PublicKeyFromPEM(str_pem)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rsa_key, ok := public_key.(*rsa.PublicKey)
+
+	if !ok {
+		return nil, fmt.Errorf("Public key is not an RSA key, %T", public_key)
+	}
+
+	return rsa_key, nil
+}
+
+// PublicKeyFromPEM returns a new public key instance derived from 'str_pem'. The return value will be either a
+// `*rsa.PublicKey` or an `ed25519.PublicKey` instance.
+func PublicKeyFromPEM(str_pem string) (any, error) {
+
 	public_key_block, _ := pem.Decode([]byte(str_pem))
 
 	if public_key_block == nil {
@@ -62,7 +82,12 @@ func RSAPublicKeyFromPEM(str_pem string) (*rsa.PublicKey, error) {
 		return nil, fmt.Errorf("Failed to parse PEM block containing public key, %w", err)
 	}
 
-	return public_key.(*rsa.PublicKey), nil
+	switch public_key.(type) {
+	case *rsa.PublicKey, ed25519.PublicKey:
+		return public_key, nil
+	default:
+		return nil, fmt.Errorf("Unsupported public key type, %T", public_key)
+	}
 }
 
 // RSAPrivateKeyFromPEM returns a new `rsa.PrivateKey` instance derived from 'str_pem'.
@@ -0,0 +1,110 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/aaronland/go-roster"
+	"github.com/sfomuseum/go-activitypub"
+)
+
+type GetAccessTokensCallbackFunc func(context.Context, *activitypub.AccessToken) error
+
+// AccessTokensDatabase defines an interface for storing the API tokens used to authenticate client-to-server (C2S) requests for individual accounts.
+type AccessTokensDatabase interface {
+	// GetAccessTokenWithId returns the `activitypub.AccessToken` instance with a specific unique ID.
+	GetAccessTokenWithId(context.Context, int64) (*activitypub.AccessToken, error)
+	// GetAccessTokenWithToken returns the `activitypub.AccessToken` instance for a specific (hashed) token.
+	GetAccessTokenWithToken(context.Context, string) (*activitypub.AccessToken, error)
+	// GetAccessTokensForAccount iterates through all the `activitypub.AccessToken` instances for a specific account.
+	GetAccessTokensForAccount(context.Context, int64, GetAccessTokensCallbackFunc) error
+	// AddAccessToken adds a new `activitypub.AccessToken` instance.
+	AddAccessToken(context.Context, *activitypub.AccessToken) error
+	// RemoveAccessToken removes a specific `activitypub.AccessToken` instance.
+	RemoveAccessToken(context.Context, *activitypub.AccessToken) error
+	// Close performs any final operations to terminate the underlying database connection.
+	Close(context.Context) error
+}
+
+var access_tokens_database_roster roster.Roster
+
+// AccessTokensDatabaseInitializationFunc is a function defined by individual access_tokens_database package and used to create
+// an instance of that access_tokens_database
+type AccessTokensDatabaseInitializationFunc func(ctx context.Context, uri string) (AccessTokensDatabase, error)
+
+// RegisterAccessTokensDatabase registers 'scheme' as a key pointing to 'init_func' in an internal lookup table
+// used to create new `AccessTokensDatabase` instances by the `NewAccessTokensDatabase` method.
+func RegisterAccessTokensDatabase(ctx context.Context, scheme string, init_func AccessTokensDatabaseInitializationFunc) error {
+
+	err := ensureAccessTokensDatabaseRoster()
+
+	if err != nil {
+		return err
+	}
+
+	return access_tokens_database_roster.Register(ctx, scheme, init_func)
+}
+
+func ensureAccessTokensDatabaseRoster() error {
+
+	if access_tokens_database_roster == nil {
+
+		r, err := roster.NewDefaultRoster()
+
+		if err != nil {
+			return err
+		}
+
+		access_tokens_database_roster = r
+	}
+
+	return nil
+}
+
+// NewAccessTokensDatabase returns a new `AccessTokensDatabase` instance configured by 'uri'. The value of 'uri' is parsed
+// as a `url.URL` and its scheme is used as the key for a corresponding `AccessTokensDatabaseInitializationFunc`
+// function used to instantiate the new `AccessTokensDatabase`. It is assumed that the scheme (and initialization
+// function) have been registered by the `RegisterAccessTokensDatabase` method.
+func NewAccessTokensDatabase(ctx context.Context, uri string) (AccessTokensDatabase, error) {
+
+	u, err := url.Parse(uri)
+
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := u.Scheme
+
+	i, err := access_tokens_database_roster.Driver(ctx, scheme)
+
+	if err != nil {
+		return nil, err
+	}
+
+	init_func := i.(AccessTokensDatabaseInitializationFunc)
+	return init_func(ctx, uri)
+}
+
+// Schemes returns the list of schemes that have been registered.
+func AccessTokensDatabaseSchemes() []string {
+
+	ctx := context.Background()
+	schemes := []string{}
+
+	err := ensureAccessTokensDatabaseRoster()
+
+	if err != nil {
+		return schemes
+	}
+
+	for _, dr := range access_tokens_database_roster.Drivers(ctx) {
+		scheme := fmt.Sprintf("%s://", strings.ToLower(dr))
+		schemes = append(schemes, scheme)
+	}
+
+	sort.Strings(schemes)
+	return schemes
+}
@@ -0,0 +1,127 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	aa_docstore "github.com/aaronland/gocloud/docstore"
+	"github.com/sfomuseum/go-activitypub"
+	gc_docstore "gocloud.dev/docstore"
+)
+
+type DocstoreAccessTokensDatabase struct {
+	AccessTokensDatabase
+	collection *gc_docstore.Collection
+}
+
+func init() {
+
+	ctx := context.Background()
+
+	err := RegisterAccessTokensDatabase(ctx, "awsdynamodb", NewDocstoreAccessTokensDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+
+	for _, scheme := range gc_docstore.DefaultURLMux().CollectionSchemes() {
+		err := RegisterAccessTokensDatabase(ctx, scheme, NewDocstoreAccessTokensDatabase)
+
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+func NewDocstoreAccessTokensDatabase(ctx context.Context, uri string) (AccessTokensDatabase, error) {
+
+	col, err := aa_docstore.OpenCollection(ctx, uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open collection, %w", err)
+	}
+
+	db := &DocstoreAccessTokensDatabase{
+		collection: col,
+	}
+
+	return db, nil
+}
+
+func (db *DocstoreAccessTokensDatabase) GetAccessTokenWithId(ctx context.Context, id int64) (*activitypub.AccessToken, error) {
+
+	q := db.collection.Query()
+	q = q.Where("Id", "=", id)
+
+	return db.getAccessToken(ctx, q)
+}
+
+func (db *DocstoreAccessTokensDatabase) GetAccessTokenWithToken(ctx context.Context, token string) (*activitypub.AccessToken, error) {
+
+	q := db.collection.Query()
+	q = q.Where("Token", "=", token)
+
+	return db.getAccessToken(ctx, q)
+}
+
+func (db *DocstoreAccessTokensDatabase) GetAccessTokensForAccount(ctx context.Context, account_id int64, cb GetAccessTokensCallbackFunc) error {
+
+	q := db.collection.Query()
+	q = q.Where("AccountId", "=", account_id)
+
+	iter := q.Get(ctx)
+	defer iter.Stop()
+
+	for {
+
+		var t activitypub.AccessToken
+		err := iter.Next(ctx, &t)
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("Failed to interate, %w", err)
+		} else {
+
+			err := cb(ctx, &t)
+
+			if err != nil {
+				return fmt.Errorf("Failed to execute access tokens callback for '%d', %w", t.Id, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (db *DocstoreAccessTokensDatabase) AddAccessToken(ctx context.Context, t *activitypub.AccessToken) error {
+
+	return db.collection.Put(ctx, t)
+}
+
+func (db *DocstoreAccessTokensDatabase) RemoveAccessToken(ctx context.Context, t *activitypub.AccessToken) error {
+
+	return db.collection.Delete(ctx, t)
+}
+
+func (db *DocstoreAccessTokensDatabase) Close(ctx context.Context) error {
+	return db.collection.Close()
+}
+
+func (db *DocstoreAccessTokensDatabase) getAccessToken(ctx context.Context, q *gc_docstore.Query) (*activitypub.AccessToken, error) {
+
+	iter := q.Get(ctx)
+	defer iter.Stop()
+
+	var t activitypub.AccessToken
+	err := iter.Next(ctx, &t)
+
+	if err == io.EOF {
+		return nil, activitypub.ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("Failed to interate, %w", err)
+	} else {
+		return &t, nil
+	}
+}
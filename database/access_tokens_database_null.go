@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+
+	"github.com/sfomuseum/go-activitypub"
+)
+
+type NullAccessTokensDatabase struct {
+	AccessTokensDatabase
+}
+
+func init() {
+
+	ctx := context.Background()
+	err := RegisterAccessTokensDatabase(ctx, "null", NewNullAccessTokensDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func NewNullAccessTokensDatabase(ctx context.Context, uri string) (AccessTokensDatabase, error) {
+	db := &NullAccessTokensDatabase{}
+	return db, nil
+}
+
+func (db *NullAccessTokensDatabase) GetAccessTokenWithId(ctx context.Context, id int64) (*activitypub.AccessToken, error) {
+	return nil, activitypub.ErrNotFound
+}
+
+func (db *NullAccessTokensDatabase) GetAccessTokenWithToken(ctx context.Context, token string) (*activitypub.AccessToken, error) {
+	return nil, activitypub.ErrNotFound
+}
+
+func (db *NullAccessTokensDatabase) GetAccessTokensForAccount(ctx context.Context, account_id int64, cb GetAccessTokensCallbackFunc) error {
+	return nil
+}
+
+func (db *NullAccessTokensDatabase) AddAccessToken(ctx context.Context, t *activitypub.AccessToken) error {
+	return nil
+}
+
+func (db *NullAccessTokensDatabase) RemoveAccessToken(ctx context.Context, t *activitypub.AccessToken) error {
+	return nil
+}
+
+func (db *NullAccessTokensDatabase) Close(ctx context.Context) error {
+	return nil
+}
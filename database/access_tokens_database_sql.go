@@ -0,0 +1,181 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	pg_sql "github.com/aaronland/go-pagination-sql"
+	"github.com/aaronland/go-pagination/countable"
+	"github.com/sfomuseum/go-activitypub"
+	sfom_sql "github.com/sfomuseum/go-database/sql"
+)
+
+const SQL_ACCESS_TOKENS_TABLE_NAME string = "access_tokens"
+
+type SQLAccessTokensDatabase struct {
+	AccessTokensDatabase
+	database *sql.DB
+}
+
+func init() {
+
+	ctx := context.Background()
+	err := RegisterAccessTokensDatabase(ctx, "sql", NewSQLAccessTokensDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func NewSQLAccessTokensDatabase(ctx context.Context, uri string) (AccessTokensDatabase, error) {
+
+	conn, err := sfom_sql.OpenWithURI(ctx, uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open database connection, %w", err)
+	}
+
+	db := &SQLAccessTokensDatabase{
+		database: conn,
+	}
+
+	return db, nil
+}
+
+func (db *SQLAccessTokensDatabase) GetAccessTokenWithId(ctx context.Context, id int64) (*activitypub.AccessToken, error) {
+
+	where := "id = ?"
+	return db.getAccessToken(ctx, where, id)
+}
+
+func (db *SQLAccessTokensDatabase) GetAccessTokenWithToken(ctx context.Context, token string) (*activitypub.AccessToken, error) {
+
+	where := "token = ?"
+	return db.getAccessToken(ctx, where, token)
+}
+
+func (db *SQLAccessTokensDatabase) GetAccessTokensForAccount(ctx context.Context, account_id int64, cb GetAccessTokensCallbackFunc) error {
+
+	pg_callback := func(pg_rsp pg_sql.PaginatedResponse) error {
+
+		rows := pg_rsp.Rows()
+
+		for rows.Next() {
+
+			var id int64
+			var account_id int64
+			var token string
+			var label string
+			var created int64
+
+			err := rows.Scan(&id, &account_id, &token, &label, &created)
+
+			if err != nil {
+				return fmt.Errorf("Failed to query database, %w", err)
+			}
+
+			t := &activitypub.AccessToken{
+				Id:        id,
+				AccountId: account_id,
+				Token:     token,
+				Label:     label,
+				Created:   created,
+			}
+
+			err = cb(ctx, t)
+
+			if err != nil {
+				return fmt.Errorf("Failed to execute access tokens callback for %d, %w", id, err)
+			}
+		}
+
+		err := rows.Close()
+
+		if err != nil {
+			return fmt.Errorf("Failed to iterate through database rows, %w", err)
+		}
+
+		return nil
+	}
+
+	pg_opts, err := countable.NewCountableOptions()
+
+	if err != nil {
+		return fmt.Errorf("Failed to create pagination options, %w", err)
+	}
+
+	q := fmt.Sprintf("SELECT id, account_id, token, label, created FROM %s WHERE account_id = ? ORDER BY created ASC", SQL_ACCESS_TOKENS_TABLE_NAME)
+
+	err = pg_sql.QueryPaginatedAll(db.database, pg_opts, pg_callback, q, account_id)
+
+	if err != nil {
+		return fmt.Errorf("Failed to execute paginated query, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLAccessTokensDatabase) AddAccessToken(ctx context.Context, t *activitypub.AccessToken) error {
+
+	q := fmt.Sprintf("INSERT INTO %s (id, account_id, token, label, created) VALUES (?, ?, ?, ?, ?)", SQL_ACCESS_TOKENS_TABLE_NAME)
+
+	_, err := db.database.ExecContext(ctx, q, t.Id, t.AccountId, t.Token, t.Label, t.Created)
+
+	if err != nil {
+		return fmt.Errorf("Failed to add access token, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLAccessTokensDatabase) RemoveAccessToken(ctx context.Context, t *activitypub.AccessToken) error {
+
+	q := fmt.Sprintf("DELETE FROM %s WHERE id = ?", SQL_ACCESS_TOKENS_TABLE_NAME)
+
+	_, err := db.database.ExecContext(ctx, q, t.Id)
+
+	if err != nil {
+		return fmt.Errorf("Failed to remove access token, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLAccessTokensDatabase) Close(ctx context.Context) error {
+	return db.database.Close()
+}
+
+func (db *SQLAccessTokensDatabase) getAccessToken(ctx context.Context, where string, args ...interface{}) (*activitypub.AccessToken, error) {
+
+	var id int64
+	var account_id int64
+	var token string
+	var label string
+	var created int64
+
+	q := fmt.Sprintf("SELECT id, account_id, token, label, created FROM %s WHERE %s", SQL_ACCESS_TOKENS_TABLE_NAME, where)
+
+	row := db.database.QueryRowContext(ctx, q, args...)
+
+	err := row.Scan(&id, &account_id, &token, &label, &created)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, activitypub.ErrNotFound
+	case err != nil:
+		return nil, fmt.Errorf("Failed to query database, %w", err)
+	default:
+		//
+	}
+
+	t := &activitypub.AccessToken{
+		Id:        id,
+		AccountId: account_id,
+		Token:     token,
+		Label:     label,
+		Created:   created,
+	}
+
+	return t, nil
+}
@@ -99,9 +99,9 @@ func (db *SQLAccountsDatabase) GetAccountIdsForDateRange(ctx context.Context, st
 
 func (db *SQLAccountsDatabase) AddAccount(ctx context.Context, a *activitypub.Account) error {
 
-	q := fmt.Sprintf("INSERT INTO %s (id, account_type, name, display_name, blurb, url, public_key_uri, private_key_uri, created, lastmodified) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", SQL_ACCOUNTS_TABLE_NAME)
+	q := fmt.Sprintf("INSERT INTO %s (id, account_type, name, display_name, blurb, url, public_key_uri, private_key_uri, manually_approve_followers, created, lastmodified) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", SQL_ACCOUNTS_TABLE_NAME)
 
-	_, err := db.database.ExecContext(ctx, q, a.Id, a.AccountType, a.Name, a.DisplayName, a.Blurb, a.URL, a.PublicKeyURI, a.PrivateKeyURI, a.Created, a.LastModified)
+	_, err := db.database.ExecContext(ctx, q, a.Id, a.AccountType, a.Name, a.DisplayName, a.Blurb, a.URL, a.PublicKeyURI, a.PrivateKeyURI, a.ManuallyApproveFollowers, a.Created, a.LastModified)
 
 	if err != nil {
 		return fmt.Errorf("Failed to add account, %w", err)
@@ -130,14 +130,15 @@ func (db *SQLAccountsDatabase) getAccount(ctx context.Context, where string, arg
 	var url string
 	var public_key_uri string
 	var private_key_uri string
+	var manually_approve_followers sql.NullBool
 	var created int64
 	var lastmod int64
 
-	q := fmt.Sprintf("SELECT id, account_type, name, display_name, blurb, url, public_key_uri, private_key_uri, created, lastmodified FROM %s WHERE %s", SQL_ACCOUNTS_TABLE_NAME, where)
+	q := fmt.Sprintf("SELECT id, account_type, name, display_name, blurb, url, public_key_uri, private_key_uri, manually_approve_followers, created, lastmodified FROM %s WHERE %s", SQL_ACCOUNTS_TABLE_NAME, where)
 
 	row := db.database.QueryRowContext(ctx, q, args...)
 
-	err := row.Scan(&id, &account_type, &name, &display_name, &blurb, &url, &public_key_uri, &private_key_uri, &created, &lastmod)
+	err := row.Scan(&id, &account_type, &name, &display_name, &blurb, &url, &public_key_uri, &private_key_uri, &manually_approve_followers, &created, &lastmod)
 
 	switch {
 	case err == sql.ErrNoRows:
@@ -161,6 +162,10 @@ func (db *SQLAccountsDatabase) getAccount(ctx context.Context, where string, arg
 		LastModified:  lastmod,
 	}
 
+	if manually_approve_followers.Valid {
+		a.ManuallyApproveFollowers = manually_approve_followers.Bool
+	}
+
 	return a, nil
 }
 
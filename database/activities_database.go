@@ -13,13 +13,34 @@ import (
 
 type GetActivitiesCallbackFunc func(context.Context, *activitypub.Activity) error
 
+// ActivitiesCursor defines the page of activities returned by the `GetActivitiesForAccountWithCursor` method.
+type ActivitiesCursor struct {
+	// Only include activities of these types. If empty activities of all types are included.
+	ActivityTypes []activitypub.ActivityType
+	// If greater than zero only include activities older than (with IDs less than) MaxId.
+	MaxId int64
+	// If greater than zero only include activities newer than (with IDs greater than) MinId.
+	MinId int64
+	// If true include the activities closest to MinId (or the oldest activities if MinId is zero) rather than the newest ones.
+	Oldest bool
+	// The maximum number of activities to include.
+	Limit int
+}
+
 type ActivitiesDatabase interface {
 	AddActivity(context.Context, *activitypub.Activity) error
+	RemoveActivity(context.Context, *activitypub.Activity) error
 	GetActivityWithId(context.Context, int64) (*activitypub.Activity, error)
 	GetActivityWithActivityPubId(context.Context, string) (*activitypub.Activity, error)
-	GetActivityWithActivityTypeAnId(context.Context, activitypub.ActivityType, int64) (*activitypub.Activity, error)
+	GetActivityWithActivityTypeAndId(context.Context, activitypub.ActivityType, int64) (*activitypub.Activity, error)
 	GetActivities(context.Context, GetActivitiesCallbackFunc) error
 	GetActivitiesForAccount(context.Context, int64, GetActivitiesCallbackFunc) error
+	// GetActivitiesForAccountWithCursor iterates through the page of activities for an account defined by an `ActivitiesCursor`
+	// instance. Activities are always dispatched newest (highest ID) first.
+	GetActivitiesForAccountWithCursor(context.Context, int64, *ActivitiesCursor, GetActivitiesCallbackFunc) error
+	// CountActivitiesForAccount returns the number of activities, of the types in a list of activity types (or all types if the list
+	// is empty), for an account.
+	CountActivitiesForAccount(context.Context, int64, []activitypub.ActivityType) (int64, error)
 	Close(context.Context) error
 }
 
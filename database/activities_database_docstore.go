@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"slices"
 
 	aa_docstore "github.com/aaronland/gocloud/docstore"
 	"github.com/sfomuseum/go-activitypub"
@@ -56,6 +57,10 @@ func (db *DocstoreActivitiesDatabase) AddActivity(ctx context.Context, f *activi
 	return db.collection.Put(ctx, f)
 }
 
+func (db *DocstoreActivitiesDatabase) RemoveActivity(ctx context.Context, f *activitypub.Activity) error {
+	return db.collection.Delete(ctx, f)
+}
+
 func (db *DocstoreActivitiesDatabase) GetActivityWithId(ctx context.Context, id int64) (*activitypub.Activity, error) {
 
 	q := db.collection.Query()
@@ -96,6 +101,94 @@ func (db *DocstoreActivitiesDatabase) GetActivitiesForAccount(ctx context.Contex
 	return db.getActivitiesWithQuery(ctx, q, cb)
 }
 
+func (db *DocstoreActivitiesDatabase) GetActivitiesForAccountWithCursor(ctx context.Context, id int64, cursor *ActivitiesCursor, cb GetActivitiesCallbackFunc) error {
+
+	q := db.collection.Query()
+	q = q.Where("AccountId", "=", id)
+
+	if cursor.MaxId > 0 {
+		q = q.Where("Id", "<", cursor.MaxId)
+	}
+
+	if cursor.MinId > 0 {
+		q = q.Where("Id", ">", cursor.MinId)
+	}
+
+	if cursor.Oldest {
+		q = q.OrderBy("Id", gc_docstore.Ascending)
+	} else {
+		q = q.OrderBy("Id", gc_docstore.Descending)
+	}
+
+	// Activity types are filtered here rather than in the query so that the limit
+	// applies to the activities that match them.
+
+	activities := make([]*activitypub.Activity, 0)
+
+	iter := q.Get(ctx)
+	defer iter.Stop()
+
+	for len(activities) < cursor.Limit {
+
+		var a activitypub.Activity
+		err := iter.Next(ctx, &a)
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("Failed to interate, %w", err)
+		}
+
+		if len(cursor.ActivityTypes) > 0 && !slices.Contains(cursor.ActivityTypes, a.ActivityType) {
+			continue
+		}
+
+		activities = append(activities, &a)
+	}
+
+	if cursor.Oldest {
+		slices.Reverse(activities)
+	}
+
+	for _, a := range activities {
+
+		err := cb(ctx, a)
+
+		if err != nil {
+			return fmt.Errorf("Failed to execute activities callback for '%d', %w", a.Id, err)
+		}
+	}
+
+	return nil
+}
+
+func (db *DocstoreActivitiesDatabase) CountActivitiesForAccount(ctx context.Context, id int64, activity_types []activitypub.ActivityType) (int64, error) {
+
+	// Docstore has no notion of a count query so just iterate through the account's activities
+
+	q := db.collection.Query()
+	q = q.Where("AccountId", "=", id)
+
+	count := int64(0)
+
+	cb := func(ctx context.Context, a *activitypub.Activity) error {
+
+		if len(activity_types) == 0 || slices.Contains(activity_types, a.ActivityType) {
+			count += 1
+		}
+
+		return nil
+	}
+
+	err := db.getActivitiesWithQuery(ctx, q, cb)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
 func (db *DocstoreActivitiesDatabase) Close(ctx context.Context) error {
 	return db.collection.Close()
 }
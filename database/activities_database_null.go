@@ -29,6 +29,10 @@ func (db *NullActivitiesDatabase) AddActivity(ctx context.Context, f *activitypu
 	return nil
 }
 
+func (db *NullActivitiesDatabase) RemoveActivity(ctx context.Context, f *activitypub.Activity) error {
+	return nil
+}
+
 func (db *NullActivitiesDatabase) GetActivityWithId(ctx context.Context, id int64) (*activitypub.Activity, error) {
 	return nil, activitypub.ErrNotFound
 }
@@ -49,6 +53,14 @@ func (db *NullActivitiesDatabase) GetActivitiesForAccount(ctx context.Context, i
 	return nil
 }
 
+func (db *NullActivitiesDatabase) GetActivitiesForAccountWithCursor(ctx context.Context, id int64, cursor *ActivitiesCursor, cb GetActivitiesCallbackFunc) error {
+	return nil
+}
+
+func (db *NullActivitiesDatabase) CountActivitiesForAccount(ctx context.Context, id int64, activity_types []activitypub.ActivityType) (int64, error) {
+	return 0, nil
+}
+
 func (db *NullActivitiesDatabase) Close(ctx context.Context) error {
 	return nil
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"slices"
+	"strings"
 
 	pg_sql "github.com/aaronland/go-pagination-sql"
 	"github.com/aaronland/go-pagination/countable"
@@ -55,6 +57,19 @@ func (db *SQLActivitiesDatabase) AddActivity(ctx context.Context, a *activitypub
 	return nil
 }
 
+func (db *SQLActivitiesDatabase) RemoveActivity(ctx context.Context, a *activitypub.Activity) error {
+
+	q := fmt.Sprintf("DELETE FROM %s WHERE id = ?", SQL_ACTIVITIES_TABLE_NAME)
+
+	_, err := db.database.ExecContext(ctx, q, a.Id)
+
+	if err != nil {
+		return fmt.Errorf("Failed to remove activity, %w", err)
+	}
+
+	return nil
+}
+
 func (db *SQLActivitiesDatabase) GetActivityWithId(ctx context.Context, id int64) (*activitypub.Activity, error) {
 
 	where := "id = ?"
@@ -89,6 +104,108 @@ func (db *SQLActivitiesDatabase) GetActivitiesForAccount(ctx context.Context, id
 	return db.getActivities(ctx, where, args, cb)
 }
 
+func (db *SQLActivitiesDatabase) GetActivitiesForAccountWithCursor(ctx context.Context, id int64, cursor *ActivitiesCursor, cb GetActivitiesCallbackFunc) error {
+
+	where, args := sqlActivitiesForAccountWhere(id, cursor.ActivityTypes)
+
+	if cursor.MaxId > 0 {
+		where = fmt.Sprintf("%s AND id < ?", where)
+		args = append(args, cursor.MaxId)
+	}
+
+	if cursor.MinId > 0 {
+		where = fmt.Sprintf("%s AND id > ?", where)
+		args = append(args, cursor.MinId)
+	}
+
+	order := "DESC"
+
+	if cursor.Oldest {
+		order = "ASC"
+	}
+
+	args = append(args, cursor.Limit)
+
+	q := fmt.Sprintf("SELECT id, activitypub_id, account_id, activity_type, activity_type_id, body, created FROM %s WHERE %s ORDER BY id %s LIMIT ?", SQL_ACTIVITIES_TABLE_NAME, where, order)
+
+	rows, err := db.database.QueryContext(ctx, q, args...)
+
+	if err != nil {
+		return fmt.Errorf("Failed to query database, %w", err)
+	}
+
+	defer rows.Close()
+
+	activities := make([]*activitypub.Activity, 0)
+
+	for rows.Next() {
+
+		var id int64
+		var activitypub_id string
+		var account_id int64
+		var activity_type int
+		var activity_type_id int64
+		var body string
+		var created int64
+
+		err := rows.Scan(&id, &activitypub_id, &account_id, &activity_type, &activity_type_id, &body, &created)
+
+		if err != nil {
+			return fmt.Errorf("Failed to query database, %w", err)
+		}
+
+		a := &activitypub.Activity{
+			Id:             id,
+			ActivityPubId:  activitypub_id,
+			AccountId:      account_id,
+			ActivityType:   activitypub.ActivityType(activity_type),
+			ActivityTypeId: activity_type_id,
+			Body:           body,
+			Created:        created,
+		}
+
+		activities = append(activities, a)
+	}
+
+	err = rows.Err()
+
+	if err != nil {
+		return fmt.Errorf("Failed to iterate through database rows, %w", err)
+	}
+
+	if cursor.Oldest {
+		slices.Reverse(activities)
+	}
+
+	for _, a := range activities {
+
+		err := cb(ctx, a)
+
+		if err != nil {
+			return fmt.Errorf("Failed to execute activities callback for activity %d, %w", a.Id, err)
+		}
+	}
+
+	return nil
+}
+
+func (db *SQLActivitiesDatabase) CountActivitiesForAccount(ctx context.Context, id int64, activity_types []activitypub.ActivityType) (int64, error) {
+
+	where, args := sqlActivitiesForAccountWhere(id, activity_types)
+
+	q := fmt.Sprintf("SELECT COUNT(id) FROM %s WHERE %s", SQL_ACTIVITIES_TABLE_NAME, where)
+
+	var count int64
+
+	err := db.database.QueryRowContext(ctx, q, args...).Scan(&count)
+
+	if err != nil {
+		return 0, fmt.Errorf("Failed to count activities, %w", err)
+	}
+
+	return count, nil
+}
+
 func (db *SQLActivitiesDatabase) Close(ctx context.Context) error {
 	return db.database.Close()
 }
@@ -168,8 +285,6 @@ func (db *SQLActivitiesDatabase) getActivities(ctx context.Context, where string
 			if err != nil {
 				return fmt.Errorf("Failed to execute following callback for account %d, %w", id, err)
 			}
-
-			return nil
 		}
 
 		err := rows.Close()
@@ -198,3 +313,25 @@ func (db *SQLActivitiesDatabase) getActivities(ctx context.Context, where string
 	return nil
 
 }
+
+// sqlActivitiesForAccountWhere returns the WHERE clause, and its arguments, for selecting the activities of the
+// types in 'activity_types' (or all types if empty) for the account with ID 'account_id'.
+func sqlActivitiesForAccountWhere(account_id int64, activity_types []activitypub.ActivityType) (string, []interface{}) {
+
+	where := "account_id = ?"
+	args := []interface{}{account_id}
+
+	if len(activity_types) > 0 {
+
+		placeholders := make([]string, len(activity_types))
+
+		for idx, t := range activity_types {
+			placeholders[idx] = "?"
+			args = append(args, int(t))
+		}
+
+		where = fmt.Sprintf("%s AND activity_type IN (%s)", where, strings.Join(placeholders, ", "))
+	}
+
+	return where, args
+}
@@ -0,0 +1,108 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/aaronland/go-roster"
+	"github.com/sfomuseum/go-activitypub"
+)
+
+// ActorsDatabase defines an interface for caching remote ActivityPub actor (profile) documents.
+type ActorsDatabase interface {
+	// GetActorWithId returns the cached actor matching a specific actor URI.
+	GetActorWithId(context.Context, string) (*activitypub.Actor, error)
+	// GetActorWithAddress returns the cached actor matching a specific "@name@host" address.
+	GetActorWithAddress(context.Context, string) (*activitypub.Actor, error)
+	// AddActor adds a new `activitypub.Actor` instance.
+	AddActor(context.Context, *activitypub.Actor) error
+	// UpdateActor updates a specific `activitypub.Actor` instance.
+	UpdateActor(context.Context, *activitypub.Actor) error
+	// RemoveActor removes a specific `activitypub.Actor` instance.
+	RemoveActor(context.Context, *activitypub.Actor) error
+	// Close performs any final operations to terminate the underlying database connection.
+	Close(context.Context) error
+}
+
+var actors_database_roster roster.Roster
+
+// ActorsDatabaseInitializationFunc is a function defined by individual actors_database package and used to create
+// an instance of that actors_database
+type ActorsDatabaseInitializationFunc func(ctx context.Context, uri string) (ActorsDatabase, error)
+
+// RegisterActorsDatabase registers 'scheme' as a key pointing to 'init_func' in an internal lookup table
+// used to create new `ActorsDatabase` instances by the `NewActorsDatabase` method.
+func RegisterActorsDatabase(ctx context.Context, scheme string, init_func ActorsDatabaseInitializationFunc) error {
+
+	err := ensureActorsDatabaseRoster()
+
+	if err != nil {
+		return err
+	}
+
+	return actors_database_roster.Register(ctx, scheme, init_func)
+}
+
+func ensureActorsDatabaseRoster() error {
+
+	if actors_database_roster == nil {
+
+		r, err := roster.NewDefaultRoster()
+
+		if err != nil {
+			return err
+		}
+
+		actors_database_roster = r
+	}
+
+	return nil
+}
+
+// NewActorsDatabase returns a new `ActorsDatabase` instance configured by 'uri'. The value of 'uri' is parsed
+// as a `url.URL` and its scheme is used as the key for a corresponding `ActorsDatabaseInitializationFunc`
+// function used to instantiate the new `ActorsDatabase`. It is assumed that the scheme (and initialization
+// function) have been registered by the `RegisterActorsDatabase` method.
+func NewActorsDatabase(ctx context.Context, uri string) (ActorsDatabase, error) {
+
+	u, err := url.Parse(uri)
+
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := u.Scheme
+
+	i, err := actors_database_roster.Driver(ctx, scheme)
+
+	if err != nil {
+		return nil, err
+	}
+
+	init_func := i.(ActorsDatabaseInitializationFunc)
+	return init_func(ctx, uri)
+}
+
+// Schemes returns the list of schemes that have been registered.
+func ActorsDatabaseSchemes() []string {
+
+	ctx := context.Background()
+	schemes := []string{}
+
+	err := ensureActorsDatabaseRoster()
+
+	if err != nil {
+		return schemes
+	}
+
+	for _, dr := range actors_database_roster.Drivers(ctx) {
+		scheme := fmt.Sprintf("%s://", strings.ToLower(dr))
+		schemes = append(schemes, scheme)
+	}
+
+	sort.Strings(schemes)
+	return schemes
+}
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	aa_docstore "github.com/aaronland/gocloud/docstore"
+	"github.com/sfomuseum/go-activitypub"
+	gc_docstore "gocloud.dev/docstore"
+)
+
+type DocstoreActorsDatabase struct {
+	ActorsDatabase
+	collection *gc_docstore.Collection
+}
+
+func init() {
+
+	ctx := context.Background()
+
+	err := RegisterActorsDatabase(ctx, "awsdynamodb", NewDocstoreActorsDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+
+	for _, scheme := range gc_docstore.DefaultURLMux().CollectionSchemes() {
+		err := RegisterActorsDatabase(ctx, scheme, NewDocstoreActorsDatabase)
+
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+func NewDocstoreActorsDatabase(ctx context.Context, uri string) (ActorsDatabase, error) {
+
+	col, err := aa_docstore.OpenCollection(ctx, uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open collection, %w", err)
+	}
+
+	db := &DocstoreActorsDatabase{
+		collection: col,
+	}
+
+	return db, nil
+}
+
+func (db *DocstoreActorsDatabase) GetActorWithId(ctx context.Context, id string) (*activitypub.Actor, error) {
+
+	q := db.collection.Query()
+	q = q.Where("Id", "=", id)
+
+	return db.getActor(ctx, q)
+}
+
+func (db *DocstoreActorsDatabase) GetActorWithAddress(ctx context.Context, address string) (*activitypub.Actor, error) {
+
+	q := db.collection.Query()
+	q = q.Where("Address", "=", address)
+
+	return db.getActor(ctx, q)
+}
+
+func (db *DocstoreActorsDatabase) AddActor(ctx context.Context, a *activitypub.Actor) error {
+
+	return db.collection.Put(ctx, a)
+}
+
+func (db *DocstoreActorsDatabase) UpdateActor(ctx context.Context, a *activitypub.Actor) error {
+
+	return db.collection.Replace(ctx, a)
+}
+
+func (db *DocstoreActorsDatabase) RemoveActor(ctx context.Context, a *activitypub.Actor) error {
+
+	return db.collection.Delete(ctx, a)
+}
+
+func (db *DocstoreActorsDatabase) Close(ctx context.Context) error {
+	return db.collection.Close()
+}
+
+func (db *DocstoreActorsDatabase) getActor(ctx context.Context, q *gc_docstore.Query) (*activitypub.Actor, error) {
+
+	iter := q.Get(ctx)
+	defer iter.Stop()
+
+	var a activitypub.Actor
+	err := iter.Next(ctx, &a)
+
+	if err == io.EOF {
+		return nil, activitypub.ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("Failed to interate, %w", err)
+	} else {
+		return &a, nil
+	}
+}
@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+
+	"github.com/sfomuseum/go-activitypub"
+)
+
+type NullActorsDatabase struct {
+	ActorsDatabase
+}
+
+func init() {
+
+	ctx := context.Background()
+	err := RegisterActorsDatabase(ctx, "null", NewNullActorsDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func NewNullActorsDatabase(ctx context.Context, uri string) (ActorsDatabase, error) {
+	db := &NullActorsDatabase{}
+	return db, nil
+}
+
+func (db *NullActorsDatabase) GetActorWithId(ctx context.Context, id string) (*activitypub.Actor, error) {
+	return nil, activitypub.ErrNotFound
+}
+
+func (db *NullActorsDatabase) GetActorWithAddress(ctx context.Context, address string) (*activitypub.Actor, error) {
+	return nil, activitypub.ErrNotFound
+}
+
+func (db *NullActorsDatabase) AddActor(ctx context.Context, a *activitypub.Actor) error {
+	return nil
+}
+
+func (db *NullActorsDatabase) UpdateActor(ctx context.Context, a *activitypub.Actor) error {
+	return nil
+}
+
+func (db *NullActorsDatabase) RemoveActor(ctx context.Context, a *activitypub.Actor) error {
+	return nil
+}
+
+func (db *NullActorsDatabase) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,131 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/sfomuseum/go-activitypub"
+	sfom_sql "github.com/sfomuseum/go-database/sql"
+)
+
+const SQL_ACTORS_TABLE_NAME string = "actors"
+
+type SQLActorsDatabase struct {
+	ActorsDatabase
+	database *sql.DB
+}
+
+func init() {
+
+	ctx := context.Background()
+	err := RegisterActorsDatabase(ctx, "sql", NewSQLActorsDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func NewSQLActorsDatabase(ctx context.Context, uri string) (ActorsDatabase, error) {
+
+	conn, err := sfom_sql.OpenWithURI(ctx, uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open database connection, %w", err)
+	}
+
+	db := &SQLActorsDatabase{
+		database: conn,
+	}
+
+	return db, nil
+}
+
+func (db *SQLActorsDatabase) GetActorWithId(ctx context.Context, id string) (*activitypub.Actor, error) {
+
+	where := "id = ?"
+	return db.getActor(ctx, where, id)
+}
+
+func (db *SQLActorsDatabase) GetActorWithAddress(ctx context.Context, address string) (*activitypub.Actor, error) {
+
+	where := "address = ?"
+	return db.getActor(ctx, where, address)
+}
+
+func (db *SQLActorsDatabase) AddActor(ctx context.Context, a *activitypub.Actor) error {
+
+	q := fmt.Sprintf("INSERT INTO %s (id, address, body, created, lastmodified) VALUES (?, ?, ?, ?, ?)", SQL_ACTORS_TABLE_NAME)
+
+	_, err := db.database.ExecContext(ctx, q, a.Id, a.Address, a.Body, a.Created, a.LastModified)
+
+	if err != nil {
+		return fmt.Errorf("Failed to add actor, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLActorsDatabase) UpdateActor(ctx context.Context, a *activitypub.Actor) error {
+
+	q := fmt.Sprintf("UPDATE %s SET address = ?, body = ?, lastmodified = ? WHERE id = ?", SQL_ACTORS_TABLE_NAME)
+
+	_, err := db.database.ExecContext(ctx, q, a.Address, a.Body, a.LastModified, a.Id)
+
+	if err != nil {
+		return fmt.Errorf("Failed to update actor, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLActorsDatabase) RemoveActor(ctx context.Context, a *activitypub.Actor) error {
+
+	q := fmt.Sprintf("DELETE FROM %s WHERE id = ?", SQL_ACTORS_TABLE_NAME)
+
+	_, err := db.database.ExecContext(ctx, q, a.Id)
+
+	if err != nil {
+		return fmt.Errorf("Failed to remove actor, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLActorsDatabase) Close(ctx context.Context) error {
+	return db.database.Close()
+}
+
+func (db *SQLActorsDatabase) getActor(ctx context.Context, where string, args ...interface{}) (*activitypub.Actor, error) {
+
+	var id string
+	var address string
+	var body string
+	var created int64
+	var lastmodified int64
+
+	q := fmt.Sprintf("SELECT id, address, body, created, lastmodified FROM %s WHERE %s", SQL_ACTORS_TABLE_NAME, where)
+
+	row := db.database.QueryRowContext(ctx, q, args...)
+
+	err := row.Scan(&id, &address, &body, &created, &lastmodified)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, activitypub.ErrNotFound
+	case err != nil:
+		return nil, fmt.Errorf("Failed to query database, %w", err)
+	default:
+		//
+	}
+
+	a := &activitypub.Actor{
+		Id:           id,
+		Address:      address,
+		Body:         body,
+		Created:      created,
+		LastModified: lastmodified,
+	}
+
+	return a, nil
+}
@@ -66,7 +66,7 @@ func (db *SQLBoostsDatabase) GetBoostsForAccount(ctx context.Context, account_id
 	return db.getBoostsForQuery(ctx, where, args, cb)
 }
 
-func (db *SQLBoostsDatabase) GetBoostsForPosts(ctx context.Context, post_id int64, cb GetBoostsCallbackFunc) error {
+func (db *SQLBoostsDatabase) GetBoostsForPost(ctx context.Context, post_id int64, cb GetBoostsCallbackFunc) error {
 
 	where := "post_id = ?"
 
@@ -90,7 +90,7 @@ func (db *SQLBoostsDatabase) GetBoostsForPostIdAndActor(ctx context.Context, pos
 
 func (db *SQLBoostsDatabase) AddBoost(ctx context.Context, b *activitypub.Boost) error {
 
-	q := fmt.Sprintf("INSERT INTO %s (id, account_id, psot_id, actor, created) VALUES (?, ?, ?, ?, ?)", SQL_BOOSTS_TABLE_NAME)
+	q := fmt.Sprintf("INSERT INTO %s (id, account_id, post_id, actor, created) VALUES (?, ?, ?, ?, ?)", SQL_BOOSTS_TABLE_NAME)
 
 	_, err := db.database.ExecContext(ctx, q, b.Id, b.AccountId, b.PostId, b.Actor, b.Created)
 
@@ -190,8 +190,6 @@ func (db *SQLBoostsDatabase) getBoostsForQuery(ctx context.Context, where string
 				}
 
 			}
-
-			return nil
 		}
 
 		err := rows.Close()
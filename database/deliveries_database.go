@@ -18,6 +18,7 @@ type DeliveriesDatabase interface {
 	AddDelivery(context.Context, *activitypub.Delivery) error
 	GetDeliveryWithId(context.Context, int64) (*activitypub.Delivery, error)
 	GetDeliveries(context.Context, GetDeliveriesCallbackFunc) error
+	GetDeliveriesWithActivityId(context.Context, int64, GetDeliveriesCallbackFunc) error
 	GetDeliveriesWithActivityIdAndRecipient(context.Context, int64, string, GetDeliveriesCallbackFunc) error
 	GetDeliveriesWithActivityPubIdAndRecipient(context.Context, string, string, GetDeliveriesCallbackFunc) error
 	GetDeliveryIdsForDateRange(context.Context, int64, int64, GetDeliveryIdsCallbackFunc) error
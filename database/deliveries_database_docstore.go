@@ -99,6 +99,14 @@ func (db *DocstoreDeliveriesDatabase) GetDeliveries(ctx context.Context, deliver
 	return db.getDeliveriesWithQuery(ctx, q, deliveries_callback)
 }
 
+func (db *DocstoreDeliveriesDatabase) GetDeliveriesWithActivityId(ctx context.Context, activity_id int64, deliveries_callback GetDeliveriesCallbackFunc) error {
+
+	q := db.collection.Query()
+	q = q.Where("ActivityId", "=", activity_id)
+
+	return db.getDeliveriesWithQuery(ctx, q, deliveries_callback)
+}
+
 func (db *DocstoreDeliveriesDatabase) GetDeliveriesWithActivityIdAndRecipient(ctx context.Context, activity_id int64, recipient string, deliveries_callback GetDeliveriesCallbackFunc) error {
 
 	q := db.collection.Query()
@@ -36,6 +36,10 @@ func (db *NullDeliveriesDatabase) GetDeliveries(ctx context.Context, cb GetDeliv
 	return nil
 }
 
+func (db *NullDeliveriesDatabase) GetDeliveriesWithActivityId(ctx context.Context, activity_id int64, cb GetDeliveriesCallbackFunc) error {
+	return nil
+}
+
 func (db *NullDeliveriesDatabase) GetDeliveriesWithActivityIdAndRecipient(ctx context.Context, activity_id int64, recipient string, cb GetDeliveriesCallbackFunc) error {
 	return nil
 }
@@ -41,6 +41,10 @@ func (db *SlogDeliveriesDatabase) GetDeliveries(ctx context.Context, cb GetDeliv
 	return nil
 }
 
+func (db *SlogDeliveriesDatabase) GetDeliveriesWithActivityId(ctx context.Context, activity_id int64, cb GetDeliveriesCallbackFunc) error {
+	return nil
+}
+
 func (db *SlogDeliveriesDatabase) GetDeliveriesWithActivityIdAndRecipient(ctx context.Context, activity_id int64, recipient string, cb GetDeliveriesCallbackFunc) error {
 	return nil
 }
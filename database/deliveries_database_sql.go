@@ -67,6 +67,16 @@ func (db *SQLDeliveriesDatabase) GetDeliveries(ctx context.Context, cb GetDelive
 	return db.getDeliveries(ctx, where, args, cb)
 }
 
+func (db *SQLDeliveriesDatabase) GetDeliveriesWithActivityId(ctx context.Context, activity_id int64, cb GetDeliveriesCallbackFunc) error {
+
+	where := "activity_id = ?"
+	args := []interface{}{
+		activity_id,
+	}
+
+	return db.getDeliveries(ctx, where, args, cb)
+}
+
 func (db *SQLDeliveriesDatabase) GetDeliveriesWithActivityIdAndRecipient(ctx context.Context, activity_id int64, recipient string, cb GetDeliveriesCallbackFunc) error {
 
 	where := "activity_id = ? AND recipient = ?"
@@ -238,8 +248,6 @@ func (db *SQLDeliveriesDatabase) getDeliveries(ctx context.Context, where string
 			if err != nil {
 				return fmt.Errorf("Failed to execute following callback for delivery %d, %w", id, err)
 			}
-
-			return nil
 		}
 
 		err := rows.Close()
@@ -0,0 +1,110 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/aaronland/go-roster"
+	"github.com/sfomuseum/go-activitypub"
+)
+
+type GetDomainAllowsCallbackFunc func(context.Context, *activitypub.DomainAllow) error
+
+// DomainAllowsDatabase defines an interface for storing the remote domains a server is allowed to federate with when running in allowlist mode.
+type DomainAllowsDatabase interface {
+	// GetDomainAllowWithId returns the `activitypub.DomainAllow` instance with a specific unique ID.
+	GetDomainAllowWithId(context.Context, int64) (*activitypub.DomainAllow, error)
+	// GetDomainAllowWithDomain returns the `activitypub.DomainAllow` instance for a specific domain.
+	GetDomainAllowWithDomain(context.Context, string) (*activitypub.DomainAllow, error)
+	// GetDomainAllows iterates through all the `activitypub.DomainAllow` instances.
+	GetDomainAllows(context.Context, GetDomainAllowsCallbackFunc) error
+	// AddDomainAllow adds a new `activitypub.DomainAllow` instance.
+	AddDomainAllow(context.Context, *activitypub.DomainAllow) error
+	// RemoveDomainAllow removes a specific `activitypub.DomainAllow` instance.
+	RemoveDomainAllow(context.Context, *activitypub.DomainAllow) error
+	// Close performs any final operations to terminate the underlying database connection.
+	Close(context.Context) error
+}
+
+var domain_allows_database_roster roster.Roster
+
+// DomainAllowsDatabaseInitializationFunc is a function defined by individual domain_allows_database package and used to create
+// an instance of that domain_allows_database
+type DomainAllowsDatabaseInitializationFunc func(ctx context.Context, uri string) (DomainAllowsDatabase, error)
+
+// RegisterDomainAllowsDatabase registers 'scheme' as a key pointing to 'init_func' in an internal lookup table
+// used to create new `DomainAllowsDatabase` instances by the `NewDomainAllowsDatabase` method.
+func RegisterDomainAllowsDatabase(ctx context.Context, scheme string, init_func DomainAllowsDatabaseInitializationFunc) error {
+
+	err := ensureDomainAllowsDatabaseRoster()
+
+	if err != nil {
+		return err
+	}
+
+	return domain_allows_database_roster.Register(ctx, scheme, init_func)
+}
+
+func ensureDomainAllowsDatabaseRoster() error {
+
+	if domain_allows_database_roster == nil {
+
+		r, err := roster.NewDefaultRoster()
+
+		if err != nil {
+			return err
+		}
+
+		domain_allows_database_roster = r
+	}
+
+	return nil
+}
+
+// NewDomainAllowsDatabase returns a new `DomainAllowsDatabase` instance configured by 'uri'. The value of 'uri' is parsed
+// as a `url.URL` and its scheme is used as the key for a corresponding `DomainAllowsDatabaseInitializationFunc`
+// function used to instantiate the new `DomainAllowsDatabase`. It is assumed that the scheme (and initialization
+// function) have been registered by the `RegisterDomainAllowsDatabase` method.
+func NewDomainAllowsDatabase(ctx context.Context, uri string) (DomainAllowsDatabase, error) {
+
+	u, err := url.Parse(uri)
+
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := u.Scheme
+
+	i, err := domain_allows_database_roster.Driver(ctx, scheme)
+
+	if err != nil {
+		return nil, err
+	}
+
+	init_func := i.(DomainAllowsDatabaseInitializationFunc)
+	return init_func(ctx, uri)
+}
+
+// Schemes returns the list of schemes that have been registered.
+func DomainAllowsDatabaseSchemes() []string {
+
+	ctx := context.Background()
+	schemes := []string{}
+
+	err := ensureDomainAllowsDatabaseRoster()
+
+	if err != nil {
+		return schemes
+	}
+
+	for _, dr := range domain_allows_database_roster.Drivers(ctx) {
+		scheme := fmt.Sprintf("%s://", strings.ToLower(dr))
+		schemes = append(schemes, scheme)
+	}
+
+	sort.Strings(schemes)
+	return schemes
+}
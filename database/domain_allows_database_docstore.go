@@ -0,0 +1,126 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	aa_docstore "github.com/aaronland/gocloud/docstore"
+	"github.com/sfomuseum/go-activitypub"
+	gc_docstore "gocloud.dev/docstore"
+)
+
+type DocstoreDomainAllowsDatabase struct {
+	DomainAllowsDatabase
+	collection *gc_docstore.Collection
+}
+
+func init() {
+
+	ctx := context.Background()
+
+	err := RegisterDomainAllowsDatabase(ctx, "awsdynamodb", NewDocstoreDomainAllowsDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+
+	for _, scheme := range gc_docstore.DefaultURLMux().CollectionSchemes() {
+		err := RegisterDomainAllowsDatabase(ctx, scheme, NewDocstoreDomainAllowsDatabase)
+
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+func NewDocstoreDomainAllowsDatabase(ctx context.Context, uri string) (DomainAllowsDatabase, error) {
+
+	col, err := aa_docstore.OpenCollection(ctx, uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open collection, %w", err)
+	}
+
+	db := &DocstoreDomainAllowsDatabase{
+		collection: col,
+	}
+
+	return db, nil
+}
+
+func (db *DocstoreDomainAllowsDatabase) GetDomainAllowWithId(ctx context.Context, id int64) (*activitypub.DomainAllow, error) {
+
+	q := db.collection.Query()
+	q = q.Where("Id", "=", id)
+
+	return db.getDomainAllow(ctx, q)
+}
+
+func (db *DocstoreDomainAllowsDatabase) GetDomainAllowWithDomain(ctx context.Context, domain string) (*activitypub.DomainAllow, error) {
+
+	q := db.collection.Query()
+	q = q.Where("Domain", "=", domain)
+
+	return db.getDomainAllow(ctx, q)
+}
+
+func (db *DocstoreDomainAllowsDatabase) GetDomainAllows(ctx context.Context, cb GetDomainAllowsCallbackFunc) error {
+
+	q := db.collection.Query()
+
+	iter := q.Get(ctx)
+	defer iter.Stop()
+
+	for {
+
+		var a activitypub.DomainAllow
+		err := iter.Next(ctx, &a)
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("Failed to interate, %w", err)
+		} else {
+
+			err := cb(ctx, &a)
+
+			if err != nil {
+				return fmt.Errorf("Failed to execute domain allows callback for '%s', %w", a.Domain, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (db *DocstoreDomainAllowsDatabase) AddDomainAllow(ctx context.Context, a *activitypub.DomainAllow) error {
+
+	return db.collection.Put(ctx, a)
+}
+
+func (db *DocstoreDomainAllowsDatabase) RemoveDomainAllow(ctx context.Context, a *activitypub.DomainAllow) error {
+
+	return db.collection.Delete(ctx, a)
+}
+
+func (db *DocstoreDomainAllowsDatabase) Close(ctx context.Context) error {
+	return db.collection.Close()
+}
+
+func (db *DocstoreDomainAllowsDatabase) getDomainAllow(ctx context.Context, q *gc_docstore.Query) (*activitypub.DomainAllow, error) {
+
+	iter := q.Get(ctx)
+	defer iter.Stop()
+
+	var a activitypub.DomainAllow
+	err := iter.Next(ctx, &a)
+
+	if err == io.EOF {
+		return nil, activitypub.ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("Failed to interate, %w", err)
+	} else {
+		return &a, nil
+	}
+}
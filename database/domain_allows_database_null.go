@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+
+	"github.com/sfomuseum/go-activitypub"
+)
+
+type NullDomainAllowsDatabase struct {
+	DomainAllowsDatabase
+}
+
+func init() {
+
+	ctx := context.Background()
+	err := RegisterDomainAllowsDatabase(ctx, "null", NewNullDomainAllowsDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func NewNullDomainAllowsDatabase(ctx context.Context, uri string) (DomainAllowsDatabase, error) {
+	db := &NullDomainAllowsDatabase{}
+	return db, nil
+}
+
+func (db *NullDomainAllowsDatabase) GetDomainAllowWithId(ctx context.Context, id int64) (*activitypub.DomainAllow, error) {
+	return nil, activitypub.ErrNotFound
+}
+
+func (db *NullDomainAllowsDatabase) GetDomainAllowWithDomain(ctx context.Context, domain string) (*activitypub.DomainAllow, error) {
+	return nil, activitypub.ErrNotFound
+}
+
+func (db *NullDomainAllowsDatabase) GetDomainAllows(ctx context.Context, cb GetDomainAllowsCallbackFunc) error {
+	return nil
+}
+
+func (db *NullDomainAllowsDatabase) AddDomainAllow(ctx context.Context, a *activitypub.DomainAllow) error {
+	return nil
+}
+
+func (db *NullDomainAllowsDatabase) RemoveDomainAllow(ctx context.Context, a *activitypub.DomainAllow) error {
+	return nil
+}
+
+func (db *NullDomainAllowsDatabase) Close(ctx context.Context) error {
+	return nil
+}
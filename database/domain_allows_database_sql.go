@@ -0,0 +1,177 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	pg_sql "github.com/aaronland/go-pagination-sql"
+	"github.com/aaronland/go-pagination/countable"
+	"github.com/sfomuseum/go-activitypub"
+	sfom_sql "github.com/sfomuseum/go-database/sql"
+)
+
+const SQL_DOMAIN_ALLOWS_TABLE_NAME string = "domain_allows"
+
+type SQLDomainAllowsDatabase struct {
+	DomainAllowsDatabase
+	database *sql.DB
+}
+
+func init() {
+
+	ctx := context.Background()
+	err := RegisterDomainAllowsDatabase(ctx, "sql", NewSQLDomainAllowsDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func NewSQLDomainAllowsDatabase(ctx context.Context, uri string) (DomainAllowsDatabase, error) {
+
+	conn, err := sfom_sql.OpenWithURI(ctx, uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open database connection, %w", err)
+	}
+
+	db := &SQLDomainAllowsDatabase{
+		database: conn,
+	}
+
+	return db, nil
+}
+
+func (db *SQLDomainAllowsDatabase) GetDomainAllowWithId(ctx context.Context, id int64) (*activitypub.DomainAllow, error) {
+
+	where := "id = ?"
+	return db.getDomainAllow(ctx, where, id)
+}
+
+func (db *SQLDomainAllowsDatabase) GetDomainAllowWithDomain(ctx context.Context, domain string) (*activitypub.DomainAllow, error) {
+
+	where := "domain = ?"
+	return db.getDomainAllow(ctx, where, domain)
+}
+
+func (db *SQLDomainAllowsDatabase) GetDomainAllows(ctx context.Context, cb GetDomainAllowsCallbackFunc) error {
+
+	pg_callback := func(pg_rsp pg_sql.PaginatedResponse) error {
+
+		rows := pg_rsp.Rows()
+
+		for rows.Next() {
+
+			var id int64
+			var domain string
+			var comment string
+			var created int64
+
+			err := rows.Scan(&id, &domain, &comment, &created)
+
+			if err != nil {
+				return fmt.Errorf("Failed to query database, %w", err)
+			}
+
+			a := &activitypub.DomainAllow{
+				Id:      id,
+				Domain:  domain,
+				Comment: comment,
+				Created: created,
+			}
+
+			err = cb(ctx, a)
+
+			if err != nil {
+				return fmt.Errorf("Failed to execute domain allows callback for %s, %w", domain, err)
+			}
+		}
+
+		err := rows.Close()
+
+		if err != nil {
+			return fmt.Errorf("Failed to iterate through database rows, %w", err)
+		}
+
+		return nil
+	}
+
+	pg_opts, err := countable.NewCountableOptions()
+
+	if err != nil {
+		return fmt.Errorf("Failed to create pagination options, %w", err)
+	}
+
+	q := fmt.Sprintf("SELECT id, domain, comment, created FROM %s ORDER BY domain ASC", SQL_DOMAIN_ALLOWS_TABLE_NAME)
+
+	err = pg_sql.QueryPaginatedAll(db.database, pg_opts, pg_callback, q)
+
+	if err != nil {
+		return fmt.Errorf("Failed to execute paginated query, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLDomainAllowsDatabase) AddDomainAllow(ctx context.Context, a *activitypub.DomainAllow) error {
+
+	q := fmt.Sprintf("INSERT INTO %s (id, domain, comment, created) VALUES (?, ?, ?, ?)", SQL_DOMAIN_ALLOWS_TABLE_NAME)
+
+	_, err := db.database.ExecContext(ctx, q, a.Id, a.Domain, a.Comment, a.Created)
+
+	if err != nil {
+		return fmt.Errorf("Failed to add domain allow, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLDomainAllowsDatabase) RemoveDomainAllow(ctx context.Context, a *activitypub.DomainAllow) error {
+
+	q := fmt.Sprintf("DELETE FROM %s WHERE id = ?", SQL_DOMAIN_ALLOWS_TABLE_NAME)
+
+	_, err := db.database.ExecContext(ctx, q, a.Id)
+
+	if err != nil {
+		return fmt.Errorf("Failed to remove domain allow, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLDomainAllowsDatabase) Close(ctx context.Context) error {
+	return db.database.Close()
+}
+
+func (db *SQLDomainAllowsDatabase) getDomainAllow(ctx context.Context, where string, args ...interface{}) (*activitypub.DomainAllow, error) {
+
+	var id int64
+	var domain string
+	var comment string
+	var created int64
+
+	q := fmt.Sprintf("SELECT id, domain, comment, created FROM %s WHERE %s", SQL_DOMAIN_ALLOWS_TABLE_NAME, where)
+
+	row := db.database.QueryRowContext(ctx, q, args...)
+
+	err := row.Scan(&id, &domain, &comment, &created)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, activitypub.ErrNotFound
+	case err != nil:
+		return nil, fmt.Errorf("Failed to query database, %w", err)
+	default:
+		//
+	}
+
+	a := &activitypub.DomainAllow{
+		Id:      id,
+		Domain:  domain,
+		Comment: comment,
+		Created: created,
+	}
+
+	return a, nil
+}
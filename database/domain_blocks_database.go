@@ -0,0 +1,112 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/aaronland/go-roster"
+	"github.com/sfomuseum/go-activitypub"
+)
+
+type GetDomainBlocksCallbackFunc func(context.Context, *activitypub.DomainBlock) error
+
+// DomainBlocksDatabase defines an interface for storing server-wide blocks for remote domains.
+type DomainBlocksDatabase interface {
+	// GetDomainBlockWithId returns the `activitypub.DomainBlock` instance with a specific unique ID.
+	GetDomainBlockWithId(context.Context, int64) (*activitypub.DomainBlock, error)
+	// GetDomainBlockWithDomain returns the `activitypub.DomainBlock` instance for a specific domain.
+	GetDomainBlockWithDomain(context.Context, string) (*activitypub.DomainBlock, error)
+	// GetDomainBlocks iterates through all the `activitypub.DomainBlock` instances.
+	GetDomainBlocks(context.Context, GetDomainBlocksCallbackFunc) error
+	// AddDomainBlock adds a new `activitypub.DomainBlock` instance.
+	AddDomainBlock(context.Context, *activitypub.DomainBlock) error
+	// UpdateDomainBlock updates a specific `activitypub.DomainBlock` instance.
+	UpdateDomainBlock(context.Context, *activitypub.DomainBlock) error
+	// RemoveDomainBlock removes a specific `activitypub.DomainBlock` instance.
+	RemoveDomainBlock(context.Context, *activitypub.DomainBlock) error
+	// Close performs any final operations to terminate the underlying database connection.
+	Close(context.Context) error
+}
+
+var domain_blocks_database_roster roster.Roster
+
+// DomainBlocksDatabaseInitializationFunc is a function defined by individual domain_blocks_database package and used to create
+// an instance of that domain_blocks_database
+type DomainBlocksDatabaseInitializationFunc func(ctx context.Context, uri string) (DomainBlocksDatabase, error)
+
+// RegisterDomainBlocksDatabase registers 'scheme' as a key pointing to 'init_func' in an internal lookup table
+// used to create new `DomainBlocksDatabase` instances by the `NewDomainBlocksDatabase` method.
+func RegisterDomainBlocksDatabase(ctx context.Context, scheme string, init_func DomainBlocksDatabaseInitializationFunc) error {
+
+	err := ensureDomainBlocksDatabaseRoster()
+
+	if err != nil {
+		return err
+	}
+
+	return domain_blocks_database_roster.Register(ctx, scheme, init_func)
+}
+
+func ensureDomainBlocksDatabaseRoster() error {
+
+	if domain_blocks_database_roster == nil {
+
+		r, err := roster.NewDefaultRoster()
+
+		if err != nil {
+			return err
+		}
+
+		domain_blocks_database_roster = r
+	}
+
+	return nil
+}
+
+// NewDomainBlocksDatabase returns a new `DomainBlocksDatabase` instance configured by 'uri'. The value of 'uri' is parsed
+// as a `url.URL` and its scheme is used as the key for a corresponding `DomainBlocksDatabaseInitializationFunc`
+// function used to instantiate the new `DomainBlocksDatabase`. It is assumed that the scheme (and initialization
+// function) have been registered by the `RegisterDomainBlocksDatabase` method.
+func NewDomainBlocksDatabase(ctx context.Context, uri string) (DomainBlocksDatabase, error) {
+
+	u, err := url.Parse(uri)
+
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := u.Scheme
+
+	i, err := domain_blocks_database_roster.Driver(ctx, scheme)
+
+	if err != nil {
+		return nil, err
+	}
+
+	init_func := i.(DomainBlocksDatabaseInitializationFunc)
+	return init_func(ctx, uri)
+}
+
+// Schemes returns the list of schemes that have been registered.
+func DomainBlocksDatabaseSchemes() []string {
+
+	ctx := context.Background()
+	schemes := []string{}
+
+	err := ensureDomainBlocksDatabaseRoster()
+
+	if err != nil {
+		return schemes
+	}
+
+	for _, dr := range domain_blocks_database_roster.Drivers(ctx) {
+		scheme := fmt.Sprintf("%s://", strings.ToLower(dr))
+		schemes = append(schemes, scheme)
+	}
+
+	sort.Strings(schemes)
+	return schemes
+}
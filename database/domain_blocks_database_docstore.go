@@ -0,0 +1,131 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	aa_docstore "github.com/aaronland/gocloud/docstore"
+	"github.com/sfomuseum/go-activitypub"
+	gc_docstore "gocloud.dev/docstore"
+)
+
+type DocstoreDomainBlocksDatabase struct {
+	DomainBlocksDatabase
+	collection *gc_docstore.Collection
+}
+
+func init() {
+
+	ctx := context.Background()
+
+	err := RegisterDomainBlocksDatabase(ctx, "awsdynamodb", NewDocstoreDomainBlocksDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+
+	for _, scheme := range gc_docstore.DefaultURLMux().CollectionSchemes() {
+		err := RegisterDomainBlocksDatabase(ctx, scheme, NewDocstoreDomainBlocksDatabase)
+
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+func NewDocstoreDomainBlocksDatabase(ctx context.Context, uri string) (DomainBlocksDatabase, error) {
+
+	col, err := aa_docstore.OpenCollection(ctx, uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open collection, %w", err)
+	}
+
+	db := &DocstoreDomainBlocksDatabase{
+		collection: col,
+	}
+
+	return db, nil
+}
+
+func (db *DocstoreDomainBlocksDatabase) GetDomainBlockWithId(ctx context.Context, id int64) (*activitypub.DomainBlock, error) {
+
+	q := db.collection.Query()
+	q = q.Where("Id", "=", id)
+
+	return db.getDomainBlock(ctx, q)
+}
+
+func (db *DocstoreDomainBlocksDatabase) GetDomainBlockWithDomain(ctx context.Context, domain string) (*activitypub.DomainBlock, error) {
+
+	q := db.collection.Query()
+	q = q.Where("Domain", "=", domain)
+
+	return db.getDomainBlock(ctx, q)
+}
+
+func (db *DocstoreDomainBlocksDatabase) GetDomainBlocks(ctx context.Context, cb GetDomainBlocksCallbackFunc) error {
+
+	q := db.collection.Query()
+
+	iter := q.Get(ctx)
+	defer iter.Stop()
+
+	for {
+
+		var b activitypub.DomainBlock
+		err := iter.Next(ctx, &b)
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("Failed to interate, %w", err)
+		} else {
+
+			err := cb(ctx, &b)
+
+			if err != nil {
+				return fmt.Errorf("Failed to execute domain blocks callback for '%s', %w", b.Domain, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (db *DocstoreDomainBlocksDatabase) AddDomainBlock(ctx context.Context, b *activitypub.DomainBlock) error {
+
+	return db.collection.Put(ctx, b)
+}
+
+func (db *DocstoreDomainBlocksDatabase) UpdateDomainBlock(ctx context.Context, b *activitypub.DomainBlock) error {
+
+	return db.collection.Replace(ctx, b)
+}
+
+func (db *DocstoreDomainBlocksDatabase) RemoveDomainBlock(ctx context.Context, b *activitypub.DomainBlock) error {
+
+	return db.collection.Delete(ctx, b)
+}
+
+func (db *DocstoreDomainBlocksDatabase) Close(ctx context.Context) error {
+	return db.collection.Close()
+}
+
+func (db *DocstoreDomainBlocksDatabase) getDomainBlock(ctx context.Context, q *gc_docstore.Query) (*activitypub.DomainBlock, error) {
+
+	iter := q.Get(ctx)
+	defer iter.Stop()
+
+	var b activitypub.DomainBlock
+	err := iter.Next(ctx, &b)
+
+	if err == io.EOF {
+		return nil, activitypub.ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("Failed to interate, %w", err)
+	} else {
+		return &b, nil
+	}
+}
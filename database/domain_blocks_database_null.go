@@ -0,0 +1,54 @@
+package database
+
+import (
+	"context"
+
+	"github.com/sfomuseum/go-activitypub"
+)
+
+type NullDomainBlocksDatabase struct {
+	DomainBlocksDatabase
+}
+
+func init() {
+
+	ctx := context.Background()
+	err := RegisterDomainBlocksDatabase(ctx, "null", NewNullDomainBlocksDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func NewNullDomainBlocksDatabase(ctx context.Context, uri string) (DomainBlocksDatabase, error) {
+	db := &NullDomainBlocksDatabase{}
+	return db, nil
+}
+
+func (db *NullDomainBlocksDatabase) GetDomainBlockWithId(ctx context.Context, id int64) (*activitypub.DomainBlock, error) {
+	return nil, activitypub.ErrNotFound
+}
+
+func (db *NullDomainBlocksDatabase) GetDomainBlockWithDomain(ctx context.Context, domain string) (*activitypub.DomainBlock, error) {
+	return nil, activitypub.ErrNotFound
+}
+
+func (db *NullDomainBlocksDatabase) GetDomainBlocks(ctx context.Context, cb GetDomainBlocksCallbackFunc) error {
+	return nil
+}
+
+func (db *NullDomainBlocksDatabase) AddDomainBlock(ctx context.Context, b *activitypub.DomainBlock) error {
+	return nil
+}
+
+func (db *NullDomainBlocksDatabase) UpdateDomainBlock(ctx context.Context, b *activitypub.DomainBlock) error {
+	return nil
+}
+
+func (db *NullDomainBlocksDatabase) RemoveDomainBlock(ctx context.Context, b *activitypub.DomainBlock) error {
+	return nil
+}
+
+func (db *NullDomainBlocksDatabase) Close(ctx context.Context) error {
+	return nil
+}
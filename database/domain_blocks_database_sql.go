@@ -0,0 +1,214 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	pg_sql "github.com/aaronland/go-pagination-sql"
+	"github.com/aaronland/go-pagination/countable"
+	"github.com/sfomuseum/go-activitypub"
+	sfom_sql "github.com/sfomuseum/go-database/sql"
+)
+
+const SQL_DOMAIN_BLOCKS_TABLE_NAME string = "domain_blocks"
+
+type SQLDomainBlocksDatabase struct {
+	DomainBlocksDatabase
+	database *sql.DB
+}
+
+func init() {
+
+	ctx := context.Background()
+	err := RegisterDomainBlocksDatabase(ctx, "sql", NewSQLDomainBlocksDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func NewSQLDomainBlocksDatabase(ctx context.Context, uri string) (DomainBlocksDatabase, error) {
+
+	conn, err := sfom_sql.OpenWithURI(ctx, uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open database connection, %w", err)
+	}
+
+	db := &SQLDomainBlocksDatabase{
+		database: conn,
+	}
+
+	return db, nil
+}
+
+func (db *SQLDomainBlocksDatabase) GetDomainBlockWithId(ctx context.Context, id int64) (*activitypub.DomainBlock, error) {
+
+	where := "id = ?"
+	return db.getDomainBlock(ctx, where, id)
+}
+
+func (db *SQLDomainBlocksDatabase) GetDomainBlockWithDomain(ctx context.Context, domain string) (*activitypub.DomainBlock, error) {
+
+	where := "domain = ?"
+	return db.getDomainBlock(ctx, where, domain)
+}
+
+func (db *SQLDomainBlocksDatabase) GetDomainBlocks(ctx context.Context, cb GetDomainBlocksCallbackFunc) error {
+
+	pg_callback := func(pg_rsp pg_sql.PaginatedResponse) error {
+
+		rows := pg_rsp.Rows()
+
+		for rows.Next() {
+
+			var id int64
+			var domain string
+			var severity string
+			var reject_media int
+			var reject_reports int
+			var public_comment string
+			var private_comment string
+			var obfuscate int
+			var created int64
+			var lastmodified int64
+
+			err := rows.Scan(&id, &domain, &severity, &reject_media, &reject_reports, &public_comment, &private_comment, &obfuscate, &created, &lastmodified)
+
+			if err != nil {
+				return fmt.Errorf("Failed to query database, %w", err)
+			}
+
+			b := &activitypub.DomainBlock{
+				Id:             id,
+				Domain:         domain,
+				Severity:       severity,
+				RejectMedia:    reject_media > 0,
+				RejectReports:  reject_reports > 0,
+				PublicComment:  public_comment,
+				PrivateComment: private_comment,
+				Obfuscate:      obfuscate > 0,
+				Created:        created,
+				LastModified:   lastmodified,
+			}
+
+			err = cb(ctx, b)
+
+			if err != nil {
+				return fmt.Errorf("Failed to execute domain blocks callback for %s, %w", domain, err)
+			}
+		}
+
+		err := rows.Close()
+
+		if err != nil {
+			return fmt.Errorf("Failed to iterate through database rows, %w", err)
+		}
+
+		return nil
+	}
+
+	pg_opts, err := countable.NewCountableOptions()
+
+	if err != nil {
+		return fmt.Errorf("Failed to create pagination options, %w", err)
+	}
+
+	q := fmt.Sprintf("SELECT id, domain, severity, reject_media, reject_reports, public_comment, private_comment, obfuscate, created, lastmodified FROM %s ORDER BY domain ASC", SQL_DOMAIN_BLOCKS_TABLE_NAME)
+
+	err = pg_sql.QueryPaginatedAll(db.database, pg_opts, pg_callback, q)
+
+	if err != nil {
+		return fmt.Errorf("Failed to execute paginated query, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLDomainBlocksDatabase) AddDomainBlock(ctx context.Context, b *activitypub.DomainBlock) error {
+
+	q := fmt.Sprintf("INSERT INTO %s (id, domain, severity, reject_media, reject_reports, public_comment, private_comment, obfuscate, created, lastmodified) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", SQL_DOMAIN_BLOCKS_TABLE_NAME)
+
+	_, err := db.database.ExecContext(ctx, q, b.Id, b.Domain, b.Severity, b.RejectMedia, b.RejectReports, b.PublicComment, b.PrivateComment, b.Obfuscate, b.Created, b.LastModified)
+
+	if err != nil {
+		return fmt.Errorf("Failed to add domain block, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLDomainBlocksDatabase) UpdateDomainBlock(ctx context.Context, b *activitypub.DomainBlock) error {
+
+	q := fmt.Sprintf("UPDATE %s SET domain = ?, severity = ?, reject_media = ?, reject_reports = ?, public_comment = ?, private_comment = ?, obfuscate = ?, lastmodified = ? WHERE id = ?", SQL_DOMAIN_BLOCKS_TABLE_NAME)
+
+	_, err := db.database.ExecContext(ctx, q, b.Domain, b.Severity, b.RejectMedia, b.RejectReports, b.PublicComment, b.PrivateComment, b.Obfuscate, b.LastModified, b.Id)
+
+	if err != nil {
+		return fmt.Errorf("Failed to update domain block, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLDomainBlocksDatabase) RemoveDomainBlock(ctx context.Context, b *activitypub.DomainBlock) error {
+
+	q := fmt.Sprintf("DELETE FROM %s WHERE id = ?", SQL_DOMAIN_BLOCKS_TABLE_NAME)
+
+	_, err := db.database.ExecContext(ctx, q, b.Id)
+
+	if err != nil {
+		return fmt.Errorf("Failed to remove domain block, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLDomainBlocksDatabase) Close(ctx context.Context) error {
+	return db.database.Close()
+}
+
+func (db *SQLDomainBlocksDatabase) getDomainBlock(ctx context.Context, where string, args ...interface{}) (*activitypub.DomainBlock, error) {
+
+	var id int64
+	var domain string
+	var severity string
+	var reject_media int
+	var reject_reports int
+	var public_comment string
+	var private_comment string
+	var obfuscate int
+	var created int64
+	var lastmodified int64
+
+	q := fmt.Sprintf("SELECT id, domain, severity, reject_media, reject_reports, public_comment, private_comment, obfuscate, created, lastmodified FROM %s WHERE %s", SQL_DOMAIN_BLOCKS_TABLE_NAME, where)
+
+	row := db.database.QueryRowContext(ctx, q, args...)
+
+	err := row.Scan(&id, &domain, &severity, &reject_media, &reject_reports, &public_comment, &private_comment, &obfuscate, &created, &lastmodified)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, activitypub.ErrNotFound
+	case err != nil:
+		return nil, fmt.Errorf("Failed to query database, %w", err)
+	default:
+		//
+	}
+
+	b := &activitypub.DomainBlock{
+		Id:             id,
+		Domain:         domain,
+		Severity:       severity,
+		RejectMedia:    reject_media > 0,
+		RejectReports:  reject_reports > 0,
+		PublicComment:  public_comment,
+		PrivateComment: private_comment,
+		Obfuscate:      obfuscate > 0,
+		Created:        created,
+		LastModified:   lastmodified,
+	}
+
+	return b, nil
+}
@@ -0,0 +1,110 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/aaronland/go-roster"
+	"github.com/sfomuseum/go-activitypub"
+)
+
+type GetFollowRequestsCallbackFunc func(context.Context, *activitypub.FollowRequest) error
+
+// FollowRequestsDatabase defines an interface for working with pending requests to follow accounts which manually approve followers.
+type FollowRequestsDatabase interface {
+	// GetFollowRequestsForAccount iterates through all the pending follow requests for a specific account and dispatches each to an instance of `GetFollowRequestsCallbackFunc`.
+	GetFollowRequestsForAccount(context.Context, int64, GetFollowRequestsCallbackFunc) error
+	// GetFollowRequestWithId returns the follow request matching a specific 64-bit ID.
+	GetFollowRequestWithId(context.Context, int64) (*activitypub.FollowRequest, error)
+	// GetFollowRequest returns the follow request for a specific account ID and follower address.
+	GetFollowRequest(context.Context, int64, string) (*activitypub.FollowRequest, error)
+	// AddFollowRequest adds a new `activitypub.FollowRequest` instance.
+	AddFollowRequest(context.Context, *activitypub.FollowRequest) error
+	// RemoveFollowRequest removes a specific `activitypub.FollowRequest` instance.
+	RemoveFollowRequest(context.Context, *activitypub.FollowRequest) error
+	// Close performs any final operations to terminate the underlying database connection.
+	Close(context.Context) error
+}
+
+var follow_requests_database_roster roster.Roster
+
+// FollowRequestsDatabaseInitializationFunc is a function defined by individual follow_requests_database package and used to create
+// an instance of that follow_requests_database
+type FollowRequestsDatabaseInitializationFunc func(ctx context.Context, uri string) (FollowRequestsDatabase, error)
+
+// RegisterFollowRequestsDatabase registers 'scheme' as a key pointing to 'init_func' in an internal lookup table
+// used to create new `FollowRequestsDatabase` instances by the `NewFollowRequestsDatabase` method.
+func RegisterFollowRequestsDatabase(ctx context.Context, scheme string, init_func FollowRequestsDatabaseInitializationFunc) error {
+
+	err := ensureFollowRequestsDatabaseRoster()
+
+	if err != nil {
+		return err
+	}
+
+	return follow_requests_database_roster.Register(ctx, scheme, init_func)
+}
+
+func ensureFollowRequestsDatabaseRoster() error {
+
+	if follow_requests_database_roster == nil {
+
+		r, err := roster.NewDefaultRoster()
+
+		if err != nil {
+			return err
+		}
+
+		follow_requests_database_roster = r
+	}
+
+	return nil
+}
+
+// NewFollowRequestsDatabase returns a new `FollowRequestsDatabase` instance configured by 'uri'. The value of 'uri' is parsed
+// as a `url.URL` and its scheme is used as the key for a corresponding `FollowRequestsDatabaseInitializationFunc`
+// function used to instantiate the new `FollowRequestsDatabase`. It is assumed that the scheme (and initialization
+// function) have been registered by the `RegisterFollowRequestsDatabase` method.
+func NewFollowRequestsDatabase(ctx context.Context, uri string) (FollowRequestsDatabase, error) {
+
+	u, err := url.Parse(uri)
+
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := u.Scheme
+
+	i, err := follow_requests_database_roster.Driver(ctx, scheme)
+
+	if err != nil {
+		return nil, err
+	}
+
+	init_func := i.(FollowRequestsDatabaseInitializationFunc)
+	return init_func(ctx, uri)
+}
+
+// Schemes returns the list of schemes that have been registered.
+func FollowRequestsDatabaseSchemes() []string {
+
+	ctx := context.Background()
+	schemes := []string{}
+
+	err := ensureFollowRequestsDatabaseRoster()
+
+	if err != nil {
+		return schemes
+	}
+
+	for _, dr := range follow_requests_database_roster.Drivers(ctx) {
+		scheme := fmt.Sprintf("%s://", strings.ToLower(dr))
+		schemes = append(schemes, scheme)
+	}
+
+	sort.Strings(schemes)
+	return schemes
+}
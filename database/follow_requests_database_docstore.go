@@ -0,0 +1,128 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	aa_docstore "github.com/aaronland/gocloud/docstore"
+	"github.com/sfomuseum/go-activitypub"
+	gc_docstore "gocloud.dev/docstore"
+)
+
+type DocstoreFollowRequestsDatabase struct {
+	FollowRequestsDatabase
+	collection *gc_docstore.Collection
+}
+
+func init() {
+
+	ctx := context.Background()
+
+	err := RegisterFollowRequestsDatabase(ctx, "awsdynamodb", NewDocstoreFollowRequestsDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+
+	for _, scheme := range gc_docstore.DefaultURLMux().CollectionSchemes() {
+		err := RegisterFollowRequestsDatabase(ctx, scheme, NewDocstoreFollowRequestsDatabase)
+
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+func NewDocstoreFollowRequestsDatabase(ctx context.Context, uri string) (FollowRequestsDatabase, error) {
+
+	col, err := aa_docstore.OpenCollection(ctx, uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open collection, %w", err)
+	}
+
+	db := &DocstoreFollowRequestsDatabase{
+		collection: col,
+	}
+
+	return db, nil
+}
+
+func (db *DocstoreFollowRequestsDatabase) GetFollowRequestWithId(ctx context.Context, id int64) (*activitypub.FollowRequest, error) {
+
+	q := db.collection.Query()
+	q = q.Where("Id", "=", id)
+
+	return db.getFollowRequest(ctx, q)
+}
+
+func (db *DocstoreFollowRequestsDatabase) GetFollowRequest(ctx context.Context, account_id int64, follower_address string) (*activitypub.FollowRequest, error) {
+
+	q := db.collection.Query()
+	q = q.Where("AccountId", "=", account_id)
+	q = q.Where("FollowerAddress", "=", follower_address)
+
+	return db.getFollowRequest(ctx, q)
+}
+
+func (db *DocstoreFollowRequestsDatabase) GetFollowRequestsForAccount(ctx context.Context, account_id int64, cb GetFollowRequestsCallbackFunc) error {
+
+	q := db.collection.Query()
+	q = q.Where("AccountId", "=", account_id)
+
+	iter := q.Get(ctx)
+	defer iter.Stop()
+
+	for {
+
+		var r activitypub.FollowRequest
+		err := iter.Next(ctx, &r)
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("Failed to interate, %w", err)
+		} else {
+
+			err := cb(ctx, &r)
+
+			if err != nil {
+				return fmt.Errorf("Failed to execute callback for follow request %d, %w", r.Id, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (db *DocstoreFollowRequestsDatabase) AddFollowRequest(ctx context.Context, r *activitypub.FollowRequest) error {
+
+	return db.collection.Put(ctx, r)
+}
+
+func (db *DocstoreFollowRequestsDatabase) RemoveFollowRequest(ctx context.Context, r *activitypub.FollowRequest) error {
+
+	return db.collection.Delete(ctx, r)
+}
+
+func (db *DocstoreFollowRequestsDatabase) Close(ctx context.Context) error {
+	return db.collection.Close()
+}
+
+func (db *DocstoreFollowRequestsDatabase) getFollowRequest(ctx context.Context, q *gc_docstore.Query) (*activitypub.FollowRequest, error) {
+
+	iter := q.Get(ctx)
+	defer iter.Stop()
+
+	var r activitypub.FollowRequest
+	err := iter.Next(ctx, &r)
+
+	if err == io.EOF {
+		return nil, activitypub.ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("Failed to interate, %w", err)
+	} else {
+		return &r, nil
+	}
+}
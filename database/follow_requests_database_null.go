@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+
+	"github.com/sfomuseum/go-activitypub"
+)
+
+type NullFollowRequestsDatabase struct {
+	FollowRequestsDatabase
+}
+
+func init() {
+
+	ctx := context.Background()
+	err := RegisterFollowRequestsDatabase(ctx, "null", NewNullFollowRequestsDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func NewNullFollowRequestsDatabase(ctx context.Context, uri string) (FollowRequestsDatabase, error) {
+	db := &NullFollowRequestsDatabase{}
+	return db, nil
+}
+
+func (db *NullFollowRequestsDatabase) GetFollowRequestWithId(ctx context.Context, id int64) (*activitypub.FollowRequest, error) {
+	return nil, activitypub.ErrNotFound
+}
+
+func (db *NullFollowRequestsDatabase) GetFollowRequest(ctx context.Context, account_id int64, follower_address string) (*activitypub.FollowRequest, error) {
+	return nil, activitypub.ErrNotFound
+}
+
+func (db *NullFollowRequestsDatabase) GetFollowRequestsForAccount(ctx context.Context, account_id int64, cb GetFollowRequestsCallbackFunc) error {
+	return nil
+}
+
+func (db *NullFollowRequestsDatabase) AddFollowRequest(ctx context.Context, r *activitypub.FollowRequest) error {
+	return nil
+}
+
+func (db *NullFollowRequestsDatabase) RemoveFollowRequest(ctx context.Context, r *activitypub.FollowRequest) error {
+	return nil
+}
+
+func (db *NullFollowRequestsDatabase) Close(ctx context.Context) error {
+	return nil
+}
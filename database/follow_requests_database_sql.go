@@ -0,0 +1,185 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	pg_sql "github.com/aaronland/go-pagination-sql"
+	"github.com/aaronland/go-pagination/countable"
+	"github.com/sfomuseum/go-activitypub"
+	sfom_sql "github.com/sfomuseum/go-database/sql"
+)
+
+const SQL_FOLLOW_REQUESTS_TABLE_NAME string = "follow_requests"
+
+type SQLFollowRequestsDatabase struct {
+	FollowRequestsDatabase
+	database *sql.DB
+}
+
+func init() {
+
+	ctx := context.Background()
+	err := RegisterFollowRequestsDatabase(ctx, "sql", NewSQLFollowRequestsDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func NewSQLFollowRequestsDatabase(ctx context.Context, uri string) (FollowRequestsDatabase, error) {
+
+	conn, err := sfom_sql.OpenWithURI(ctx, uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open database connection, %w", err)
+	}
+
+	db := &SQLFollowRequestsDatabase{
+		database: conn,
+	}
+
+	return db, nil
+}
+
+func (db *SQLFollowRequestsDatabase) GetFollowRequestWithId(ctx context.Context, id int64) (*activitypub.FollowRequest, error) {
+
+	where := "id = ?"
+	return db.getFollowRequest(ctx, where, id)
+}
+
+func (db *SQLFollowRequestsDatabase) GetFollowRequest(ctx context.Context, account_id int64, follower_address string) (*activitypub.FollowRequest, error) {
+
+	where := "account_id = ? AND follower_address = ?"
+	return db.getFollowRequest(ctx, where, account_id, follower_address)
+}
+
+func (db *SQLFollowRequestsDatabase) GetFollowRequestsForAccount(ctx context.Context, account_id int64, cb GetFollowRequestsCallbackFunc) error {
+
+	pg_callback := func(pg_rsp pg_sql.PaginatedResponse) error {
+
+		rows := pg_rsp.Rows()
+
+		for rows.Next() {
+
+			var id int64
+			var account_id int64
+			var follower_address string
+			var activity_id string
+			var body string
+			var created int64
+
+			err := rows.Scan(&id, &account_id, &follower_address, &activity_id, &body, &created)
+
+			if err != nil {
+				return fmt.Errorf("Failed to query database, %w", err)
+			}
+
+			r := &activitypub.FollowRequest{
+				Id:              id,
+				AccountId:       account_id,
+				FollowerAddress: follower_address,
+				ActivityId:      activity_id,
+				Body:            body,
+				Created:         created,
+			}
+
+			err = cb(ctx, r)
+
+			if err != nil {
+				return fmt.Errorf("Failed to execute callback for follow request %d, %w", r.Id, err)
+			}
+		}
+
+		err := rows.Close()
+
+		if err != nil {
+			return fmt.Errorf("Failed to iterate through database rows, %w", err)
+		}
+
+		return nil
+	}
+
+	pg_opts, err := countable.NewCountableOptions()
+
+	if err != nil {
+		return fmt.Errorf("Failed to create pagination options, %w", err)
+	}
+
+	q := fmt.Sprintf("SELECT id, account_id, follower_address, activity_id, body, created FROM %s WHERE account_id = ? ORDER BY created ASC", SQL_FOLLOW_REQUESTS_TABLE_NAME)
+
+	err = pg_sql.QueryPaginatedAll(db.database, pg_opts, pg_callback, q, account_id)
+
+	if err != nil {
+		return fmt.Errorf("Failed to execute paginated query, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLFollowRequestsDatabase) AddFollowRequest(ctx context.Context, r *activitypub.FollowRequest) error {
+
+	q := fmt.Sprintf("INSERT INTO %s (id, account_id, follower_address, activity_id, body, created) VALUES (?, ?, ?, ?, ?, ?)", SQL_FOLLOW_REQUESTS_TABLE_NAME)
+
+	_, err := db.database.ExecContext(ctx, q, r.Id, r.AccountId, r.FollowerAddress, r.ActivityId, r.Body, r.Created)
+
+	if err != nil {
+		return fmt.Errorf("Failed to add follow request, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLFollowRequestsDatabase) RemoveFollowRequest(ctx context.Context, r *activitypub.FollowRequest) error {
+
+	q := fmt.Sprintf("DELETE FROM %s WHERE id = ?", SQL_FOLLOW_REQUESTS_TABLE_NAME)
+
+	_, err := db.database.ExecContext(ctx, q, r.Id)
+
+	if err != nil {
+		return fmt.Errorf("Failed to remove follow request, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLFollowRequestsDatabase) Close(ctx context.Context) error {
+	return db.database.Close()
+}
+
+func (db *SQLFollowRequestsDatabase) getFollowRequest(ctx context.Context, where string, args ...interface{}) (*activitypub.FollowRequest, error) {
+
+	var id int64
+	var account_id int64
+	var follower_address string
+	var activity_id string
+	var body string
+	var created int64
+
+	q := fmt.Sprintf("SELECT id, account_id, follower_address, activity_id, body, created FROM %s WHERE %s", SQL_FOLLOW_REQUESTS_TABLE_NAME, where)
+
+	row := db.database.QueryRowContext(ctx, q, args...)
+
+	err := row.Scan(&id, &account_id, &follower_address, &activity_id, &body, &created)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, activitypub.ErrNotFound
+	case err != nil:
+		return nil, fmt.Errorf("Failed to query database, %w", err)
+	default:
+		//
+	}
+
+	r := &activitypub.FollowRequest{
+		Id:              id,
+		AccountId:       account_id,
+		FollowerAddress: follower_address,
+		ActivityId:      activity_id,
+		Body:            body,
+		Created:         created,
+	}
+
+	return r, nil
+}
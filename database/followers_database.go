@@ -19,6 +19,8 @@ type FollowersDatabase interface {
 	GetFollowerIdsForDateRange(context.Context, int64, int64, GetFollowerIdsCallbackFunc) error
 	GetAllFollowers(context.Context, GetFollowersCallbackFunc) error
 	GetFollowersForAccount(context.Context, int64, GetFollowersCallbackFunc) error
+	// GetAccountIdsForFollowerAddress iterates through the IDs of all the accounts followed by a specific "@name@host" address and dispatches each to an instance of `GetAccountIdsCallbackFunc`.
+	GetAccountIdsForFollowerAddress(context.Context, string, GetAccountIdsCallbackFunc) error
 	HasFollowers(context.Context, int64) (bool, error)
 	GetFollower(context.Context, int64, string) (*activitypub.Follower, error)
 	AddFollower(context.Context, *activitypub.Follower) error
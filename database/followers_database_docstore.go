@@ -144,6 +144,36 @@ func (db *DocstoreFollowersDatabase) GetFollowersForAccount(ctx context.Context,
 	return db.getFollowerAddressesWithCallback(ctx, q, cb)
 }
 
+func (db *DocstoreFollowersDatabase) GetAccountIdsForFollowerAddress(ctx context.Context, follower_address string, cb GetAccountIdsCallbackFunc) error {
+
+	q := db.collection.Query()
+	q = q.Where("FollowerAddress", "=", follower_address)
+
+	iter := q.Get(ctx, "AccountId")
+	defer iter.Stop()
+
+	for {
+
+		var f activitypub.Follower
+		err := iter.Next(ctx, &f)
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("Failed to interate, %w", err)
+		} else {
+
+			err := cb(ctx, f.AccountId)
+
+			if err != nil {
+				return fmt.Errorf("Failed to invoke callback for account %d, %w", f.AccountId, err)
+			}
+		}
+	}
+
+	return nil
+}
+
 func (db *DocstoreFollowersDatabase) Close(ctx context.Context) error {
 	return db.collection.Close()
 }
@@ -33,6 +33,10 @@ func (db *NullFollowersDatabase) GetAllFollowers(ctx context.Context, cb GetFoll
 	return nil
 }
 
+func (db *NullFollowersDatabase) GetFollowersForAccount(ctx context.Context, account_id int64, cb GetFollowersCallbackFunc) error {
+	return nil
+}
+
 func (db *NullFollowersDatabase) HasFollowers(ctx context.Context, account_id int64) (bool, error) {
 	return false, nil
 }
@@ -45,6 +49,18 @@ func (db *NullFollowersDatabase) GetFollowerWithId(ctx context.Context, follower
 	return nil, activitypub.ErrNotFound
 }
 
+func (db *NullFollowersDatabase) AddFollower(ctx context.Context, f *activitypub.Follower) error {
+	return nil
+}
+
+func (db *NullFollowersDatabase) RemoveFollower(ctx context.Context, f *activitypub.Follower) error {
+	return nil
+}
+
+func (db *NullFollowersDatabase) GetAccountIdsForFollowerAddress(ctx context.Context, follower_address string, cb GetAccountIdsCallbackFunc) error {
+	return nil
+}
+
 func (db *NullFollowersDatabase) Close(ctx context.Context) error {
 	return nil
 }
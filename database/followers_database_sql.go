@@ -211,6 +211,55 @@ func (db *SQLFollowersDatabase) GetAllFollowers(ctx context.Context, followers_c
 	return db.getFollowerAddressesWithCallback(ctx, q, args, followers_callback)
 }
 
+func (db *SQLFollowersDatabase) GetAccountIdsForFollowerAddress(ctx context.Context, follower_address string, cb GetAccountIdsCallbackFunc) error {
+
+	pg_callback := func(pg_rsp pg_sql.PaginatedResponse) error {
+
+		rows := pg_rsp.Rows()
+
+		for rows.Next() {
+
+			var account_id int64
+
+			err := rows.Scan(&account_id)
+
+			if err != nil {
+				return fmt.Errorf("Failed to scan row, %w", err)
+			}
+
+			err = cb(ctx, account_id)
+
+			if err != nil {
+				return fmt.Errorf("Failed to execute callback for account %d, %w", account_id, err)
+			}
+		}
+
+		err := rows.Close()
+
+		if err != nil {
+			return fmt.Errorf("Failed to iterate through database rows, %w", err)
+		}
+
+		return nil
+	}
+
+	pg_opts, err := countable.NewCountableOptions()
+
+	if err != nil {
+		return fmt.Errorf("Failed to create pagination options, %w", err)
+	}
+
+	q := fmt.Sprintf("SELECT account_id FROM %s WHERE follower_address = ?", SQL_FOLLOWERS_TABLE_NAME)
+
+	err = pg_sql.QueryPaginatedAll(db.database, pg_opts, pg_callback, q, follower_address)
+
+	if err != nil {
+		return fmt.Errorf("Failed to execute paginated query, %w", err)
+	}
+
+	return nil
+}
+
 func (db *SQLFollowersDatabase) getFollowerAddressesWithCallback(ctx context.Context, q string, args []interface{}, followers_callback GetFollowersCallbackFunc) error {
 
 	pg_callback := func(pg_rsp pg_sql.PaginatedResponse) error {
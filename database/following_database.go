@@ -17,8 +17,11 @@ type GetFollowingCallbackFunc func(context.Context, string) error
 type FollowingDatabase interface {
 	GetFollowingIdsForDateRange(context.Context, int64, int64, GetFollowingIdsCallbackFunc) error
 	GetFollowingForAccount(context.Context, int64, GetFollowingCallbackFunc) error
+	// GetAccountIdsForFollowingAddress iterates through the IDs of all the accounts following a specific "@name@host" address and dispatches each to an instance of `GetAccountIdsCallbackFunc`.
+	GetAccountIdsForFollowingAddress(context.Context, string, GetAccountIdsCallbackFunc) error
 	GetFollowing(context.Context, int64, string) (*activitypub.Following, error)
 	AddFollowing(context.Context, *activitypub.Following) error
+	UpdateFollowing(context.Context, *activitypub.Following) error
 	RemoveFollowing(context.Context, *activitypub.Following) error
 	Close(context.Context) error
 }
@@ -113,6 +113,11 @@ func (db *DocstoreFollowingDatabase) AddFollowing(ctx context.Context, f *activi
 	return db.collection.Put(ctx, f)
 }
 
+func (db *DocstoreFollowingDatabase) UpdateFollowing(ctx context.Context, f *activitypub.Following) error {
+
+	return db.collection.Replace(ctx, f)
+}
+
 func (db *DocstoreFollowingDatabase) RemoveFollowing(ctx context.Context, f *activitypub.Following) error {
 
 	return db.collection.Delete(ctx, f)
@@ -148,6 +153,36 @@ func (db *DocstoreFollowingDatabase) GetFollowingForAccount(ctx context.Context,
 	return nil
 }
 
+func (db *DocstoreFollowingDatabase) GetAccountIdsForFollowingAddress(ctx context.Context, following_address string, cb GetAccountIdsCallbackFunc) error {
+
+	q := db.collection.Query()
+	q = q.Where("FollowingAddress", "=", following_address)
+
+	iter := q.Get(ctx, "AccountId")
+	defer iter.Stop()
+
+	for {
+
+		var f activitypub.Following
+		err := iter.Next(ctx, &f)
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("Failed to interate, %w", err)
+		} else {
+
+			err := cb(ctx, f.AccountId)
+
+			if err != nil {
+				return fmt.Errorf("Failed to invoke callback for account %d, %w", f.AccountId, err)
+			}
+		}
+	}
+
+	return nil
+}
+
 func (db *DocstoreFollowingDatabase) Close(ctx context.Context) error {
 	return db.collection.Close()
 }
@@ -36,6 +36,10 @@ func (db *NullFollowingDatabase) AddFollowing(ctx context.Context, f *activitypu
 	return nil
 }
 
+func (db *NullFollowingDatabase) UpdateFollowing(ctx context.Context, f *activitypub.Following) error {
+	return nil
+}
+
 func (db *NullFollowingDatabase) RemoveFollowing(ctx context.Context, f *activitypub.Following) error {
 	return nil
 }
@@ -44,6 +48,10 @@ func (db *NullFollowingDatabase) GetFollowingForAccount(ctx context.Context, acc
 	return nil
 }
 
+func (db *NullFollowingDatabase) GetAccountIdsForFollowingAddress(ctx context.Context, following_address string, cb GetAccountIdsCallbackFunc) error {
+	return nil
+}
+
 func (db *NullFollowingDatabase) Close(ctx context.Context) error {
 	return nil
 }
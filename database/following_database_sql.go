@@ -95,39 +95,57 @@ func (db *SQLFollowingDatabase) GetFollowingIdsForDateRange(ctx context.Context,
 
 func (db *SQLFollowingDatabase) GetFollowing(ctx context.Context, account_id int64, following_address string) (*activitypub.Following, error) {
 
-	q := fmt.Sprintf("SELECT id, created FROM %s WHERE account_id = ? AND following_address = ?", SQL_FOLLOWING_TABLE_NAME)
+	q := fmt.Sprintf("SELECT id, activity_id, state, created, lastmodified FROM %s WHERE account_id = ? AND following_address = ?", SQL_FOLLOWING_TABLE_NAME)
 
 	row := db.database.QueryRowContext(ctx, q, account_id, following_address)
 
 	var id int64
+	var activity_id sql.NullString
+	var state sql.NullInt64
 	var created int64
+	var lastmod sql.NullInt64
 
-	err := row.Scan(&id, &created)
+	err := row.Scan(&id, &activity_id, &state, &created, &lastmod)
 
 	switch {
 	case err == sql.ErrNoRows:
 		return nil, activitypub.ErrNotFound
 	case err != nil:
 		return nil, fmt.Errorf("Failed to query database, %w", err)
-	default:
+	}
 
-		f := &activitypub.Following{
-			Id:               id,
-			AccountId:        account_id,
-			FollowingAddress: following_address,
-			Created:          created,
-		}
+	// Rows created before follow requests were tracked will not have an activity ID, state
+	// or last modified time and are treated as accepted follow requests.
+
+	f := &activitypub.Following{
+		Id:               id,
+		AccountId:        account_id,
+		FollowingAddress: following_address,
+		State:            activitypub.AcceptedFollowingState,
+		Created:          created,
+		LastModified:     created,
+	}
+
+	if activity_id.Valid {
+		f.ActivityId = activity_id.String
+	}
+
+	if state.Valid {
+		f.State = activitypub.FollowingState(state.Int64)
+	}
 
-		return f, nil
+	if lastmod.Valid {
+		f.LastModified = lastmod.Int64
 	}
 
+	return f, nil
 }
 
 func (db *SQLFollowingDatabase) AddFollowing(ctx context.Context, f *activitypub.Following) error {
 
-	q := fmt.Sprintf("INSERT INTO %s (id, account_id, following_address, created) VALUES (?, ?, ?, ?)", SQL_FOLLOWING_TABLE_NAME)
+	q := fmt.Sprintf("INSERT INTO %s (id, account_id, following_address, activity_id, state, created, lastmodified) VALUES (?, ?, ?, ?, ?, ?, ?)", SQL_FOLLOWING_TABLE_NAME)
 
-	_, err := db.database.ExecContext(ctx, q, f.Id, f.AccountId, f.FollowingAddress, f.Created)
+	_, err := db.database.ExecContext(ctx, q, f.Id, f.AccountId, f.FollowingAddress, f.ActivityId, f.State, f.Created, f.LastModified)
 
 	if err != nil {
 		return fmt.Errorf("Failed to add following, %w", err)
@@ -136,6 +154,19 @@ func (db *SQLFollowingDatabase) AddFollowing(ctx context.Context, f *activitypub
 	return nil
 }
 
+func (db *SQLFollowingDatabase) UpdateFollowing(ctx context.Context, f *activitypub.Following) error {
+
+	q := fmt.Sprintf("UPDATE %s SET account_id=?, following_address=?, activity_id=?, state=?, created=?, lastmodified=? WHERE id = ?", SQL_FOLLOWING_TABLE_NAME)
+
+	_, err := db.database.ExecContext(ctx, q, f.AccountId, f.FollowingAddress, f.ActivityId, f.State, f.Created, f.LastModified, f.Id)
+
+	if err != nil {
+		return fmt.Errorf("Failed to update following, %w", err)
+	}
+
+	return nil
+}
+
 func (db *SQLFollowingDatabase) RemoveFollowing(ctx context.Context, f *activitypub.Following) error {
 
 	q := fmt.Sprintf("DELETE FROM %s WHERE id = ?", SQL_FOLLOWING_TABLE_NAME)
@@ -200,6 +231,55 @@ func (db *SQLFollowingDatabase) GetFollowingForAccount(ctx context.Context, acco
 	return nil
 }
 
+func (db *SQLFollowingDatabase) GetAccountIdsForFollowingAddress(ctx context.Context, following_address string, cb GetAccountIdsCallbackFunc) error {
+
+	pg_callback := func(pg_rsp pg_sql.PaginatedResponse) error {
+
+		rows := pg_rsp.Rows()
+
+		for rows.Next() {
+
+			var account_id int64
+
+			err := rows.Scan(&account_id)
+
+			if err != nil {
+				return fmt.Errorf("Failed to scan row, %w", err)
+			}
+
+			err = cb(ctx, account_id)
+
+			if err != nil {
+				return fmt.Errorf("Failed to execute callback for account %d, %w", account_id, err)
+			}
+		}
+
+		err := rows.Close()
+
+		if err != nil {
+			return fmt.Errorf("Failed to iterate through database rows, %w", err)
+		}
+
+		return nil
+	}
+
+	pg_opts, err := countable.NewCountableOptions()
+
+	if err != nil {
+		return fmt.Errorf("Failed to create pagination options, %w", err)
+	}
+
+	q := fmt.Sprintf("SELECT account_id FROM %s WHERE following_address = ?", SQL_FOLLOWING_TABLE_NAME)
+
+	err = pg_sql.QueryPaginatedAll(db.database, pg_opts, pg_callback, q, following_address)
+
+	if err != nil {
+		return fmt.Errorf("Failed to execute paginated query, %w", err)
+	}
+
+	return nil
+}
+
 func (db *SQLFollowingDatabase) Close(ctx context.Context) error {
 	return db.database.Close()
 }
@@ -18,6 +18,7 @@ type LikesDatabase interface {
 	GetLikeIdsForDateRange(context.Context, int64, int64, GetLikeIdsCallbackFunc) error
 	GetLikesForPost(context.Context, int64, GetLikesCallbackFunc) error
 	GetLikeWithPostIdAndActor(context.Context, int64, string) (*activitypub.Like, error)
+	GetLikeWithPostIdActorAndEmoji(context.Context, int64, string, string) (*activitypub.Like, error)
 	GetLikeWithId(context.Context, int64) (*activitypub.Like, error)
 	AddLike(context.Context, *activitypub.Like) error
 	RemoveLike(context.Context, *activitypub.Like) error
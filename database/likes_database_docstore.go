@@ -96,6 +96,37 @@ func (db *DocstoreLikesDatabase) GetLikeWithPostIdAndActor(ctx context.Context,
 	return db.getLike(ctx, q)
 }
 
+func (db *DocstoreLikesDatabase) GetLikeWithPostIdActorAndEmoji(ctx context.Context, post_id int64, actor string, emoji string) (*activitypub.Like, error) {
+
+	q := db.collection.Query()
+	q = q.Where("PostId", "=", post_id)
+	q = q.Where("Actor", "=", actor)
+
+	iter := q.Get(ctx)
+	defer iter.Stop()
+
+	// Filter on emoji here rather than in the query since plain likes may not
+	// have an "Emoji" property at all
+
+	for {
+
+		var l activitypub.Like
+		err := iter.Next(ctx, &l)
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("Failed to interate, %w", err)
+		}
+
+		if l.Emoji == emoji {
+			return &l, nil
+		}
+	}
+
+	return nil, activitypub.ErrNotFound
+}
+
 func (db *DocstoreLikesDatabase) GetLikesForPost(ctx context.Context, post_id int64, cb GetLikesCallbackFunc) error {
 
 	q := db.collection.Query()
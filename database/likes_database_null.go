@@ -36,6 +36,10 @@ func (db *NullLikesDatabase) GetLikeWithPostIdAndActor(ctx context.Context, id i
 	return nil, activitypub.ErrNotFound
 }
 
+func (db *NullLikesDatabase) GetLikeWithPostIdActorAndEmoji(ctx context.Context, id int64, actor string, emoji string) (*activitypub.Like, error) {
+	return nil, activitypub.ErrNotFound
+}
+
 func (db *NullLikesDatabase) GetLikesForPost(ctx context.Context, post_id int64, cb GetLikesCallbackFunc) error {
 	return nil
 }
@@ -64,8 +64,6 @@ func (db *SQLLikesDatabase) GetLikeIdsForDateRange(ctx context.Context, start in
 			if err != nil {
 				return fmt.Errorf("Failed to execute following callback for like %d, %w", id, err)
 			}
-
-			return nil
 		}
 
 		err := rows.Close()
@@ -106,80 +104,29 @@ func (db *SQLLikesDatabase) GetLikeWithPostIdAndActor(ctx context.Context, post_
 	return db.getLike(ctx, where, post_id, actor)
 }
 
-func (db *SQLLikesDatabase) GetLikesForPostIdAndActor(ctx context.Context, post_id int64, actor string, cb GetLikesCallbackFunc) error {
-
-	pg_callback := func(pg_rsp pg_sql.PaginatedResponse) error {
-
-		rows := pg_rsp.Rows()
-
-		for rows.Next() {
-
-			var id int64
-			var account_id int64
-			var post_id int64
-			var actor string
-			var created int64
-
-			err := rows.Scan(&id, &account_id, &post_id, &actor, &created)
-
-			switch {
-			case err == sql.ErrNoRows:
-				return nil
-			case err != nil:
-				return err
-			default:
-
-				b := &activitypub.Like{
-					Id:        id,
-					AccountId: account_id,
-					PostId:    post_id,
-					Actor:     actor,
-					Created:   created,
-				}
-
-				err = cb(ctx, b)
-
-				if err != nil {
-					return fmt.Errorf("Failed to execute callback for '%b', %w", b.Id, err)
-				}
-
-			}
-
-			return nil
-		}
-
-		err := rows.Close()
-
-		if err != nil {
-			return fmt.Errorf("Failed to iterate through database rows, %w", err)
-		}
-
-		return nil
-	}
-
-	pg_opts, err := countable.NewCountableOptions()
-
-	if err != nil {
-		return fmt.Errorf("Failed to create pagination options, %w", err)
-	}
+func (db *SQLLikesDatabase) GetLikeWithPostIdActorAndEmoji(ctx context.Context, post_id int64, actor string, emoji string) (*activitypub.Like, error) {
 
-	q := fmt.Sprintf("SELECT id, account_id, post_id, actor, created FROM %s WHERE post_id = ? AND actor = ?", SQL_LIKES_TABLE_NAME)
+	where := "post_id = ? AND actor = ? AND emoji = ?"
+	return db.getLike(ctx, where, post_id, actor, emoji)
+}
 
-	err = pg_sql.QueryPaginatedAll(db.database, pg_opts, pg_callback, q, post_id, actor)
+func (db *SQLLikesDatabase) GetLikesForPost(ctx context.Context, post_id int64, cb GetLikesCallbackFunc) error {
 
-	if err != nil {
-		return fmt.Errorf("Failed to execute paginated query, %w", err)
-	}
+	where := "post_id = ?"
+	return db.getLikes(ctx, cb, where, post_id)
+}
 
-	return nil
+func (db *SQLLikesDatabase) GetLikesForPostIdAndActor(ctx context.Context, post_id int64, actor string, cb GetLikesCallbackFunc) error {
 
+	where := "post_id = ? AND actor = ?"
+	return db.getLikes(ctx, cb, where, post_id, actor)
 }
 
 func (db *SQLLikesDatabase) AddLike(ctx context.Context, b *activitypub.Like) error {
 
-	q := fmt.Sprintf("INSERT INTO %s (id, account_id, psot_id, actor, created) VALUES (?, ?, ?, ?, ?)", SQL_LIKES_TABLE_NAME)
+	q := fmt.Sprintf("INSERT INTO %s (id, account_id, post_id, actor, emoji, emoji_url, created) VALUES (?, ?, ?, ?, ?, ?, ?)", SQL_LIKES_TABLE_NAME)
 
-	_, err := db.database.ExecContext(ctx, q, b.Id, b.AccountId, b.PostId, b.Actor, b.Created)
+	_, err := db.database.ExecContext(ctx, q, b.Id, b.AccountId, b.PostId, b.Actor, b.Emoji, b.EmojiURL, b.Created)
 
 	if err != nil {
 		return fmt.Errorf("Failed to add like, %w", err)
@@ -211,13 +158,15 @@ func (db *SQLLikesDatabase) getLike(ctx context.Context, where string, args ...i
 	var account_id int64
 	var post_id int64
 	var actor string
+	var emoji string
+	var emoji_url string
 	var created int64
 
-	q := fmt.Sprintf("SELECT id, account_id, post_id, actor, created FROM %s WHERE %s", SQL_LIKES_TABLE_NAME, where)
+	q := fmt.Sprintf("SELECT id, account_id, post_id, actor, emoji, emoji_url, created FROM %s WHERE %s", SQL_LIKES_TABLE_NAME, where)
 
 	row := db.database.QueryRowContext(ctx, q, args...)
 
-	err := row.Scan(&id, &account_id, &post_id, &actor, &created)
+	err := row.Scan(&id, &account_id, &post_id, &actor, &emoji, &emoji_url, &created)
 
 	switch {
 	case err == sql.ErrNoRows:
@@ -233,8 +182,75 @@ func (db *SQLLikesDatabase) getLike(ctx context.Context, where string, args ...i
 		AccountId: account_id,
 		PostId:    post_id,
 		Actor:     actor,
+		Emoji:     emoji,
+		EmojiURL:  emoji_url,
 		Created:   created,
 	}
 
 	return b, nil
 }
+
+func (db *SQLLikesDatabase) getLikes(ctx context.Context, cb GetLikesCallbackFunc, where string, args ...interface{}) error {
+
+	pg_callback := func(pg_rsp pg_sql.PaginatedResponse) error {
+
+		rows := pg_rsp.Rows()
+
+		for rows.Next() {
+
+			var id int64
+			var account_id int64
+			var post_id int64
+			var actor string
+			var emoji string
+			var emoji_url string
+			var created int64
+
+			err := rows.Scan(&id, &account_id, &post_id, &actor, &emoji, &emoji_url, &created)
+
+			if err != nil {
+				return fmt.Errorf("Failed to query database, %w", err)
+			}
+
+			b := &activitypub.Like{
+				Id:        id,
+				AccountId: account_id,
+				PostId:    post_id,
+				Actor:     actor,
+				Emoji:     emoji,
+				EmojiURL:  emoji_url,
+				Created:   created,
+			}
+
+			err = cb(ctx, b)
+
+			if err != nil {
+				return fmt.Errorf("Failed to execute callback for like %d, %w", b.Id, err)
+			}
+		}
+
+		err := rows.Close()
+
+		if err != nil {
+			return fmt.Errorf("Failed to iterate through database rows, %w", err)
+		}
+
+		return nil
+	}
+
+	pg_opts, err := countable.NewCountableOptions()
+
+	if err != nil {
+		return fmt.Errorf("Failed to create pagination options, %w", err)
+	}
+
+	q := fmt.Sprintf("SELECT id, account_id, post_id, actor, emoji, emoji_url, created FROM %s WHERE %s", SQL_LIKES_TABLE_NAME, where)
+
+	err = pg_sql.QueryPaginatedAll(db.database, pg_opts, pg_callback, q, args...)
+
+	if err != nil {
+		return fmt.Errorf("Failed to execute paginated query, %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/aaronland/go-roster"
+	"github.com/sfomuseum/go-activitypub"
+)
+
+type GetMediaCallbackFunc func(context.Context, *activitypub.Media) error
+
+// MediaDatabase defines an interface for storing the media files (attachments) associated with posts.
+type MediaDatabase interface {
+	// GetMediaWithId returns the `activitypub.Media` instance with a specific unique ID.
+	GetMediaWithId(context.Context, int64) (*activitypub.Media, error)
+	// GetMediaForPost iterates through all the `activitypub.Media` instances for a specific post, in the order they were added.
+	GetMediaForPost(context.Context, int64, GetMediaCallbackFunc) error
+	// AddMedia adds a new `activitypub.Media` instance.
+	AddMedia(context.Context, *activitypub.Media) error
+	// RemoveMedia removes a specific `activitypub.Media` instance.
+	RemoveMedia(context.Context, *activitypub.Media) error
+	// Close performs any final operations to terminate the underlying database connection.
+	Close(context.Context) error
+}
+
+var media_database_roster roster.Roster
+
+// MediaDatabaseInitializationFunc is a function defined by individual media_database package and used to create
+// an instance of that media_database
+type MediaDatabaseInitializationFunc func(ctx context.Context, uri string) (MediaDatabase, error)
+
+// RegisterMediaDatabase registers 'scheme' as a key pointing to 'init_func' in an internal lookup table
+// used to create new `MediaDatabase` instances by the `NewMediaDatabase` method.
+func RegisterMediaDatabase(ctx context.Context, scheme string, init_func MediaDatabaseInitializationFunc) error {
+
+	err := ensureMediaDatabaseRoster()
+
+	if err != nil {
+		return err
+	}
+
+	return media_database_roster.Register(ctx, scheme, init_func)
+}
+
+func ensureMediaDatabaseRoster() error {
+
+	if media_database_roster == nil {
+
+		r, err := roster.NewDefaultRoster()
+
+		if err != nil {
+			return err
+		}
+
+		media_database_roster = r
+	}
+
+	return nil
+}
+
+// NewMediaDatabase returns a new `MediaDatabase` instance configured by 'uri'. The value of 'uri' is parsed
+// as a `url.URL` and its scheme is used as the key for a corresponding `MediaDatabaseInitializationFunc`
+// function used to instantiate the new `MediaDatabase`. It is assumed that the scheme (and initialization
+// function) have been registered by the `RegisterMediaDatabase` method.
+func NewMediaDatabase(ctx context.Context, uri string) (MediaDatabase, error) {
+
+	u, err := url.Parse(uri)
+
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := u.Scheme
+
+	i, err := media_database_roster.Driver(ctx, scheme)
+
+	if err != nil {
+		return nil, err
+	}
+
+	init_func := i.(MediaDatabaseInitializationFunc)
+	return init_func(ctx, uri)
+}
+
+// Schemes returns the list of schemes that have been registered.
+func MediaDatabaseSchemes() []string {
+
+	ctx := context.Background()
+	schemes := []string{}
+
+	err := ensureMediaDatabaseRoster()
+
+	if err != nil {
+		return schemes
+	}
+
+	for _, dr := range media_database_roster.Drivers(ctx) {
+		scheme := fmt.Sprintf("%s://", strings.ToLower(dr))
+		schemes = append(schemes, scheme)
+	}
+
+	sort.Strings(schemes)
+	return schemes
+}
@@ -0,0 +1,130 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	aa_docstore "github.com/aaronland/gocloud/docstore"
+	"github.com/sfomuseum/go-activitypub"
+	gc_docstore "gocloud.dev/docstore"
+)
+
+type DocstoreMediaDatabase struct {
+	MediaDatabase
+	collection *gc_docstore.Collection
+}
+
+func init() {
+
+	ctx := context.Background()
+
+	err := RegisterMediaDatabase(ctx, "awsdynamodb", NewDocstoreMediaDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+
+	for _, scheme := range gc_docstore.DefaultURLMux().CollectionSchemes() {
+		err := RegisterMediaDatabase(ctx, scheme, NewDocstoreMediaDatabase)
+
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+func NewDocstoreMediaDatabase(ctx context.Context, uri string) (MediaDatabase, error) {
+
+	col, err := aa_docstore.OpenCollection(ctx, uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open collection, %w", err)
+	}
+
+	db := &DocstoreMediaDatabase{
+		collection: col,
+	}
+
+	return db, nil
+}
+
+func (db *DocstoreMediaDatabase) GetMediaWithId(ctx context.Context, id int64) (*activitypub.Media, error) {
+
+	q := db.collection.Query()
+	q = q.Where("Id", "=", id)
+
+	iter := q.Get(ctx)
+	defer iter.Stop()
+
+	var m activitypub.Media
+	err := iter.Next(ctx, &m)
+
+	if err == io.EOF {
+		return nil, activitypub.ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("Failed to interate, %w", err)
+	} else {
+		return &m, nil
+	}
+}
+
+func (db *DocstoreMediaDatabase) GetMediaForPost(ctx context.Context, post_id int64, cb GetMediaCallbackFunc) error {
+
+	q := db.collection.Query()
+	q = q.Where("PostId", "=", post_id)
+
+	iter := q.Get(ctx)
+	defer iter.Stop()
+
+	// Media are collected and sorted before being handed to 'cb' because
+	// not all docstore drivers support ordering query results. Media IDs are
+	// (snowflake) IDs which increase over time so sorting by ID preserves the
+	// order in which attachments were added to a post.
+
+	media := make([]*activitypub.Media, 0)
+
+	for {
+
+		var m activitypub.Media
+		err := iter.Next(ctx, &m)
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("Failed to interate, %w", err)
+		} else {
+			media = append(media, &m)
+		}
+	}
+
+	sort.Slice(media, func(i, j int) bool {
+		return media[i].Id < media[j].Id
+	})
+
+	for _, m := range media {
+
+		err := cb(ctx, m)
+
+		if err != nil {
+			return fmt.Errorf("Failed to execute media callback for '%d', %w", m.Id, err)
+		}
+	}
+
+	return nil
+}
+
+func (db *DocstoreMediaDatabase) AddMedia(ctx context.Context, m *activitypub.Media) error {
+
+	return db.collection.Put(ctx, m)
+}
+
+func (db *DocstoreMediaDatabase) RemoveMedia(ctx context.Context, m *activitypub.Media) error {
+
+	return db.collection.Delete(ctx, m)
+}
+
+func (db *DocstoreMediaDatabase) Close(ctx context.Context) error {
+	return db.collection.Close()
+}
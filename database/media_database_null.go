@@ -0,0 +1,46 @@
+package database
+
+import (
+	"context"
+
+	"github.com/sfomuseum/go-activitypub"
+)
+
+type NullMediaDatabase struct {
+	MediaDatabase
+}
+
+func init() {
+
+	ctx := context.Background()
+	err := RegisterMediaDatabase(ctx, "null", NewNullMediaDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func NewNullMediaDatabase(ctx context.Context, uri string) (MediaDatabase, error) {
+	db := &NullMediaDatabase{}
+	return db, nil
+}
+
+func (db *NullMediaDatabase) GetMediaWithId(ctx context.Context, id int64) (*activitypub.Media, error) {
+	return nil, activitypub.ErrNotFound
+}
+
+func (db *NullMediaDatabase) GetMediaForPost(ctx context.Context, post_id int64, cb GetMediaCallbackFunc) error {
+	return nil
+}
+
+func (db *NullMediaDatabase) AddMedia(ctx context.Context, m *activitypub.Media) error {
+	return nil
+}
+
+func (db *NullMediaDatabase) RemoveMedia(ctx context.Context, m *activitypub.Media) error {
+	return nil
+}
+
+func (db *NullMediaDatabase) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,199 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	pg_sql "github.com/aaronland/go-pagination-sql"
+	"github.com/aaronland/go-pagination/countable"
+	"github.com/sfomuseum/go-activitypub"
+	sfom_sql "github.com/sfomuseum/go-database/sql"
+)
+
+const SQL_MEDIA_TABLE_NAME string = "media"
+
+type SQLMediaDatabase struct {
+	MediaDatabase
+	database *sql.DB
+}
+
+func init() {
+
+	ctx := context.Background()
+	err := RegisterMediaDatabase(ctx, "sql", NewSQLMediaDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func NewSQLMediaDatabase(ctx context.Context, uri string) (MediaDatabase, error) {
+
+	conn, err := sfom_sql.OpenWithURI(ctx, uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open database connection, %w", err)
+	}
+
+	db := &SQLMediaDatabase{
+		database: conn,
+	}
+
+	return db, nil
+}
+
+func (db *SQLMediaDatabase) GetMediaWithId(ctx context.Context, id int64) (*activitypub.Media, error) {
+
+	var post_id int64
+	var account_id int64
+	var uri string
+	var media_type string
+	var description string
+	var width int
+	var height int
+	var focal_x float64
+	var focal_y float64
+	var blurhash string
+	var created int64
+
+	q := fmt.Sprintf("SELECT post_id, account_id, uri, media_type, description, width, height, focal_point_x, focal_point_y, blurhash, created FROM %s WHERE id = ?", SQL_MEDIA_TABLE_NAME)
+
+	row := db.database.QueryRowContext(ctx, q, id)
+
+	err := row.Scan(&post_id, &account_id, &uri, &media_type, &description, &width, &height, &focal_x, &focal_y, &blurhash, &created)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, activitypub.ErrNotFound
+	case err != nil:
+		return nil, fmt.Errorf("Failed to query database, %w", err)
+	default:
+		//
+	}
+
+	m := &activitypub.Media{
+		Id:          id,
+		PostId:      post_id,
+		AccountId:   account_id,
+		URI:         uri,
+		MediaType:   media_type,
+		Description: description,
+		Width:       width,
+		Height:      height,
+		FocalPointX: focal_x,
+		FocalPointY: focal_y,
+		Blurhash:    blurhash,
+		Created:     created,
+	}
+
+	return m, nil
+}
+
+func (db *SQLMediaDatabase) GetMediaForPost(ctx context.Context, post_id int64, cb GetMediaCallbackFunc) error {
+
+	pg_callback := func(pg_rsp pg_sql.PaginatedResponse) error {
+
+		rows := pg_rsp.Rows()
+
+		for rows.Next() {
+
+			var id int64
+			var post_id int64
+			var account_id int64
+			var uri string
+			var media_type string
+			var description string
+			var width int
+			var height int
+			var focal_x float64
+			var focal_y float64
+			var blurhash string
+			var created int64
+
+			err := rows.Scan(&id, &post_id, &account_id, &uri, &media_type, &description, &width, &height, &focal_x, &focal_y, &blurhash, &created)
+
+			if err != nil {
+				return fmt.Errorf("Failed to query database, %w", err)
+			}
+
+			m := &activitypub.Media{
+				Id:          id,
+				PostId:      post_id,
+				AccountId:   account_id,
+				URI:         uri,
+				MediaType:   media_type,
+				Description: description,
+				Width:       width,
+				Height:      height,
+				FocalPointX: focal_x,
+				FocalPointY: focal_y,
+				Blurhash:    blurhash,
+				Created:     created,
+			}
+
+			err = cb(ctx, m)
+
+			if err != nil {
+				return fmt.Errorf("Failed to execute media callback for %d, %w", id, err)
+			}
+		}
+
+		err := rows.Close()
+
+		if err != nil {
+			return fmt.Errorf("Failed to iterate through database rows, %w", err)
+		}
+
+		return nil
+	}
+
+	pg_opts, err := countable.NewCountableOptions()
+
+	if err != nil {
+		return fmt.Errorf("Failed to create pagination options, %w", err)
+	}
+
+	// Media IDs are (snowflake) IDs which increase over time so ordering by ID
+	// preserves the order in which attachments were added to a post.
+
+	q := fmt.Sprintf("SELECT id, post_id, account_id, uri, media_type, description, width, height, focal_point_x, focal_point_y, blurhash, created FROM %s WHERE post_id = ? ORDER BY id ASC", SQL_MEDIA_TABLE_NAME)
+
+	err = pg_sql.QueryPaginatedAll(db.database, pg_opts, pg_callback, q, post_id)
+
+	if err != nil {
+		return fmt.Errorf("Failed to execute paginated query, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLMediaDatabase) AddMedia(ctx context.Context, m *activitypub.Media) error {
+
+	q := fmt.Sprintf("INSERT INTO %s (id, post_id, account_id, uri, media_type, description, width, height, focal_point_x, focal_point_y, blurhash, created) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", SQL_MEDIA_TABLE_NAME)
+
+	_, err := db.database.ExecContext(ctx, q, m.Id, m.PostId, m.AccountId, m.URI, m.MediaType, m.Description, m.Width, m.Height, m.FocalPointX, m.FocalPointY, m.Blurhash, m.Created)
+
+	if err != nil {
+		return fmt.Errorf("Failed to add media, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLMediaDatabase) RemoveMedia(ctx context.Context, m *activitypub.Media) error {
+
+	q := fmt.Sprintf("DELETE FROM %s WHERE id = ?", SQL_MEDIA_TABLE_NAME)
+
+	_, err := db.database.ExecContext(ctx, q, m.Id)
+
+	if err != nil {
+		return fmt.Errorf("Failed to remove media, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLMediaDatabase) Close(ctx context.Context) error {
+	return db.database.Close()
+}
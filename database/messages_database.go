@@ -18,6 +18,7 @@ type MessagesDatabase interface {
 	GetMessageIdsForDateRange(context.Context, int64, int64, GetMessageIdsCallbackFunc) error
 	GetMessagesForAccount(context.Context, int64, GetMessagesCallbackFunc) error
 	GetMessagesForAccountAndAuthor(context.Context, int64, string, GetMessagesCallbackFunc) error
+	GetMessagesForNote(context.Context, int64, GetMessagesCallbackFunc) error
 	GetMessageWithId(context.Context, int64) (*activitypub.Message, error)
 	GetMessageWithAccountAndNoteIds(context.Context, int64, int64) (*activitypub.Message, error)
 	AddMessage(context.Context, *activitypub.Message) error
@@ -150,6 +150,14 @@ func (db *DocstoreMessagesDatabase) GetMessagesForAccountAndAuthor(ctx context.C
 	return db.getMessagesWithCallback(ctx, q, callback_func)
 }
 
+func (db *DocstoreMessagesDatabase) GetMessagesForNote(ctx context.Context, note_id int64, callback_func GetMessagesCallbackFunc) error {
+
+	q := db.collection.Query()
+	q = q.Where("NoteId", "=", note_id)
+
+	return db.getMessagesWithCallback(ctx, q, callback_func)
+}
+
 func (db *DocstoreMessagesDatabase) getMessagesWithCallback(ctx context.Context, q *gc_docstore.Query, callback_func GetMessagesCallbackFunc) error {
 
 	iter := q.Get(ctx)
@@ -59,6 +59,10 @@ func (db *NullMessagesDatabase) GetMessagesForAccountAndAuthor(ctx context.Conte
 	return nil
 }
 
+func (db *NullMessagesDatabase) GetMessagesForNote(ctx context.Context, note_id int64, callback_func GetMessagesCallbackFunc) error {
+	return nil
+}
+
 func (db *NullMessagesDatabase) Close(ctx context.Context) error {
 	return nil
 }
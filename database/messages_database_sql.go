@@ -200,6 +200,16 @@ func (db *SQLMessagesDatabase) GetMessagesForAccountAndAuthor(ctx context.Contex
 	return db.getMessagesWithCallback(ctx, where, args, callback_func)
 }
 
+func (db *SQLMessagesDatabase) GetMessagesForNote(ctx context.Context, note_id int64, callback_func GetMessagesCallbackFunc) error {
+
+	where := "note_id = ?"
+	args := []interface{}{
+		note_id,
+	}
+
+	return db.getMessagesWithCallback(ctx, where, args, callback_func)
+}
+
 func (db *SQLMessagesDatabase) getMessagesWithCallback(ctx context.Context, where string, args []interface{}, callback_func GetMessagesCallbackFunc) error {
 
 	pg_callback := func(pg_rsp pg_sql.PaginatedResponse) error {
@@ -0,0 +1,108 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/aaronland/go-roster"
+	"github.com/sfomuseum/go-activitypub"
+)
+
+type GetPostRevisionsCallbackFunc func(context.Context, *activitypub.PostRevision) error
+
+// PostRevisionsDatabase defines an interface for storing the prior versions of posts that have been edited.
+type PostRevisionsDatabase interface {
+	// GetPostRevisionWithId returns the `activitypub.PostRevision` instance with a specific unique ID.
+	GetPostRevisionWithId(context.Context, int64) (*activitypub.PostRevision, error)
+	// GetPostRevisionsForPost iterates through all the `activitypub.PostRevision` instances for a specific post, oldest first.
+	GetPostRevisionsForPost(context.Context, int64, GetPostRevisionsCallbackFunc) error
+	// AddPostRevision adds a new `activitypub.PostRevision` instance.
+	AddPostRevision(context.Context, *activitypub.PostRevision) error
+	// RemovePostRevision removes a specific `activitypub.PostRevision` instance.
+	RemovePostRevision(context.Context, *activitypub.PostRevision) error
+	// Close performs any final operations to terminate the underlying database connection.
+	Close(context.Context) error
+}
+
+var post_revisions_database_roster roster.Roster
+
+// PostRevisionsDatabaseInitializationFunc is a function defined by individual post_revisions_database package and used to create
+// an instance of that post_revisions_database
+type PostRevisionsDatabaseInitializationFunc func(ctx context.Context, uri string) (PostRevisionsDatabase, error)
+
+// RegisterPostRevisionsDatabase registers 'scheme' as a key pointing to 'init_func' in an internal lookup table
+// used to create new `PostRevisionsDatabase` instances by the `NewPostRevisionsDatabase` method.
+func RegisterPostRevisionsDatabase(ctx context.Context, scheme string, init_func PostRevisionsDatabaseInitializationFunc) error {
+
+	err := ensurePostRevisionsDatabaseRoster()
+
+	if err != nil {
+		return err
+	}
+
+	return post_revisions_database_roster.Register(ctx, scheme, init_func)
+}
+
+func ensurePostRevisionsDatabaseRoster() error {
+
+	if post_revisions_database_roster == nil {
+
+		r, err := roster.NewDefaultRoster()
+
+		if err != nil {
+			return err
+		}
+
+		post_revisions_database_roster = r
+	}
+
+	return nil
+}
+
+// NewPostRevisionsDatabase returns a new `PostRevisionsDatabase` instance configured by 'uri'. The value of 'uri' is parsed
+// as a `url.URL` and its scheme is used as the key for a corresponding `PostRevisionsDatabaseInitializationFunc`
+// function used to instantiate the new `PostRevisionsDatabase`. It is assumed that the scheme (and initialization
+// function) have been registered by the `RegisterPostRevisionsDatabase` method.
+func NewPostRevisionsDatabase(ctx context.Context, uri string) (PostRevisionsDatabase, error) {
+
+	u, err := url.Parse(uri)
+
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := u.Scheme
+
+	i, err := post_revisions_database_roster.Driver(ctx, scheme)
+
+	if err != nil {
+		return nil, err
+	}
+
+	init_func := i.(PostRevisionsDatabaseInitializationFunc)
+	return init_func(ctx, uri)
+}
+
+// Schemes returns the list of schemes that have been registered.
+func PostRevisionsDatabaseSchemes() []string {
+
+	ctx := context.Background()
+	schemes := []string{}
+
+	err := ensurePostRevisionsDatabaseRoster()
+
+	if err != nil {
+		return schemes
+	}
+
+	for _, dr := range post_revisions_database_roster.Drivers(ctx) {
+		scheme := fmt.Sprintf("%s://", strings.ToLower(dr))
+		schemes = append(schemes, scheme)
+	}
+
+	sort.Strings(schemes)
+	return schemes
+}
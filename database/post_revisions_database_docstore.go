@@ -0,0 +1,128 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	aa_docstore "github.com/aaronland/gocloud/docstore"
+	"github.com/sfomuseum/go-activitypub"
+	gc_docstore "gocloud.dev/docstore"
+)
+
+type DocstorePostRevisionsDatabase struct {
+	PostRevisionsDatabase
+	collection *gc_docstore.Collection
+}
+
+func init() {
+
+	ctx := context.Background()
+
+	err := RegisterPostRevisionsDatabase(ctx, "awsdynamodb", NewDocstorePostRevisionsDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+
+	for _, scheme := range gc_docstore.DefaultURLMux().CollectionSchemes() {
+		err := RegisterPostRevisionsDatabase(ctx, scheme, NewDocstorePostRevisionsDatabase)
+
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+func NewDocstorePostRevisionsDatabase(ctx context.Context, uri string) (PostRevisionsDatabase, error) {
+
+	col, err := aa_docstore.OpenCollection(ctx, uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open collection, %w", err)
+	}
+
+	db := &DocstorePostRevisionsDatabase{
+		collection: col,
+	}
+
+	return db, nil
+}
+
+func (db *DocstorePostRevisionsDatabase) GetPostRevisionWithId(ctx context.Context, id int64) (*activitypub.PostRevision, error) {
+
+	q := db.collection.Query()
+	q = q.Where("Id", "=", id)
+
+	iter := q.Get(ctx)
+	defer iter.Stop()
+
+	var r activitypub.PostRevision
+	err := iter.Next(ctx, &r)
+
+	if err == io.EOF {
+		return nil, activitypub.ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("Failed to interate, %w", err)
+	} else {
+		return &r, nil
+	}
+}
+
+func (db *DocstorePostRevisionsDatabase) GetPostRevisionsForPost(ctx context.Context, post_id int64, cb GetPostRevisionsCallbackFunc) error {
+
+	q := db.collection.Query()
+	q = q.Where("PostId", "=", post_id)
+
+	iter := q.Get(ctx)
+	defer iter.Stop()
+
+	// Revisions are collected and sorted before being handed to 'cb' because
+	// not all docstore drivers support ordering query results
+
+	revisions := make([]*activitypub.PostRevision, 0)
+
+	for {
+
+		var r activitypub.PostRevision
+		err := iter.Next(ctx, &r)
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("Failed to interate, %w", err)
+		} else {
+			revisions = append(revisions, &r)
+		}
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Created < revisions[j].Created
+	})
+
+	for _, r := range revisions {
+
+		err := cb(ctx, r)
+
+		if err != nil {
+			return fmt.Errorf("Failed to execute post revisions callback for '%d', %w", r.Id, err)
+		}
+	}
+
+	return nil
+}
+
+func (db *DocstorePostRevisionsDatabase) AddPostRevision(ctx context.Context, r *activitypub.PostRevision) error {
+
+	return db.collection.Put(ctx, r)
+}
+
+func (db *DocstorePostRevisionsDatabase) RemovePostRevision(ctx context.Context, r *activitypub.PostRevision) error {
+
+	return db.collection.Delete(ctx, r)
+}
+
+func (db *DocstorePostRevisionsDatabase) Close(ctx context.Context) error {
+	return db.collection.Close()
+}
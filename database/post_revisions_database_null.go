@@ -0,0 +1,46 @@
+package database
+
+import (
+	"context"
+
+	"github.com/sfomuseum/go-activitypub"
+)
+
+type NullPostRevisionsDatabase struct {
+	PostRevisionsDatabase
+}
+
+func init() {
+
+	ctx := context.Background()
+	err := RegisterPostRevisionsDatabase(ctx, "null", NewNullPostRevisionsDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func NewNullPostRevisionsDatabase(ctx context.Context, uri string) (PostRevisionsDatabase, error) {
+	db := &NullPostRevisionsDatabase{}
+	return db, nil
+}
+
+func (db *NullPostRevisionsDatabase) GetPostRevisionWithId(ctx context.Context, id int64) (*activitypub.PostRevision, error) {
+	return nil, activitypub.ErrNotFound
+}
+
+func (db *NullPostRevisionsDatabase) GetPostRevisionsForPost(ctx context.Context, post_id int64, cb GetPostRevisionsCallbackFunc) error {
+	return nil
+}
+
+func (db *NullPostRevisionsDatabase) AddPostRevision(ctx context.Context, r *activitypub.PostRevision) error {
+	return nil
+}
+
+func (db *NullPostRevisionsDatabase) RemovePostRevision(ctx context.Context, r *activitypub.PostRevision) error {
+	return nil
+}
+
+func (db *NullPostRevisionsDatabase) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,176 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	pg_sql "github.com/aaronland/go-pagination-sql"
+	"github.com/aaronland/go-pagination/countable"
+	"github.com/sfomuseum/go-activitypub"
+	sfom_sql "github.com/sfomuseum/go-database/sql"
+)
+
+const SQL_POST_REVISIONS_TABLE_NAME string = "post_revisions"
+
+type SQLPostRevisionsDatabase struct {
+	PostRevisionsDatabase
+	database *sql.DB
+}
+
+func init() {
+
+	ctx := context.Background()
+	err := RegisterPostRevisionsDatabase(ctx, "sql", NewSQLPostRevisionsDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func NewSQLPostRevisionsDatabase(ctx context.Context, uri string) (PostRevisionsDatabase, error) {
+
+	conn, err := sfom_sql.OpenWithURI(ctx, uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open database connection, %w", err)
+	}
+
+	db := &SQLPostRevisionsDatabase{
+		database: conn,
+	}
+
+	return db, nil
+}
+
+func (db *SQLPostRevisionsDatabase) GetPostRevisionWithId(ctx context.Context, id int64) (*activitypub.PostRevision, error) {
+
+	var post_id int64
+	var account_id int64
+	var body string
+	var in_reply_to string
+	var lastmod int64
+	var created int64
+
+	q := fmt.Sprintf("SELECT post_id, account_id, body, in_reply_to, lastmodified, created FROM %s WHERE id = ?", SQL_POST_REVISIONS_TABLE_NAME)
+
+	row := db.database.QueryRowContext(ctx, q, id)
+
+	err := row.Scan(&post_id, &account_id, &body, &in_reply_to, &lastmod, &created)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, activitypub.ErrNotFound
+	case err != nil:
+		return nil, fmt.Errorf("Failed to query database, %w", err)
+	default:
+		//
+	}
+
+	r := &activitypub.PostRevision{
+		Id:           id,
+		PostId:       post_id,
+		AccountId:    account_id,
+		Body:         body,
+		InReplyTo:    in_reply_to,
+		LastModified: lastmod,
+		Created:      created,
+	}
+
+	return r, nil
+}
+
+func (db *SQLPostRevisionsDatabase) GetPostRevisionsForPost(ctx context.Context, post_id int64, cb GetPostRevisionsCallbackFunc) error {
+
+	pg_callback := func(pg_rsp pg_sql.PaginatedResponse) error {
+
+		rows := pg_rsp.Rows()
+
+		for rows.Next() {
+
+			var id int64
+			var post_id int64
+			var account_id int64
+			var body string
+			var in_reply_to string
+			var lastmod int64
+			var created int64
+
+			err := rows.Scan(&id, &post_id, &account_id, &body, &in_reply_to, &lastmod, &created)
+
+			if err != nil {
+				return fmt.Errorf("Failed to query database, %w", err)
+			}
+
+			r := &activitypub.PostRevision{
+				Id:           id,
+				PostId:       post_id,
+				AccountId:    account_id,
+				Body:         body,
+				InReplyTo:    in_reply_to,
+				LastModified: lastmod,
+				Created:      created,
+			}
+
+			err = cb(ctx, r)
+
+			if err != nil {
+				return fmt.Errorf("Failed to execute post revisions callback for %d, %w", id, err)
+			}
+		}
+
+		err := rows.Close()
+
+		if err != nil {
+			return fmt.Errorf("Failed to iterate through database rows, %w", err)
+		}
+
+		return nil
+	}
+
+	pg_opts, err := countable.NewCountableOptions()
+
+	if err != nil {
+		return fmt.Errorf("Failed to create pagination options, %w", err)
+	}
+
+	q := fmt.Sprintf("SELECT id, post_id, account_id, body, in_reply_to, lastmodified, created FROM %s WHERE post_id = ? ORDER BY created ASC", SQL_POST_REVISIONS_TABLE_NAME)
+
+	err = pg_sql.QueryPaginatedAll(db.database, pg_opts, pg_callback, q, post_id)
+
+	if err != nil {
+		return fmt.Errorf("Failed to execute paginated query, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLPostRevisionsDatabase) AddPostRevision(ctx context.Context, r *activitypub.PostRevision) error {
+
+	q := fmt.Sprintf("INSERT INTO %s (id, post_id, account_id, body, in_reply_to, lastmodified, created) VALUES (?, ?, ?, ?, ?, ?, ?)", SQL_POST_REVISIONS_TABLE_NAME)
+
+	_, err := db.database.ExecContext(ctx, q, r.Id, r.PostId, r.AccountId, r.Body, r.InReplyTo, r.LastModified, r.Created)
+
+	if err != nil {
+		return fmt.Errorf("Failed to add post revision, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLPostRevisionsDatabase) RemovePostRevision(ctx context.Context, r *activitypub.PostRevision) error {
+
+	q := fmt.Sprintf("DELETE FROM %s WHERE id = ?", SQL_POST_REVISIONS_TABLE_NAME)
+
+	_, err := db.database.ExecContext(ctx, q, r.Id)
+
+	if err != nil {
+		return fmt.Errorf("Failed to remove post revision, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLPostRevisionsDatabase) Close(ctx context.Context) error {
+	return db.database.Close()
+}
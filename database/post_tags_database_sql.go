@@ -11,7 +11,7 @@ import (
 	sfom_sql "github.com/sfomuseum/go-database/sql"
 )
 
-const SQL_POST_TAGS_TABLE_NAME string = "posts_tags"
+const SQL_POST_TAGS_TABLE_NAME string = "post_tags"
 
 type SQLPostTagsDatabase struct {
 	PostTagsDatabase
@@ -63,8 +63,6 @@ func (db *SQLPostTagsDatabase) GetPostTagIdsForDateRange(ctx context.Context, st
 			if err != nil {
 				return fmt.Errorf("Failed to execute following callback for post tag %d, %w", id, err)
 			}
-
-			return nil
 		}
 
 		err := rows.Close()
@@ -102,7 +100,7 @@ func (db *SQLPostTagsDatabase) GetPostTagWithId(ctx context.Context, id int64) (
 	var pt_type string
 	var created int64
 
-	q := fmt.Sprintf("SELECT id, account_id, post_id, href, name, type, created FROM %s", SQL_POST_TAGS_TABLE_NAME)
+	q := fmt.Sprintf("SELECT id, account_id, post_id, href, name, type, created FROM %s WHERE id = ?", SQL_POST_TAGS_TABLE_NAME)
 
 	row := db.database.QueryRowContext(ctx, q, id)
 
@@ -229,8 +227,6 @@ func (db *SQLPostTagsDatabase) getPostTagsWithCallback(ctx context.Context, wher
 			if err != nil {
 				return fmt.Errorf("Failed to execute following callback for post tag %d, %w", t.Id, err)
 			}
-
-			return nil
 		}
 
 		err := rows.Close()
@@ -248,7 +244,7 @@ func (db *SQLPostTagsDatabase) getPostTagsWithCallback(ctx context.Context, wher
 		return fmt.Errorf("Failed to create pagination options, %w", err)
 	}
 
-	q := fmt.Sprintf("SELECT id, account_id, post_id, href, name, typem created FROM %s WHERE %s ORDER BY created DESC", SQL_POST_TAGS_TABLE_NAME, where)
+	q := fmt.Sprintf("SELECT id, account_id, post_id, href, name, type, created FROM %s WHERE %s ORDER BY created DESC", SQL_POST_TAGS_TABLE_NAME, where)
 
 	err = pg_sql.QueryPaginatedAll(db.database, pg_opts, pg_callback, q, args...)
 
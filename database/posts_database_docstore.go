@@ -87,6 +87,10 @@ func (db *DocstorePostsDatabase) UpdatePost(ctx context.Context, p *activitypub.
 	return db.collection.Replace(ctx, p)
 }
 
+func (db *DocstorePostsDatabase) RemovePost(ctx context.Context, p *activitypub.Post) error {
+	return db.collection.Delete(ctx, p)
+}
+
 func (db *DocstorePostsDatabase) GetPostWithId(ctx context.Context, id int64) (*activitypub.Post, error) {
 
 	q := db.collection.Query()
@@ -38,6 +38,10 @@ func (db *NullPostsDatabase) UpdatePost(ctx context.Context, p *activitypub.Post
 	return nil
 }
 
+func (db *NullPostsDatabase) RemovePost(ctx context.Context, p *activitypub.Post) error {
+	return nil
+}
+
 func (db *NullPostsDatabase) GetPostWithId(ctx context.Context, id int64) (*activitypub.Post, error) {
 	return nil, activitypub.ErrNotFound
 }
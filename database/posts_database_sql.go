@@ -106,6 +106,32 @@ func (db *SQLPostsDatabase) AddPost(ctx context.Context, p *activitypub.Post) er
 	return nil
 }
 
+func (db *SQLPostsDatabase) UpdatePost(ctx context.Context, p *activitypub.Post) error {
+
+	q := fmt.Sprintf("UPDATE %s SET account_id = ?, body = ?, in_reply_to = ?, created = ?, lastmodified = ? WHERE id = ?", SQL_POSTS_TABLE_NAME)
+
+	_, err := db.database.ExecContext(ctx, q, p.AccountId, p.Body, p.InReplyTo, p.Created, p.LastModified, p.Id)
+
+	if err != nil {
+		return fmt.Errorf("Failed to update post, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLPostsDatabase) RemovePost(ctx context.Context, p *activitypub.Post) error {
+
+	q := fmt.Sprintf("DELETE FROM %s WHERE id = ?", SQL_POSTS_TABLE_NAME)
+
+	_, err := db.database.ExecContext(ctx, q, p.Id)
+
+	if err != nil {
+		return fmt.Errorf("Failed to remove post, %w", err)
+	}
+
+	return nil
+}
+
 func (db *SQLPostsDatabase) GetPostWithId(ctx context.Context, id int64) (*activitypub.Post, error) {
 	where := "id = ?"
 	return db.getPost(ctx, where, id)
@@ -122,7 +148,7 @@ func (db *SQLPostsDatabase) getPost(ctx context.Context, where string, args ...i
 
 	q := fmt.Sprintf("SELECT id, account_id, body, in_reply_to, created, lastmodified FROM %s WHERE %s", SQL_POSTS_TABLE_NAME, where)
 
-	row := db.database.QueryRowContext(ctx, q, id)
+	row := db.database.QueryRowContext(ctx, q, args...)
 
 	err := row.Scan(&id, &account_id, &body, &in_reply_to, &created, &lastmod)
 
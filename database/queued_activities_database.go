@@ -0,0 +1,109 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/aaronland/go-roster"
+	"github.com/sfomuseum/go-activitypub"
+)
+
+type GetQueuedActivitiesCallbackFunc func(context.Context, *activitypub.QueuedActivity) error
+
+// QueuedActivitiesDatabase defines an interface for storing verified activities posted to the inboxes of local accounts
+// until they have been processed by an `InboxQueue`.
+type QueuedActivitiesDatabase interface {
+	// AddQueuedActivity adds a new `activitypub.QueuedActivity` instance.
+	AddQueuedActivity(context.Context, *activitypub.QueuedActivity) error
+	// GetQueuedActivityWithId returns the `activitypub.QueuedActivity` instance with a specific unique ID.
+	GetQueuedActivityWithId(context.Context, int64) (*activitypub.QueuedActivity, error)
+	// GetQueuedActivities iterates through all the `activitypub.QueuedActivity` instances.
+	GetQueuedActivities(context.Context, GetQueuedActivitiesCallbackFunc) error
+	// RemoveQueuedActivity removes a specific `activitypub.QueuedActivity` instance.
+	RemoveQueuedActivity(context.Context, *activitypub.QueuedActivity) error
+	// Close performs any final operations to terminate the underlying database connection.
+	Close(context.Context) error
+}
+
+var queued_activities_database_roster roster.Roster
+
+// QueuedActivitiesDatabaseInitializationFunc is a function defined by individual queued_activities_database package and used to create
+// an instance of that queued_activities_database
+type QueuedActivitiesDatabaseInitializationFunc func(ctx context.Context, uri string) (QueuedActivitiesDatabase, error)
+
+// RegisterQueuedActivitiesDatabase registers 'scheme' as a key pointing to 'init_func' in an internal lookup table
+// used to create new `QueuedActivitiesDatabase` instances by the `NewQueuedActivitiesDatabase` method.
+func RegisterQueuedActivitiesDatabase(ctx context.Context, scheme string, init_func QueuedActivitiesDatabaseInitializationFunc) error {
+
+	err := ensureQueuedActivitiesDatabaseRoster()
+
+	if err != nil {
+		return err
+	}
+
+	return queued_activities_database_roster.Register(ctx, scheme, init_func)
+}
+
+func ensureQueuedActivitiesDatabaseRoster() error {
+
+	if queued_activities_database_roster == nil {
+
+		r, err := roster.NewDefaultRoster()
+
+		if err != nil {
+			return err
+		}
+
+		queued_activities_database_roster = r
+	}
+
+	return nil
+}
+
+// NewQueuedActivitiesDatabase returns a new `QueuedActivitiesDatabase` instance configured by 'uri'. The value of 'uri' is parsed
+// as a `url.URL` and its scheme is used as the key for a corresponding `QueuedActivitiesDatabaseInitializationFunc`
+// function used to instantiate the new `QueuedActivitiesDatabase`. It is assumed that the scheme (and initialization
+// function) have been registered by the `RegisterQueuedActivitiesDatabase` method.
+func NewQueuedActivitiesDatabase(ctx context.Context, uri string) (QueuedActivitiesDatabase, error) {
+
+	u, err := url.Parse(uri)
+
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := u.Scheme
+
+	i, err := queued_activities_database_roster.Driver(ctx, scheme)
+
+	if err != nil {
+		return nil, err
+	}
+
+	init_func := i.(QueuedActivitiesDatabaseInitializationFunc)
+	return init_func(ctx, uri)
+}
+
+// Schemes returns the list of schemes that have been registered.
+func QueuedActivitiesDatabaseSchemes() []string {
+
+	ctx := context.Background()
+	schemes := []string{}
+
+	err := ensureQueuedActivitiesDatabaseRoster()
+
+	if err != nil {
+		return schemes
+	}
+
+	for _, dr := range queued_activities_database_roster.Drivers(ctx) {
+		scheme := fmt.Sprintf("%s://", strings.ToLower(dr))
+		schemes = append(schemes, scheme)
+	}
+
+	sort.Strings(schemes)
+	return schemes
+}
@@ -0,0 +1,113 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	aa_docstore "github.com/aaronland/gocloud/docstore"
+	"github.com/sfomuseum/go-activitypub"
+	gc_docstore "gocloud.dev/docstore"
+)
+
+type DocstoreQueuedActivitiesDatabase struct {
+	QueuedActivitiesDatabase
+	collection *gc_docstore.Collection
+}
+
+func init() {
+
+	ctx := context.Background()
+
+	err := RegisterQueuedActivitiesDatabase(ctx, "awsdynamodb", NewDocstoreQueuedActivitiesDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+
+	for _, scheme := range gc_docstore.DefaultURLMux().CollectionSchemes() {
+		err := RegisterQueuedActivitiesDatabase(ctx, scheme, NewDocstoreQueuedActivitiesDatabase)
+
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+func NewDocstoreQueuedActivitiesDatabase(ctx context.Context, uri string) (QueuedActivitiesDatabase, error) {
+
+	col, err := aa_docstore.OpenCollection(ctx, uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open collection, %w", err)
+	}
+
+	db := &DocstoreQueuedActivitiesDatabase{
+		collection: col,
+	}
+
+	return db, nil
+}
+
+func (db *DocstoreQueuedActivitiesDatabase) AddQueuedActivity(ctx context.Context, q *activitypub.QueuedActivity) error {
+
+	return db.collection.Put(ctx, q)
+}
+
+func (db *DocstoreQueuedActivitiesDatabase) GetQueuedActivityWithId(ctx context.Context, id int64) (*activitypub.QueuedActivity, error) {
+
+	q := db.collection.Query()
+	q = q.Where("Id", "=", id)
+
+	iter := q.Get(ctx)
+	defer iter.Stop()
+
+	var a activitypub.QueuedActivity
+	err := iter.Next(ctx, &a)
+
+	if err == io.EOF {
+		return nil, activitypub.ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("Failed to interate, %w", err)
+	} else {
+		return &a, nil
+	}
+}
+
+func (db *DocstoreQueuedActivitiesDatabase) GetQueuedActivities(ctx context.Context, cb GetQueuedActivitiesCallbackFunc) error {
+
+	q := db.collection.Query()
+
+	iter := q.Get(ctx)
+	defer iter.Stop()
+
+	for {
+
+		var a activitypub.QueuedActivity
+		err := iter.Next(ctx, &a)
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("Failed to interate, %w", err)
+		} else {
+
+			err := cb(ctx, &a)
+
+			if err != nil {
+				return fmt.Errorf("Failed to execute queued activities callback for '%d', %w", a.Id, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (db *DocstoreQueuedActivitiesDatabase) RemoveQueuedActivity(ctx context.Context, q *activitypub.QueuedActivity) error {
+
+	return db.collection.Delete(ctx, q)
+}
+
+func (db *DocstoreQueuedActivitiesDatabase) Close(ctx context.Context) error {
+	return db.collection.Close()
+}
@@ -0,0 +1,46 @@
+package database
+
+import (
+	"context"
+
+	"github.com/sfomuseum/go-activitypub"
+)
+
+type NullQueuedActivitiesDatabase struct {
+	QueuedActivitiesDatabase
+}
+
+func init() {
+
+	ctx := context.Background()
+	err := RegisterQueuedActivitiesDatabase(ctx, "null", NewNullQueuedActivitiesDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func NewNullQueuedActivitiesDatabase(ctx context.Context, uri string) (QueuedActivitiesDatabase, error) {
+	db := &NullQueuedActivitiesDatabase{}
+	return db, nil
+}
+
+func (db *NullQueuedActivitiesDatabase) AddQueuedActivity(ctx context.Context, q *activitypub.QueuedActivity) error {
+	return nil
+}
+
+func (db *NullQueuedActivitiesDatabase) GetQueuedActivityWithId(ctx context.Context, id int64) (*activitypub.QueuedActivity, error) {
+	return nil, activitypub.ErrNotFound
+}
+
+func (db *NullQueuedActivitiesDatabase) GetQueuedActivities(ctx context.Context, cb GetQueuedActivitiesCallbackFunc) error {
+	return nil
+}
+
+func (db *NullQueuedActivitiesDatabase) RemoveQueuedActivity(ctx context.Context, q *activitypub.QueuedActivity) error {
+	return nil
+}
+
+func (db *NullQueuedActivitiesDatabase) Close(ctx context.Context) error {
+	return nil
+}
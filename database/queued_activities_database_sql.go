@@ -0,0 +1,180 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	pg_sql "github.com/aaronland/go-pagination-sql"
+	"github.com/aaronland/go-pagination/countable"
+	"github.com/sfomuseum/go-activitypub"
+	sfom_sql "github.com/sfomuseum/go-database/sql"
+)
+
+const SQL_QUEUED_ACTIVITIES_TABLE_NAME string = "queued_activities"
+
+type SQLQueuedActivitiesDatabase struct {
+	QueuedActivitiesDatabase
+	database *sql.DB
+}
+
+func init() {
+
+	ctx := context.Background()
+	err := RegisterQueuedActivitiesDatabase(ctx, "sql", NewSQLQueuedActivitiesDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func NewSQLQueuedActivitiesDatabase(ctx context.Context, uri string) (QueuedActivitiesDatabase, error) {
+
+	conn, err := sfom_sql.OpenWithURI(ctx, uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open database connection, %w", err)
+	}
+
+	db := &SQLQueuedActivitiesDatabase{
+		database: conn,
+	}
+
+	return db, nil
+}
+
+func (db *SQLQueuedActivitiesDatabase) AddQueuedActivity(ctx context.Context, a *activitypub.QueuedActivity) error {
+
+	q := fmt.Sprintf("INSERT INTO %s (id, activitypub_id, account_id, activity_type, requestor_address, requestor_actor, body, created) VALUES (?, ?, ?, ?, ?, ?, ?, ?)", SQL_QUEUED_ACTIVITIES_TABLE_NAME)
+
+	_, err := db.database.ExecContext(ctx, q, a.Id, a.ActivityPubId, a.AccountId, a.ActivityType, a.RequestorAddress, a.RequestorActor, a.Body, a.Created)
+
+	if err != nil {
+		return fmt.Errorf("Failed to add queued activity, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLQueuedActivitiesDatabase) GetQueuedActivityWithId(ctx context.Context, id int64) (*activitypub.QueuedActivity, error) {
+
+	var activitypub_id string
+	var account_id int64
+	var activity_type string
+	var requestor_address string
+	var requestor_actor string
+	var body string
+	var created int64
+
+	q := fmt.Sprintf("SELECT activitypub_id, account_id, activity_type, requestor_address, requestor_actor, body, created FROM %s WHERE id = ?", SQL_QUEUED_ACTIVITIES_TABLE_NAME)
+
+	row := db.database.QueryRowContext(ctx, q, id)
+
+	err := row.Scan(&activitypub_id, &account_id, &activity_type, &requestor_address, &requestor_actor, &body, &created)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, activitypub.ErrNotFound
+	case err != nil:
+		return nil, fmt.Errorf("Failed to query database, %w", err)
+	default:
+		//
+	}
+
+	a := &activitypub.QueuedActivity{
+		Id:               id,
+		ActivityPubId:    activitypub_id,
+		AccountId:        account_id,
+		ActivityType:     activity_type,
+		RequestorAddress: requestor_address,
+		RequestorActor:   requestor_actor,
+		Body:             body,
+		Created:          created,
+	}
+
+	return a, nil
+}
+
+func (db *SQLQueuedActivitiesDatabase) GetQueuedActivities(ctx context.Context, cb GetQueuedActivitiesCallbackFunc) error {
+
+	pg_callback := func(pg_rsp pg_sql.PaginatedResponse) error {
+
+		rows := pg_rsp.Rows()
+
+		for rows.Next() {
+
+			var id int64
+			var activitypub_id string
+			var account_id int64
+			var activity_type string
+			var requestor_address string
+			var requestor_actor string
+			var body string
+			var created int64
+
+			err := rows.Scan(&id, &activitypub_id, &account_id, &activity_type, &requestor_address, &requestor_actor, &body, &created)
+
+			if err != nil {
+				return fmt.Errorf("Failed to query database, %w", err)
+			}
+
+			a := &activitypub.QueuedActivity{
+				Id:               id,
+				ActivityPubId:    activitypub_id,
+				AccountId:        account_id,
+				ActivityType:     activity_type,
+				RequestorAddress: requestor_address,
+				RequestorActor:   requestor_actor,
+				Body:             body,
+				Created:          created,
+			}
+
+			err = cb(ctx, a)
+
+			if err != nil {
+				return fmt.Errorf("Failed to execute queued activities callback for %d, %w", id, err)
+			}
+		}
+
+		err := rows.Close()
+
+		if err != nil {
+			return fmt.Errorf("Failed to iterate through database rows, %w", err)
+		}
+
+		return nil
+	}
+
+	pg_opts, err := countable.NewCountableOptions()
+
+	if err != nil {
+		return fmt.Errorf("Failed to create pagination options, %w", err)
+	}
+
+	q := fmt.Sprintf("SELECT id, activitypub_id, account_id, activity_type, requestor_address, requestor_actor, body, created FROM %s ORDER BY created ASC", SQL_QUEUED_ACTIVITIES_TABLE_NAME)
+
+	err = pg_sql.QueryPaginatedAll(db.database, pg_opts, pg_callback, q)
+
+	if err != nil {
+		return fmt.Errorf("Failed to execute paginated query, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLQueuedActivitiesDatabase) RemoveQueuedActivity(ctx context.Context, a *activitypub.QueuedActivity) error {
+
+	q := fmt.Sprintf("DELETE FROM %s WHERE id = ?", SQL_QUEUED_ACTIVITIES_TABLE_NAME)
+
+	_, err := db.database.ExecContext(ctx, q, a.Id)
+
+	if err != nil {
+		return fmt.Errorf("Failed to remove queued activity, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLQueuedActivitiesDatabase) Close(ctx context.Context) error {
+	return db.database.Close()
+}
@@ -0,0 +1,116 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/aaronland/go-roster"
+	"github.com/sfomuseum/go-activitypub"
+)
+
+type GetReceivedActivitiesCallbackFunc func(context.Context, *activitypub.ReceivedActivity) error
+
+// ReceivedActivitiesDatabase defines an interface for logging activities posted to the inboxes of local accounts.
+type ReceivedActivitiesDatabase interface {
+	// AddReceivedActivity adds a new `activitypub.ReceivedActivity` instance. Received activities are unique per ActivityPub
+	// activity ID, account and actor and `activitypub.ErrDuplicate` is returned if a matching record already exists.
+	AddReceivedActivity(context.Context, *activitypub.ReceivedActivity) error
+	// UpdateReceivedActivity updates a specific `activitypub.ReceivedActivity` instance.
+	UpdateReceivedActivity(context.Context, *activitypub.ReceivedActivity) error
+	// RemoveReceivedActivity removes a specific `activitypub.ReceivedActivity` instance.
+	RemoveReceivedActivity(context.Context, *activitypub.ReceivedActivity) error
+	// GetReceivedActivityWithId returns the `activitypub.ReceivedActivity` instance with a specific unique ID.
+	GetReceivedActivityWithId(context.Context, int64) (*activitypub.ReceivedActivity, error)
+	// GetReceivedActivities iterates through all the `activitypub.ReceivedActivity` instances.
+	GetReceivedActivities(context.Context, GetReceivedActivitiesCallbackFunc) error
+	// GetReceivedActivitiesForAccount iterates through all the `activitypub.ReceivedActivity` instances for a specific account.
+	GetReceivedActivitiesForAccount(context.Context, int64, GetReceivedActivitiesCallbackFunc) error
+	// GetReceivedActivitiesWithActivityPubIdAndAccount iterates through all the `activitypub.ReceivedActivity` instances
+	// for a specific ActivityPub activity ID and account.
+	GetReceivedActivitiesWithActivityPubIdAndAccount(context.Context, string, int64, GetReceivedActivitiesCallbackFunc) error
+	// Close performs any final operations to terminate the underlying database connection.
+	Close(context.Context) error
+}
+
+var received_activities_database_roster roster.Roster
+
+// ReceivedActivitiesDatabaseInitializationFunc is a function defined by individual received_activities_database package and used to create
+// an instance of that received_activities_database
+type ReceivedActivitiesDatabaseInitializationFunc func(ctx context.Context, uri string) (ReceivedActivitiesDatabase, error)
+
+// RegisterReceivedActivitiesDatabase registers 'scheme' as a key pointing to 'init_func' in an internal lookup table
+// used to create new `ReceivedActivitiesDatabase` instances by the `NewReceivedActivitiesDatabase` method.
+func RegisterReceivedActivitiesDatabase(ctx context.Context, scheme string, init_func ReceivedActivitiesDatabaseInitializationFunc) error {
+
+	err := ensureReceivedActivitiesDatabaseRoster()
+
+	if err != nil {
+		return err
+	}
+
+	return received_activities_database_roster.Register(ctx, scheme, init_func)
+}
+
+func ensureReceivedActivitiesDatabaseRoster() error {
+
+	if received_activities_database_roster == nil {
+
+		r, err := roster.NewDefaultRoster()
+
+		if err != nil {
+			return err
+		}
+
+		received_activities_database_roster = r
+	}
+
+	return nil
+}
+
+// NewReceivedActivitiesDatabase returns a new `ReceivedActivitiesDatabase` instance configured by 'uri'. The value of 'uri' is parsed
+// as a `url.URL` and its scheme is used as the key for a corresponding `ReceivedActivitiesDatabaseInitializationFunc`
+// function used to instantiate the new `ReceivedActivitiesDatabase`. It is assumed that the scheme (and initialization
+// function) have been registered by the `RegisterReceivedActivitiesDatabase` method.
+func NewReceivedActivitiesDatabase(ctx context.Context, uri string) (ReceivedActivitiesDatabase, error) {
+
+	u, err := url.Parse(uri)
+
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := u.Scheme
+
+	i, err := received_activities_database_roster.Driver(ctx, scheme)
+
+	if err != nil {
+		return nil, err
+	}
+
+	init_func := i.(ReceivedActivitiesDatabaseInitializationFunc)
+	return init_func(ctx, uri)
+}
+
+// Schemes returns the list of schemes that have been registered.
+func ReceivedActivitiesDatabaseSchemes() []string {
+
+	ctx := context.Background()
+	schemes := []string{}
+
+	err := ensureReceivedActivitiesDatabaseRoster()
+
+	if err != nil {
+		return schemes
+	}
+
+	for _, dr := range received_activities_database_roster.Drivers(ctx) {
+		scheme := fmt.Sprintf("%s://", strings.ToLower(dr))
+		schemes = append(schemes, scheme)
+	}
+
+	sort.Strings(schemes)
+	return schemes
+}
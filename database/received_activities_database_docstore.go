@@ -0,0 +1,163 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	aa_docstore "github.com/aaronland/gocloud/docstore"
+	"github.com/sfomuseum/go-activitypub"
+	gc_docstore "gocloud.dev/docstore"
+)
+
+type DocstoreReceivedActivitiesDatabase struct {
+	ReceivedActivitiesDatabase
+	collection *gc_docstore.Collection
+}
+
+func init() {
+
+	ctx := context.Background()
+
+	err := RegisterReceivedActivitiesDatabase(ctx, "awsdynamodb", NewDocstoreReceivedActivitiesDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+
+	for _, scheme := range gc_docstore.DefaultURLMux().CollectionSchemes() {
+		err := RegisterReceivedActivitiesDatabase(ctx, scheme, NewDocstoreReceivedActivitiesDatabase)
+
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+func NewDocstoreReceivedActivitiesDatabase(ctx context.Context, uri string) (ReceivedActivitiesDatabase, error) {
+
+	col, err := aa_docstore.OpenCollection(ctx, uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open collection, %w", err)
+	}
+
+	db := &DocstoreReceivedActivitiesDatabase{
+		collection: col,
+	}
+
+	return db, nil
+}
+
+func (db *DocstoreReceivedActivitiesDatabase) AddReceivedActivity(ctx context.Context, r *activitypub.ReceivedActivity) error {
+
+	// Docstore collections can only enforce uniqueness on the primary key so, unlike the SQL implementation,
+	// checking for an existing record and adding a new one is not atomic.
+
+	is_duplicate := false
+
+	received_cb := func(ctx context.Context, existing *activitypub.ReceivedActivity) error {
+
+		if existing.Actor == r.Actor {
+			is_duplicate = true
+		}
+
+		return nil
+	}
+
+	err := db.GetReceivedActivitiesWithActivityPubIdAndAccount(ctx, r.ActivityPubId, r.AccountId, received_cb)
+
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve received activities, %w", err)
+	}
+
+	if is_duplicate {
+		return activitypub.ErrDuplicate
+	}
+
+	return db.collection.Put(ctx, r)
+}
+
+func (db *DocstoreReceivedActivitiesDatabase) UpdateReceivedActivity(ctx context.Context, r *activitypub.ReceivedActivity) error {
+
+	return db.collection.Replace(ctx, r)
+}
+
+func (db *DocstoreReceivedActivitiesDatabase) RemoveReceivedActivity(ctx context.Context, r *activitypub.ReceivedActivity) error {
+
+	return db.collection.Delete(ctx, r)
+}
+
+func (db *DocstoreReceivedActivitiesDatabase) GetReceivedActivityWithId(ctx context.Context, id int64) (*activitypub.ReceivedActivity, error) {
+
+	q := db.collection.Query()
+	q = q.Where("Id", "=", id)
+
+	iter := q.Get(ctx)
+	defer iter.Stop()
+
+	var r activitypub.ReceivedActivity
+	err := iter.Next(ctx, &r)
+
+	if err == io.EOF {
+		return nil, activitypub.ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("Failed to interate, %w", err)
+	} else {
+		return &r, nil
+	}
+}
+
+func (db *DocstoreReceivedActivitiesDatabase) GetReceivedActivities(ctx context.Context, cb GetReceivedActivitiesCallbackFunc) error {
+
+	q := db.collection.Query()
+	return db.getReceivedActivitiesWithQuery(ctx, q, cb)
+}
+
+func (db *DocstoreReceivedActivitiesDatabase) GetReceivedActivitiesForAccount(ctx context.Context, account_id int64, cb GetReceivedActivitiesCallbackFunc) error {
+
+	q := db.collection.Query()
+	q = q.Where("AccountId", "=", account_id)
+
+	return db.getReceivedActivitiesWithQuery(ctx, q, cb)
+}
+
+func (db *DocstoreReceivedActivitiesDatabase) GetReceivedActivitiesWithActivityPubIdAndAccount(ctx context.Context, activitypub_id string, account_id int64, cb GetReceivedActivitiesCallbackFunc) error {
+
+	q := db.collection.Query()
+	q = q.Where("ActivityPubId", "=", activitypub_id)
+	q = q.Where("AccountId", "=", account_id)
+
+	return db.getReceivedActivitiesWithQuery(ctx, q, cb)
+}
+
+func (db *DocstoreReceivedActivitiesDatabase) Close(ctx context.Context) error {
+	return db.collection.Close()
+}
+
+func (db *DocstoreReceivedActivitiesDatabase) getReceivedActivitiesWithQuery(ctx context.Context, q *gc_docstore.Query, cb GetReceivedActivitiesCallbackFunc) error {
+
+	iter := q.Get(ctx)
+	defer iter.Stop()
+
+	for {
+
+		var r activitypub.ReceivedActivity
+		err := iter.Next(ctx, &r)
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("Failed to interate, %w", err)
+		} else {
+
+			err := cb(ctx, &r)
+
+			if err != nil {
+				return fmt.Errorf("Failed to execute received activities callback for '%d', %w", r.Id, err)
+			}
+		}
+	}
+
+	return nil
+}
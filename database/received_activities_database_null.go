@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+
+	"github.com/sfomuseum/go-activitypub"
+)
+
+type NullReceivedActivitiesDatabase struct {
+	ReceivedActivitiesDatabase
+}
+
+func init() {
+
+	ctx := context.Background()
+	err := RegisterReceivedActivitiesDatabase(ctx, "null", NewNullReceivedActivitiesDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func NewNullReceivedActivitiesDatabase(ctx context.Context, uri string) (ReceivedActivitiesDatabase, error) {
+	db := &NullReceivedActivitiesDatabase{}
+	return db, nil
+}
+
+func (db *NullReceivedActivitiesDatabase) AddReceivedActivity(ctx context.Context, r *activitypub.ReceivedActivity) error {
+	return nil
+}
+
+func (db *NullReceivedActivitiesDatabase) UpdateReceivedActivity(ctx context.Context, r *activitypub.ReceivedActivity) error {
+	return nil
+}
+
+func (db *NullReceivedActivitiesDatabase) RemoveReceivedActivity(ctx context.Context, r *activitypub.ReceivedActivity) error {
+	return nil
+}
+
+func (db *NullReceivedActivitiesDatabase) GetReceivedActivityWithId(ctx context.Context, id int64) (*activitypub.ReceivedActivity, error) {
+	return nil, activitypub.ErrNotFound
+}
+
+func (db *NullReceivedActivitiesDatabase) GetReceivedActivities(ctx context.Context, cb GetReceivedActivitiesCallbackFunc) error {
+	return nil
+}
+
+func (db *NullReceivedActivitiesDatabase) GetReceivedActivitiesForAccount(ctx context.Context, account_id int64, cb GetReceivedActivitiesCallbackFunc) error {
+	return nil
+}
+
+func (db *NullReceivedActivitiesDatabase) GetReceivedActivitiesWithActivityPubIdAndAccount(ctx context.Context, activitypub_id string, account_id int64, cb GetReceivedActivitiesCallbackFunc) error {
+	return nil
+}
+
+func (db *NullReceivedActivitiesDatabase) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,241 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	pg_sql "github.com/aaronland/go-pagination-sql"
+	"github.com/aaronland/go-pagination/countable"
+	"github.com/sfomuseum/go-activitypub"
+	sfom_sql "github.com/sfomuseum/go-database/sql"
+)
+
+const SQL_RECEIVED_ACTIVITIES_TABLE_NAME string = "received_activities"
+
+type SQLReceivedActivitiesDatabase struct {
+	ReceivedActivitiesDatabase
+	database *sql.DB
+}
+
+func init() {
+
+	ctx := context.Background()
+	err := RegisterReceivedActivitiesDatabase(ctx, "sql", NewSQLReceivedActivitiesDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func NewSQLReceivedActivitiesDatabase(ctx context.Context, uri string) (ReceivedActivitiesDatabase, error) {
+
+	conn, err := sfom_sql.OpenWithURI(ctx, uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open database connection, %w", err)
+	}
+
+	db := &SQLReceivedActivitiesDatabase{
+		database: conn,
+	}
+
+	return db, nil
+}
+
+func (db *SQLReceivedActivitiesDatabase) AddReceivedActivity(ctx context.Context, r *activitypub.ReceivedActivity) error {
+
+	q := fmt.Sprintf("INSERT INTO %s (id, activitypub_id, account_id, actor, activity_type, status, outcome, received) VALUES (?, ?, ?, ?, ?, ?, ?, ?)", SQL_RECEIVED_ACTIVITIES_TABLE_NAME)
+
+	_, err := db.database.ExecContext(ctx, q, r.Id, r.ActivityPubId, r.AccountId, r.Actor, r.ActivityType, r.Status, r.Outcome, r.Received)
+
+	if err != nil {
+
+		// The insert is rejected by the unique (activitypub_id, account_id, actor) index if the activity
+		// has already been received. Drivers report constraint violations differently so check for an
+		// existing record rather than parsing the error.
+
+		where := "activitypub_id = ? AND account_id = ? AND actor = ?"
+		_, get_err := db.getReceivedActivity(ctx, where, r.ActivityPubId, r.AccountId, r.Actor)
+
+		if get_err == nil {
+			return activitypub.ErrDuplicate
+		}
+
+		return fmt.Errorf("Failed to add received activity, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLReceivedActivitiesDatabase) UpdateReceivedActivity(ctx context.Context, r *activitypub.ReceivedActivity) error {
+
+	q := fmt.Sprintf("UPDATE %s SET activitypub_id=?, account_id=?, actor=?, activity_type=?, status=?, outcome=?, received=? WHERE id = ?", SQL_RECEIVED_ACTIVITIES_TABLE_NAME)
+
+	_, err := db.database.ExecContext(ctx, q, r.ActivityPubId, r.AccountId, r.Actor, r.ActivityType, r.Status, r.Outcome, r.Received, r.Id)
+
+	if err != nil {
+		return fmt.Errorf("Failed to update received activity, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLReceivedActivitiesDatabase) RemoveReceivedActivity(ctx context.Context, r *activitypub.ReceivedActivity) error {
+
+	q := fmt.Sprintf("DELETE FROM %s WHERE id = ?", SQL_RECEIVED_ACTIVITIES_TABLE_NAME)
+
+	_, err := db.database.ExecContext(ctx, q, r.Id)
+
+	if err != nil {
+		return fmt.Errorf("Failed to remove received activity, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLReceivedActivitiesDatabase) GetReceivedActivityWithId(ctx context.Context, id int64) (*activitypub.ReceivedActivity, error) {
+
+	where := "id = ?"
+	return db.getReceivedActivity(ctx, where, id)
+}
+
+func (db *SQLReceivedActivitiesDatabase) GetReceivedActivities(ctx context.Context, cb GetReceivedActivitiesCallbackFunc) error {
+
+	where := "1 = 1"
+	args := make([]interface{}, 0)
+
+	return db.getReceivedActivities(ctx, where, args, cb)
+}
+
+func (db *SQLReceivedActivitiesDatabase) GetReceivedActivitiesForAccount(ctx context.Context, account_id int64, cb GetReceivedActivitiesCallbackFunc) error {
+
+	where := "account_id = ?"
+	args := []interface{}{
+		account_id,
+	}
+
+	return db.getReceivedActivities(ctx, where, args, cb)
+}
+
+func (db *SQLReceivedActivitiesDatabase) GetReceivedActivitiesWithActivityPubIdAndAccount(ctx context.Context, activitypub_id string, account_id int64, cb GetReceivedActivitiesCallbackFunc) error {
+
+	where := "activitypub_id = ? AND account_id = ?"
+	args := []interface{}{
+		activitypub_id,
+		account_id,
+	}
+
+	return db.getReceivedActivities(ctx, where, args, cb)
+}
+
+func (db *SQLReceivedActivitiesDatabase) Close(ctx context.Context) error {
+	return db.database.Close()
+}
+
+func (db *SQLReceivedActivitiesDatabase) getReceivedActivity(ctx context.Context, where string, args ...interface{}) (*activitypub.ReceivedActivity, error) {
+
+	var id int64
+	var activitypub_id string
+	var account_id int64
+	var actor string
+	var activity_type string
+	var status int
+	var outcome string
+	var received int64
+
+	q := fmt.Sprintf("SELECT id, activitypub_id, account_id, actor, activity_type, status, outcome, received FROM %s WHERE %s", SQL_RECEIVED_ACTIVITIES_TABLE_NAME, where)
+
+	row := db.database.QueryRowContext(ctx, q, args...)
+
+	err := row.Scan(&id, &activitypub_id, &account_id, &actor, &activity_type, &status, &outcome, &received)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, activitypub.ErrNotFound
+	case err != nil:
+		return nil, fmt.Errorf("Failed to query database, %w", err)
+	default:
+		//
+	}
+
+	r := &activitypub.ReceivedActivity{
+		Id:            id,
+		ActivityPubId: activitypub_id,
+		AccountId:     account_id,
+		Actor:         actor,
+		ActivityType:  activity_type,
+		Status:        status,
+		Outcome:       outcome,
+		Received:      received,
+	}
+
+	return r, nil
+}
+
+func (db *SQLReceivedActivitiesDatabase) getReceivedActivities(ctx context.Context, where string, args []interface{}, cb GetReceivedActivitiesCallbackFunc) error {
+
+	pg_callback := func(pg_rsp pg_sql.PaginatedResponse) error {
+
+		rows := pg_rsp.Rows()
+
+		for rows.Next() {
+
+			var id int64
+			var activitypub_id string
+			var account_id int64
+			var actor string
+			var activity_type string
+			var status int
+			var outcome string
+			var received int64
+
+			err := rows.Scan(&id, &activitypub_id, &account_id, &actor, &activity_type, &status, &outcome, &received)
+
+			if err != nil {
+				return fmt.Errorf("Failed to query database, %w", err)
+			}
+
+			r := &activitypub.ReceivedActivity{
+				Id:            id,
+				ActivityPubId: activitypub_id,
+				AccountId:     account_id,
+				Actor:         actor,
+				ActivityType:  activity_type,
+				Status:        status,
+				Outcome:       outcome,
+				Received:      received,
+			}
+
+			err = cb(ctx, r)
+
+			if err != nil {
+				return fmt.Errorf("Failed to execute received activities callback for %d, %w", id, err)
+			}
+		}
+
+		err := rows.Close()
+
+		if err != nil {
+			return fmt.Errorf("Failed to iterate through database rows, %w", err)
+		}
+
+		return nil
+	}
+
+	pg_opts, err := countable.NewCountableOptions()
+
+	if err != nil {
+		return fmt.Errorf("Failed to create pagination options, %w", err)
+	}
+
+	q := fmt.Sprintf("SELECT id, activitypub_id, account_id, actor, activity_type, status, outcome, received FROM %s WHERE %s ORDER BY received DESC", SQL_RECEIVED_ACTIVITIES_TABLE_NAME, where)
+
+	err = pg_sql.QueryPaginatedAll(db.database, pg_opts, pg_callback, q, args...)
+
+	if err != nil {
+		return fmt.Errorf("Failed to execute paginated query, %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/aaronland/go-roster"
+	"github.com/sfomuseum/go-activitypub"
+)
+
+type GetReportsCallbackFunc func(context.Context, *activitypub.Report) error
+
+// ReportsDatabase defines an interface for storing moderation reports ("Flag" activities) received about local accounts.
+type ReportsDatabase interface {
+	// GetReportWithId returns the `activitypub.Report` instance with a specific unique ID.
+	GetReportWithId(context.Context, int64) (*activitypub.Report, error)
+	// GetReports iterates through all the `activitypub.Report` instances.
+	GetReports(context.Context, GetReportsCallbackFunc) error
+	// GetReportsForAccount iterates through all the `activitypub.Report` instances for a specific account.
+	GetReportsForAccount(context.Context, int64, GetReportsCallbackFunc) error
+	// AddReport adds a new `activitypub.Report` instance.
+	AddReport(context.Context, *activitypub.Report) error
+	// UpdateReport updates a specific `activitypub.Report` instance.
+	UpdateReport(context.Context, *activitypub.Report) error
+	// Close performs any final operations to terminate the underlying database connection.
+	Close(context.Context) error
+}
+
+var reports_database_roster roster.Roster
+
+// ReportsDatabaseInitializationFunc is a function defined by individual reports_database package and used to create
+// an instance of that reports_database
+type ReportsDatabaseInitializationFunc func(ctx context.Context, uri string) (ReportsDatabase, error)
+
+// RegisterReportsDatabase registers 'scheme' as a key pointing to 'init_func' in an internal lookup table
+// used to create new `ReportsDatabase` instances by the `NewReportsDatabase` method.
+func RegisterReportsDatabase(ctx context.Context, scheme string, init_func ReportsDatabaseInitializationFunc) error {
+
+	err := ensureReportsDatabaseRoster()
+
+	if err != nil {
+		return err
+	}
+
+	return reports_database_roster.Register(ctx, scheme, init_func)
+}
+
+func ensureReportsDatabaseRoster() error {
+
+	if reports_database_roster == nil {
+
+		r, err := roster.NewDefaultRoster()
+
+		if err != nil {
+			return err
+		}
+
+		reports_database_roster = r
+	}
+
+	return nil
+}
+
+// NewReportsDatabase returns a new `ReportsDatabase` instance configured by 'uri'. The value of 'uri' is parsed
+// as a `url.URL` and its scheme is used as the key for a corresponding `ReportsDatabaseInitializationFunc`
+// function used to instantiate the new `ReportsDatabase`. It is assumed that the scheme (and initialization
+// function) have been registered by the `RegisterReportsDatabase` method.
+func NewReportsDatabase(ctx context.Context, uri string) (ReportsDatabase, error) {
+
+	u, err := url.Parse(uri)
+
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := u.Scheme
+
+	i, err := reports_database_roster.Driver(ctx, scheme)
+
+	if err != nil {
+		return nil, err
+	}
+
+	init_func := i.(ReportsDatabaseInitializationFunc)
+	return init_func(ctx, uri)
+}
+
+// Schemes returns the list of schemes that have been registered.
+func ReportsDatabaseSchemes() []string {
+
+	ctx := context.Background()
+	schemes := []string{}
+
+	err := ensureReportsDatabaseRoster()
+
+	if err != nil {
+		return schemes
+	}
+
+	for _, dr := range reports_database_roster.Drivers(ctx) {
+		scheme := fmt.Sprintf("%s://", strings.ToLower(dr))
+		schemes = append(schemes, scheme)
+	}
+
+	sort.Strings(schemes)
+	return schemes
+}
@@ -0,0 +1,125 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	aa_docstore "github.com/aaronland/gocloud/docstore"
+	"github.com/sfomuseum/go-activitypub"
+	gc_docstore "gocloud.dev/docstore"
+)
+
+type DocstoreReportsDatabase struct {
+	ReportsDatabase
+	collection *gc_docstore.Collection
+}
+
+func init() {
+
+	ctx := context.Background()
+
+	err := RegisterReportsDatabase(ctx, "awsdynamodb", NewDocstoreReportsDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+
+	for _, scheme := range gc_docstore.DefaultURLMux().CollectionSchemes() {
+		err := RegisterReportsDatabase(ctx, scheme, NewDocstoreReportsDatabase)
+
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+func NewDocstoreReportsDatabase(ctx context.Context, uri string) (ReportsDatabase, error) {
+
+	col, err := aa_docstore.OpenCollection(ctx, uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open collection, %w", err)
+	}
+
+	db := &DocstoreReportsDatabase{
+		collection: col,
+	}
+
+	return db, nil
+}
+
+func (db *DocstoreReportsDatabase) GetReportWithId(ctx context.Context, id int64) (*activitypub.Report, error) {
+
+	q := db.collection.Query()
+	q = q.Where("Id", "=", id)
+
+	iter := q.Get(ctx)
+	defer iter.Stop()
+
+	var r activitypub.Report
+	err := iter.Next(ctx, &r)
+
+	if err == io.EOF {
+		return nil, activitypub.ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("Failed to interate, %w", err)
+	} else {
+		return &r, nil
+	}
+}
+
+func (db *DocstoreReportsDatabase) GetReports(ctx context.Context, cb GetReportsCallbackFunc) error {
+
+	q := db.collection.Query()
+	return db.getReportsWithQuery(ctx, q, cb)
+}
+
+func (db *DocstoreReportsDatabase) GetReportsForAccount(ctx context.Context, account_id int64, cb GetReportsCallbackFunc) error {
+
+	q := db.collection.Query()
+	q = q.Where("AccountId", "=", account_id)
+
+	return db.getReportsWithQuery(ctx, q, cb)
+}
+
+func (db *DocstoreReportsDatabase) AddReport(ctx context.Context, r *activitypub.Report) error {
+
+	return db.collection.Put(ctx, r)
+}
+
+func (db *DocstoreReportsDatabase) UpdateReport(ctx context.Context, r *activitypub.Report) error {
+
+	return db.collection.Replace(ctx, r)
+}
+
+func (db *DocstoreReportsDatabase) Close(ctx context.Context) error {
+	return db.collection.Close()
+}
+
+func (db *DocstoreReportsDatabase) getReportsWithQuery(ctx context.Context, q *gc_docstore.Query, cb GetReportsCallbackFunc) error {
+
+	iter := q.Get(ctx)
+	defer iter.Stop()
+
+	for {
+
+		var r activitypub.Report
+		err := iter.Next(ctx, &r)
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("Failed to interate, %w", err)
+		} else {
+
+			err := cb(ctx, &r)
+
+			if err != nil {
+				return fmt.Errorf("Failed to execute reports callback for '%d', %w", r.Id, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+
+	"github.com/sfomuseum/go-activitypub"
+)
+
+type NullReportsDatabase struct {
+	ReportsDatabase
+}
+
+func init() {
+
+	ctx := context.Background()
+	err := RegisterReportsDatabase(ctx, "null", NewNullReportsDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func NewNullReportsDatabase(ctx context.Context, uri string) (ReportsDatabase, error) {
+	db := &NullReportsDatabase{}
+	return db, nil
+}
+
+func (db *NullReportsDatabase) GetReportWithId(ctx context.Context, id int64) (*activitypub.Report, error) {
+	return nil, activitypub.ErrNotFound
+}
+
+func (db *NullReportsDatabase) GetReports(ctx context.Context, cb GetReportsCallbackFunc) error {
+	return nil
+}
+
+func (db *NullReportsDatabase) GetReportsForAccount(ctx context.Context, account_id int64, cb GetReportsCallbackFunc) error {
+	return nil
+}
+
+func (db *NullReportsDatabase) AddReport(ctx context.Context, r *activitypub.Report) error {
+	return nil
+}
+
+func (db *NullReportsDatabase) UpdateReport(ctx context.Context, r *activitypub.Report) error {
+	return nil
+}
+
+func (db *NullReportsDatabase) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,258 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	pg_sql "github.com/aaronland/go-pagination-sql"
+	"github.com/aaronland/go-pagination/countable"
+	"github.com/sfomuseum/go-activitypub"
+	sfom_sql "github.com/sfomuseum/go-database/sql"
+)
+
+const SQL_REPORTS_TABLE_NAME string = "reports"
+
+type SQLReportsDatabase struct {
+	ReportsDatabase
+	database *sql.DB
+}
+
+func init() {
+
+	ctx := context.Background()
+	err := RegisterReportsDatabase(ctx, "sql", NewSQLReportsDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func NewSQLReportsDatabase(ctx context.Context, uri string) (ReportsDatabase, error) {
+
+	conn, err := sfom_sql.OpenWithURI(ctx, uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open database connection, %w", err)
+	}
+
+	db := &SQLReportsDatabase{
+		database: conn,
+	}
+
+	return db, nil
+}
+
+func (db *SQLReportsDatabase) GetReportWithId(ctx context.Context, id int64) (*activitypub.Report, error) {
+
+	where := "id = ?"
+	return db.getReport(ctx, where, id)
+}
+
+func (db *SQLReportsDatabase) GetReports(ctx context.Context, cb GetReportsCallbackFunc) error {
+
+	where := "1 = 1"
+	args := make([]interface{}, 0)
+
+	return db.getReports(ctx, where, args, cb)
+}
+
+func (db *SQLReportsDatabase) GetReportsForAccount(ctx context.Context, account_id int64, cb GetReportsCallbackFunc) error {
+
+	where := "account_id = ?"
+	args := []interface{}{
+		account_id,
+	}
+
+	return db.getReports(ctx, where, args, cb)
+}
+
+func (db *SQLReportsDatabase) AddReport(ctx context.Context, r *activitypub.Report) error {
+
+	q := fmt.Sprintf("INSERT INTO %s (id, account_id, activity_id, reporter_actor, post_ids, comment, resolved, created, lastmodified) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)", SQL_REPORTS_TABLE_NAME)
+
+	_, err := db.database.ExecContext(ctx, q, r.Id, r.AccountId, r.ActivityId, r.ReporterActor, joinReportPostIds(r.PostIds), r.Comment, r.Resolved, r.Created, r.LastModified)
+
+	if err != nil {
+		return fmt.Errorf("Failed to add report, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLReportsDatabase) UpdateReport(ctx context.Context, r *activitypub.Report) error {
+
+	q := fmt.Sprintf("UPDATE %s SET account_id = ?, activity_id = ?, reporter_actor = ?, post_ids = ?, comment = ?, resolved = ?, lastmodified = ? WHERE id = ?", SQL_REPORTS_TABLE_NAME)
+
+	_, err := db.database.ExecContext(ctx, q, r.AccountId, r.ActivityId, r.ReporterActor, joinReportPostIds(r.PostIds), r.Comment, r.Resolved, r.LastModified, r.Id)
+
+	if err != nil {
+		return fmt.Errorf("Failed to update report, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLReportsDatabase) Close(ctx context.Context) error {
+	return db.database.Close()
+}
+
+func (db *SQLReportsDatabase) getReport(ctx context.Context, where string, args ...interface{}) (*activitypub.Report, error) {
+
+	var id int64
+	var account_id int64
+	var activity_id string
+	var reporter_actor string
+	var post_ids string
+	var comment string
+	var resolved int64
+	var created int64
+	var lastmodified int64
+
+	q := fmt.Sprintf("SELECT id, account_id, activity_id, reporter_actor, post_ids, comment, resolved, created, lastmodified FROM %s WHERE %s", SQL_REPORTS_TABLE_NAME, where)
+
+	row := db.database.QueryRowContext(ctx, q, args...)
+
+	err := row.Scan(&id, &account_id, &activity_id, &reporter_actor, &post_ids, &comment, &resolved, &created, &lastmodified)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, activitypub.ErrNotFound
+	case err != nil:
+		return nil, fmt.Errorf("Failed to query database, %w", err)
+	default:
+		//
+	}
+
+	ids, err := splitReportPostIds(post_ids)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse post IDs for report %d, %w", id, err)
+	}
+
+	r := &activitypub.Report{
+		Id:            id,
+		AccountId:     account_id,
+		ActivityId:    activity_id,
+		ReporterActor: reporter_actor,
+		PostIds:       ids,
+		Comment:       comment,
+		Resolved:      resolved,
+		Created:       created,
+		LastModified:  lastmodified,
+	}
+
+	return r, nil
+}
+
+func (db *SQLReportsDatabase) getReports(ctx context.Context, where string, args []interface{}, cb GetReportsCallbackFunc) error {
+
+	pg_callback := func(pg_rsp pg_sql.PaginatedResponse) error {
+
+		rows := pg_rsp.Rows()
+
+		for rows.Next() {
+
+			var id int64
+			var account_id int64
+			var activity_id string
+			var reporter_actor string
+			var post_ids string
+			var comment string
+			var resolved int64
+			var created int64
+			var lastmodified int64
+
+			err := rows.Scan(&id, &account_id, &activity_id, &reporter_actor, &post_ids, &comment, &resolved, &created, &lastmodified)
+
+			if err != nil {
+				return fmt.Errorf("Failed to query database, %w", err)
+			}
+
+			ids, err := splitReportPostIds(post_ids)
+
+			if err != nil {
+				return fmt.Errorf("Failed to parse post IDs for report %d, %w", id, err)
+			}
+
+			r := &activitypub.Report{
+				Id:            id,
+				AccountId:     account_id,
+				ActivityId:    activity_id,
+				ReporterActor: reporter_actor,
+				PostIds:       ids,
+				Comment:       comment,
+				Resolved:      resolved,
+				Created:       created,
+				LastModified:  lastmodified,
+			}
+
+			err = cb(ctx, r)
+
+			if err != nil {
+				return fmt.Errorf("Failed to execute reports callback for %d, %w", id, err)
+			}
+		}
+
+		err := rows.Close()
+
+		if err != nil {
+			return fmt.Errorf("Failed to iterate through database rows, %w", err)
+		}
+
+		return nil
+	}
+
+	pg_opts, err := countable.NewCountableOptions()
+
+	if err != nil {
+		return fmt.Errorf("Failed to create pagination options, %w", err)
+	}
+
+	q := fmt.Sprintf("SELECT id, account_id, activity_id, reporter_actor, post_ids, comment, resolved, created, lastmodified FROM %s WHERE %s ORDER BY created DESC", SQL_REPORTS_TABLE_NAME, where)
+
+	err = pg_sql.QueryPaginatedAll(db.database, pg_opts, pg_callback, q, args...)
+
+	if err != nil {
+		return fmt.Errorf("Failed to execute paginated query, %w", err)
+	}
+
+	return nil
+}
+
+// joinReportPostIds encodes 'ids' as a comma-separated string for storing in a single column.
+func joinReportPostIds(ids []int64) string {
+
+	str_ids := make([]string, len(ids))
+
+	for idx, id := range ids {
+		str_ids[idx] = strconv.FormatInt(id, 10)
+	}
+
+	return strings.Join(str_ids, ",")
+}
+
+// splitReportPostIds decodes a comma-separated string of post IDs created by `joinReportPostIds`.
+func splitReportPostIds(str_ids string) ([]int64, error) {
+
+	ids := make([]int64, 0)
+
+	if str_ids == "" {
+		return ids, nil
+	}
+
+	for _, str_id := range strings.Split(str_ids, ",") {
+
+		id, err := strconv.ParseInt(str_id, 10, 64)
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid post ID '%s', %w", str_id, err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
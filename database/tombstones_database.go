@@ -0,0 +1,110 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/aaronland/go-roster"
+	"github.com/sfomuseum/go-activitypub"
+)
+
+type GetTombstonesCallbackFunc func(context.Context, *activitypub.Tombstone) error
+
+// TombstonesDatabase defines an interface for storing records of posts that have been deleted.
+type TombstonesDatabase interface {
+	// GetTombstoneWithId returns the `activitypub.Tombstone` instance with a specific unique ID.
+	GetTombstoneWithId(context.Context, int64) (*activitypub.Tombstone, error)
+	// GetTombstoneWithPostId returns the `activitypub.Tombstone` instance for a specific (deleted) post.
+	GetTombstoneWithPostId(context.Context, int64) (*activitypub.Tombstone, error)
+	// GetTombstonesForAccount iterates through all the `activitypub.Tombstone` instances for a specific account.
+	GetTombstonesForAccount(context.Context, int64, GetTombstonesCallbackFunc) error
+	// AddTombstone adds a new `activitypub.Tombstone` instance.
+	AddTombstone(context.Context, *activitypub.Tombstone) error
+	// RemoveTombstone removes a specific `activitypub.Tombstone` instance.
+	RemoveTombstone(context.Context, *activitypub.Tombstone) error
+	// Close performs any final operations to terminate the underlying database connection.
+	Close(context.Context) error
+}
+
+var tombstones_database_roster roster.Roster
+
+// TombstonesDatabaseInitializationFunc is a function defined by individual tombstones_database package and used to create
+// an instance of that tombstones_database
+type TombstonesDatabaseInitializationFunc func(ctx context.Context, uri string) (TombstonesDatabase, error)
+
+// RegisterTombstonesDatabase registers 'scheme' as a key pointing to 'init_func' in an internal lookup table
+// used to create new `TombstonesDatabase` instances by the `NewTombstonesDatabase` method.
+func RegisterTombstonesDatabase(ctx context.Context, scheme string, init_func TombstonesDatabaseInitializationFunc) error {
+
+	err := ensureTombstonesDatabaseRoster()
+
+	if err != nil {
+		return err
+	}
+
+	return tombstones_database_roster.Register(ctx, scheme, init_func)
+}
+
+func ensureTombstonesDatabaseRoster() error {
+
+	if tombstones_database_roster == nil {
+
+		r, err := roster.NewDefaultRoster()
+
+		if err != nil {
+			return err
+		}
+
+		tombstones_database_roster = r
+	}
+
+	return nil
+}
+
+// NewTombstonesDatabase returns a new `TombstonesDatabase` instance configured by 'uri'. The value of 'uri' is parsed
+// as a `url.URL` and its scheme is used as the key for a corresponding `TombstonesDatabaseInitializationFunc`
+// function used to instantiate the new `TombstonesDatabase`. It is assumed that the scheme (and initialization
+// function) have been registered by the `RegisterTombstonesDatabase` method.
+func NewTombstonesDatabase(ctx context.Context, uri string) (TombstonesDatabase, error) {
+
+	u, err := url.Parse(uri)
+
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := u.Scheme
+
+	i, err := tombstones_database_roster.Driver(ctx, scheme)
+
+	if err != nil {
+		return nil, err
+	}
+
+	init_func := i.(TombstonesDatabaseInitializationFunc)
+	return init_func(ctx, uri)
+}
+
+// Schemes returns the list of schemes that have been registered.
+func TombstonesDatabaseSchemes() []string {
+
+	ctx := context.Background()
+	schemes := []string{}
+
+	err := ensureTombstonesDatabaseRoster()
+
+	if err != nil {
+		return schemes
+	}
+
+	for _, dr := range tombstones_database_roster.Drivers(ctx) {
+		scheme := fmt.Sprintf("%s://", strings.ToLower(dr))
+		schemes = append(schemes, scheme)
+	}
+
+	sort.Strings(schemes)
+	return schemes
+}
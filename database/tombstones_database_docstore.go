@@ -0,0 +1,127 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	aa_docstore "github.com/aaronland/gocloud/docstore"
+	"github.com/sfomuseum/go-activitypub"
+	gc_docstore "gocloud.dev/docstore"
+)
+
+type DocstoreTombstonesDatabase struct {
+	TombstonesDatabase
+	collection *gc_docstore.Collection
+}
+
+func init() {
+
+	ctx := context.Background()
+
+	err := RegisterTombstonesDatabase(ctx, "awsdynamodb", NewDocstoreTombstonesDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+
+	for _, scheme := range gc_docstore.DefaultURLMux().CollectionSchemes() {
+		err := RegisterTombstonesDatabase(ctx, scheme, NewDocstoreTombstonesDatabase)
+
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+func NewDocstoreTombstonesDatabase(ctx context.Context, uri string) (TombstonesDatabase, error) {
+
+	col, err := aa_docstore.OpenCollection(ctx, uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open collection, %w", err)
+	}
+
+	db := &DocstoreTombstonesDatabase{
+		collection: col,
+	}
+
+	return db, nil
+}
+
+func (db *DocstoreTombstonesDatabase) GetTombstoneWithId(ctx context.Context, id int64) (*activitypub.Tombstone, error) {
+
+	q := db.collection.Query()
+	q = q.Where("Id", "=", id)
+
+	return db.getTombstone(ctx, q)
+}
+
+func (db *DocstoreTombstonesDatabase) GetTombstoneWithPostId(ctx context.Context, post_id int64) (*activitypub.Tombstone, error) {
+
+	q := db.collection.Query()
+	q = q.Where("PostId", "=", post_id)
+
+	return db.getTombstone(ctx, q)
+}
+
+func (db *DocstoreTombstonesDatabase) GetTombstonesForAccount(ctx context.Context, account_id int64, cb GetTombstonesCallbackFunc) error {
+
+	q := db.collection.Query()
+	q = q.Where("AccountId", "=", account_id)
+
+	iter := q.Get(ctx)
+	defer iter.Stop()
+
+	for {
+
+		var t activitypub.Tombstone
+		err := iter.Next(ctx, &t)
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("Failed to interate, %w", err)
+		} else {
+
+			err := cb(ctx, &t)
+
+			if err != nil {
+				return fmt.Errorf("Failed to execute tombstones callback for '%d', %w", t.Id, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (db *DocstoreTombstonesDatabase) AddTombstone(ctx context.Context, t *activitypub.Tombstone) error {
+
+	return db.collection.Put(ctx, t)
+}
+
+func (db *DocstoreTombstonesDatabase) RemoveTombstone(ctx context.Context, t *activitypub.Tombstone) error {
+
+	return db.collection.Delete(ctx, t)
+}
+
+func (db *DocstoreTombstonesDatabase) Close(ctx context.Context) error {
+	return db.collection.Close()
+}
+
+func (db *DocstoreTombstonesDatabase) getTombstone(ctx context.Context, q *gc_docstore.Query) (*activitypub.Tombstone, error) {
+
+	iter := q.Get(ctx)
+	defer iter.Stop()
+
+	var t activitypub.Tombstone
+	err := iter.Next(ctx, &t)
+
+	if err == io.EOF {
+		return nil, activitypub.ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("Failed to interate, %w", err)
+	} else {
+		return &t, nil
+	}
+}
@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+
+	"github.com/sfomuseum/go-activitypub"
+)
+
+type NullTombstonesDatabase struct {
+	TombstonesDatabase
+}
+
+func init() {
+
+	ctx := context.Background()
+	err := RegisterTombstonesDatabase(ctx, "null", NewNullTombstonesDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func NewNullTombstonesDatabase(ctx context.Context, uri string) (TombstonesDatabase, error) {
+	db := &NullTombstonesDatabase{}
+	return db, nil
+}
+
+func (db *NullTombstonesDatabase) GetTombstoneWithId(ctx context.Context, id int64) (*activitypub.Tombstone, error) {
+	return nil, activitypub.ErrNotFound
+}
+
+func (db *NullTombstonesDatabase) GetTombstoneWithPostId(ctx context.Context, post_id int64) (*activitypub.Tombstone, error) {
+	return nil, activitypub.ErrNotFound
+}
+
+func (db *NullTombstonesDatabase) GetTombstonesForAccount(ctx context.Context, account_id int64, cb GetTombstonesCallbackFunc) error {
+	return nil
+}
+
+func (db *NullTombstonesDatabase) AddTombstone(ctx context.Context, t *activitypub.Tombstone) error {
+	return nil
+}
+
+func (db *NullTombstonesDatabase) RemoveTombstone(ctx context.Context, t *activitypub.Tombstone) error {
+	return nil
+}
+
+func (db *NullTombstonesDatabase) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,200 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	pg_sql "github.com/aaronland/go-pagination-sql"
+	"github.com/aaronland/go-pagination/countable"
+	"github.com/sfomuseum/go-activitypub"
+	sfom_sql "github.com/sfomuseum/go-database/sql"
+)
+
+const SQL_TOMBSTONES_TABLE_NAME string = "tombstones"
+
+type SQLTombstonesDatabase struct {
+	TombstonesDatabase
+	database *sql.DB
+}
+
+func init() {
+
+	ctx := context.Background()
+	err := RegisterTombstonesDatabase(ctx, "sql", NewSQLTombstonesDatabase)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func NewSQLTombstonesDatabase(ctx context.Context, uri string) (TombstonesDatabase, error) {
+
+	conn, err := sfom_sql.OpenWithURI(ctx, uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open database connection, %w", err)
+	}
+
+	db := &SQLTombstonesDatabase{
+		database: conn,
+	}
+
+	return db, nil
+}
+
+func (db *SQLTombstonesDatabase) GetTombstoneWithId(ctx context.Context, id int64) (*activitypub.Tombstone, error) {
+
+	where := "id = ?"
+	return db.getTombstone(ctx, where, id)
+}
+
+func (db *SQLTombstonesDatabase) GetTombstoneWithPostId(ctx context.Context, post_id int64) (*activitypub.Tombstone, error) {
+
+	where := "post_id = ?"
+	return db.getTombstone(ctx, where, post_id)
+}
+
+func (db *SQLTombstonesDatabase) GetTombstonesForAccount(ctx context.Context, account_id int64, cb GetTombstonesCallbackFunc) error {
+
+	pg_callback := func(pg_rsp pg_sql.PaginatedResponse) error {
+
+		rows := pg_rsp.Rows()
+
+		for rows.Next() {
+
+			var id int64
+			var post_id int64
+			var account_id int64
+			var former_type string
+			var published int64
+			var created int64
+			var recipients string
+
+			err := rows.Scan(&id, &post_id, &account_id, &former_type, &published, &created, &recipients)
+
+			if err != nil {
+				return fmt.Errorf("Failed to query database, %w", err)
+			}
+
+			t := &activitypub.Tombstone{
+				Id:         id,
+				PostId:     post_id,
+				AccountId:  account_id,
+				FormerType: former_type,
+				Published:  published,
+				Created:    created,
+				Recipients: splitTombstoneRecipients(recipients),
+			}
+
+			err = cb(ctx, t)
+
+			if err != nil {
+				return fmt.Errorf("Failed to execute tombstones callback for %d, %w", id, err)
+			}
+		}
+
+		err := rows.Close()
+
+		if err != nil {
+			return fmt.Errorf("Failed to iterate through database rows, %w", err)
+		}
+
+		return nil
+	}
+
+	pg_opts, err := countable.NewCountableOptions()
+
+	if err != nil {
+		return fmt.Errorf("Failed to create pagination options, %w", err)
+	}
+
+	q := fmt.Sprintf("SELECT id, post_id, account_id, former_type, published, created, recipients FROM %s WHERE account_id = ? ORDER BY created ASC", SQL_TOMBSTONES_TABLE_NAME)
+
+	err = pg_sql.QueryPaginatedAll(db.database, pg_opts, pg_callback, q, account_id)
+
+	if err != nil {
+		return fmt.Errorf("Failed to execute paginated query, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLTombstonesDatabase) AddTombstone(ctx context.Context, t *activitypub.Tombstone) error {
+
+	q := fmt.Sprintf("INSERT INTO %s (id, post_id, account_id, former_type, published, created, recipients) VALUES (?, ?, ?, ?, ?, ?, ?)", SQL_TOMBSTONES_TABLE_NAME)
+
+	_, err := db.database.ExecContext(ctx, q, t.Id, t.PostId, t.AccountId, t.FormerType, t.Published, t.Created, strings.Join(t.Recipients, ","))
+
+	if err != nil {
+		return fmt.Errorf("Failed to add tombstone, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLTombstonesDatabase) RemoveTombstone(ctx context.Context, t *activitypub.Tombstone) error {
+
+	q := fmt.Sprintf("DELETE FROM %s WHERE id = ?", SQL_TOMBSTONES_TABLE_NAME)
+
+	_, err := db.database.ExecContext(ctx, q, t.Id)
+
+	if err != nil {
+		return fmt.Errorf("Failed to remove tombstone, %w", err)
+	}
+
+	return nil
+}
+
+func (db *SQLTombstonesDatabase) Close(ctx context.Context) error {
+	return db.database.Close()
+}
+
+func (db *SQLTombstonesDatabase) getTombstone(ctx context.Context, where string, args ...interface{}) (*activitypub.Tombstone, error) {
+
+	var id int64
+	var post_id int64
+	var account_id int64
+	var former_type string
+	var published int64
+	var created int64
+	var recipients string
+
+	q := fmt.Sprintf("SELECT id, post_id, account_id, former_type, published, created, recipients FROM %s WHERE %s", SQL_TOMBSTONES_TABLE_NAME, where)
+
+	row := db.database.QueryRowContext(ctx, q, args...)
+
+	err := row.Scan(&id, &post_id, &account_id, &former_type, &published, &created, &recipients)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, activitypub.ErrNotFound
+	case err != nil:
+		return nil, fmt.Errorf("Failed to query database, %w", err)
+	default:
+		//
+	}
+
+	t := &activitypub.Tombstone{
+		Id:         id,
+		PostId:     post_id,
+		AccountId:  account_id,
+		FormerType: former_type,
+		Published:  published,
+		Created:    created,
+		Recipients: splitTombstoneRecipients(recipients),
+	}
+
+	return t, nil
+}
+
+// splitTombstoneRecipients decodes the comma-separated list of recipient addresses stored with a tombstone.
+func splitTombstoneRecipients(str_recipients string) []string {
+
+	if str_recipients == "" {
+		return []string{}
+	}
+
+	return strings.Split(str_recipients, ",")
+}
@@ -8,7 +8,7 @@ import (
 	"time"
 
 	"github.com/sfomuseum/go-activitypub"
-	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/actors"
 	"github.com/sfomuseum/go-activitypub/database"
 	"github.com/sfomuseum/go-activitypub/id"
 	"github.com/sfomuseum/go-activitypub/uris"
@@ -28,6 +28,10 @@ type DeliverActivityOptions struct {
 	DeliveriesDatabase database.DeliveriesDatabase `json:"deliveries_database,omitempty"`
 	// MaxAttempts is the maximum number of times to attempt to deliver the activity.
 	MaxAttempts int `json:"max_attempts"`
+	// ActorsDatabase is an optional `database.ActorsDatabase` instance used to cache the (remote) actors that activities are delivered to.
+	ActorsDatabase database.ActorsDatabase `json:"actors_database,omitempty"`
+	// ActorsTTL is the amount of time that cached actors are considered valid. If zero then cached actors never expire.
+	ActorsTTL time.Duration `json:"actors_ttl,omitempty"`
 }
 
 // DeliveryActivity attempts to deliver an `activitypub.Activity` instance to an external actor.
@@ -54,7 +58,13 @@ func DeliverActivity(ctx context.Context, opts *DeliverActivityOptions) error {
 	// I guess we could just assume that the tail end is the account name but...
 	// Note that in ap.ParseAddressFromRequest we rely on the Go 1.22 net/http
 	// {resource} placeholder to derive the name...
-	actor, err := ap.RetrieveActorWithProfileURL(ctx, from_uri)
+	actor_opts := &actors.RetrieveActorOptions{
+		ActorsDatabase: opts.ActorsDatabase,
+		TTL:            opts.ActorsTTL,
+		Insecure:       opts.URIs.Insecure,
+	}
+
+	actor, err := actors.RetrieveActorWithProfileURL(ctx, actor_opts, from_uri)
 
 	if err != nil {
 		logger.Error("Failed to retrieve actor for profile (from) URI", "error", err)
@@ -144,7 +154,7 @@ func DeliverActivity(ctx context.Context, opts *DeliverActivityOptions) error {
 		}
 	}()
 
-	recipient, err := ap.RetrieveActor(ctx, to, opts.URIs.Insecure)
+	recipient, err := actors.RetrieveActor(ctx, actor_opts, to)
 
 	if err != nil {
 		logger.Error("Failed to retrieve (to) actor", "error", err)
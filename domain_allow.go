@@ -0,0 +1,50 @@
+package activitypub
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sfomuseum/go-activitypub/id"
+)
+
+// DomainAllow is a server-wide allowance for all the actors on a given domain (and its subdomains) used when the server is
+// running in "allowlist" mode and only federates with a curated set of hosts.
+type DomainAllow struct {
+	// The unique ID of the domain allowance.
+	Id int64 `json:"id"`
+	// The domain being allowed.
+	Domain string `json:"domain"`
+	// An optional comment describing the reason (or partner) for the allowance.
+	Comment string `json:"comment,omitempty"`
+	// The Unix timestamp when the domain allowance was created.
+	Created int64 `json:"created"`
+}
+
+// NewDomainAllow returns a new `DomainAllow` instance for 'domain'.
+func NewDomainAllow(ctx context.Context, domain string) (*DomainAllow, error) {
+
+	domain = strings.ToLower(strings.TrimSpace(domain))
+
+	if domain == "" {
+		return nil, fmt.Errorf("Missing domain")
+	}
+
+	allow_id, err := id.NewId()
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create new domain allow ID, %w", err)
+	}
+
+	now := time.Now()
+	ts := now.Unix()
+
+	a := &DomainAllow{
+		Id:      allow_id,
+		Domain:  domain,
+		Created: ts,
+	}
+
+	return a, nil
+}
@@ -0,0 +1,103 @@
+package activitypub
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sfomuseum/go-activitypub/id"
+)
+
+const (
+	// DomainBlockSuspend is the severity for domains whose activities are refused and to which nothing is delivered.
+	DomainBlockSuspend string = "suspend"
+	// DomainBlockSilence is the severity for domains whose activities are only accepted from actors that are already followed
+	// and whose follow requests always require manual approval.
+	DomainBlockSilence string = "silence"
+	// DomainBlockNoop is the severity for domains which are not blocked but whose media may still be rejected (see `DomainBlock.RejectMedia`).
+	DomainBlockNoop string = "noop"
+)
+
+// DomainBlock is a server-wide block for all the actors on a given domain (and its subdomains).
+type DomainBlock struct {
+	// The unique ID of the domain block.
+	Id int64 `json:"id"`
+	// The domain being blocked.
+	Domain string `json:"domain"`
+	// The severity of the block. One of "suspend", "silence" or "noop".
+	Severity string `json:"severity"`
+	// Whether media attachments from the domain should be discarded.
+	RejectMedia bool `json:"reject_media"`
+	// Whether reports from the domain should be ignored.
+	RejectReports bool `json:"reject_reports"`
+	// A comment describing the reason for the block that may be shared publicly.
+	PublicComment string `json:"public_comment,omitempty"`
+	// A comment describing the reason for the block that is only meant for administrators.
+	PrivateComment string `json:"private_comment,omitempty"`
+	// Whether the domain should be obfuscated when the block is shared publicly.
+	Obfuscate bool `json:"obfuscate"`
+	// The Unix timestamp when the domain block was created.
+	Created int64 `json:"created"`
+	// The Unix timestamp when the domain block was last modified.
+	LastModified int64 `json:"lastmodified"`
+}
+
+// NewDomainBlock returns a new `DomainBlock` instance for 'domain' with severity 'severity'.
+func NewDomainBlock(ctx context.Context, domain string, severity string) (*DomainBlock, error) {
+
+	if !IsValidDomainBlockSeverity(severity) {
+		return nil, fmt.Errorf("Invalid domain block severity, %s", severity)
+	}
+
+	domain = strings.ToLower(strings.TrimSpace(domain))
+
+	if domain == "" {
+		return nil, fmt.Errorf("Missing domain")
+	}
+
+	block_id, err := id.NewId()
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create new domain block ID, %w", err)
+	}
+
+	now := time.Now()
+	ts := now.Unix()
+
+	b := &DomainBlock{
+		Id:           block_id,
+		Domain:       domain,
+		Severity:     severity,
+		Created:      ts,
+		LastModified: ts,
+	}
+
+	return b, nil
+}
+
+// IsValidDomainBlockSeverity returns a boolean value indicating whether 'severity' is a valid domain block severity.
+func IsValidDomainBlockSeverity(severity string) bool {
+
+	switch severity {
+	case DomainBlockSuspend, DomainBlockSilence, DomainBlockNoop:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsSuspended returns a boolean value indicating whether the domain blocked by 'b' is suspended.
+func (b *DomainBlock) IsSuspended() bool {
+	return b.Severity == DomainBlockSuspend
+}
+
+// IsSilenced returns a boolean value indicating whether the domain blocked by 'b' is silenced.
+func (b *DomainBlock) IsSilenced() bool {
+	return b.Severity == DomainBlockSilence
+}
+
+// RejectsMedia returns a boolean value indicating whether media attachments from the domain blocked by 'b' should be discarded.
+func (b *DomainBlock) RejectsMedia() bool {
+	return b.RejectMedia || b.IsSuspended()
+}
@@ -9,3 +9,6 @@ var ErrNotImplemented = errors.New("Not implemented")
 
 // ErrNotFound is an error indicating that an item is not present or was not found.
 var ErrNotFound = errors.New("Not found")
+
+// ErrDuplicate is an error indicating that an item with the same unique properties already exists.
+var ErrDuplicate = errors.New("Duplicate")
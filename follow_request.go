@@ -0,0 +1,70 @@
+package activitypub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/id"
+)
+
+// FollowRequest is a request, from an external actor, to follow an account that manually approves followers.
+type FollowRequest struct {
+	// A unique 64-bit ID for the follow request.
+	Id int64 `json:"id"`
+	// The unique 64-bit ID of the account being followed.
+	AccountId int64 `json:"account_id"`
+	// The address of the actor requesting to follow the account.
+	FollowerAddress string `json:"follower_address"`
+	// The unique ID of the ActivityPub "Follow" activity.
+	ActivityId string `json:"activity_id"`
+	// The JSON-encoded body of the ActivityPub "Follow" activity.
+	Body string `json:"body"`
+	// The Unix timestamp when the follow request was created.
+	Created int64 `json:"created"`
+}
+
+// NewFollowRequest returns a new `FollowRequest` instance for 'follow_activity' sent by 'follower_address' to 'account_id'.
+func NewFollowRequest(ctx context.Context, account_id int64, follower_address string, follow_activity *ap.Activity) (*FollowRequest, error) {
+
+	enc_activity, err := json.Marshal(follow_activity)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal follow activity, %w", err)
+	}
+
+	db_id, err := id.NewId()
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create new follow request ID, %w", err)
+	}
+
+	now := time.Now()
+	ts := now.Unix()
+
+	r := &FollowRequest{
+		Id:              db_id,
+		AccountId:       account_id,
+		FollowerAddress: follower_address,
+		ActivityId:      follow_activity.Id,
+		Body:            string(enc_activity),
+		Created:         ts,
+	}
+
+	return r, nil
+}
+
+// UnmarshalActivity returns the unmarshaled "Follow" `*ap.Activity` that is encapsulated by 'r'.
+func (r *FollowRequest) UnmarshalActivity() (*ap.Activity, error) {
+
+	var ap_activity *ap.Activity
+	err := json.Unmarshal([]byte(r.Body), &ap_activity)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal activity, %w", err)
+	}
+
+	return ap_activity, nil
+}
@@ -0,0 +1,227 @@
+package followers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/blocks"
+	"github.com/sfomuseum/go-activitypub/database"
+	"github.com/sfomuseum/go-activitypub/queue"
+	"github.com/sfomuseum/go-activitypub/uris"
+)
+
+// FollowRequestOptions defines configuration options for approving or rejecting pending follow requests.
+type FollowRequestOptions struct {
+	// FollowersDatabase is the database where approved followers are stored.
+	FollowersDatabase database.FollowersDatabase
+	// FollowRequestsDatabase is the database where pending follow requests are stored.
+	FollowRequestsDatabase database.FollowRequestsDatabase
+	// ProcessFollowerQueue is an optional queue used to schedule custom post-processing for approved followers.
+	ProcessFollowerQueue queue.ProcessFollowerQueue
+	// URIs is the `uris.URIs` instance used to derive account and activity URLs.
+	URIs *uris.URIs
+	// DomainBlocksDatabase is an optional database of server-wide domain blocks. Follow requests from suspended domains can not be approved.
+	DomainBlocksDatabase database.DomainBlocksDatabase
+	// DomainAllowsDatabase is an optional database of allowed domains. If present "Accept" and "Reject" activities are only delivered to actors on those domains.
+	DomainAllowsDatabase database.DomainAllowsDatabase
+	// AccountsDatabase is the database used to look up the account delivering "Accept" and "Reject" activities.
+	AccountsDatabase database.AccountsDatabase
+	// DeliveriesDatabase is the database where the delivery of "Accept" and "Reject" activities is logged.
+	DeliveriesDatabase database.DeliveriesDatabase
+	// DeliveryQueue is the queue used to schedule the delivery of "Accept" and "Reject" activities.
+	DeliveryQueue queue.DeliveryQueue
+	// MaxAttempts is the maximum number of attempts to deliver "Accept" and "Reject" activities.
+	MaxAttempts int
+}
+
+// AddFollowRequest records a pending request, from 'follower_address', to follow 'account_id' (using 'follow_activity').
+// If a pending request for the same account and follower already exists it will be returned instead.
+func AddFollowRequest(ctx context.Context, db database.FollowRequestsDatabase, account_id int64, follower_address string, follow_activity *ap.Activity) (*activitypub.FollowRequest, error) {
+
+	r, err := db.GetFollowRequest(ctx, account_id, follower_address)
+
+	if err == nil {
+		return r, nil
+	}
+
+	if err != activitypub.ErrNotFound {
+		return nil, fmt.Errorf("Failed to retrieve existing follow request, %w", err)
+	}
+
+	r, err = activitypub.NewFollowRequest(ctx, account_id, follower_address, follow_activity)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create new follow request, %w", err)
+	}
+
+	err = db.AddFollowRequest(ctx, r)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to add follow request, %w", err)
+	}
+
+	return r, nil
+}
+
+// ApproveFollowRequest adds the actor associated with 'r' as a follower of 'acct', schedules an "Accept" activity for delivery
+// to that actor and then removes 'r' from the database of pending follow requests. If the "Accept" activity can not be scheduled the
+// newly created follower will be removed and 'r' will be left in place so that it can be approved again later. Follow
+// requests from actors on suspended domains can not be approved.
+func ApproveFollowRequest(ctx context.Context, opts *FollowRequestOptions, acct *activitypub.Account, r *activitypub.FollowRequest) error {
+
+	if r.AccountId != acct.Id {
+		return fmt.Errorf("Follow request is not associated with account")
+	}
+
+	host, err := blocks.HostFromAddress(r.FollowerAddress)
+
+	if err != nil {
+		return fmt.Errorf("Failed to derive host for %s, %w", r.FollowerAddress, err)
+	}
+
+	is_suspended, err := blocks.IsSuspendedHost(ctx, opts.DomainBlocksDatabase, host)
+
+	if err != nil {
+		return fmt.Errorf("Failed to determine if %s is suspended, %w", host, err)
+	}
+
+	if is_suspended {
+		return fmt.Errorf("Follow request is from a suspended domain (%s)", host)
+	}
+
+	logger := slog.Default()
+	logger = logger.With("account", acct.Name)
+	logger = logger.With("follow request", r.Id)
+	logger = logger.With("follower", r.FollowerAddress)
+
+	is_follower, _, err := IsFollower(ctx, opts.FollowersDatabase, acct.Id, r.FollowerAddress)
+
+	if err != nil {
+		return fmt.Errorf("Failed to determine if %s is already a follower, %w", r.FollowerAddress, err)
+	}
+
+	follower_id := int64(-1)
+
+	if !is_follower {
+
+		id, err := AddFollower(ctx, opts.FollowersDatabase, acct.Id, r.FollowerAddress)
+
+		if err != nil {
+			return fmt.Errorf("Failed to add follower, %w", err)
+		}
+
+		follower_id = id
+		logger = logger.With("follower id", follower_id)
+	}
+
+	err = respondToFollowRequest(ctx, opts, acct, r, ap.ACCEPT_ACTIVITY)
+
+	if err != nil {
+
+		if follower_id > -1 {
+
+			f, f_err := GetFollower(ctx, opts.FollowersDatabase, acct.Id, r.FollowerAddress)
+
+			if f_err != nil {
+				logger.Error("Failed to retrieve newly created follower to remove", "error", f_err)
+			} else {
+
+				f_err = opts.FollowersDatabase.RemoveFollower(ctx, f)
+
+				if f_err != nil {
+					logger.Error("Failed to remove follower", "error", f_err)
+				}
+			}
+		}
+
+		return err
+	}
+
+	if follower_id > -1 && opts.ProcessFollowerQueue != nil {
+
+		err = opts.ProcessFollowerQueue.ProcessFollower(ctx, follower_id)
+
+		if err != nil {
+			logger.Error("Failed to queue process follower job", "error", err)
+		}
+	}
+
+	return nil
+}
+
+// RejectFollowRequest schedules a "Reject" activity for delivery to the actor associated with 'r' and then removes 'r' from the
+// database of pending follow requests.
+func RejectFollowRequest(ctx context.Context, opts *FollowRequestOptions, acct *activitypub.Account, r *activitypub.FollowRequest) error {
+
+	if r.AccountId != acct.Id {
+		return fmt.Errorf("Follow request is not associated with account")
+	}
+
+	return respondToFollowRequest(ctx, opts, acct, r, ap.REJECT_ACTIVITY)
+}
+
+// respondToFollowRequest schedules an "Accept" or "Reject" activity (defined by 'activity_type') for the "Follow" activity
+// encapsulated by 'r' for delivery to the actor that sent it, using 'opts.DeliveryQueue', and then removes 'r' from the database
+// of pending follow requests.
+func respondToFollowRequest(ctx context.Context, opts *FollowRequestOptions, acct *activitypub.Account, r *activitypub.FollowRequest, activity_type string) error {
+
+	follow_activity, err := r.UnmarshalActivity()
+
+	if err != nil {
+		return fmt.Errorf("Failed to derive follow activity from request, %w", err)
+	}
+
+	from := acct.AccountURL(ctx, opts.URIs).String()
+
+	var response *ap.Activity
+
+	switch activity_type {
+	case ap.ACCEPT_ACTIVITY:
+		response, err = ap.NewAcceptActivity(ctx, opts.URIs, from, follow_activity)
+	case ap.REJECT_ACTIVITY:
+		response, err = ap.NewRejectActivity(ctx, opts.URIs, from, follow_activity)
+	default:
+		return fmt.Errorf("Invalid or unsupported activity type, %s", activity_type)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed to create new %s activity, %w", activity_type, err)
+	}
+
+	activity, err := activitypub.NewActivity(ctx, response)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create new AP wrapper, %w", err)
+	}
+
+	activity.AccountId = acct.Id
+
+	deliver_opts := &queue.DeliverActivityToRecipientsOptions{
+		AccountsDatabase:     opts.AccountsDatabase,
+		DeliveriesDatabase:   opts.DeliveriesDatabase,
+		DeliveryQueue:        opts.DeliveryQueue,
+		Activity:             activity,
+		Recipients:           []string{r.FollowerAddress},
+		MaxAttempts:          opts.MaxAttempts,
+		URIs:                 opts.URIs,
+		DomainBlocksDatabase: opts.DomainBlocksDatabase,
+		DomainAllowsDatabase: opts.DomainAllowsDatabase,
+	}
+
+	err = queue.DeliverActivityToRecipients(ctx, deliver_opts)
+
+	if err != nil {
+		return fmt.Errorf("Failed to deliver %s activity to %s, %w", activity_type, r.FollowerAddress, err)
+	}
+
+	err = opts.FollowRequestsDatabase.RemoveFollowRequest(ctx, r)
+
+	if err != nil {
+		return fmt.Errorf("Failed to remove follow request, %w", err)
+	}
+
+	return nil
+}
@@ -8,11 +8,29 @@ import (
 	"github.com/sfomuseum/go-activitypub/id"
 )
 
+const (
+	// AcceptedFollowingState indicates that a follow request has been accepted. It is the default (zero) value so that
+	// following records created before follow requests were tracked are treated as accepted.
+	AcceptedFollowingState FollowingState = iota
+	// PendingFollowingState indicates that a follow request has been sent but not yet accepted or rejected.
+	PendingFollowingState
+	// RejectedFollowingState indicates that a follow request has been rejected.
+	RejectedFollowingState
+)
+
+// FollowingState is the state of a follow request sent by an account.
+type FollowingState int
+
 type Following struct {
 	Id               int64  `json:"id"`
 	AccountId        int64  `json:"account_id"`
 	FollowingAddress string `json:"following_address"`
-	Created          int64  `json:"created"`
+	// The unique ID of the ActivityPub "Follow" activity used to request the follow.
+	ActivityId string `json:"activity_id"`
+	// The state of the follow request.
+	State        FollowingState `json:"state"`
+	Created      int64          `json:"created"`
+	LastModified int64          `json:"lastmodified"`
 }
 
 func NewFollowing(ctx context.Context, account_id int64, following_address string) (*Following, error) {
@@ -30,8 +48,15 @@ func NewFollowing(ctx context.Context, account_id int64, following_address strin
 		Id:               db_id,
 		AccountId:        account_id,
 		FollowingAddress: following_address,
+		State:            PendingFollowingState,
 		Created:          ts,
+		LastModified:     ts,
 	}
 
 	return b, nil
 }
+
+// IsAccepted returns a boolean value indicating whether the follow request has been accepted.
+func (f *Following) IsAccepted() bool {
+	return f.State == AcceptedFollowingState
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync/atomic"
+	"time"
 
 	"github.com/sfomuseum/go-activitypub"
 	"github.com/sfomuseum/go-activitypub/ap"
@@ -45,7 +46,28 @@ func AddFollowing(ctx context.Context, db database.FollowingDatabase, account_id
 	return db.AddFollowing(ctx, f)
 }
 
-// Is account_id following following_address?
+// UpdateFollowingState assigns 'state' to 'f', updates its last modified time and saves the result in 'db'.
+func UpdateFollowingState(ctx context.Context, db database.FollowingDatabase, f *activitypub.Following, state activitypub.FollowingState) (*activitypub.Following, error) {
+
+	now := time.Now()
+	ts := now.Unix()
+
+	f.State = state
+	f.LastModified = ts
+
+	err := db.UpdateFollowing(ctx, f)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to update following, %w", err)
+	}
+
+	return f, nil
+}
+
+// IsFollowing returns a boolean value indicating whether there is a following record for 'account_id' and 'following_address'
+// and, if so, the record itself. Records are returned regardless of their state, including pending and rejected follow requests,
+// so callers that need to know whether 'account_id' is actually following 'following_address' must check the record's `State`
+// property (or its `IsAccepted` method).
 func IsFollowing(ctx context.Context, db database.FollowingDatabase, account_id int64, following_address string) (bool, *activitypub.Following, error) {
 
 	f, err := GetFollowing(ctx, db, account_id, following_address)
@@ -0,0 +1,33 @@
+package activitypub
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFollowingState(t *testing.T) {
+
+	ctx := context.Background()
+
+	// Following records created before follow requests were tracked have no state
+
+	var f Following
+
+	if !f.IsAccepted() {
+		t.Fatalf("Expected following with no state to be accepted")
+	}
+
+	new_f, err := NewFollowing(ctx, 1, "bob@example.com")
+
+	if err != nil {
+		t.Fatalf("Failed to create new following, %v", err)
+	}
+
+	if new_f.IsAccepted() {
+		t.Fatalf("Expected new following to be pending")
+	}
+
+	if new_f.State != PendingFollowingState {
+		t.Fatalf("Unexpected state for new following, %d", new_f.State)
+	}
+}
@@ -12,11 +12,17 @@ import (
 // post.go and boost.go). Specifically this data and the correspinding `LikesDatabase` was created
 // to record likes from external actors about posts created by accounts on this server. It is not
 // currently suited to record or deliver likes of external posts made by accounts on this server.
+//
+// Likes are also used to record emoji reactions, either "EmojiReact" activities or "Like" activities with
+// a "content" property (as sent by Misskey, Pleroma and Akkoma). In those cases `Emoji` is the emoji (or
+// custom emoji shortcode, for example ":blobcat:") and `EmojiURL` is the URL of the image for custom emoji.
 type Like struct {
 	Id        int64  `json:"id"`
 	AccountId int64  `json:"account_id"`
 	PostId    int64  `json:"post_id"`
 	Actor     string `json:"actor"`
+	Emoji     string `json:"emoji,omitempty"`
+	EmojiURL  string `json:"emoji_url,omitempty"`
 	Created   int64  `json:"created"`
 }
 
@@ -41,3 +47,24 @@ func NewLike(ctx context.Context, post *Post, actor string) (*Like, error) {
 
 	return l, nil
 }
+
+// NewEmojiReaction returns a new `Like` instance recording an emoji reaction by 'actor' to 'post'. 'emoji_url' is
+// the URL of the image for custom emoji and may be empty.
+func NewEmojiReaction(ctx context.Context, post *Post, actor string, emoji string, emoji_url string) (*Like, error) {
+
+	l, err := NewLike(ctx, post, actor)
+
+	if err != nil {
+		return nil, err
+	}
+
+	l.Emoji = emoji
+	l.EmojiURL = emoji_url
+
+	return l, nil
+}
+
+// IsEmojiReaction returns a boolean value indicating whether 'l' is an emoji reaction rather than a plain like.
+func (l *Like) IsEmojiReaction() bool {
+	return l.Emoji != ""
+}
@@ -0,0 +1,75 @@
+package activitypub
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sfomuseum/go-activitypub/id"
+)
+
+// Media is a file (typically an image) attached to a post.
+type Media struct {
+	// The unique ID for the media attachment.
+	Id int64 `json:"id"`
+	// The unique ID of the post the media is attached to.
+	PostId int64 `json:"post_id"`
+	// The AccountsDatabase ID of the author of the post.
+	AccountId int64 `json:"account_id"`
+	// URI is a valid `gocloud.dev/blob` URI (as in the bucket URI + filename) referencing the media file.
+	URI string `json:"uri"`
+	// The (MIME) media type of the file, for example "image/jpeg".
+	MediaType string `json:"media_type"`
+	// A description (alt text) of the media.
+	Description string `json:"description"`
+	// The width of the media in pixels, if known.
+	Width int `json:"width"`
+	// The height of the media in pixels, if known.
+	Height int `json:"height"`
+	// The horizontal position of the focal point of the media, from -1.0 (left) to 1.0 (right).
+	FocalPointX float64 `json:"focal_point_x"`
+	// The vertical position of the focal point of the media, from -1.0 (bottom) to 1.0 (top).
+	FocalPointY float64 `json:"focal_point_y"`
+	// A compact (blurhash) representation of the media used as a placeholder while it loads.
+	Blurhash string `json:"blurhash"`
+	// The Unix timestamp when the media was created.
+	Created int64 `json:"created"`
+}
+
+// NewMedia returns a new `Media` instance attached to 'post'.
+func NewMedia(ctx context.Context, post *Post) (*Media, error) {
+
+	media_id, err := id.NewId()
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive new media ID, %w", err)
+	}
+
+	now := time.Now()
+	ts := now.Unix()
+
+	m := &Media{
+		Id:        media_id,
+		PostId:    post.Id,
+		AccountId: post.AccountId,
+		Created:   ts,
+	}
+
+	return m, nil
+}
+
+// IsImage returns a boolean value indicating whether 'm' is an image.
+func (m *Media) IsImage() bool {
+	return strings.HasPrefix(m.MediaType, "image/")
+}
+
+// IsVideo returns a boolean value indicating whether 'm' is a video.
+func (m *Media) IsVideo() bool {
+	return strings.HasPrefix(m.MediaType, "video/")
+}
+
+// IsAudio returns a boolean value indicating whether 'm' is an audio file.
+func (m *Media) IsAudio() bool {
+	return strings.HasPrefix(m.MediaType, "audio/")
+}
@@ -0,0 +1,185 @@
+package media
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+// The maximum width or height of the (scaled) image used to derive a blurhash. Blurhashes only
+// capture a handful of components so there is no benefit to processing every pixel of large images.
+const blurhash_max_dimension int = 64
+
+const blurhash_characters string = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// Blurhash returns the blurhash (https://blurha.sh/) representation of 'im' using 'x_components'
+// horizontal and 'y_components' vertical components, each of which must be between 1 and 9.
+func Blurhash(im image.Image, x_components int, y_components int) (string, error) {
+
+	if x_components < 1 || x_components > 9 || y_components < 1 || y_components > 9 {
+		return "", fmt.Errorf("Blurhash components must be between 1 and 9")
+	}
+
+	bounds := im.Bounds()
+	w := bounds.Dx()
+	h := bounds.Dy()
+
+	if w == 0 || h == 0 {
+		return "", fmt.Errorf("Image has no dimensions")
+	}
+
+	if w > blurhash_max_dimension || h > blurhash_max_dimension {
+
+		scale := float64(blurhash_max_dimension) / math.Max(float64(w), float64(h))
+
+		w = int(math.Max(1, math.Round(float64(w)*scale)))
+		h = int(math.Max(1, math.Round(float64(h)*scale)))
+
+		dst := image.NewRGBA(image.Rect(0, 0, w, h))
+		draw.ApproxBiLinear.Scale(dst, dst.Bounds(), im, bounds, draw.Src, nil)
+
+		im = dst
+		bounds = dst.Bounds()
+	}
+
+	// Convert the image to linear RGB once, rather than for every component
+
+	linear := make([][3]float64, w*h)
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+
+			r, g, b, _ := im.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+
+			linear[y*w+x] = [3]float64{
+				srgbToLinear(int(r >> 8)),
+				srgbToLinear(int(g >> 8)),
+				srgbToLinear(int(b >> 8)),
+			}
+		}
+	}
+
+	factors := make([][3]float64, x_components*y_components)
+
+	for j := 0; j < y_components; j++ {
+		for i := 0; i < x_components; i++ {
+
+			normalisation := 2.0
+
+			if i == 0 && j == 0 {
+				normalisation = 1.0
+			}
+
+			var r, g, b float64
+
+			for y := 0; y < h; y++ {
+
+				cos_y := math.Cos(math.Pi * float64(j) * float64(y) / float64(h))
+
+				for x := 0; x < w; x++ {
+
+					basis := normalisation * math.Cos(math.Pi*float64(i)*float64(x)/float64(w)) * cos_y
+					px := linear[y*w+x]
+
+					r += basis * px[0]
+					g += basis * px[1]
+					b += basis * px[2]
+				}
+			}
+
+			scale := 1.0 / float64(w*h)
+			factors[j*x_components+i] = [3]float64{r * scale, g * scale, b * scale}
+		}
+	}
+
+	var sb strings.Builder
+
+	size_flag := (x_components - 1) + (y_components-1)*9
+	sb.WriteString(encode83(size_flag, 1))
+
+	dc := factors[0]
+	ac := factors[1:]
+
+	maximum_value := 1.0
+
+	if len(ac) > 0 {
+
+		actual_max := 0.0
+
+		for _, f := range ac {
+			for _, v := range f {
+				actual_max = math.Max(actual_max, math.Abs(v))
+			}
+		}
+
+		quantised_max := int(math.Max(0, math.Min(82, math.Floor(actual_max*166-0.5))))
+		maximum_value = float64(quantised_max+1) / 166
+
+		sb.WriteString(encode83(quantised_max, 1))
+
+	} else {
+		sb.WriteString(encode83(0, 1))
+	}
+
+	sb.WriteString(encode83(encodeDC(dc), 4))
+
+	for _, f := range ac {
+		sb.WriteString(encode83(encodeAC(f, maximum_value), 2))
+	}
+
+	return sb.String(), nil
+}
+
+func encodeDC(v [3]float64) int {
+	return (linearToSrgb(v[0]) << 16) + (linearToSrgb(v[1]) << 8) + linearToSrgb(v[2])
+}
+
+func encodeAC(v [3]float64, maximum_value float64) int {
+
+	quant := func(c float64) int {
+		return int(math.Max(0, math.Min(18, math.Floor(signPow(c/maximum_value, 0.5)*9+9.5))))
+	}
+
+	return quant(v[0])*19*19 + quant(v[1])*19 + quant(v[2])
+}
+
+func encode83(value int, length int) string {
+
+	var sb strings.Builder
+
+	for i := 1; i <= length; i++ {
+		digit := (value / int(math.Pow(83, float64(length-i)))) % 83
+		sb.WriteByte(blurhash_characters[digit])
+	}
+
+	return sb.String()
+}
+
+func srgbToLinear(v int) float64 {
+
+	c := float64(v) / 255
+
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+func linearToSrgb(v float64) int {
+
+	c := math.Max(0, math.Min(1, v))
+
+	if c <= 0.0031308 {
+		return int(c*12.92*255 + 0.5)
+	}
+
+	return int((1.055*math.Pow(c, 1/2.4)-0.055)*255 + 0.5)
+}
+
+func signPow(v float64, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(v), exp), v)
+}
@@ -0,0 +1,288 @@
+// Package media provides methods for storing and retrieving the media files (attachments) associated with posts.
+package media
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/aaronland/gocloud/blob/bucket"
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/database"
+	"github.com/sfomuseum/go-activitypub/uris"
+	"gocloud.dev/blob"
+	"gocloud.dev/gcerrors"
+)
+
+// The number of horizontal and vertical components used to derive blurhashes for images.
+const BLURHASH_X_COMPONENTS int = 4
+const BLURHASH_Y_COMPONENTS int = 3
+
+type AddMediaOptions struct {
+	// A valid `gocloud.dev/blob` bucket URI where media files are stored.
+	BucketURI     string
+	MediaDatabase database.MediaDatabase
+}
+
+// AddMedia reads the file at 'path' and stores it, and a new `activitypub.Media` record describing it, as an attachment for 'post'.
+func AddMedia(ctx context.Context, opts *AddMediaOptions, post *activitypub.Post, path string, description string) (*activitypub.Media, error) {
+
+	r, err := os.Open(path)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open %s for reading, %w", path, err)
+	}
+
+	defer r.Close()
+
+	return AddMediaWithReader(ctx, opts, post, r, filepath.Base(path), description)
+}
+
+// AddMediaWithReader stores the body of 'r' (whose filename is 'filename'), and a new `activitypub.Media` record describing it, as an
+// attachment for 'post'. The media type of the file is derived from 'filename' or, failing that, its contents. The dimensions and
+// blurhash of images are derived from the image itself.
+func AddMediaWithReader(ctx context.Context, opts *AddMediaOptions, post *activitypub.Post, r io.Reader, filename string, description string) (*activitypub.Media, error) {
+
+	data, err := io.ReadAll(r)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read %s, %w", filename, err)
+	}
+
+	m, err := activitypub.NewMedia(ctx, post)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create new media, %w", err)
+	}
+
+	logger := slog.Default()
+	logger = logger.With("post id", post.Id)
+	logger = logger.With("media id", m.Id)
+	logger = logger.With("filename", filename)
+
+	ext := strings.ToLower(filepath.Ext(filename))
+
+	media_type := mime.TypeByExtension(ext)
+
+	if media_type == "" {
+		media_type = http.DetectContentType(data)
+	}
+
+	media_type, _, err = mime.ParseMediaType(media_type)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse media type for %s, %w", filename, err)
+	}
+
+	m.MediaType = media_type
+	m.Description = description
+
+	if m.IsImage() {
+
+		// Not being able to decode an image (for example a format for which there is no
+		// registered decoder) is not fatal; the image is still attached but without its
+		// dimensions or a blurhash.
+
+		im, _, err := image.Decode(bytes.NewReader(data))
+
+		if err != nil {
+			logger.Warn("Failed to decode image, dimensions and blurhash will not be recorded", "error", err)
+		} else {
+
+			bounds := im.Bounds()
+			m.Width = bounds.Dx()
+			m.Height = bounds.Dy()
+
+			hash, err := Blurhash(im, BLURHASH_X_COMPONENTS, BLURHASH_Y_COMPONENTS)
+
+			if err != nil {
+				logger.Warn("Failed to derive blurhash for image", "error", err)
+			} else {
+				m.Blurhash = hash
+			}
+		}
+	}
+
+	key := fmt.Sprintf("%d%s", m.Id, ext)
+
+	b, err := bucket.OpenBucket(ctx, opts.BucketURI)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open bucket, %w", err)
+	}
+
+	defer b.Close()
+
+	wr_opts := &blob.WriterOptions{
+		ContentType: m.MediaType,
+	}
+
+	err = b.WriteAll(ctx, key, data, wr_opts)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to write media file, %w", err)
+	}
+
+	media_uri, err := mediaURI(opts.BucketURI, key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	m.URI = media_uri
+
+	err = opts.MediaDatabase.AddMedia(ctx, m)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to add media, %w", err)
+	}
+
+	logger.Debug("Added media", "uri", m.URI, "media type", m.MediaType)
+	return m, nil
+}
+
+// RemoveMedia removes the file associated with 'm' from its bucket and then removes 'm' from 'media_db'.
+func RemoveMedia(ctx context.Context, media_db database.MediaDatabase, m *activitypub.Media) error {
+
+	bucket_uri, key, err := bucket.ParseURI(m.URI)
+
+	if err != nil {
+		return fmt.Errorf("Failed to parse media URI, %w", err)
+	}
+
+	b, err := bucket.OpenBucket(ctx, bucket_uri)
+
+	if err != nil {
+		return fmt.Errorf("Failed to open bucket, %w", err)
+	}
+
+	defer b.Close()
+
+	err = b.Delete(ctx, key)
+
+	if err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+		return fmt.Errorf("Failed to remove media file, %w", err)
+	}
+
+	err = media_db.RemoveMedia(ctx, m)
+
+	if err != nil {
+		return fmt.Errorf("Failed to remove media, %w", err)
+	}
+
+	return nil
+}
+
+// NewReader returns a new `io.ReadCloser` instance for reading the file associated with 'm'.
+func NewReader(ctx context.Context, m *activitypub.Media) (io.ReadCloser, error) {
+
+	bucket_uri, key, err := bucket.ParseURI(m.URI)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse media URI, %w", err)
+	}
+
+	b, err := bucket.OpenBucket(ctx, bucket_uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open bucket, %w", err)
+	}
+
+	r, err := b.NewReader(ctx, key, nil)
+
+	if err != nil {
+		b.Close()
+		return nil, fmt.Errorf("Failed to open media file for reading, %w", err)
+	}
+
+	rsp := &mediaReader{
+		Reader: r,
+		bucket: b,
+	}
+
+	return rsp, nil
+}
+
+// GetMediaForPost returns the list of `activitypub.Media` instances attached to 'post_id', in the order they were added.
+func GetMediaForPost(ctx context.Context, media_db database.MediaDatabase, post_id int64) ([]*activitypub.Media, error) {
+
+	attachments := make([]*activitypub.Media, 0)
+
+	media_cb := func(ctx context.Context, m *activitypub.Media) error {
+		attachments = append(attachments, m)
+		return nil
+	}
+
+	err := media_db.GetMediaForPost(ctx, post_id, media_cb)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve media for post, %w", err)
+	}
+
+	return attachments, nil
+}
+
+// AttachmentFromMedia returns a new (ActivityPub) `Attachment` instance derived from 'acct' and 'm'. Images are
+// returned as "Image" attachments and everything else as "Document" attachments.
+func AttachmentFromMedia(ctx context.Context, uris_table *uris.URIs, acct *activitypub.Account, m *activitypub.Media) *ap.Attachment {
+
+	media_url := acct.MediaURL(ctx, uris_table, m)
+
+	a := &ap.Attachment{
+		Type:      "Document",
+		MediaType: m.MediaType,
+		Name:      m.Description,
+		URL:       media_url.String(),
+		Width:     m.Width,
+		Height:    m.Height,
+		Blurhash:  m.Blurhash,
+	}
+
+	if m.IsImage() {
+		a.Type = "Image"
+		a.FocalPoint = []float64{m.FocalPointX, m.FocalPointY}
+	}
+
+	return a
+}
+
+// mediaURI returns a `gocloud.dev/blob` URI (as in the bucket URI + filename) for 'key' in 'bucket_uri'.
+func mediaURI(bucket_uri string, key string) (string, error) {
+
+	u, err := url.Parse(bucket_uri)
+
+	if err != nil {
+		return "", fmt.Errorf("Failed to parse bucket URI, %w", err)
+	}
+
+	u.Path = path.Join("/", u.Path, key)
+	return u.String(), nil
+}
+
+// mediaReader closes the bucket a media file was read from when the reader is closed.
+type mediaReader struct {
+	*blob.Reader
+	bucket *blob.Bucket
+}
+
+func (r *mediaReader) Close() error {
+
+	err := r.Reader.Close()
+	r.bucket.Close()
+
+	return err
+}
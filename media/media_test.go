@@ -0,0 +1,158 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"testing"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/database"
+	"github.com/sfomuseum/go-activitypub/uris"
+	_ "gocloud.dev/blob/fileblob"
+)
+
+type testMediaDatabase struct {
+	database.MediaDatabase
+	media map[int64]*activitypub.Media
+}
+
+func (db *testMediaDatabase) AddMedia(ctx context.Context, m *activitypub.Media) error {
+	db.media[m.Id] = m
+	return nil
+}
+
+func (db *testMediaDatabase) RemoveMedia(ctx context.Context, m *activitypub.Media) error {
+	delete(db.media, m.Id)
+	return nil
+}
+
+func TestBlurhash(t *testing.T) {
+
+	im := image.NewRGBA(image.Rect(0, 0, 100, 80))
+	draw.Draw(im, im.Bounds(), &image.Uniform{color.RGBA{255, 0, 0, 255}}, image.Point{}, draw.Src)
+
+	hash, err := Blurhash(im, BLURHASH_X_COMPONENTS, BLURHASH_Y_COMPONENTS)
+
+	if err != nil {
+		t.Fatalf("Failed to derive blurhash, %v", err)
+	}
+
+	// 4 x 3 components: one size flag, one maximum AC value, four DC characters and two characters for each of the 11 AC components
+
+	if len(hash) != 28 {
+		t.Fatalf("Unexpected blurhash length, %d (%s)", len(hash), hash)
+	}
+
+	if hash[0:1] != "L" {
+		t.Fatalf("Unexpected size flag, %s", hash[0:1])
+	}
+
+	dc := encode83(0xFF0000, 4)
+
+	if hash[2:6] != dc {
+		t.Fatalf("Unexpected DC component, expected %s but got %s", dc, hash[2:6])
+	}
+
+	_, err = Blurhash(im, 0, 3)
+
+	if err == nil {
+		t.Fatalf("Expected invalid number of components to fail")
+	}
+}
+
+func TestAddMedia(t *testing.T) {
+
+	ctx := context.Background()
+
+	uris_table := uris.DefaultURIs()
+	uris_table.Hostname = "example.com"
+
+	acct := &activitypub.Account{
+		Id:   1234,
+		Name: "alice",
+	}
+
+	post := &activitypub.Post{
+		Id:        5678,
+		AccountId: acct.Id,
+	}
+
+	media_db := &testMediaDatabase{
+		media: make(map[int64]*activitypub.Media),
+	}
+
+	opts := &AddMediaOptions{
+		BucketURI:     fmt.Sprintf("file://%s", t.TempDir()),
+		MediaDatabase: media_db,
+	}
+
+	im := image.NewRGBA(image.Rect(0, 0, 40, 30))
+	draw.Draw(im, im.Bounds(), &image.Uniform{color.RGBA{0, 128, 255, 255}}, image.Point{}, draw.Src)
+
+	var buf bytes.Buffer
+
+	err := png.Encode(&buf, im)
+
+	if err != nil {
+		t.Fatalf("Failed to encode image, %v", err)
+	}
+
+	m, err := AddMediaWithReader(ctx, opts, post, bytes.NewReader(buf.Bytes()), "example.png", "A blue rectangle")
+
+	if err != nil {
+		t.Fatalf("Failed to add media, %v", err)
+	}
+
+	if m.PostId != post.Id || m.AccountId != acct.Id || m.MediaType != "image/png" || m.Width != 40 || m.Height != 30 || m.Blurhash == "" {
+		t.Fatalf("Unexpected media, %v", m)
+	}
+
+	if len(media_db.media) != 1 {
+		t.Fatalf("Expected media to be recorded")
+	}
+
+	r, err := NewReader(ctx, m)
+
+	if err != nil {
+		t.Fatalf("Failed to open media for reading, %v", err)
+	}
+
+	body, err := io.ReadAll(r)
+	r.Close()
+
+	if err != nil {
+		t.Fatalf("Failed to read media, %v", err)
+	}
+
+	if !bytes.Equal(body, buf.Bytes()) {
+		t.Fatalf("Media file does not match the original")
+	}
+
+	a := AttachmentFromMedia(ctx, uris_table, acct, m)
+
+	if a.Type != "Image" || a.Name != "A blue rectangle" || a.URL != acct.MediaURL(ctx, uris_table, m).String() || len(a.FocalPoint) != 2 {
+		t.Fatalf("Unexpected attachment, %v", a)
+	}
+
+	err = RemoveMedia(ctx, media_db, m)
+
+	if err != nil {
+		t.Fatalf("Failed to remove media, %v", err)
+	}
+
+	if len(media_db.media) != 0 {
+		t.Fatalf("Expected media to be removed")
+	}
+
+	_, err = NewReader(ctx, m)
+
+	if err == nil {
+		t.Fatalf("Expected media file to be removed")
+	}
+}
@@ -3,6 +3,7 @@ package notes
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/sfomuseum/go-activitypub"
 	"github.com/sfomuseum/go-activitypub/database"
@@ -24,3 +25,56 @@ func AddNote(ctx context.Context, db database.NotesDatabase, uuid string, author
 
 	return n, nil
 }
+
+// UpdateNote replaces the body of 'n' with 'body', updates its last modified time and saves the result in 'db'.
+func UpdateNote(ctx context.Context, db database.NotesDatabase, n *activitypub.Note, body string) (*activitypub.Note, error) {
+
+	now := time.Now()
+	ts := now.Unix()
+
+	n.Body = body
+	n.LastModified = ts
+
+	err := db.UpdateNote(ctx, n)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to update note, %w", err)
+	}
+
+	return n, nil
+}
+
+// RemoveNote removes 'n' from 'notes_db' as well as any messages (in 'messages_db') which
+// point to that note.
+func RemoveNote(ctx context.Context, notes_db database.NotesDatabase, messages_db database.MessagesDatabase, n *activitypub.Note) error {
+
+	to_remove := make([]*activitypub.Message, 0)
+
+	messages_cb := func(ctx context.Context, m *activitypub.Message) error {
+		to_remove = append(to_remove, m)
+		return nil
+	}
+
+	err := messages_db.GetMessagesForNote(ctx, n.Id, messages_cb)
+
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve messages for note, %w", err)
+	}
+
+	for _, m := range to_remove {
+
+		err := messages_db.RemoveMessage(ctx, m)
+
+		if err != nil {
+			return fmt.Errorf("Failed to remove message %d, %w", m.Id, err)
+		}
+	}
+
+	err = notes_db.RemoveNote(ctx, n)
+
+	if err != nil {
+		return fmt.Errorf("Failed to remove note, %w", err)
+	}
+
+	return nil
+}
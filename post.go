@@ -48,3 +48,8 @@ func NewPost(ctx context.Context, acct *Account, body string) (*Post, error) {
 
 	return p, nil
 }
+
+// IsEdited returns a boolean value indicating whether 'p' has been edited since it was created.
+func (p *Post) IsEdited() bool {
+	return p.LastModified > p.Created
+}
@@ -0,0 +1,52 @@
+package activitypub
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sfomuseum/go-activitypub/id"
+)
+
+// PostRevision is a prior version of a post, recorded when the post is edited.
+type PostRevision struct {
+	// The unique ID for the post revision.
+	Id int64 `json:"id"`
+	// The unique ID of the post this is a revision of.
+	PostId int64 `json:"post_id"`
+	// The AccountsDatabase ID of the author of the post.
+	AccountId int64 `json:"account_id"`
+	// The body of the post before it was edited.
+	Body string `json:"body"`
+	// The URL of the post this post was referencing before it was edited.
+	InReplyTo string `json:"in_reply_to"`
+	// The Unix timestamp when this version of the post was last modified.
+	LastModified int64 `json:"lastmodified"`
+	// The Unix timestamp when the post revision was created (when the post was edited).
+	Created int64 `json:"created"`
+}
+
+// NewPostRevision returns a new `PostRevision` instance recording the current state of 'post'.
+func NewPostRevision(ctx context.Context, post *Post) (*PostRevision, error) {
+
+	revision_id, err := id.NewId()
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive new post revision ID, %w", err)
+	}
+
+	now := time.Now()
+	ts := now.Unix()
+
+	r := &PostRevision{
+		Id:           revision_id,
+		PostId:       post.Id,
+		AccountId:    post.AccountId,
+		Body:         post.Body,
+		InReplyTo:    post.InReplyTo,
+		LastModified: post.LastModified,
+		Created:      ts,
+	}
+
+	return r, nil
+}
@@ -0,0 +1,315 @@
+package posts
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/database"
+	"github.com/sfomuseum/go-activitypub/media"
+	"github.com/sfomuseum/go-activitypub/queue"
+	"github.com/sfomuseum/go-activitypub/uris"
+)
+
+type DeletePostOptions struct {
+	PostsDatabase         database.PostsDatabase
+	PostTagsDatabase      database.PostTagsDatabase
+	PostRevisionsDatabase database.PostRevisionsDatabase
+	LikesDatabase         database.LikesDatabase
+	BoostsDatabase        database.BoostsDatabase
+	ActivitiesDatabase    database.ActivitiesDatabase
+	DeliveriesDatabase    database.DeliveriesDatabase
+	TombstonesDatabase    database.TombstonesDatabase
+	MediaDatabase         database.MediaDatabase
+}
+
+// PostActivities returns the "Create" activity and any "Update" activities (one for each revision of the post)
+// associated with 'post'.
+func PostActivities(ctx context.Context, activities_db database.ActivitiesDatabase, post_revisions_db database.PostRevisionsDatabase, post *activitypub.Post) ([]*activitypub.Activity, error) {
+
+	activities := make([]*activitypub.Activity, 0)
+
+	create_activity, err := activities_db.GetActivityWithActivityTypeAndId(ctx, activitypub.PostActivityType, post.Id)
+
+	switch {
+	case err == activitypub.ErrNotFound:
+		// pass
+	case err != nil:
+		return nil, fmt.Errorf("Failed to retrieve activity for post, %w", err)
+	default:
+		activities = append(activities, create_activity)
+	}
+
+	revisions_cb := func(ctx context.Context, r *activitypub.PostRevision) error {
+
+		update_activity, err := activities_db.GetActivityWithActivityTypeAndId(ctx, activitypub.UpdateActivityType, r.Id)
+
+		switch {
+		case err == activitypub.ErrNotFound:
+			// pass
+		case err != nil:
+			return fmt.Errorf("Failed to retrieve activity for post revision %d, %w", r.Id, err)
+		default:
+			activities = append(activities, update_activity)
+		}
+
+		return nil
+	}
+
+	err = post_revisions_db.GetPostRevisionsForPost(ctx, post.Id, revisions_cb)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve revisions for post, %w", err)
+	}
+
+	return activities, nil
+}
+
+// PostRecipients returns the list of unique addresses that the "Create" activity, and any "Update" activities, for 'post' were
+// successfully delivered to.
+func PostRecipients(ctx context.Context, activities_db database.ActivitiesDatabase, post_revisions_db database.PostRevisionsDatabase, deliveries_db database.DeliveriesDatabase, post *activitypub.Post) ([]string, error) {
+
+	activities, err := PostActivities(ctx, activities_db, post_revisions_db, post)
+
+	if err != nil {
+		return nil, err
+	}
+
+	recipients := make([]string, 0)
+
+	for _, a := range activities {
+
+		activity_recipients, err := queue.ActivityRecipients(ctx, deliveries_db, a.Id)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to derive recipients for activity %d, %w", a.Id, err)
+		}
+
+		for _, r := range activity_recipients {
+
+			if !slices.Contains(recipients, r) {
+				recipients = append(recipients, r)
+			}
+		}
+	}
+
+	return recipients, nil
+}
+
+// DeletePost removes 'post' from the posts database and records a tombstone for it in the tombstones database. The tombstone
+// includes the people who received the post (see `PostRecipients`) since that can no longer be derived once the post's activities
+// are removed. It also removes the post tags (mentions), likes, boosts, revisions, media attachments (and their files) and the
+// "Create" and "Update" activities associated with the post. It returns the tombstone for further processing as needed.
+func DeletePost(ctx context.Context, opts *DeletePostOptions, acct *activitypub.Account, post *activitypub.Post) (*activitypub.Tombstone, error) {
+
+	if post.AccountId != acct.Id {
+		return nil, fmt.Errorf("Post %d does not belong to account %d", post.Id, acct.Id)
+	}
+
+	// Record the tombstone first so that requests for the post return "410 Gone"
+	// even if one of the subsequent clean up steps fails. If there is already a
+	// tombstone then this is a retry of a deletion that failed part way through.
+
+	tombstone, err := opts.TombstonesDatabase.GetTombstoneWithPostId(ctx, post.Id)
+
+	switch {
+	case err == activitypub.ErrNotFound:
+
+		recipients, err := PostRecipients(ctx, opts.ActivitiesDatabase, opts.PostRevisionsDatabase, opts.DeliveriesDatabase, post)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to derive recipients for post, %w", err)
+		}
+
+		tombstone, err = activitypub.NewTombstone(ctx, post)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create new tombstone, %w", err)
+		}
+
+		tombstone.Recipients = recipients
+
+		err = opts.TombstonesDatabase.AddTombstone(ctx, tombstone)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to add tombstone, %w", err)
+		}
+
+	case err != nil:
+		return nil, fmt.Errorf("Failed to retrieve tombstone for post, %w", err)
+	}
+
+	// Records are gathered before they are removed so that nothing is removed while
+	// the underlying database is still being iterated over
+
+	activities, err := PostActivities(ctx, opts.ActivitiesDatabase, opts.PostRevisionsDatabase, post)
+
+	if err != nil {
+		return nil, err
+	}
+
+	post_tags := make([]*activitypub.PostTag, 0)
+
+	tags_cb := func(ctx context.Context, t *activitypub.PostTag) error {
+		post_tags = append(post_tags, t)
+		return nil
+	}
+
+	err = opts.PostTagsDatabase.GetPostTagsForPost(ctx, post.Id, tags_cb)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve post tags for post, %w", err)
+	}
+
+	likes := make([]*activitypub.Like, 0)
+
+	likes_cb := func(ctx context.Context, l *activitypub.Like) error {
+		likes = append(likes, l)
+		return nil
+	}
+
+	err = opts.LikesDatabase.GetLikesForPost(ctx, post.Id, likes_cb)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve likes for post, %w", err)
+	}
+
+	boosts := make([]*activitypub.Boost, 0)
+
+	boosts_cb := func(ctx context.Context, b *activitypub.Boost) error {
+		boosts = append(boosts, b)
+		return nil
+	}
+
+	err = opts.BoostsDatabase.GetBoostsForPost(ctx, post.Id, boosts_cb)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve boosts for post, %w", err)
+	}
+
+	revisions := make([]*activitypub.PostRevision, 0)
+
+	revisions_cb := func(ctx context.Context, r *activitypub.PostRevision) error {
+		revisions = append(revisions, r)
+		return nil
+	}
+
+	err = opts.PostRevisionsDatabase.GetPostRevisionsForPost(ctx, post.Id, revisions_cb)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve revisions for post, %w", err)
+	}
+
+	attachments, err := media.GetMediaForPost(ctx, opts.MediaDatabase, post.Id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Remove all the things
+
+	for _, t := range post_tags {
+
+		err := opts.PostTagsDatabase.RemovePostTag(ctx, t)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to remove post tag %d, %w", t.Id, err)
+		}
+	}
+
+	for _, l := range likes {
+
+		err := opts.LikesDatabase.RemoveLike(ctx, l)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to remove like %d, %w", l.Id, err)
+		}
+	}
+
+	for _, b := range boosts {
+
+		err := opts.BoostsDatabase.RemoveBoost(ctx, b)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to remove boost %d, %w", b.Id, err)
+		}
+	}
+
+	for _, r := range revisions {
+
+		err := opts.PostRevisionsDatabase.RemovePostRevision(ctx, r)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to remove post revision %d, %w", r.Id, err)
+		}
+	}
+
+	for _, m := range attachments {
+
+		err := media.RemoveMedia(ctx, opts.MediaDatabase, m)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to remove media %d, %w", m.Id, err)
+		}
+	}
+
+	for _, a := range activities {
+
+		err := opts.ActivitiesDatabase.RemoveActivity(ctx, a)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to remove activity %d, %w", a.Id, err)
+		}
+	}
+
+	err = opts.PostsDatabase.RemovePost(ctx, post)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to remove post, %w", err)
+	}
+
+	return tombstone, nil
+}
+
+// TombstoneObject returns a new (ActivityPub) `Tombstone` instance derived from 'acct' and 'tombstone'.
+func TombstoneObject(ctx context.Context, uris_table *uris.URIs, acct *activitypub.Account, tombstone *activitypub.Tombstone) *ap.Tombstone {
+
+	post := &activitypub.Post{
+		Id: tombstone.PostId,
+	}
+
+	post_url := acct.PostURL(ctx, uris_table, post)
+
+	published := time.Unix(tombstone.Published, 0)
+	deleted := time.Unix(tombstone.Created, 0)
+
+	t := &ap.Tombstone{
+		Id:         post_url.String(),
+		Type:       "Tombstone",
+		FormerType: tombstone.FormerType,
+		Published:  published.Format(http.TimeFormat),
+		Deleted:    deleted.Format(http.TimeFormat),
+	}
+
+	return t
+}
+
+// DeleteActivityFromTombstone creates a new (ActivityPub) "Delete" `Activity` instance derived from 'acct' and 'tombstone'
+// for notifying the people who received the (deleted) post that it has been deleted.
+func DeleteActivityFromTombstone(ctx context.Context, uris_table *uris.URIs, acct *activitypub.Account, tombstone *activitypub.Tombstone) (*ap.Activity, error) {
+
+	from_u := acct.AccountURL(ctx, uris_table)
+	from := from_u.String()
+
+	obj := TombstoneObject(ctx, uris_table, acct, tombstone)
+
+	to := []string{
+		ap.ACTIVITYSTREAMS_CONTEXT_PUBLIC,
+	}
+
+	return ap.NewDeleteActivity(ctx, uris_table, from, to, obj)
+}
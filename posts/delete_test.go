@@ -0,0 +1,221 @@
+package posts
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/uris"
+	_ "gocloud.dev/blob/fileblob"
+)
+
+func TestDeletePost(t *testing.T) {
+
+	ctx := context.Background()
+
+	uris_table := uris.DefaultURIs()
+	uris_table.Hostname = "example.com"
+
+	acct := &activitypub.Account{
+		Id:   1234,
+		Name: "alice",
+	}
+
+	post := &activitypub.Post{
+		Id:           5678,
+		AccountId:    acct.Id,
+		Body:         "Hello @bob@example.social",
+		Created:      1700000000,
+		LastModified: 1700000100,
+	}
+
+	posts_db := &testPostsDatabase{
+		posts: map[int64]*activitypub.Post{
+			post.Id: post,
+		},
+	}
+
+	post_tags_db := &testPostTagsDatabase{
+		tags: map[int64]*activitypub.PostTag{
+			1: &activitypub.PostTag{Id: 1, PostId: post.Id, AccountId: acct.Id, Name: "bob@example.social", Type: "Mention"},
+			2: &activitypub.PostTag{Id: 2, PostId: 9999, AccountId: acct.Id, Name: "bob@example.social", Type: "Mention"},
+		},
+	}
+
+	post_revisions_db := &testPostRevisionsDatabase{
+		revisions: []*activitypub.PostRevision{
+			&activitypub.PostRevision{Id: 10, PostId: post.Id, AccountId: acct.Id},
+		},
+	}
+
+	likes_db := &testLikesDatabase{
+		likes: map[int64]*activitypub.Like{
+			20: &activitypub.Like{Id: 20, PostId: post.Id, AccountId: acct.Id},
+		},
+	}
+
+	boosts_db := &testBoostsDatabase{
+		boosts: map[int64]*activitypub.Boost{
+			30: &activitypub.Boost{Id: 30, PostId: post.Id, AccountId: acct.Id},
+		},
+	}
+
+	activities_db := &testActivitiesDatabase{
+		activities: map[int64]*activitypub.Activity{
+			40: &activitypub.Activity{Id: 40, ActivityType: activitypub.PostActivityType, ActivityTypeId: post.Id},
+			41: &activitypub.Activity{Id: 41, ActivityType: activitypub.UpdateActivityType, ActivityTypeId: 10},
+			42: &activitypub.Activity{Id: 42, ActivityType: activitypub.PostActivityType, ActivityTypeId: 9999},
+		},
+	}
+
+	deliveries_db := &testDeliveriesDatabase{
+		deliveries: []*activitypub.Delivery{
+			&activitypub.Delivery{Id: 60, ActivityId: 40, Recipient: "bob@example.social", Success: true},
+			&activitypub.Delivery{Id: 61, ActivityId: 41, Recipient: "bob@example.social", Success: true},
+			&activitypub.Delivery{Id: 62, ActivityId: 41, Recipient: "carol@example.social", Success: true},
+			&activitypub.Delivery{Id: 63, ActivityId: 40, Recipient: "doug@example.social", Success: false},
+			&activitypub.Delivery{Id: 64, ActivityId: 42, Recipient: "erin@example.social", Success: true},
+		},
+	}
+
+	tombstones_db := &testTombstonesDatabase{
+		tombstones: make(map[int64]*activitypub.Tombstone),
+	}
+
+	media_root := t.TempDir()
+	media_path := filepath.Join(media_root, "50.png")
+
+	err := os.WriteFile(media_path, []byte("not really a PNG"), 0644)
+
+	if err != nil {
+		t.Fatalf("Failed to write media file, %v", err)
+	}
+
+	media_db := &testMediaDatabase{
+		media: map[int64]*activitypub.Media{
+			50: &activitypub.Media{Id: 50, PostId: post.Id, AccountId: acct.Id, URI: fmt.Sprintf("file://%s", media_path), MediaType: "image/png"},
+		},
+	}
+
+	opts := &DeletePostOptions{
+		PostsDatabase:         posts_db,
+		PostTagsDatabase:      post_tags_db,
+		PostRevisionsDatabase: post_revisions_db,
+		LikesDatabase:         likes_db,
+		BoostsDatabase:        boosts_db,
+		ActivitiesDatabase:    activities_db,
+		DeliveriesDatabase:    deliveries_db,
+		TombstonesDatabase:    tombstones_db,
+		MediaDatabase:         media_db,
+	}
+
+	other := &activitypub.Account{
+		Id:   9999,
+		Name: "mallory",
+	}
+
+	_, err = DeletePost(ctx, opts, other, post)
+
+	if err == nil {
+		t.Fatalf("Expected delete by another account to fail")
+	}
+
+	activities, err := PostActivities(ctx, activities_db, post_revisions_db, post)
+
+	if err != nil {
+		t.Fatalf("Failed to derive activities for post, %v", err)
+	}
+
+	if len(activities) != 2 {
+		t.Fatalf("Expected 2 activities for post, got %d", len(activities))
+	}
+
+	tombstone, err := DeletePost(ctx, opts, acct, post)
+
+	if err != nil {
+		t.Fatalf("Failed to delete post, %v", err)
+	}
+
+	if tombstone.PostId != post.Id || tombstone.AccountId != acct.Id || tombstone.Published != post.Created {
+		t.Fatalf("Unexpected tombstone, %v", tombstone)
+	}
+
+	if len(tombstones_db.tombstones) != 1 {
+		t.Fatalf("Expected tombstone to be recorded")
+	}
+
+	// Recipients are recorded with the tombstone since the activities they are derived from have been removed
+
+	if !slices.Equal(tombstone.Recipients, []string{"bob@example.social", "carol@example.social"}) {
+		t.Fatalf("Unexpected tombstone recipients, %v", tombstone.Recipients)
+	}
+
+	// Deleting a post again (for example after a partial failure) reuses the existing tombstone
+
+	retry, err := DeletePost(ctx, opts, acct, post)
+
+	if err != nil {
+		t.Fatalf("Failed to retry deleting post, %v", err)
+	}
+
+	if retry.Id != tombstone.Id || len(tombstones_db.tombstones) != 1 {
+		t.Fatalf("Expected existing tombstone to be reused")
+	}
+
+	if !slices.Equal(retry.Recipients, tombstone.Recipients) {
+		t.Fatalf("Expected tombstone recipients to be preserved, %v", retry.Recipients)
+	}
+
+	if len(posts_db.posts) != 0 {
+		t.Fatalf("Expected post to be removed")
+	}
+
+	if len(post_tags_db.tags) != 1 {
+		t.Fatalf("Expected post tags for post (and only post) to be removed")
+	}
+
+	if len(likes_db.likes) != 0 || len(boosts_db.boosts) != 0 || len(post_revisions_db.revisions) != 0 {
+		t.Fatalf("Expected likes, boosts and revisions for post to be removed")
+	}
+
+	if len(media_db.media) != 0 {
+		t.Fatalf("Expected media for post to be removed")
+	}
+
+	_, err = os.Stat(media_path)
+
+	if !os.IsNotExist(err) {
+		t.Fatalf("Expected media file for post to be removed")
+	}
+
+	if len(activities_db.activities) != 1 {
+		t.Fatalf("Expected activities for post (and only post) to be removed")
+	}
+
+	ap_activity, err := DeleteActivityFromTombstone(ctx, uris_table, acct, tombstone)
+
+	if err != nil {
+		t.Fatalf("Failed to derive delete activity from tombstone, %v", err)
+	}
+
+	if ap_activity.Type != "Delete" {
+		t.Fatalf("Unexpected activity type, %s", ap_activity.Type)
+	}
+
+	obj, ok := ap_activity.Object.(*ap.Tombstone)
+
+	if !ok {
+		t.Fatalf("Expected activity object to be a tombstone")
+	}
+
+	post_url := acct.PostURL(ctx, uris_table, post)
+
+	if obj.Id != post_url.String() || obj.FormerType != "Note" {
+		t.Fatalf("Unexpected tombstone object, %v", obj)
+	}
+}
@@ -0,0 +1,150 @@
+package posts
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/database"
+	"github.com/sfomuseum/go-activitypub/uris"
+)
+
+type EditPostOptions struct {
+	URIs                  *uris.URIs
+	PostsDatabase         database.PostsDatabase
+	PostTagsDatabase      database.PostTagsDatabase
+	PostRevisionsDatabase database.PostRevisionsDatabase
+}
+
+// EditPost replaces the body of 'post' with 'body'. The prior version of the post is recorded in the post revisions
+// database before the post itself is updated (and its last modified date bumped). Then it parses 'body' looking for
+// other ActivityPub addresses, adding "mention" post tags for new addresses and removing the post tags for addresses
+// no longer mentioned. It returns the revision recording the prior version of the post and the list of post tags
+// (mentions) for the updated post for further processing as needed.
+func EditPost(ctx context.Context, opts *EditPostOptions, acct *activitypub.Account, post *activitypub.Post, body string) (*activitypub.PostRevision, []*activitypub.PostTag, error) {
+
+	if post.AccountId != acct.Id {
+		return nil, nil, fmt.Errorf("Post %d does not belong to account %d", post.Id, acct.Id)
+	}
+
+	// Record the current version of the post
+
+	rev, err := activitypub.NewPostRevision(ctx, post)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to create new post revision, %w", err)
+	}
+
+	err = opts.PostRevisionsDatabase.AddPostRevision(ctx, rev)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to add post revision, %w", err)
+	}
+
+	// Update the post
+
+	now := time.Now()
+
+	post.Body = body
+	post.LastModified = now.Unix()
+
+	// Ensure the post is flagged as edited even if it is edited in the same second it was created
+
+	if post.LastModified <= post.Created {
+		post.LastModified = post.Created + 1
+	}
+
+	err = opts.PostsDatabase.UpdatePost(ctx, post)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to update post, %w", err)
+	}
+
+	// Determine other accounts mentioned in post
+
+	addrs_mentioned, err := ap.ParseAddressesFromString(body)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to derive addresses mentioned in message body, %w", err)
+	}
+
+	// Reconcile the existing post tags with the accounts mentioned in the updated post
+
+	post_tags := make([]*activitypub.PostTag, 0)
+	stale_tags := make([]*activitypub.PostTag, 0)
+	addrs_tagged := make([]string, 0)
+
+	tags_cb := func(ctx context.Context, t *activitypub.PostTag) error {
+
+		if t.Type == "Mention" && !slices.Contains(addrs_mentioned, t.Name) {
+			stale_tags = append(stale_tags, t)
+			return nil
+		}
+
+		post_tags = append(post_tags, t)
+		addrs_tagged = append(addrs_tagged, t.Name)
+		return nil
+	}
+
+	err = opts.PostTagsDatabase.GetPostTagsForPost(ctx, post.Id, tags_cb)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to retrieve post tags for post, %w", err)
+	}
+
+	for _, t := range stale_tags {
+
+		slog.Debug("Remove post tag no longer mentioned in post", "post id", post.Id, "name", t.Name)
+
+		err := opts.PostTagsDatabase.RemovePostTag(ctx, t)
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed to remove post tag (mention) for '%s', %w", t.Name, err)
+		}
+	}
+
+	addrs_new := make([]string, 0)
+
+	for _, addr := range addrs_mentioned {
+
+		if !slices.Contains(addrs_tagged, addr) {
+			addrs_new = append(addrs_new, addr)
+		}
+	}
+
+	new_tags, err := addMentions(ctx, opts.URIs, opts.PostTagsDatabase, post, addrs_new)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	post_tags = append(post_tags, new_tags...)
+
+	// Return all the things
+
+	return rev, post_tags, nil
+}
+
+// UpdateActivityFromPost creates a new (ActivityPub) "Update" `Activity` instance derived from 'acct', 'post', 'post_tags' and
+// 'attachments' for notifying the people who received 'post' that it has been edited.
+func UpdateActivityFromPost(ctx context.Context, uris_table *uris.URIs, acct *activitypub.Account, post *activitypub.Post, mentions []*activitypub.PostTag, attachments []*activitypub.Media) (*ap.Activity, error) {
+
+	from_u := acct.AccountURL(ctx, uris_table)
+	from := from_u.String()
+
+	note, err := NoteFromPost(ctx, uris_table, acct, post, mentions, attachments)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive note from post, %w", err)
+	}
+
+	to := []string{
+		ap.ACTIVITYSTREAMS_CONTEXT_PUBLIC,
+	}
+
+	return ap.NewUpdateActivity(ctx, uris_table, from, to, note)
+}
@@ -0,0 +1,100 @@
+package posts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/uris"
+)
+
+func TestEditPost(t *testing.T) {
+
+	ctx := context.Background()
+
+	uris_table := uris.DefaultURIs()
+	uris_table.Hostname = "example.com"
+
+	acct := &activitypub.Account{
+		Id:   1234,
+		Name: "alice",
+	}
+
+	post := &activitypub.Post{
+		Id:           5678,
+		AccountId:    acct.Id,
+		Body:         "Hello @bob@example.social",
+		Created:      1700000000,
+		LastModified: 1700000000,
+	}
+
+	posts_db := &testPostsDatabase{
+		posts: map[int64]*activitypub.Post{
+			post.Id: post,
+		},
+	}
+
+	post_tags_db := &testPostTagsDatabase{
+		tags: map[int64]*activitypub.PostTag{
+			1: &activitypub.PostTag{Id: 1, PostId: post.Id, AccountId: acct.Id, Name: "bob@example.social", Type: "Mention"},
+		},
+	}
+
+	post_revisions_db := &testPostRevisionsDatabase{
+		revisions: make([]*activitypub.PostRevision, 0),
+	}
+
+	opts := &EditPostOptions{
+		URIs:                  uris_table,
+		PostsDatabase:         posts_db,
+		PostTagsDatabase:      post_tags_db,
+		PostRevisionsDatabase: post_revisions_db,
+	}
+
+	rev, mentions, err := EditPost(ctx, opts, acct, post, "Hello world")
+
+	if err != nil {
+		t.Fatalf("Failed to edit post, %v", err)
+	}
+
+	if rev.PostId != post.Id || rev.Body != "Hello @bob@example.social" || rev.LastModified != 1700000000 {
+		t.Fatalf("Unexpected post revision, %v", rev)
+	}
+
+	if len(post_revisions_db.revisions) != 1 {
+		t.Fatalf("Expected post revision to be recorded")
+	}
+
+	if posts_db.posts[post.Id].Body != "Hello world" {
+		t.Fatalf("Expected post body to be updated")
+	}
+
+	if !post.IsEdited() {
+		t.Fatalf("Expected post to be flagged as edited")
+	}
+
+	if len(mentions) != 0 || len(post_tags_db.tags) != 0 {
+		t.Fatalf("Expected mention no longer in post to be removed")
+	}
+
+	note, err := NoteFromPost(ctx, uris_table, acct, post, mentions, nil)
+
+	if err != nil {
+		t.Fatalf("Failed to derive note from post, %v", err)
+	}
+
+	if note.Updated == "" {
+		t.Fatalf("Expected note to have an updated date")
+	}
+
+	other := &activitypub.Account{
+		Id:   9999,
+		Name: "mallory",
+	}
+
+	_, _, err = EditPost(ctx, opts, other, post, "Goodbye world")
+
+	if err == nil {
+		t.Fatalf("Expected edit by another account to fail")
+	}
+}
@@ -0,0 +1,245 @@
+package posts
+
+import (
+	"context"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/database"
+)
+
+type testPostsDatabase struct {
+	database.PostsDatabase
+	posts map[int64]*activitypub.Post
+}
+
+func (db *testPostsDatabase) UpdatePost(ctx context.Context, p *activitypub.Post) error {
+	db.posts[p.Id] = p
+	return nil
+}
+
+func (db *testPostsDatabase) RemovePost(ctx context.Context, p *activitypub.Post) error {
+	delete(db.posts, p.Id)
+	return nil
+}
+
+type testPostTagsDatabase struct {
+	database.PostTagsDatabase
+	tags map[int64]*activitypub.PostTag
+}
+
+func (db *testPostTagsDatabase) GetPostTagsForPost(ctx context.Context, post_id int64, cb database.GetPostTagsCallbackFunc) error {
+
+	for _, t := range db.tags {
+
+		if t.PostId != post_id {
+			continue
+		}
+
+		err := cb(ctx, t)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *testPostTagsDatabase) RemovePostTag(ctx context.Context, t *activitypub.PostTag) error {
+	delete(db.tags, t.Id)
+	return nil
+}
+
+type testPostRevisionsDatabase struct {
+	database.PostRevisionsDatabase
+	revisions []*activitypub.PostRevision
+}
+
+func (db *testPostRevisionsDatabase) AddPostRevision(ctx context.Context, r *activitypub.PostRevision) error {
+	db.revisions = append(db.revisions, r)
+	return nil
+}
+
+func (db *testPostRevisionsDatabase) GetPostRevisionsForPost(ctx context.Context, post_id int64, cb database.GetPostRevisionsCallbackFunc) error {
+
+	for _, r := range db.revisions {
+
+		if r.PostId != post_id {
+			continue
+		}
+
+		err := cb(ctx, r)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *testPostRevisionsDatabase) RemovePostRevision(ctx context.Context, r *activitypub.PostRevision) error {
+
+	revisions := make([]*activitypub.PostRevision, 0)
+
+	for _, other := range db.revisions {
+
+		if other.Id != r.Id {
+			revisions = append(revisions, other)
+		}
+	}
+
+	db.revisions = revisions
+	return nil
+}
+
+type testMediaDatabase struct {
+	database.MediaDatabase
+	media map[int64]*activitypub.Media
+}
+
+func (db *testMediaDatabase) GetMediaForPost(ctx context.Context, post_id int64, cb database.GetMediaCallbackFunc) error {
+
+	for _, m := range db.media {
+
+		if m.PostId != post_id {
+			continue
+		}
+
+		err := cb(ctx, m)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *testMediaDatabase) RemoveMedia(ctx context.Context, m *activitypub.Media) error {
+	delete(db.media, m.Id)
+	return nil
+}
+
+type testLikesDatabase struct {
+	database.LikesDatabase
+	likes map[int64]*activitypub.Like
+}
+
+func (db *testLikesDatabase) GetLikesForPost(ctx context.Context, post_id int64, cb database.GetLikesCallbackFunc) error {
+
+	for _, l := range db.likes {
+
+		if l.PostId != post_id {
+			continue
+		}
+
+		err := cb(ctx, l)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *testLikesDatabase) RemoveLike(ctx context.Context, l *activitypub.Like) error {
+	delete(db.likes, l.Id)
+	return nil
+}
+
+type testBoostsDatabase struct {
+	database.BoostsDatabase
+	boosts map[int64]*activitypub.Boost
+}
+
+func (db *testBoostsDatabase) GetBoostsForPost(ctx context.Context, post_id int64, cb database.GetBoostsCallbackFunc) error {
+
+	for _, b := range db.boosts {
+
+		if b.PostId != post_id {
+			continue
+		}
+
+		err := cb(ctx, b)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *testBoostsDatabase) RemoveBoost(ctx context.Context, b *activitypub.Boost) error {
+	delete(db.boosts, b.Id)
+	return nil
+}
+
+type testActivitiesDatabase struct {
+	database.ActivitiesDatabase
+	activities map[int64]*activitypub.Activity
+}
+
+func (db *testActivitiesDatabase) GetActivityWithActivityTypeAndId(ctx context.Context, activity_type activitypub.ActivityType, activity_type_id int64) (*activitypub.Activity, error) {
+
+	for _, a := range db.activities {
+
+		if a.ActivityType == activity_type && a.ActivityTypeId == activity_type_id {
+			return a, nil
+		}
+	}
+
+	return nil, activitypub.ErrNotFound
+}
+
+func (db *testActivitiesDatabase) RemoveActivity(ctx context.Context, a *activitypub.Activity) error {
+	delete(db.activities, a.Id)
+	return nil
+}
+
+type testDeliveriesDatabase struct {
+	database.DeliveriesDatabase
+	deliveries []*activitypub.Delivery
+}
+
+func (db *testDeliveriesDatabase) GetDeliveriesWithActivityId(ctx context.Context, activity_id int64, cb database.GetDeliveriesCallbackFunc) error {
+
+	for _, d := range db.deliveries {
+
+		if d.ActivityId != activity_id {
+			continue
+		}
+
+		err := cb(ctx, d)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type testTombstonesDatabase struct {
+	database.TombstonesDatabase
+	tombstones map[int64]*activitypub.Tombstone
+}
+
+func (db *testTombstonesDatabase) GetTombstoneWithPostId(ctx context.Context, post_id int64) (*activitypub.Tombstone, error) {
+
+	for _, t := range db.tombstones {
+
+		if t.PostId == post_id {
+			return t, nil
+		}
+	}
+
+	return nil, activitypub.ErrNotFound
+}
+
+func (db *testTombstonesDatabase) AddTombstone(ctx context.Context, t *activitypub.Tombstone) error {
+	db.tombstones[t.Id] = t
+	return nil
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/sfomuseum/go-activitypub"
 	"github.com/sfomuseum/go-activitypub/ap"
 	"github.com/sfomuseum/go-activitypub/database"
+	"github.com/sfomuseum/go-activitypub/media"
 	"github.com/sfomuseum/go-activitypub/uris"
 )
 
@@ -55,11 +56,26 @@ func AddPost(ctx context.Context, opts *AddPostOptions, acct *activitypub.Accoun
 	// Create "mention" tags for any other accounts mentioned in post
 	// Add each mention to the "post tags" database
 
+	post_tags, err := addMentions(ctx, opts.URIs, opts.PostTagsDatabase, p, addrs_mentioned)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Return all the things
+
+	return p, post_tags, nil
+}
+
+// addMentions creates a "mention" post tag for each address in 'addrs' and adds it to 'post_tags_db'. Addresses whose
+// actor record can not be retrieved are skipped.
+func addMentions(ctx context.Context, uris_table *uris.URIs, post_tags_db database.PostTagsDatabase, p *activitypub.Post, addrs []string) ([]*activitypub.PostTag, error) {
+
 	post_tags := make([]*activitypub.PostTag, 0)
 
-	for _, name := range addrs_mentioned {
+	for _, name := range addrs {
 
-		actor, err := ap.RetrieveActor(ctx, name, opts.URIs.Insecure)
+		actor, err := ap.RetrieveActor(ctx, name, uris_table.Insecure)
 
 		if err != nil {
 			slog.Error("Failed to retrieve actor data for name, skipping", "name", name, "error", err)
@@ -79,28 +95,25 @@ func AddPost(ctx context.Context, opts *AddPostOptions, acct *activitypub.Accoun
 		t, err := activitypub.NewMention(ctx, p, mention_name, mention_href)
 
 		if err != nil {
-			return nil, nil, fmt.Errorf("Failed to create mention for '%s', %w", name, err)
+			return nil, fmt.Errorf("Failed to create mention for '%s', %w", name, err)
 		}
 
-		err = opts.PostTagsDatabase.AddPostTag(ctx, t)
+		err = post_tags_db.AddPostTag(ctx, t)
 
 		if err != nil {
-			return nil, nil, fmt.Errorf("Failed to record post tag (mention) for '%s', %w", name, err)
+			return nil, fmt.Errorf("Failed to record post tag (mention) for '%s', %w", name, err)
 		}
 
 		post_tags = append(post_tags, t)
 	}
 
-	// Return all the things
-
-	return p, post_tags, nil
+	return post_tags, nil
 }
 
 // ActivityFromPost should be kept in /ap but that causes Golang import cycle errors.
-// ActivityFromPost should also be updated to accept media attachments.
 
-// ActivityFromPost creates a new (ActivityPub) `Activity` instance derived from 'acct', 'post' and 'post_tags'.
-func ActivityFromPost(ctx context.Context, uris_table *uris.URIs, acct *activitypub.Account, post *activitypub.Post, mentions []*activitypub.PostTag) (*ap.Activity, error) {
+// ActivityFromPost creates a new (ActivityPub) `Activity` instance derived from 'acct', 'post', 'post_tags' and 'attachments'.
+func ActivityFromPost(ctx context.Context, uris_table *uris.URIs, acct *activitypub.Account, post *activitypub.Post, mentions []*activitypub.PostTag, attachments []*activitypub.Media) (*ap.Activity, error) {
 
 	from_u := acct.AccountURL(ctx, uris_table)
 	from := from_u.String()
@@ -111,7 +124,7 @@ func ActivityFromPost(ctx context.Context, uris_table *uris.URIs, acct *activity
 
 	logger.Debug("Create note from post")
 
-	note, err := NoteFromPost(ctx, uris_table, acct, post, mentions)
+	note, err := NoteFromPost(ctx, uris_table, acct, post, mentions, attachments)
 
 	if err != nil {
 		logger.Error("Failed to create note from post", "error", err)
@@ -124,15 +137,14 @@ func ActivityFromPost(ctx context.Context, uris_table *uris.URIs, acct *activity
 
 	logger.Debug("Create activity from note")
 
-	// Something something something media attachments here...
-
 	return ap.NewCreateActivity(ctx, uris_table, from, to, note)
 }
 
 // THIS SHOULD BE IN /ap BUT CAUSES IMPORT CYCLE ERRORS
 
-// NoteFromPost creates a new (ActivityPub) `Note` instance derived from 'acct', 'post' and 'post_tags'.
-func NoteFromPost(ctx context.Context, uris_table *uris.URIs, acct *activitypub.Account, post *activitypub.Post, post_tags []*activitypub.PostTag) (*ap.Note, error) {
+// NoteFromPost creates a new (ActivityPub) `Note` instance derived from 'acct', 'post', 'post_tags' and 'attachments'.
+// Media attachments are included as "Image" attachments if they are images and "Document" attachments otherwise.
+func NoteFromPost(ctx context.Context, uris_table *uris.URIs, acct *activitypub.Account, post *activitypub.Post, post_tags []*activitypub.PostTag, attachments []*activitypub.Media) (*ap.Note, error) {
 
 	attr := acct.ProfileURL(ctx, uris_table).String()
 	post_url := acct.PostURL(ctx, uris_table, post)
@@ -172,6 +184,19 @@ func NoteFromPost(ctx context.Context, uris_table *uris.URIs, acct *activitypub.
 		URL:       post_url.String(),
 	}
 
+	if post.IsEdited() {
+		n.Updated = time.Unix(post.LastModified, 0).Format(http.TimeFormat)
+	}
+
+	if len(attachments) > 0 {
+
+		n.Attachments = make([]*ap.Attachment, len(attachments))
+
+		for idx, m := range attachments {
+			n.Attachments[idx] = media.AttachmentFromMedia(ctx, uris_table, acct, m)
+		}
+	}
+
 	return n, nil
 }
 
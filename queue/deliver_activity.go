@@ -4,23 +4,29 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/blocks"
 	"github.com/sfomuseum/go-activitypub/database"
 	"github.com/sfomuseum/go-activitypub/deliver"
 	"github.com/sfomuseum/go-activitypub/uris"
 )
 
 type DeliverActivityToFollowersOptions struct {
-	AccountsDatabase   database.AccountsDatabase
-	FollowersDatabase  database.FollowersDatabase
-	NotesDatabase      database.NotesDatabase
-	DeliveriesDatabase database.DeliveriesDatabase
-	DeliveryQueue      DeliveryQueue
-	Activity           *activitypub.Activity
-	Mentions           []*activitypub.PostTag `json:"mentions"`
-	MaxAttempts        int                    `json:"max_attempts"`
-	URIs               *uris.URIs
+	AccountsDatabase     database.AccountsDatabase
+	FollowersDatabase    database.FollowersDatabase
+	NotesDatabase        database.NotesDatabase
+	DeliveriesDatabase   database.DeliveriesDatabase
+	DeliveryQueue        DeliveryQueue
+	Activity             *activitypub.Activity
+	Mentions             []*activitypub.PostTag `json:"mentions"`
+	MaxAttempts          int                    `json:"max_attempts"`
+	URIs                 *uris.URIs
+	ActorsDatabase       database.ActorsDatabase
+	ActorsTTL            time.Duration
+	DomainBlocksDatabase database.DomainBlocksDatabase
+	DomainAllowsDatabase database.DomainAllowsDatabase
 }
 
 func DeliverActivityToFollowers(ctx context.Context, opts *DeliverActivityToFollowersOptions) error {
@@ -44,55 +50,21 @@ func DeliverActivityToFollowers(ctx context.Context, opts *DeliverActivityToFoll
 	acct_address := acct.Address(opts.URIs.Hostname)
 	logger = logger.With("from address", acct_address)
 
-	followers_cb := func(ctx context.Context, follower_address string) error {
-
-		logger.Info("Process follower (for activity deliver)", "follower", follower_address)
-
-		already_delivered := false
-
-		deliveries_cb := func(ctx context.Context, d *activitypub.Delivery) error {
-
-			if d.Success {
-				logger.Info("Delivery (to follower) already happened", "delivery id", d.Id, "activity id", d.ActivityId, "recipient", d.Recipient)
-				already_delivered = true
-			}
-
-			return nil
-		}
-
-		// This will probably fail because types...?
-		err := opts.DeliveriesDatabase.GetDeliveriesWithActivityIdAndRecipient(ctx, opts.Activity.Id, follower_address, deliveries_cb)
-
-		if err != nil {
-			logger.Error("Failed to retrieve deliveries for post and recipient", "recipient", follower_address, "error", err)
-			return fmt.Errorf("Failed to retrieve deliveries for post (%d) and recipient (%s), %w", opts.Activity.Id, follower_address, err)
-		}
-
-		if already_delivered {
-			logger.Info("Activity already delivered", "recipient", follower_address)
-			return nil
-		}
-
-		deliver_opts := &deliver.DeliverActivityOptions{
-			To:                 follower_address,
-			Activity:           opts.Activity,
-			URIs:               opts.URIs,
-			AccountsDatabase:   opts.AccountsDatabase,
-			DeliveriesDatabase: opts.DeliveriesDatabase,
-			MaxAttempts:        opts.MaxAttempts,
-		}
-
-		logger.Info("Queue deliver activity", "to", follower_address)
-
-		err = opts.DeliveryQueue.DeliverActivity(ctx, deliver_opts)
-
-		if err != nil {
-			logger.Error("Failed to schedule post delivery", "recipient", follower_address, "error", err)
-			return fmt.Errorf("Failed to deliver post to %s, %w", follower_address, err)
-		}
+	recipient_opts := &DeliverActivityToRecipientsOptions{
+		AccountsDatabase:     opts.AccountsDatabase,
+		DeliveriesDatabase:   opts.DeliveriesDatabase,
+		DeliveryQueue:        opts.DeliveryQueue,
+		Activity:             opts.Activity,
+		MaxAttempts:          opts.MaxAttempts,
+		URIs:                 opts.URIs,
+		ActorsDatabase:       opts.ActorsDatabase,
+		ActorsTTL:            opts.ActorsTTL,
+		DomainBlocksDatabase: opts.DomainBlocksDatabase,
+		DomainAllowsDatabase: opts.DomainAllowsDatabase,
+	}
 
-		logger.Info("Activity delivery complete", "recipient", follower_address)
-		return nil
+	followers_cb := func(ctx context.Context, follower_address string) error {
+		return deliverActivityToRecipient(ctx, recipient_opts, follower_address, logger)
 	}
 
 	err = opts.FollowersDatabase.GetFollowersForAccount(ctx, acct.Id, followers_cb)
@@ -138,3 +110,97 @@ func DeliverActivityToFollowers(ctx context.Context, opts *DeliverActivityToFoll
 
 	return nil
 }
+
+// deliverActivityToRecipient schedules 'opts.Activity' for delivery to 'recipient' unless the recipient's domain is suspended (or, in
+// allowlist mode, not allowed) or the activity has already been delivered to them.
+func deliverActivityToRecipient(ctx context.Context, opts *DeliverActivityToRecipientsOptions, recipient string, logger *slog.Logger) error {
+
+	logger.Info("Process recipient (for activity deliver)", "recipient", recipient)
+
+	// Nothing is delivered to recipients on suspended domains or, if the server is running
+	// in allowlist mode, to recipients on domains that are not on the allowlist
+
+	host, err := blocks.HostFromAddress(recipient)
+
+	if err != nil && opts.DomainAllowsDatabase != nil {
+		logger.Warn("Failed to derive host for recipient, unable to check allowlist, skipping", "recipient", recipient, "error", err)
+		return nil
+	}
+
+	if err != nil {
+		logger.Warn("Failed to derive host for recipient, unable to check domain blocks", "recipient", recipient, "error", err)
+	} else {
+
+		is_suspended, err := blocks.IsSuspendedHost(ctx, opts.DomainBlocksDatabase, host)
+
+		if err != nil {
+			logger.Error("Failed to determine if recipient host is suspended", "recipient", recipient, "error", err)
+			return fmt.Errorf("Failed to determine if recipient host (%s) is suspended, %w", host, err)
+		}
+
+		if is_suspended {
+			logger.Info("Recipient host is suspended, skipping", "recipient", recipient, "host", host)
+			return nil
+		}
+
+		is_allowed, err := blocks.IsAllowedHost(ctx, opts.DomainAllowsDatabase, host)
+
+		if err != nil {
+			logger.Error("Failed to determine if recipient host is allowed", "recipient", recipient, "error", err)
+			return fmt.Errorf("Failed to determine if recipient host (%s) is allowed, %w", host, err)
+		}
+
+		if !is_allowed {
+			logger.Warn("Recipient host is not on allowlist, skipping", "recipient", recipient, "host", host)
+			return nil
+		}
+	}
+
+	already_delivered := false
+
+	deliveries_cb := func(ctx context.Context, d *activitypub.Delivery) error {
+
+		if d.Success {
+			logger.Info("Delivery (to recipient) already happened", "delivery id", d.Id, "activity id", d.ActivityId, "recipient", d.Recipient)
+			already_delivered = true
+		}
+
+		return nil
+	}
+
+	// This will probably fail because types...?
+	err = opts.DeliveriesDatabase.GetDeliveriesWithActivityIdAndRecipient(ctx, opts.Activity.Id, recipient, deliveries_cb)
+
+	if err != nil {
+		logger.Error("Failed to retrieve deliveries for post and recipient", "recipient", recipient, "error", err)
+		return fmt.Errorf("Failed to retrieve deliveries for post (%d) and recipient (%s), %w", opts.Activity.Id, recipient, err)
+	}
+
+	if already_delivered {
+		logger.Info("Activity already delivered", "recipient", recipient)
+		return nil
+	}
+
+	deliver_opts := &deliver.DeliverActivityOptions{
+		To:                 recipient,
+		Activity:           opts.Activity,
+		URIs:               opts.URIs,
+		AccountsDatabase:   opts.AccountsDatabase,
+		DeliveriesDatabase: opts.DeliveriesDatabase,
+		MaxAttempts:        opts.MaxAttempts,
+		ActorsDatabase:     opts.ActorsDatabase,
+		ActorsTTL:          opts.ActorsTTL,
+	}
+
+	logger.Info("Queue deliver activity", "to", recipient)
+
+	err = opts.DeliveryQueue.DeliverActivity(ctx, deliver_opts)
+
+	if err != nil {
+		logger.Error("Failed to schedule post delivery", "recipient", recipient, "error", err)
+		return fmt.Errorf("Failed to deliver post to %s, %w", recipient, err)
+	}
+
+	logger.Info("Activity delivery complete", "recipient", recipient)
+	return nil
+}
@@ -0,0 +1,78 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/database"
+	"github.com/sfomuseum/go-activitypub/uris"
+)
+
+type DeliverActivityToRecipientsOptions struct {
+	AccountsDatabase     database.AccountsDatabase
+	DeliveriesDatabase   database.DeliveriesDatabase
+	DeliveryQueue        DeliveryQueue
+	Activity             *activitypub.Activity
+	Recipients           []string `json:"recipients"`
+	MaxAttempts          int      `json:"max_attempts"`
+	URIs                 *uris.URIs
+	ActorsDatabase       database.ActorsDatabase
+	ActorsTTL            time.Duration
+	DomainBlocksDatabase database.DomainBlocksDatabase
+	DomainAllowsDatabase database.DomainAllowsDatabase
+}
+
+// DeliverActivityToRecipients schedules 'opts.Activity' for delivery to each of the addresses in 'opts.Recipients', rather
+// than the followers of the account that created the activity. For example, to deliver an "Update" activity to everyone who
+// received the original post.
+func DeliverActivityToRecipients(ctx context.Context, opts *DeliverActivityToRecipientsOptions) error {
+
+	logger := slog.Default()
+	logger = logger.With("activity id", opts.Activity.Id)
+	logger = logger.With("activity type", opts.Activity.ActivityType)
+	logger = logger.With("activity type id", opts.Activity.ActivityTypeId)
+	logger = logger.With("account id", opts.Activity.AccountId)
+
+	logger.Info("Deliver activity to recipients", "count", len(opts.Recipients))
+
+	for _, recipient := range opts.Recipients {
+
+		err := deliverActivityToRecipient(ctx, opts, recipient, logger)
+
+		if err != nil {
+			logger.Error("Failed to deliver activity", "recipient", recipient, "error", err)
+			return fmt.Errorf("Failed to deliver activity to %s, %w", recipient, err)
+		}
+	}
+
+	return nil
+}
+
+// ActivityRecipients returns the list of unique addresses that the activity with ID 'activity_id' was successfully delivered to.
+func ActivityRecipients(ctx context.Context, deliveries_db database.DeliveriesDatabase, activity_id int64) ([]string, error) {
+
+	recipients := make([]string, 0)
+	seen := make(map[string]bool)
+
+	deliveries_cb := func(ctx context.Context, d *activitypub.Delivery) error {
+
+		if !d.Success || seen[d.Recipient] {
+			return nil
+		}
+
+		seen[d.Recipient] = true
+		recipients = append(recipients, d.Recipient)
+		return nil
+	}
+
+	err := deliveries_db.GetDeliveriesWithActivityId(ctx, activity_id, deliveries_cb)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve deliveries for activity %d, %w", activity_id, err)
+	}
+
+	return recipients, nil
+}
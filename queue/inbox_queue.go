@@ -0,0 +1,98 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/aaronland/go-roster"
+)
+
+// InboxQueue defines an interface for dispatching the unique ID of a verified activity, posted to the inbox of a local account
+// and stored in a `database.QueuedActivitiesDatabase` instance, to be processed separately from the request that delivered it.
+type InboxQueue interface {
+	ProcessInboxActivity(context.Context, int64) error
+	Close(context.Context) error
+}
+
+var inbox_queue_roster roster.Roster
+
+// InboxQueueInitializationFunc is a function defined by individual inbox_queue package and used to create
+// an instance of that inbox_queue
+type InboxQueueInitializationFunc func(ctx context.Context, uri string) (InboxQueue, error)
+
+// RegisterInboxQueue registers 'scheme' as a key pointing to 'init_func' in an internal lookup table
+// used to create new `InboxQueue` instances by the `NewInboxQueue` method.
+func RegisterInboxQueue(ctx context.Context, scheme string, init_func InboxQueueInitializationFunc) error {
+
+	err := ensureInboxQueueRoster()
+
+	if err != nil {
+		return err
+	}
+
+	return inbox_queue_roster.Register(ctx, scheme, init_func)
+}
+
+func ensureInboxQueueRoster() error {
+
+	if inbox_queue_roster == nil {
+
+		r, err := roster.NewDefaultRoster()
+
+		if err != nil {
+			return err
+		}
+
+		inbox_queue_roster = r
+	}
+
+	return nil
+}
+
+// NewInboxQueue returns a new `InboxQueue` instance configured by 'uri'. The value of 'uri' is parsed
+// as a `url.URL` and its scheme is used as the key for a corresponding `InboxQueueInitializationFunc`
+// function used to instantiate the new `InboxQueue`. It is assumed that the scheme (and initialization
+// function) have been registered by the `RegisterInboxQueue` method.
+func NewInboxQueue(ctx context.Context, uri string) (InboxQueue, error) {
+
+	u, err := url.Parse(uri)
+
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := u.Scheme
+
+	i, err := inbox_queue_roster.Driver(ctx, scheme)
+
+	if err != nil {
+		return nil, err
+	}
+
+	init_func := i.(InboxQueueInitializationFunc)
+	return init_func(ctx, uri)
+}
+
+// Schemes returns the list of schemes that have been registered.
+func InboxQueueSchemes() []string {
+
+	ctx := context.Background()
+	schemes := []string{}
+
+	err := ensureInboxQueueRoster()
+
+	if err != nil {
+		return schemes
+	}
+
+	for _, dr := range inbox_queue_roster.Drivers(ctx) {
+		scheme := fmt.Sprintf("%s://", strings.ToLower(dr))
+		schemes = append(schemes, scheme)
+	}
+
+	sort.Strings(schemes)
+	return schemes
+}
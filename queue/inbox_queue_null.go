@@ -0,0 +1,32 @@
+package queue
+
+import (
+	"context"
+)
+
+type NullInboxQueue struct {
+	InboxQueue
+}
+
+func init() {
+	ctx := context.Background()
+	err := RegisterInboxQueue(ctx, "null", NewNullInboxQueue)
+
+	if err != nil {
+		panic(err)
+	}
+
+}
+
+func NewNullInboxQueue(ctx context.Context, uri string) (InboxQueue, error) {
+	q := &NullInboxQueue{}
+	return q, nil
+}
+
+func (q *NullInboxQueue) ProcessInboxActivity(ctx context.Context, queued_activity_id int64) error {
+	return nil
+}
+
+func (q *NullInboxQueue) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,106 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/sfomuseum/go-pubsub/publisher"
+)
+
+type PubSubInboxQueue struct {
+	InboxQueue
+	publisher publisher.Publisher
+}
+
+var inbox_register_mu = new(sync.RWMutex)
+var inbox_register_map = map[string]bool{}
+
+func init() {
+
+	ctx := context.Background()
+
+	err := RegisterPubSubInboxSchemes(ctx)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func RegisterPubSubInboxSchemes(ctx context.Context) error {
+
+	inbox_register_mu.Lock()
+	defer inbox_register_mu.Unlock()
+
+	to_register := []string{
+		"awssqs-creds",
+	}
+
+	for _, scheme := range publisher.PublisherSchemes() {
+
+		scheme = strings.Replace(scheme, "://", "", 1)
+
+		// I don't love this so maybe prefix everything as pubsub-{SCHEME} ? TBD... ?
+
+		if scheme != "null" {
+			to_register = append(to_register, scheme)
+		}
+	}
+
+	for _, scheme := range to_register {
+
+		_, exists := inbox_register_map[scheme]
+
+		if exists {
+			continue
+		}
+
+		err := RegisterInboxQueue(ctx, scheme, NewPubSubInboxQueue)
+
+		if err != nil {
+			return fmt.Errorf("Failed to register inbox queue for '%s', %w", scheme, err)
+		}
+
+		inbox_register_map[scheme] = true
+	}
+
+	return nil
+}
+
+func NewPubSubInboxQueue(ctx context.Context, uri string) (InboxQueue, error) {
+
+	pub, err := publisher.NewPublisher(ctx, uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create publisher, %w", err)
+	}
+
+	q := &PubSubInboxQueue{
+		publisher: pub,
+	}
+
+	return q, nil
+}
+
+func (q *PubSubInboxQueue) ProcessInboxActivity(ctx context.Context, queued_activity_id int64) error {
+
+	enc_id, err := json.Marshal(queued_activity_id)
+
+	if err != nil {
+		return fmt.Errorf("Failed to marshal queued activity ID, %w", err)
+	}
+
+	err = q.publisher.Publish(ctx, string(enc_id))
+
+	if err != nil {
+		return fmt.Errorf("Failed to send queued activity ID, %w", err)
+	}
+
+	return nil
+}
+
+func (q *PubSubInboxQueue) Close(ctx context.Context) error {
+	return q.publisher.Close()
+}
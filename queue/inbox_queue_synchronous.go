@@ -0,0 +1,54 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+)
+
+// ProcessInboxActivityFunc is a function used to process a queued inbox activity with a given unique ID.
+type ProcessInboxActivityFunc func(context.Context, int64) error
+
+// SynchronousInboxQueue processes queued inbox activities immediately, in the same process that queued them, using
+// the function assigned by the `SetProcessInboxActivityFunc` method.
+type SynchronousInboxQueue struct {
+	InboxQueue
+	process_func ProcessInboxActivityFunc
+}
+
+func init() {
+	ctx := context.Background()
+	err := RegisterInboxQueue(ctx, "synchronous", NewSynchronousInboxQueue)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func NewSynchronousInboxQueue(ctx context.Context, uri string) (InboxQueue, error) {
+	q := &SynchronousInboxQueue{}
+	return q, nil
+}
+
+// SetProcessInboxActivityFunc assigns 'fn' as the function used to process queued inbox activities.
+func (q *SynchronousInboxQueue) SetProcessInboxActivityFunc(fn ProcessInboxActivityFunc) {
+	q.process_func = fn
+}
+
+func (q *SynchronousInboxQueue) ProcessInboxActivity(ctx context.Context, queued_activity_id int64) error {
+
+	if q.process_func == nil {
+		return fmt.Errorf("Synchronous inbox queue does not have a process function")
+	}
+
+	err := q.process_func(ctx, queued_activity_id)
+
+	if err != nil {
+		return fmt.Errorf("Failed to process queued activity, %w", err)
+	}
+
+	return nil
+}
+
+func (q *SynchronousInboxQueue) Close(ctx context.Context) error {
+	return nil
+}
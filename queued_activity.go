@@ -0,0 +1,93 @@
+package activitypub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/id"
+)
+
+// QueuedActivity is a record of a verified activity posted to the inbox of a local account which has been queued
+// for processing (by an `InboxQueue`) rather than processed while the remote server waits for a response.
+type QueuedActivity struct {
+	// The unique ID of the record.
+	Id int64 `json:"id"`
+	// The ActivityPub "id" of the activity that was received.
+	ActivityPubId string `json:"activitypub_id"`
+	// The unique ID of the account whose inbox the activity was posted to.
+	AccountId int64 `json:"account_id"`
+	// The type of activity that was received.
+	ActivityType string `json:"activity_type"`
+	// The "@name@host" address of the actor posting the activity.
+	RequestorAddress string `json:"requestor_address"`
+	// The JSON-encoded actor whose key was used to sign the request.
+	RequestorActor string `json:"requestor_actor"`
+	// The raw (JSON-encoded) activity that was posted to the inbox.
+	Body string `json:"body"`
+	// The Unix timestamp when the activity was received.
+	Created int64 `json:"created"`
+}
+
+// NewQueuedActivity returns a new `QueuedActivity` instance for 'activity', whose raw (JSON-encoded) representation is 'body',
+// posted to the inbox of 'account_id' by 'requestor_address' and signed by 'requestor_actor'.
+func NewQueuedActivity(ctx context.Context, activity *ap.Activity, body []byte, account_id int64, requestor_address string, requestor_actor *ap.Actor) (*QueuedActivity, error) {
+
+	db_id, err := id.NewId()
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create new queued activity ID, %w", err)
+	}
+
+	enc_actor, err := json.Marshal(requestor_actor)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal requestor actor, %w", err)
+	}
+
+	now := time.Now()
+	ts := now.Unix()
+
+	q := &QueuedActivity{
+		Id:               db_id,
+		ActivityPubId:    activity.Id,
+		AccountId:        account_id,
+		ActivityType:     activity.Type,
+		RequestorAddress: requestor_address,
+		RequestorActor:   string(enc_actor),
+		Body:             string(body),
+		Created:          ts,
+	}
+
+	return q, nil
+}
+
+// UnmarshalActivity returns the `ap.Activity` instance encoded in 'q'.
+func (q *QueuedActivity) UnmarshalActivity() (*ap.Activity, error) {
+
+	var activity *ap.Activity
+
+	err := json.Unmarshal([]byte(q.Body), &activity)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal activity, %w", err)
+	}
+
+	return activity, nil
+}
+
+// UnmarshalRequestorActor returns the `ap.Actor` instance, for the actor whose key was used to sign the request, encoded in 'q'.
+func (q *QueuedActivity) UnmarshalRequestorActor() (*ap.Actor, error) {
+
+	var actor *ap.Actor
+
+	err := json.Unmarshal([]byte(q.RequestorActor), &actor)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal requestor actor, %w", err)
+	}
+
+	return actor, nil
+}
@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit defines the parameters of a token bucket. Tokens are added to the bucket at a rate of 'Rate' tokens per
+// second up to a maximum of 'Burst' tokens and each request consumes a single token.
+type Limit struct {
+	// Rate is the number of tokens added to the bucket each second.
+	Rate float64
+	// Burst is the maximum number of tokens the bucket can hold.
+	Burst int
+}
+
+// PerMinute returns a new `Limit` instance allowing 'count' requests per minute with bursts of up to 'burst' requests.
+// If 'burst' is less than one then it will be set to one.
+func PerMinute(count int, burst int) *Limit {
+
+	if burst < 1 {
+		burst = 1
+	}
+
+	l := &Limit{
+		Rate:  float64(count) / 60.0,
+		Burst: burst,
+	}
+
+	return l
+}
+
+// IsUnlimited returns a boolean value indicating whether 'l' does not impose any limits.
+func (l *Limit) IsUnlimited() bool {
+	return l == nil || l.Rate <= 0
+}
+
+// TTL returns the amount of time it takes for an empty bucket to be filled. Buckets that have not been
+// updated for this long are full and can be safely discarded.
+func (l *Limit) TTL() time.Duration {
+	seconds := float64(l.Burst) / l.Rate
+	return time.Duration(math.Ceil(seconds * float64(time.Second)))
+}
+
+// take refills a bucket containing 'tokens' tokens, last updated at 'updated', up until 'now' and then consumes a
+// single token. It returns the number of tokens remaining in the bucket, a boolean value indicating whether a token
+// was available and, if not, the amount of time until a token will be available.
+func (l *Limit) take(tokens float64, updated time.Time, now time.Time) (float64, bool, time.Duration) {
+
+	elapsed := now.Sub(updated).Seconds()
+
+	if elapsed > 0 {
+		tokens = math.Min(float64(l.Burst), tokens+(elapsed*l.Rate))
+	}
+
+	if tokens >= 1 {
+		return tokens - 1, true, 0
+	}
+
+	return tokens, false, l.wait(tokens)
+}
+
+// wait returns the amount of time until a bucket containing 'tokens' tokens will contain a single token.
+func (l *Limit) wait(tokens float64) time.Duration {
+	seconds := (1 - tokens) / l.Rate
+	return time.Duration(math.Ceil(seconds * float64(time.Second)))
+}
+
+// Allow consumes a single token from the bucket identified by 'key' in 'rl' and returns a boolean value indicating
+// whether a token was available. If not, the amount of time until a token will be available is also returned. If 'rl'
+// is nil or 'limit' is unlimited then the request is always allowed.
+func Allow(ctx context.Context, rl RateLimiter, key string, limit *Limit) (bool, time.Duration, error) {
+
+	if rl == nil || limit.IsUnlimited() {
+		return true, 0, nil
+	}
+
+	return rl.Allow(ctx, key, limit)
+}
@@ -0,0 +1,104 @@
+// Package ratelimit provides token bucket rate limiting, with pluggable storage for counters, used to throttle requests
+// from remote hosts and actors.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aaronland/go-roster"
+)
+
+// RateLimiter defines an interface for storing token buckets and consuming tokens from them.
+type RateLimiter interface {
+	// Allow consumes a single token from the bucket identified by a key, creating the bucket if necessary, and
+	// returns a boolean value indicating whether a token was available. If not, the amount of time until a token
+	// will be available is also returned.
+	Allow(context.Context, string, *Limit) (bool, time.Duration, error)
+	// Close performs any final operations to terminate the underlying storage connection.
+	Close(context.Context) error
+}
+
+var rate_limiter_roster roster.Roster
+
+// RateLimiterInitializationFunc is a function defined by individual rate_limiter package and used to create
+// an instance of that rate_limiter
+type RateLimiterInitializationFunc func(ctx context.Context, uri string) (RateLimiter, error)
+
+// RegisterRateLimiter registers 'scheme' as a key pointing to 'init_func' in an internal lookup table
+// used to create new `RateLimiter` instances by the `NewRateLimiter` method.
+func RegisterRateLimiter(ctx context.Context, scheme string, init_func RateLimiterInitializationFunc) error {
+
+	err := ensureRateLimiterRoster()
+
+	if err != nil {
+		return err
+	}
+
+	return rate_limiter_roster.Register(ctx, scheme, init_func)
+}
+
+func ensureRateLimiterRoster() error {
+
+	if rate_limiter_roster == nil {
+
+		r, err := roster.NewDefaultRoster()
+
+		if err != nil {
+			return err
+		}
+
+		rate_limiter_roster = r
+	}
+
+	return nil
+}
+
+// NewRateLimiter returns a new `RateLimiter` instance configured by 'uri'. The value of 'uri' is parsed
+// as a `url.URL` and its scheme is used as the key for a corresponding `RateLimiterInitializationFunc`
+// function used to instantiate the new `RateLimiter`. It is assumed that the scheme (and initialization
+// function) have been registered by the `RegisterRateLimiter` method.
+func NewRateLimiter(ctx context.Context, uri string) (RateLimiter, error) {
+
+	u, err := url.Parse(uri)
+
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := u.Scheme
+
+	i, err := rate_limiter_roster.Driver(ctx, scheme)
+
+	if err != nil {
+		return nil, err
+	}
+
+	init_func := i.(RateLimiterInitializationFunc)
+	return init_func(ctx, uri)
+}
+
+// Schemes returns the list of schemes that have been registered.
+func RateLimiterSchemes() []string {
+
+	ctx := context.Background()
+	schemes := []string{}
+
+	err := ensureRateLimiterRoster()
+
+	if err != nil {
+		return schemes
+	}
+
+	for _, dr := range rate_limiter_roster.Drivers(ctx) {
+		scheme := fmt.Sprintf("%s://", strings.ToLower(dr))
+		schemes = append(schemes, scheme)
+	}
+
+	sort.Strings(schemes)
+	return schemes
+}
@@ -0,0 +1,138 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	aa_docstore "github.com/aaronland/gocloud/docstore"
+	gc_docstore "gocloud.dev/docstore"
+	"gocloud.dev/gcerrors"
+)
+
+// The maximum number of times to retry updating a bucket that was modified by another process
+const docstore_max_retries int = 5
+
+// docstoreBucket is the document used to store a token bucket.
+type docstoreBucket struct {
+	Key string
+	// The number of tokens in the bucket
+	Tokens float64
+	// The Unix timestamp, in milliseconds, when the bucket was last updated
+	Updated int64
+	// The Unix timestamp after which the bucket has been refilled completely and can be discarded. Use this
+	// as the DynamoDB "time to live" attribute to have stale buckets removed automatically.
+	Expires          int64
+	DocstoreRevision interface{}
+}
+
+// DocstoreRateLimiter implements the `RateLimiter` interface storing token buckets in a gocloud.dev/docstore collection
+// (for example DynamoDB). Counters are shared by all the processes using the same collection and concurrent updates are
+// handled using optimistic locking (document revisions).
+type DocstoreRateLimiter struct {
+	RateLimiter
+	collection *gc_docstore.Collection
+}
+
+func init() {
+
+	ctx := context.Background()
+
+	err := RegisterRateLimiter(ctx, "awsdynamodb", NewDocstoreRateLimiter)
+
+	if err != nil {
+		panic(err)
+	}
+
+	for _, scheme := range gc_docstore.DefaultURLMux().CollectionSchemes() {
+		err := RegisterRateLimiter(ctx, scheme, NewDocstoreRateLimiter)
+
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+// NewDocstoreRateLimiter returns a new `DocstoreRateLimiter` instance configured by 'uri' which is expected to be a valid
+// aaronland/gocloud/docstore URI whose partition key is "Key". For example:
+//
+//	awsdynamodb://rate_limits?partition_key=Key
+func NewDocstoreRateLimiter(ctx context.Context, uri string) (RateLimiter, error) {
+
+	col, err := aa_docstore.OpenCollection(ctx, uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open collection, %w", err)
+	}
+
+	rl := &DocstoreRateLimiter{
+		collection: col,
+	}
+
+	return rl, nil
+}
+
+func (rl *DocstoreRateLimiter) Allow(ctx context.Context, key string, limit *Limit) (bool, time.Duration, error) {
+
+	for i := 0; i < docstore_max_retries; i++ {
+
+		allowed, wait, err := rl.take(ctx, key, limit)
+
+		switch gcerrors.Code(err) {
+		case gcerrors.OK:
+			return allowed, wait, nil
+		case gcerrors.AlreadyExists, gcerrors.FailedPrecondition:
+			// Bucket was created or updated by another process, try again
+			continue
+		default:
+			return false, 0, fmt.Errorf("Failed to update bucket, %w", err)
+		}
+	}
+
+	return false, 0, fmt.Errorf("Failed to update bucket after %d attempts", docstore_max_retries)
+}
+
+func (rl *DocstoreRateLimiter) Close(ctx context.Context) error {
+	return rl.collection.Close()
+}
+
+func (rl *DocstoreRateLimiter) take(ctx context.Context, key string, limit *Limit) (bool, time.Duration, error) {
+
+	now := time.Now()
+
+	b := &docstoreBucket{
+		Key: key,
+	}
+
+	err := rl.collection.Get(ctx, b)
+	is_new := false
+
+	switch gcerrors.Code(err) {
+	case gcerrors.OK:
+		// pass
+	case gcerrors.NotFound:
+		b.Tokens = float64(limit.Burst)
+		b.Updated = now.UnixMilli()
+		is_new = true
+	default:
+		return false, 0, err
+	}
+
+	tokens, allowed, wait := limit.take(b.Tokens, time.UnixMilli(b.Updated), now)
+
+	b.Tokens = tokens
+	b.Updated = now.UnixMilli()
+	b.Expires = now.Add(limit.TTL()).Unix()
+
+	if is_new {
+		err = rl.collection.Create(ctx, b)
+	} else {
+		err = rl.collection.Replace(ctx, b)
+	}
+
+	if err != nil {
+		return false, 0, err
+	}
+
+	return allowed, wait, nil
+}
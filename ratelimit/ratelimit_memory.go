@@ -0,0 +1,96 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// How often to discard buckets that have been refilled completely
+const memory_prune_interval time.Duration = 1 * time.Minute
+
+type memoryBucket struct {
+	tokens  float64
+	updated time.Time
+	ttl     time.Duration
+}
+
+// MemoryRateLimiter implements the `RateLimiter` interface storing token buckets in memory. Counters are not
+// shared between processes so this implementation is only suitable for a single server instance.
+type MemoryRateLimiter struct {
+	RateLimiter
+	buckets    map[string]*memoryBucket
+	mu         *sync.Mutex
+	last_prune time.Time
+}
+
+func init() {
+	ctx := context.Background()
+	err := RegisterRateLimiter(ctx, "memory", NewMemoryRateLimiter)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+// NewMemoryRateLimiter returns a new `MemoryRateLimiter` instance configured by 'uri' which is expected to take the form of:
+//
+//	memory://
+func NewMemoryRateLimiter(ctx context.Context, uri string) (RateLimiter, error) {
+
+	rl := &MemoryRateLimiter{
+		buckets:    make(map[string]*memoryBucket),
+		mu:         new(sync.Mutex),
+		last_prune: time.Now(),
+	}
+
+	return rl, nil
+}
+
+func (rl *MemoryRateLimiter) Allow(ctx context.Context, key string, limit *Limit) (bool, time.Duration, error) {
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+
+	if now.Sub(rl.last_prune) > memory_prune_interval {
+		rl.prune(now)
+	}
+
+	b, exists := rl.buckets[key]
+
+	if !exists {
+
+		b = &memoryBucket{
+			tokens:  float64(limit.Burst),
+			updated: now,
+		}
+
+		rl.buckets[key] = b
+	}
+
+	tokens, allowed, wait := limit.take(b.tokens, b.updated, now)
+
+	b.tokens = tokens
+	b.updated = now
+	b.ttl = limit.TTL()
+
+	return allowed, wait, nil
+}
+
+func (rl *MemoryRateLimiter) Close(ctx context.Context) error {
+	return nil
+}
+
+func (rl *MemoryRateLimiter) prune(now time.Time) {
+
+	for key, b := range rl.buckets {
+
+		if now.Sub(b.updated) > b.ttl {
+			delete(rl.buckets, key)
+		}
+	}
+
+	rl.last_prune = now
+}
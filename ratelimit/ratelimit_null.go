@@ -0,0 +1,33 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// NullRateLimiter implements the `RateLimiter` interface but does not impose any limits.
+type NullRateLimiter struct {
+	RateLimiter
+}
+
+func init() {
+	ctx := context.Background()
+	err := RegisterRateLimiter(ctx, "null", NewNullRateLimiter)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func NewNullRateLimiter(ctx context.Context, uri string) (RateLimiter, error) {
+	rl := &NullRateLimiter{}
+	return rl, nil
+}
+
+func (rl *NullRateLimiter) Allow(ctx context.Context, key string, limit *Limit) (bool, time.Duration, error) {
+	return true, 0, nil
+}
+
+func (rl *NullRateLimiter) Close(ctx context.Context) error {
+	return nil
+}
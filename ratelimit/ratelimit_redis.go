@@ -0,0 +1,152 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// The default prefix assigned to the keys used to store token buckets
+const REDIS_DEFAULT_PREFIX string = "activitypub:ratelimit:"
+
+// Refill and consume a token from a bucket atomically. The bucket is stored as a hash with "tokens" and "updated"
+// (Unix milliseconds) fields and expires once it would have been refilled completely.
+var redis_take_script = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local ttl = tonumber(ARGV[4])
+
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "updated")
+local tokens = tonumber(bucket[1])
+local updated = tonumber(bucket[2])
+
+if tokens == nil or updated == nil then
+	tokens = burst
+	updated = now
+end
+
+local elapsed = math.max(0, now - updated) / 1000
+tokens = math.min(burst, tokens + (elapsed * rate))
+
+local allowed = 0
+
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "updated", now)
+redis.call("PEXPIRE", KEYS[1], ttl)
+
+return { allowed, tostring(tokens) }
+`)
+
+// RedisRateLimiter implements the `RateLimiter` interface storing token buckets in a Redis database. Counters
+// are shared by all the processes using the same Redis database.
+type RedisRateLimiter struct {
+	RateLimiter
+	client *redis.Client
+	prefix string
+}
+
+func init() {
+	ctx := context.Background()
+	err := RegisterRateLimiter(ctx, "redis", NewRedisRateLimiter)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+// NewRedisRateLimiter returns a new `RedisRateLimiter` instance configured by 'uri' which is expected to take the form of:
+//
+//	redis://{HOST}:{PORT}/{DATABASE}?prefix={PREFIX}
+//
+// Where {PREFIX} is an optional string to prepend to the keys used to store token buckets. If empty then `REDIS_DEFAULT_PREFIX`
+// will be used. All other parameters are handled by the `redis.ParseURL` method.
+func NewRedisRateLimiter(ctx context.Context, uri string) (RateLimiter, error) {
+
+	u, err := url.Parse(uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse URI, %w", err)
+	}
+
+	q := u.Query()
+
+	prefix := REDIS_DEFAULT_PREFIX
+
+	if q.Has("prefix") {
+		prefix = q.Get("prefix")
+		q.Del("prefix")
+	}
+
+	u.RawQuery = q.Encode()
+
+	redis_opts, err := redis.ParseURL(u.String())
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse Redis URI, %w", err)
+	}
+
+	client := redis.NewClient(redis_opts)
+
+	rl := &RedisRateLimiter{
+		client: client,
+		prefix: prefix,
+	}
+
+	return rl, nil
+}
+
+func (rl *RedisRateLimiter) Allow(ctx context.Context, key string, limit *Limit) (bool, time.Duration, error) {
+
+	now := time.Now()
+
+	keys := []string{
+		rl.prefix + key,
+	}
+
+	rsp, err := redis_take_script.Run(ctx, rl.client, keys, limit.Rate, limit.Burst, now.UnixMilli(), limit.TTL().Milliseconds()).Slice()
+
+	if err != nil {
+		return false, 0, fmt.Errorf("Failed to update bucket, %w", err)
+	}
+
+	if len(rsp) != 2 {
+		return false, 0, fmt.Errorf("Unexpected response updating bucket")
+	}
+
+	allowed, ok := rsp[0].(int64)
+
+	if !ok {
+		return false, 0, fmt.Errorf("Unexpected response updating bucket")
+	}
+
+	if allowed == 1 {
+		return true, 0, nil
+	}
+
+	str_tokens, ok := rsp[1].(string)
+
+	if !ok {
+		return false, 0, fmt.Errorf("Unexpected response updating bucket")
+	}
+
+	tokens, err := strconv.ParseFloat(str_tokens, 64)
+
+	if err != nil {
+		return false, 0, fmt.Errorf("Failed to parse tokens, %w", err)
+	}
+
+	return false, limit.wait(tokens), nil
+}
+
+func (rl *RedisRateLimiter) Close(ctx context.Context) error {
+	return rl.client.Close()
+}
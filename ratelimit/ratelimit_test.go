@@ -0,0 +1,118 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLimitTake(t *testing.T) {
+
+	limit := PerMinute(60, 2)
+
+	now := time.Now()
+
+	tokens, allowed, _ := limit.take(2, now, now)
+
+	if !allowed || tokens != 1 {
+		t.Fatalf("Expected first request to be allowed, %f tokens remaining", tokens)
+	}
+
+	tokens, allowed, _ = limit.take(tokens, now, now)
+
+	if !allowed || tokens != 0 {
+		t.Fatalf("Expected second request to be allowed, %f tokens remaining", tokens)
+	}
+
+	_, allowed, wait := limit.take(tokens, now, now)
+
+	if allowed {
+		t.Fatalf("Expected third request to be refused")
+	}
+
+	if wait != time.Second {
+		t.Fatalf("Unexpected wait time, %v", wait)
+	}
+
+	// One token is added every second
+
+	tokens, allowed, _ = limit.take(0, now, now.Add(1500*time.Millisecond))
+
+	if !allowed || tokens != 0.5 {
+		t.Fatalf("Expected request to be allowed after bucket was refilled, %f tokens remaining", tokens)
+	}
+
+	// Buckets are never filled above their burst size
+
+	tokens, _, _ = limit.take(0, now, now.Add(time.Hour))
+
+	if tokens != 1 {
+		t.Fatalf("Expected bucket to be capped at burst size, %f tokens remaining", tokens)
+	}
+}
+
+func TestMemoryRateLimiter(t *testing.T) {
+
+	ctx := context.Background()
+
+	rl, err := NewRateLimiter(ctx, "memory://")
+
+	if err != nil {
+		t.Fatalf("Failed to create rate limiter, %v", err)
+	}
+
+	defer rl.Close(ctx)
+
+	limit := PerMinute(1, 3)
+
+	for i := 0; i < 3; i++ {
+
+		allowed, _, err := rl.Allow(ctx, "host:example.com", limit)
+
+		if err != nil {
+			t.Fatalf("Failed to check rate limit, %v", err)
+		}
+
+		if !allowed {
+			t.Fatalf("Expected request %d to be allowed", i)
+		}
+	}
+
+	allowed, wait, err := rl.Allow(ctx, "host:example.com", limit)
+
+	if err != nil {
+		t.Fatalf("Failed to check rate limit, %v", err)
+	}
+
+	if allowed {
+		t.Fatalf("Expected request to be refused")
+	}
+
+	if wait <= 0 || wait > time.Minute {
+		t.Fatalf("Unexpected wait time, %v", wait)
+	}
+
+	// Buckets are independent
+
+	allowed, _, err = rl.Allow(ctx, "host:example.org", limit)
+
+	if err != nil {
+		t.Fatalf("Failed to check rate limit, %v", err)
+	}
+
+	if !allowed {
+		t.Fatalf("Expected request for other key to be allowed")
+	}
+
+	// Unlimited
+
+	allowed, _, err = Allow(ctx, rl, "host:example.com", PerMinute(0, 0))
+
+	if err != nil {
+		t.Fatalf("Failed to check rate limit, %v", err)
+	}
+
+	if !allowed {
+		t.Fatalf("Expected unlimited request to be allowed")
+	}
+}
@@ -0,0 +1,106 @@
+package activitypub
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/id"
+)
+
+const (
+	// ReceivedActivityProcessing is the outcome for activities that have been received but not yet processed.
+	ReceivedActivityProcessing string = "processing"
+	// ReceivedActivityAccepted is the outcome for activities that were processed successfully.
+	ReceivedActivityAccepted string = "accepted"
+	// ReceivedActivityRejected is the outcome for activities that were refused because of a problem with the activity or requestor.
+	ReceivedActivityRejected string = "rejected"
+	// ReceivedActivityFailed is the outcome for activities that could not be processed because of a server error.
+	ReceivedActivityFailed string = "failed"
+)
+
+// ReceivedActivity is a record of an activity posted to the inbox of a local account.
+type ReceivedActivity struct {
+	// The unique ID of the record.
+	Id int64 `json:"id"`
+	// The ActivityPub "id" of the activity that was received.
+	ActivityPubId string `json:"activitypub_id"`
+	// The unique ID of the account whose inbox the activity was posted to.
+	AccountId int64 `json:"account_id"`
+	// The actor performing the activity.
+	Actor string `json:"actor"`
+	// The type of activity that was received.
+	ActivityType string `json:"activity_type"`
+	// The HTTP status code returned when the activity was processed or 0 if it has not been processed yet.
+	Status int `json:"status"`
+	// The outcome of processing the activity. One of "processing", "accepted", "rejected" or "failed".
+	Outcome string `json:"outcome"`
+	// The Unix timestamp when the activity was received.
+	Received int64 `json:"received"`
+}
+
+// NewReceivedActivity returns a new `ReceivedActivity` instance for 'activity' posted to the inbox of 'account_id' whose
+// processing returned the HTTP status code 'status'.
+func NewReceivedActivity(ctx context.Context, activity *ap.Activity, account_id int64, status int) (*ReceivedActivity, error) {
+
+	db_id, err := id.NewId()
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create new received activity ID, %w", err)
+	}
+
+	now := time.Now()
+	ts := now.Unix()
+
+	r := &ReceivedActivity{
+		Id:            db_id,
+		ActivityPubId: activity.Id,
+		AccountId:     account_id,
+		Actor:         activity.Actor,
+		ActivityType:  activity.Type,
+		Status:        status,
+		Outcome:       ReceivedActivityOutcome(status),
+		Received:      ts,
+	}
+
+	return r, nil
+}
+
+// ReceivedActivityOutcome returns the outcome string associated with the HTTP status code 'status'.
+func ReceivedActivityOutcome(status int) string {
+
+	switch {
+	case status < http.StatusBadRequest:
+		return ReceivedActivityAccepted
+	case status < http.StatusInternalServerError:
+		return ReceivedActivityRejected
+	default:
+		return ReceivedActivityFailed
+	}
+}
+
+// NewProcessingReceivedActivity returns a new `ReceivedActivity` instance for 'activity' posted to the inbox of 'account_id'
+// which has been received but not yet processed.
+func NewProcessingReceivedActivity(ctx context.Context, activity *ap.Activity, account_id int64) (*ReceivedActivity, error) {
+
+	r, err := NewReceivedActivity(ctx, activity, account_id, 0)
+
+	if err != nil {
+		return nil, err
+	}
+
+	r.Outcome = ReceivedActivityProcessing
+	return r, nil
+}
+
+// IsProcessing returns a boolean value indicating whether the activity recorded by 'r' is still being processed.
+func (r *ReceivedActivity) IsProcessing() bool {
+	return r.Outcome == ReceivedActivityProcessing
+}
+
+// IsAccepted returns a boolean value indicating whether the activity recorded by 'r' was processed successfully.
+func (r *ReceivedActivity) IsAccepted() bool {
+	return r.Outcome == ReceivedActivityAccepted
+}
@@ -0,0 +1,27 @@
+package activitypub
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestReceivedActivityOutcome(t *testing.T) {
+
+	tests := map[int]string{
+		http.StatusOK:                  ReceivedActivityAccepted,
+		http.StatusAccepted:            ReceivedActivityAccepted,
+		http.StatusUnauthorized:        ReceivedActivityRejected,
+		http.StatusForbidden:           ReceivedActivityRejected,
+		http.StatusNotImplemented:      ReceivedActivityFailed,
+		http.StatusInternalServerError: ReceivedActivityFailed,
+	}
+
+	for status, expected := range tests {
+
+		outcome := ReceivedActivityOutcome(status)
+
+		if outcome != expected {
+			t.Fatalf("Unexpected outcome for status %d, expected '%s' but got '%s'", status, expected, outcome)
+		}
+	}
+}
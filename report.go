@@ -0,0 +1,63 @@
+package activitypub
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sfomuseum/go-activitypub/id"
+)
+
+// Report is a moderation report ("Flag" activity) received from a remote actor about a local account and, optionally,
+// one or more of its posts.
+type Report struct {
+	// The unique ID of the report.
+	Id int64 `json:"id"`
+	// The unique ID of the local account being reported.
+	AccountId int64 `json:"account_id"`
+	// The ID of the "Flag" activity that created the report.
+	ActivityId string `json:"activity_id"`
+	// The URI of the (remote) actor who sent the report. This is often a server-wide "instance" actor rather than the person who filed the report.
+	ReporterActor string `json:"reporter_actor"`
+	// The unique IDs of the account's posts referenced by the report.
+	PostIds []int64 `json:"post_ids,omitempty"`
+	// The comment included with the report.
+	Comment string `json:"comment,omitempty"`
+	// The Unix timestamp when the report was resolved. If 0 the report has not been resolved.
+	Resolved int64 `json:"resolved"`
+	// The Unix timestamp when the report was created.
+	Created int64 `json:"created"`
+	// The Unix timestamp when the report was last modified.
+	LastModified int64 `json:"lastmodified"`
+}
+
+// NewReport returns a new `Report` instance for the account with unique ID 'account_id' created by the "Flag" activity
+// with ID 'activity_id' sent by 'reporter_actor'.
+func NewReport(ctx context.Context, account_id int64, reporter_actor string, activity_id string) (*Report, error) {
+
+	report_id, err := id.NewId()
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create new report ID, %w", err)
+	}
+
+	now := time.Now()
+	ts := now.Unix()
+
+	r := &Report{
+		Id:            report_id,
+		AccountId:     account_id,
+		ActivityId:    activity_id,
+		ReporterActor: reporter_actor,
+		PostIds:       make([]int64, 0),
+		Created:       ts,
+		LastModified:  ts,
+	}
+
+	return r, nil
+}
+
+// IsResolved returns a boolean value indicating whether 'r' has been resolved.
+func (r *Report) IsResolved() bool {
+	return r.Resolved > 0
+}
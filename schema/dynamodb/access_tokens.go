@@ -0,0 +1,58 @@
+package dynamodb
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+var DynamoDBAccessTokensTable = &dynamodb.CreateTableInput{
+	KeySchema: []types.KeySchemaElement{
+		{
+			AttributeName: aws.String("Id"), // partition key
+			KeyType:       "HASH",
+		},
+	},
+	AttributeDefinitions: []types.AttributeDefinition{
+		{
+			AttributeName: aws.String("Id"),
+			AttributeType: "N",
+		},
+		{
+			AttributeName: aws.String("AccountId"),
+			AttributeType: "N",
+		},
+		{
+			AttributeName: aws.String("Token"),
+			AttributeType: "S",
+		},
+	},
+	GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{
+		{
+			IndexName: aws.String("by_account"),
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("AccountId"),
+					KeyType:       "HASH",
+				},
+			},
+			Projection: &types.Projection{
+				ProjectionType: "ALL",
+			},
+		},
+		{
+			IndexName: aws.String("by_token"),
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("Token"),
+					KeyType:       "HASH",
+				},
+			},
+			Projection: &types.Projection{
+				ProjectionType: "ALL",
+			},
+		},
+	},
+	BillingMode: BILLING_MODE,
+	TableName:   &ACCESS_TOKENS_TABLE_NAME,
+}
@@ -56,6 +56,22 @@ var DynamoDBActivitiesTable = &dynamodb.CreateTableInput{
 				ProjectionType: "ALL",
 			},
 		},
+		{
+			IndexName: aws.String("by_account_id_and_id"),
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("AccountId"),
+					KeyType:       "HASH",
+				},
+				{
+					AttributeName: aws.String("Id"),
+					KeyType:       "RANGE",
+				},
+			},
+			Projection: &types.Projection{
+				ProjectionType: "ALL",
+			},
+		},
 		{
 			IndexName: aws.String("by_created"),
 			KeySchema: []types.KeySchemaElement{
@@ -0,0 +1,42 @@
+package dynamodb
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+var DynamoDBActorsTable = &dynamodb.CreateTableInput{
+	KeySchema: []types.KeySchemaElement{
+		{
+			AttributeName: aws.String("Id"), // partition key
+			KeyType:       "HASH",
+		},
+	},
+	AttributeDefinitions: []types.AttributeDefinition{
+		{
+			AttributeName: aws.String("Id"),
+			AttributeType: "S",
+		},
+		{
+			AttributeName: aws.String("Address"),
+			AttributeType: "S",
+		},
+	},
+	GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{
+		{
+			IndexName: aws.String("by_address"),
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("Address"),
+					KeyType:       "HASH",
+				},
+			},
+			Projection: &types.Projection{
+				ProjectionType: "ALL",
+			},
+		},
+	},
+	BillingMode: BILLING_MODE,
+	TableName:   &ACTORS_TABLE_NAME,
+}
@@ -0,0 +1,42 @@
+package dynamodb
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+var DynamoDBDomainBlocksTable = &dynamodb.CreateTableInput{
+	KeySchema: []types.KeySchemaElement{
+		{
+			AttributeName: aws.String("Id"), // partition key
+			KeyType:       "HASH",
+		},
+	},
+	AttributeDefinitions: []types.AttributeDefinition{
+		{
+			AttributeName: aws.String("Id"),
+			AttributeType: "N",
+		},
+		{
+			AttributeName: aws.String("Domain"),
+			AttributeType: "S",
+		},
+	},
+	GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{
+		{
+			IndexName: aws.String("by_domain"),
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("Domain"),
+					KeyType:       "HASH",
+				},
+			},
+			Projection: &types.Projection{
+				ProjectionType: "ALL",
+			},
+		},
+	},
+	BillingMode: BILLING_MODE,
+	TableName:   &DOMAIN_BLOCKS_TABLE_NAME,
+}
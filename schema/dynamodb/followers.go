@@ -49,6 +49,19 @@ var DynamoDBFollowersTable = &dynamodb.CreateTableInput{
 			},
 		},
 
+		{
+			IndexName: aws.String("follower_address"),
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("FollowerAddress"),
+					KeyType:       "HASH",
+				},
+			},
+			Projection: &types.Projection{
+				ProjectionType: "ALL",
+			},
+		},
+
 		{
 			IndexName: aws.String("created"),
 			KeySchema: []types.KeySchemaElement{
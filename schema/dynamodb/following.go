@@ -49,6 +49,19 @@ var DynamoDBFollowingTable = &dynamodb.CreateTableInput{
 			},
 		},
 
+		{
+			IndexName: aws.String("following_address"),
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("FollowingAddress"),
+					KeyType:       "HASH",
+				},
+			},
+			Projection: &types.Projection{
+				ProjectionType: "ALL",
+			},
+		},
+
 		{
 			IndexName: aws.String("created"),
 			KeySchema: []types.KeySchemaElement{
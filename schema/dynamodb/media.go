@@ -0,0 +1,46 @@
+package dynamodb
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+var DynamoDBMediaTable = &dynamodb.CreateTableInput{
+	KeySchema: []types.KeySchemaElement{
+		{
+			AttributeName: aws.String("Id"), // partition key
+			KeyType:       "HASH",
+		},
+	},
+	AttributeDefinitions: []types.AttributeDefinition{
+		{
+			AttributeName: aws.String("Id"),
+			AttributeType: "N",
+		},
+		{
+			AttributeName: aws.String("PostId"),
+			AttributeType: "N",
+		},
+	},
+	GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{
+		{
+			IndexName: aws.String("by_post"),
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("PostId"),
+					KeyType:       "HASH",
+				},
+				{
+					AttributeName: aws.String("Id"),
+					KeyType:       "RANGE",
+				},
+			},
+			Projection: &types.Projection{
+				ProjectionType: "ALL",
+			},
+		},
+	},
+	BillingMode: BILLING_MODE,
+	TableName:   &MEDIA_TABLE_NAME,
+}
@@ -52,6 +52,22 @@ var DynamoDBMessagesTable = &dynamodb.CreateTableInput{
 				ProjectionType: "ALL",
 			},
 		},
+		{
+			IndexName: aws.String("note"),
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("NoteId"),
+					KeyType:       "HASH",
+				},
+				{
+					AttributeName: aws.String("Created"),
+					KeyType:       "RANGE",
+				},
+			},
+			Projection: &types.Projection{
+				ProjectionType: "ALL",
+			},
+		},
 		{
 			IndexName: aws.String("account_note"),
 			KeySchema: []types.KeySchemaElement{
@@ -0,0 +1,70 @@
+package dynamodb
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+var DynamoDBReceivedActivitiesTable = &dynamodb.CreateTableInput{
+	KeySchema: []types.KeySchemaElement{
+		{
+			AttributeName: aws.String("Id"), // partition key
+			KeyType:       "HASH",
+		},
+	},
+	AttributeDefinitions: []types.AttributeDefinition{
+		{
+			AttributeName: aws.String("Id"),
+			AttributeType: "N",
+		},
+		{
+			AttributeName: aws.String("ActivityPubId"),
+			AttributeType: "S",
+		},
+		{
+			AttributeName: aws.String("AccountId"),
+			AttributeType: "N",
+		},
+		{
+			AttributeName: aws.String("Received"),
+			AttributeType: "N",
+		},
+	},
+	GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{
+		{
+			IndexName: aws.String("by_activitypub_id"),
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("ActivityPubId"),
+					KeyType:       "HASH",
+				},
+				{
+					AttributeName: aws.String("AccountId"),
+					KeyType:       "RANGE",
+				},
+			},
+			Projection: &types.Projection{
+				ProjectionType: "ALL",
+			},
+		},
+		{
+			IndexName: aws.String("by_account"),
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("AccountId"),
+					KeyType:       "HASH",
+				},
+				{
+					AttributeName: aws.String("Received"),
+					KeyType:       "RANGE",
+				},
+			},
+			Projection: &types.Projection{
+				ProjectionType: "ALL",
+			},
+		},
+	},
+	BillingMode: BILLING_MODE,
+	TableName:   &RECEIVED_ACTIVITIES_TABLE_NAME,
+}
@@ -10,36 +10,60 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
+var ACCESS_TOKENS_TABLE_NAME = "access_tokens"
 var ACCOUNTS_TABLE_NAME = "accounts"
 var ACTIVITIES_TABLE_NAME = "activities"
+var ACTORS_TABLE_NAME = "actors"
 var ALIASES_TABLE_NAME = "aliases"
 var PROPERTIES_TABLE_NAME = "properties"
 var FOLLOWERS_TABLE_NAME = "followers"
 var FOLLOWING_TABLE_NAME = "following"
+var FOLLOW_REQUESTS_TABLE_NAME = "follow_requests"
 var POSTS_TABLE_NAME = "posts"
 var POST_TAGS_TABLE_NAME = "post_tags"
+var POST_REVISIONS_TABLE_NAME = "post_revisions"
 var NOTES_TABLE_NAME = "notes"
 var MESSAGES_TABLE_NAME = "messages"
 var BLOCKS_TABLE_NAME = "blocks"
+var DOMAIN_ALLOWS_TABLE_NAME = "domain_allows"
+var DOMAIN_BLOCKS_TABLE_NAME = "domain_blocks"
 var DELIVERIES_TABLE_NAME = "deliveries"
 var LIKES_TABLE_NAME = "likes"
+var MEDIA_TABLE_NAME = "media"
 var BOOSTS_TABLE_NAME = "boosts"
+var RECEIVED_ACTIVITIES_TABLE_NAME = "received_activities"
+var RATE_LIMITS_TABLE_NAME = "rate_limits"
+var REPORTS_TABLE_NAME = "reports"
+var QUEUED_ACTIVITIES_TABLE_NAME = "queued_activities"
+var TOMBSTONES_TABLE_NAME = "tombstones"
 
 var BILLING_MODE = types.BillingModePayPerRequest
 
 var DynamoDBTables = map[string]*dynamodb.CreateTableInput{
-	ACCOUNTS_TABLE_NAME:   DynamoDBAccountsTable,
-	ACTIVITIES_TABLE_NAME: DynamoDBActivitiesTable,
-	ALIASES_TABLE_NAME:    DynamoDBAliasesTable,
-	FOLLOWERS_TABLE_NAME:  DynamoDBFollowersTable,
-	FOLLOWING_TABLE_NAME:  DynamoDBFollowingTable,
-	POSTS_TABLE_NAME:      DynamoDBPostsTable,
-	POST_TAGS_TABLE_NAME:  DynamoDBPostTagsTable,
-	NOTES_TABLE_NAME:      DynamoDBNotesTable,
-	MESSAGES_TABLE_NAME:   DynamoDBMessagesTable,
-	BLOCKS_TABLE_NAME:     DynamoDBBlocksTable,
-	DELIVERIES_TABLE_NAME: DynamoDBDeliveriesTable,
-	LIKES_TABLE_NAME:      DynamoDBLikesTable,
-	BOOSTS_TABLE_NAME:     DynamoDBBoostsTable,
-	PROPERTIES_TABLE_NAME: DynamoDBPropertiesTable,
+	ACCESS_TOKENS_TABLE_NAME:       DynamoDBAccessTokensTable,
+	ACCOUNTS_TABLE_NAME:            DynamoDBAccountsTable,
+	ACTIVITIES_TABLE_NAME:          DynamoDBActivitiesTable,
+	ACTORS_TABLE_NAME:              DynamoDBActorsTable,
+	ALIASES_TABLE_NAME:             DynamoDBAliasesTable,
+	FOLLOWERS_TABLE_NAME:           DynamoDBFollowersTable,
+	FOLLOWING_TABLE_NAME:           DynamoDBFollowingTable,
+	FOLLOW_REQUESTS_TABLE_NAME:     DynamoDBFollowRequestsTable,
+	POSTS_TABLE_NAME:               DynamoDBPostsTable,
+	POST_TAGS_TABLE_NAME:           DynamoDBPostTagsTable,
+	POST_REVISIONS_TABLE_NAME:      DynamoDBPostRevisionsTable,
+	NOTES_TABLE_NAME:               DynamoDBNotesTable,
+	MESSAGES_TABLE_NAME:            DynamoDBMessagesTable,
+	BLOCKS_TABLE_NAME:              DynamoDBBlocksTable,
+	DOMAIN_ALLOWS_TABLE_NAME:       DynamoDBDomainAllowsTable,
+	DOMAIN_BLOCKS_TABLE_NAME:       DynamoDBDomainBlocksTable,
+	DELIVERIES_TABLE_NAME:          DynamoDBDeliveriesTable,
+	LIKES_TABLE_NAME:               DynamoDBLikesTable,
+	MEDIA_TABLE_NAME:               DynamoDBMediaTable,
+	BOOSTS_TABLE_NAME:              DynamoDBBoostsTable,
+	PROPERTIES_TABLE_NAME:          DynamoDBPropertiesTable,
+	RECEIVED_ACTIVITIES_TABLE_NAME: DynamoDBReceivedActivitiesTable,
+	RATE_LIMITS_TABLE_NAME:         DynamoDBRateLimitsTable,
+	REPORTS_TABLE_NAME:             DynamoDBReportsTable,
+	QUEUED_ACTIVITIES_TABLE_NAME:   DynamoDBQueuedActivitiesTable,
+	TOMBSTONES_TABLE_NAME:          DynamoDBTombstonesTable,
 }
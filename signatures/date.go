@@ -0,0 +1,69 @@
+package signatures
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// VerifyDate ensures that the "Date" header in 'req' and the "created" and "expires" parameters of 's', if present, are
+// within 'skew' of the current time. Specifically the "Date" header and "created" parameter may not be more than 'skew'
+// in the past or the future and the "expires" parameter may not be more than 'skew' in the past. If 'skew' is zero (or
+// less) then no checks are performed.
+func VerifyDate(req *http.Request, s *Signature, skew time.Duration) error {
+
+	if skew <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+
+	str_date := req.Header.Get("Date")
+
+	if str_date != "" {
+
+		t, err := http.ParseTime(str_date)
+
+		if err != nil {
+			return fmt.Errorf("Failed to parse date header, %w", err)
+		}
+
+		err = checkSkew(now, t, skew)
+
+		if err != nil {
+			return fmt.Errorf("Invalid date header, %w", err)
+		}
+	}
+
+	if s.Created != 0 {
+
+		err := checkSkew(now, time.Unix(s.Created, 0), skew)
+
+		if err != nil {
+			return fmt.Errorf("Invalid created parameter, %w", err)
+		}
+	}
+
+	if s.Expires != 0 {
+
+		if now.Sub(time.Unix(s.Expires, 0)) > skew {
+			return fmt.Errorf("Signature has expired")
+		}
+	}
+
+	return nil
+}
+
+func checkSkew(now time.Time, t time.Time, skew time.Duration) error {
+
+	d := now.Sub(t)
+
+	switch {
+	case d > skew:
+		return fmt.Errorf("%v is too far in the past", t)
+	case d < -skew:
+		return fmt.Errorf("%v is too far in the future", t)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,58 @@
+package signatures
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// VerifyDigest ensures that the "Digest" header in 'req' matches the digest of 'body'. The "Digest" header may contain
+// multiple, comma-separated, digests in which case every supported digest must match. "SHA-256" and "SHA-512" digests are
+// supported; other digests are ignored but at least one supported digest must be present.
+func VerifyDigest(req *http.Request, body []byte) error {
+
+	str_digest := req.Header.Get("Digest")
+
+	if str_digest == "" {
+		return fmt.Errorf("Request is missing digest header")
+	}
+
+	count_verified := 0
+
+	for _, d := range strings.Split(str_digest, ",") {
+
+		algo, expected, ok := strings.Cut(strings.TrimSpace(d), "=")
+
+		if !ok {
+			return fmt.Errorf("Invalid digest, %s", d)
+		}
+
+		var sum []byte
+
+		switch strings.ToUpper(algo) {
+		case "SHA-256":
+			h := sha256.Sum256(body)
+			sum = h[:]
+		case "SHA-512":
+			h := sha512.Sum512(body)
+			sum = h[:]
+		default:
+			continue
+		}
+
+		if base64.StdEncoding.EncodeToString(sum) != expected {
+			return fmt.Errorf("%s digest does not match request body", algo)
+		}
+
+		count_verified += 1
+	}
+
+	if count_verified == 0 {
+		return fmt.Errorf("Request does not contain a supported digest algorithm")
+	}
+
+	return nil
+}
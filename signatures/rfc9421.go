@@ -0,0 +1,489 @@
+package signatures
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// https://www.rfc-editor.org/rfc/rfc9421.html
+
+const (
+	// RFC9421_RSA_V1_5_SHA256 is the RFC 9421 identifier for RSASSA-PKCS1-v1_5 signatures using SHA-256.
+	RFC9421_RSA_V1_5_SHA256 string = "rsa-v1_5-sha256"
+	// RFC9421_RSA_PSS_SHA512 is the RFC 9421 identifier for RSASSA-PSS signatures using SHA-512.
+	RFC9421_RSA_PSS_SHA512 string = "rsa-pss-sha512"
+	// RFC9421_ED25519 is the RFC 9421 identifier for Ed25519 signatures.
+	RFC9421_ED25519 string = "ed25519"
+)
+
+// RFC9421_LABEL is the label assigned to RFC 9421 signatures created by this package.
+const RFC9421_LABEL string = "sig1"
+
+// DefaultRequiredComponents are the components that must be included in RFC 9421 request signatures.
+var DefaultRequiredComponents = []string{
+	"@method",
+	"@target-uri",
+	"content-digest",
+}
+
+// DefaultRequiredGetComponents are the components that must be included in RFC 9421 signatures for requests without
+// a body (for example a GET request for an actor).
+var DefaultRequiredGetComponents = []string{
+	"@method",
+	"@target-uri",
+}
+
+// RFC9421Signature is a struct containing the parameters of a RFC 9421 HTTP message signature.
+type RFC9421Signature struct {
+	// Label is the label used to associate the "Signature-Input" and "Signature" headers.
+	Label string
+	// Components is the list of (lower-cased) component identifiers, without quotes, covered by the signature.
+	Components []string
+	// KeyId is the value of the "keyid" signature parameter.
+	KeyId string
+	// Algorithm is the value of the "alg" signature parameter. It may be empty if no algorithm was specified.
+	Algorithm string
+	// Created is the Unix timestamp of the "created" signature parameter. It will be zero if no value was specified.
+	Created int64
+	// Expires is the Unix timestamp of the "expires" signature parameter. It will be zero if no value was specified.
+	Expires int64
+	// Params is the serialized "@signature-params" value, as received in the "Signature-Input" header.
+	Params string
+	// Signature is the (decoded) signature.
+	Signature []byte
+}
+
+// IsRFC9421Request returns a boolean value indicating whether 'req' has been signed using RFC 9421 HTTP message signatures.
+func IsRFC9421Request(req *http.Request) bool {
+	return req.Header.Get("Signature-Input") != ""
+}
+
+// SignRFC9421 signs 'req', whose body is 'body', using 'private_key' and the RFC 9421 HTTP message signatures specification. The
+// "Content-Digest", "Signature-Input" and "Signature" headers will be assigned to 'req'. The signature will be valid for 'ttl'.
+func SignRFC9421(req *http.Request, body []byte, key_id string, private_key *rsa.PrivateKey, ttl time.Duration) error {
+
+	digest := sha256.Sum256(body)
+	req.Header.Set("Content-Digest", fmt.Sprintf("sha-256=:%s:", base64.StdEncoding.EncodeToString(digest[:])))
+
+	now := time.Now()
+	created := now.Unix()
+	expires := now.Add(ttl).Unix()
+
+	quoted := make([]string, len(DefaultRequiredComponents))
+
+	for idx, c := range DefaultRequiredComponents {
+		quoted[idx] = strconv.Quote(c)
+	}
+
+	params := fmt.Sprintf("(%s);created=%d;expires=%d;keyid=%s;alg=%s", strings.Join(quoted, " "), created, expires, strconv.Quote(key_id), strconv.Quote(RFC9421_RSA_V1_5_SHA256))
+
+	s := &RFC9421Signature{
+		Label:      RFC9421_LABEL,
+		Components: DefaultRequiredComponents,
+		KeyId:      key_id,
+		Algorithm:  RFC9421_RSA_V1_5_SHA256,
+		Created:    created,
+		Expires:    expires,
+		Params:     params,
+	}
+
+	base, err := s.signatureBase(req)
+
+	if err != nil {
+		return fmt.Errorf("Failed to derive signature base, %w", err)
+	}
+
+	hashed := sha256.Sum256([]byte(base))
+
+	sig, err := rsa.SignPKCS1v15(rand.Reader, private_key, crypto.SHA256, hashed[:])
+
+	if err != nil {
+		return fmt.Errorf("Failed to sign request, %w", err)
+	}
+
+	req.Header.Set("Signature-Input", fmt.Sprintf("%s=%s", s.Label, s.Params))
+	req.Header.Set("Signature", fmt.Sprintf("%s=:%s:", s.Label, base64.StdEncoding.EncodeToString(sig)))
+
+	return nil
+}
+
+// ParseRFC9421Signature returns a new `RFC9421Signature` instance derived from the "Signature-Input" and "Signature"
+// headers in 'req'. If there are multiple signatures the first one, as listed in the "Signature-Input" header, is returned.
+func ParseRFC9421Signature(req *http.Request) (*RFC9421Signature, error) {
+
+	inputs, err := parseDictionary(req.Header.Get("Signature-Input"))
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse Signature-Input header, %w", err)
+	}
+
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("Request is missing Signature-Input header")
+	}
+
+	sigs, err := parseDictionary(req.Header.Get("Signature"))
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse Signature header, %w", err)
+	}
+
+	label := inputs[0][0]
+	params := inputs[0][1]
+
+	var enc_sig string
+
+	for _, kv := range sigs {
+
+		if kv[0] == label {
+			enc_sig = kv[1]
+			break
+		}
+	}
+
+	if !strings.HasPrefix(enc_sig, ":") || !strings.HasSuffix(enc_sig, ":") || len(enc_sig) < 2 {
+		return nil, fmt.Errorf("Missing or invalid signature for label '%s'", label)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(strings.Trim(enc_sig, ":"))
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode signature, %w", err)
+	}
+
+	s := &RFC9421Signature{
+		Label:     label,
+		Params:    params,
+		Signature: sig,
+	}
+
+	if !strings.HasPrefix(params, "(") {
+		return nil, fmt.Errorf("Invalid signature parameters, %s", params)
+	}
+
+	end := strings.Index(params, ")")
+
+	if end == -1 {
+		return nil, fmt.Errorf("Invalid signature parameters, %s", params)
+	}
+
+	for _, c := range strings.Fields(params[1:end]) {
+
+		if !strings.HasPrefix(c, "\"") || !strings.HasSuffix(c, "\"") || len(c) < 2 {
+			return nil, fmt.Errorf("Invalid or unsupported signature component, %s", c)
+		}
+
+		s.Components = append(s.Components, strings.ToLower(strings.Trim(c, "\"")))
+	}
+
+	for _, p := range splitOutsideQuotes(params[end+1:], ';') {
+
+		p = strings.TrimSpace(p)
+
+		if p == "" {
+			continue
+		}
+
+		k, v, _ := strings.Cut(p, "=")
+		v = strings.Trim(v, "\"")
+
+		switch k {
+		case "keyid":
+			s.KeyId = v
+		case "alg":
+			s.Algorithm = strings.ToLower(v)
+		case "created", "expires":
+
+			ts, err := strconv.ParseInt(v, 10, 64)
+
+			if err != nil {
+				return nil, fmt.Errorf("Invalid %s parameter, %w", k, err)
+			}
+
+			if k == "created" {
+				s.Created = ts
+			} else {
+				s.Expires = ts
+			}
+
+		default:
+			// pass
+		}
+	}
+
+	if s.KeyId == "" {
+		return nil, fmt.Errorf("Signature is missing keyid parameter")
+	}
+
+	return s, nil
+}
+
+// EnsureComponents ensures that all of 'required' are included in the list of components covered by 's'.
+func (s *RFC9421Signature) EnsureComponents(required ...string) error {
+
+	for _, c := range required {
+
+		if !slices.Contains(s.Components, strings.ToLower(c)) {
+			return fmt.Errorf("Signature is missing required component '%s'", c)
+		}
+	}
+
+	return nil
+}
+
+// Verify verifies 's' for 'req' using 'public_key' (which is expected to be either a `*rsa.PublicKey` or an `ed25519.PublicKey`
+// instance). If the signature algorithm is empty then it is derived from the type of 'public_key'.
+func (s *RFC9421Signature) Verify(req *http.Request, public_key any) error {
+
+	base, err := s.signatureBase(req)
+
+	if err != nil {
+		return fmt.Errorf("Failed to derive signature base, %w", err)
+	}
+
+	switch k := public_key.(type) {
+	case *rsa.PublicKey:
+
+		switch s.Algorithm {
+		case "", RFC9421_RSA_V1_5_SHA256:
+			hashed := sha256.Sum256([]byte(base))
+			err = rsa.VerifyPKCS1v15(k, crypto.SHA256, hashed[:], s.Signature)
+		case RFC9421_RSA_PSS_SHA512:
+			hashed := sha512.Sum512([]byte(base))
+			err = rsa.VerifyPSS(k, crypto.SHA512, hashed[:], s.Signature, &rsa.PSSOptions{SaltLength: 64, Hash: crypto.SHA512})
+		default:
+			return fmt.Errorf("Unsupported signature algorithm '%s' for %T key", s.Algorithm, public_key)
+		}
+
+	case ed25519.PublicKey:
+
+		switch s.Algorithm {
+		case "", RFC9421_ED25519:
+
+			if !ed25519.Verify(k, []byte(base), s.Signature) {
+				err = fmt.Errorf("invalid signature")
+			}
+
+		default:
+			return fmt.Errorf("Unsupported signature algorithm '%s' for %T key", s.Algorithm, public_key)
+		}
+
+	default:
+		return fmt.Errorf("Unsupported public key type, %T", public_key)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed to verify signature, %w", err)
+	}
+
+	return nil
+}
+
+// signatureBase returns the signature base for 's' and 'req' as defined in section 2.5 of RFC 9421.
+func (s *RFC9421Signature) signatureBase(req *http.Request) (string, error) {
+
+	lines := make([]string, 0)
+
+	for _, c := range s.Components {
+
+		v, err := componentValue(req, c)
+
+		if err != nil {
+			return "", err
+		}
+
+		lines = append(lines, fmt.Sprintf("%s: %s", strconv.Quote(c), v))
+	}
+
+	lines = append(lines, fmt.Sprintf("\"@signature-params\": %s", s.Params))
+	return strings.Join(lines, "\n"), nil
+}
+
+// componentValue returns the value of the component 'c' for 'req' as defined in section 2 of RFC 9421.
+func componentValue(req *http.Request, c string) (string, error) {
+
+	host := req.URL.Host
+
+	if host == "" {
+		host = req.Host
+	}
+
+	scheme := req.URL.Scheme
+
+	if scheme == "" {
+
+		scheme = "http"
+
+		if req.TLS != nil {
+			scheme = "https"
+		}
+	}
+
+	switch c {
+	case "@method":
+		return strings.ToUpper(req.Method), nil
+	case "@target-uri":
+		return fmt.Sprintf("%s://%s%s", strings.ToLower(scheme), strings.ToLower(host), req.URL.RequestURI()), nil
+	case "@authority":
+		return strings.ToLower(host), nil
+	case "@scheme":
+		return strings.ToLower(scheme), nil
+	case "@request-target":
+		return req.URL.RequestURI(), nil
+	case "@path":
+
+		path := req.URL.EscapedPath()
+
+		if path == "" {
+			path = "/"
+		}
+
+		return path, nil
+	case "@query":
+		return "?" + req.URL.RawQuery, nil
+	case "host":
+		return strings.ToLower(host), nil
+	}
+
+	if strings.HasPrefix(c, "@") {
+		return "", fmt.Errorf("Unsupported derived component '%s'", c)
+	}
+
+	values := req.Header.Values(c)
+
+	if len(values) == 0 {
+		return "", fmt.Errorf("Request is missing header for component '%s'", c)
+	}
+
+	for idx, v := range values {
+		values[idx] = strings.TrimSpace(v)
+	}
+
+	return strings.Join(values, ", "), nil
+}
+
+// VerifyContentDigest ensures that the "Content-Digest" header in 'req' matches the digest of 'body'. Every supported digest
+// ("sha-256" and "sha-512") must match and at least one supported digest must be present.
+func VerifyContentDigest(req *http.Request, body []byte) error {
+
+	digests, err := parseDictionary(req.Header.Get("Content-Digest"))
+
+	if err != nil {
+		return fmt.Errorf("Failed to parse Content-Digest header, %w", err)
+	}
+
+	count_verified := 0
+
+	for _, kv := range digests {
+
+		var sum []byte
+
+		switch strings.ToLower(kv[0]) {
+		case "sha-256":
+			h := sha256.Sum256(body)
+			sum = h[:]
+		case "sha-512":
+			h := sha512.Sum512(body)
+			sum = h[:]
+		default:
+			continue
+		}
+
+		expected := fmt.Sprintf(":%s:", base64.StdEncoding.EncodeToString(sum))
+
+		if kv[1] != expected {
+			return fmt.Errorf("%s digest does not match request body", kv[0])
+		}
+
+		count_verified += 1
+	}
+
+	if count_verified == 0 {
+		return fmt.Errorf("Request does not contain a supported content digest algorithm")
+	}
+
+	return nil
+}
+
+// VerifyRFC9421Date ensures that 's' has a "created" parameter and that it, the "Date" header in 'req', if present, and the
+// "expires" parameter of 's', if present, are within 'skew' of the current time. See `VerifyDate` for details.
+func VerifyRFC9421Date(req *http.Request, s *RFC9421Signature, skew time.Duration) error {
+
+	// RFC 9421 signatures are not required to cover the "Date" header so the "created" parameter is the only
+	// way to tell when a signature was made and to prevent old signatures from being replayed.
+
+	if s.Created == 0 {
+		return fmt.Errorf("Signature is missing created parameter")
+	}
+
+	cavage_s := &Signature{
+		Created: s.Created,
+		Expires: s.Expires,
+	}
+
+	return VerifyDate(req, cavage_s, skew)
+}
+
+// parseDictionary parses 'str' as a (simplified) RFC 8941 structured field dictionary returning a list of
+// [ key, (raw) value ] pairs in the order they were defined.
+func parseDictionary(str string) ([][2]string, error) {
+
+	members := make([][2]string, 0)
+
+	for _, m := range splitOutsideQuotes(str, ',') {
+
+		m = strings.TrimSpace(m)
+
+		if m == "" {
+			continue
+		}
+
+		k, v, ok := strings.Cut(m, "=")
+
+		if !ok {
+			return nil, fmt.Errorf("Invalid dictionary member, %s", m)
+		}
+
+		members = append(members, [2]string{strings.TrimSpace(k), strings.TrimSpace(v)})
+	}
+
+	return members, nil
+}
+
+// splitOutsideQuotes splits 'str' on 'sep' ignoring any instances of 'sep' that occur inside double quotes.
+func splitOutsideQuotes(str string, sep rune) []string {
+
+	parts := make([]string, 0)
+
+	in_quotes := false
+	escaped := false
+	start := 0
+
+	for idx, r := range str {
+
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && in_quotes:
+			escaped = true
+		case r == '"':
+			in_quotes = !in_quotes
+		case r == sep && !in_quotes:
+			parts = append(parts, str[start:idx])
+			start = idx + 1
+		}
+	}
+
+	parts = append(parts, str[start:])
+	return parts
+}
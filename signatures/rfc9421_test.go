@@ -0,0 +1,159 @@
+package signatures
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRFC9421(t *testing.T) {
+
+	private_key, err := rsa.GenerateKey(rand.Reader, 2048)
+
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key, %v", err)
+	}
+
+	key_id := "https://example.com/users/bob#main-key"
+	body := []byte(`{"type":"Follow"}`)
+
+	req, err := http.NewRequest(http.MethodPost, "https://example.com/ap/alice/inbox", bytes.NewReader(body))
+
+	if err != nil {
+		t.Fatalf("Failed to create request, %v", err)
+	}
+
+	err = SignRFC9421(req, body, key_id, private_key, 5*time.Minute)
+
+	if err != nil {
+		t.Fatalf("Failed to sign request, %v", err)
+	}
+
+	if !IsRFC9421Request(req) {
+		t.Fatalf("Expected RFC 9421 request")
+	}
+
+	s, err := ParseRFC9421Signature(req)
+
+	if err != nil {
+		t.Fatalf("Failed to parse signature, %v", err)
+	}
+
+	if s.KeyId != key_id {
+		t.Fatalf("Unexpected key ID, %s", s.KeyId)
+	}
+
+	if s.Algorithm != RFC9421_RSA_V1_5_SHA256 {
+		t.Fatalf("Unexpected algorithm, %s", s.Algorithm)
+	}
+
+	err = s.EnsureComponents(DefaultRequiredComponents...)
+
+	if err != nil {
+		t.Fatalf("Expected required components, %v", err)
+	}
+
+	err = VerifyContentDigest(req, body)
+
+	if err != nil {
+		t.Fatalf("Failed to verify content digest, %v", err)
+	}
+
+	err = VerifyContentDigest(req, []byte(`{"type":"Undo"}`))
+
+	if err == nil {
+		t.Fatalf("Expected content digest for modified body to fail")
+	}
+
+	err = VerifyRFC9421Date(req, s, 5*time.Minute)
+
+	if err != nil {
+		t.Fatalf("Failed to verify date, %v", err)
+	}
+
+	created := s.Created
+
+	s.Created = 0
+
+	err = VerifyRFC9421Date(req, s, 5*time.Minute)
+
+	if err == nil {
+		t.Fatalf("Expected signature without created parameter to fail")
+	}
+
+	s.Created = created - int64((10 * time.Minute).Seconds())
+
+	err = VerifyRFC9421Date(req, s, 5*time.Minute)
+
+	if err == nil {
+		t.Fatalf("Expected signature created outside clock skew to fail")
+	}
+
+	s.Created = created
+
+	err = s.Verify(req, &private_key.PublicKey)
+
+	if err != nil {
+		t.Fatalf("Failed to verify signature, %v", err)
+	}
+
+	// Server-side requests only have a path so the scheme and host are assigned manually
+
+	server_req, err := http.NewRequest(http.MethodPost, "/ap/alice/inbox", bytes.NewReader(body))
+
+	if err != nil {
+		t.Fatalf("Failed to create server request, %v", err)
+	}
+
+	server_req.Header = req.Header.Clone()
+	server_req.URL.Scheme = "https"
+	server_req.URL.Host = "example.com"
+
+	err = s.Verify(server_req, &private_key.PublicKey)
+
+	if err != nil {
+		t.Fatalf("Failed to verify signature for server request, %v", err)
+	}
+
+	server_req.URL.Path = "/ap/bob/inbox"
+
+	err = s.Verify(server_req, &private_key.PublicKey)
+
+	if err == nil {
+		t.Fatalf("Expected signature for different target URI to fail")
+	}
+
+	other_key, err := rsa.GenerateKey(rand.Reader, 2048)
+
+	if err != nil {
+		t.Fatalf("Failed to generate other RSA key, %v", err)
+	}
+
+	err = s.Verify(req, &other_key.PublicKey)
+
+	if err == nil {
+		t.Fatalf("Expected signature verified with different key to fail")
+	}
+}
+
+func TestSchemesForHost(t *testing.T) {
+
+	host := "schemes.example.com"
+
+	schemes := SchemesForHost(host)
+
+	if schemes[0] != RFC9421Scheme || schemes[1] != CavageScheme {
+		t.Fatalf("Expected default scheme to be RFC 9421, got %v", schemes)
+	}
+
+	SetSchemeForHost(host, CavageScheme)
+
+	schemes = SchemesForHost("SCHEMES.example.com")
+
+	if schemes[0] != CavageScheme || schemes[1] != RFC9421Scheme {
+		t.Fatalf("Unexpected schemes, %v", schemes)
+	}
+}
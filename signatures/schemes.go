@@ -0,0 +1,49 @@
+package signatures
+
+import (
+	"strings"
+	"sync"
+)
+
+// Scheme is the name of a HTTP signature specification.
+type Scheme string
+
+const (
+	// CavageScheme is the draft-cavage-http-signatures specification.
+	CavageScheme Scheme = "cavage"
+	// RFC9421Scheme is the RFC 9421 HTTP message signatures specification.
+	RFC9421Scheme Scheme = "rfc9421"
+)
+
+// DefaultScheme is the signature scheme to try first when delivering to a host for which no scheme has been recorded. Deliveries
+// signed with RFC 9421 that are rejected (with a 401 Unauthorized response) are retried using draft-cavage, which is then recorded
+// as the scheme to use for that host. Schemes are only recorded in memory, for the lifetime of the process.
+var DefaultScheme = RFC9421Scheme
+
+var host_schemes = new(sync.Map)
+
+// SchemesForHost returns the list of signature schemes to try, in order, when delivering to 'host'. The first scheme is
+// the one most recently recorded as having worked for 'host' (using `SetSchemeForHost`) or `DefaultScheme` if no scheme
+// has been recorded. The second scheme is the other one.
+func SchemesForHost(host string) []Scheme {
+
+	first := DefaultScheme
+
+	v, ok := host_schemes.Load(strings.ToLower(host))
+
+	if ok {
+		first = v.(Scheme)
+	}
+
+	switch first {
+	case RFC9421Scheme:
+		return []Scheme{RFC9421Scheme, CavageScheme}
+	default:
+		return []Scheme{CavageScheme, RFC9421Scheme}
+	}
+}
+
+// SetSchemeForHost records that 'scheme' was used to successfully deliver a signed request to 'host'.
+func SetSchemeForHost(host string, scheme Scheme) {
+	host_schemes.Store(strings.ToLower(host), scheme)
+}
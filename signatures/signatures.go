@@ -0,0 +1,180 @@
+// Package signatures provides methods for creating and validating the HTTP signatures attached to requests posted to ActivityPub
+// inboxes. Both draft-cavage-http-signatures (validated above and beyond the cryptographic verification performed by go-fed/httpsig)
+// and RFC 9421 HTTP message signatures are supported.
+package signatures
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-fed/httpsig"
+)
+
+// HS2019 is the (opaque) "hs2019" signature algorithm identifier. The actual algorithm is derived from the type of key
+// used to sign the request.
+const HS2019 string = "hs2019"
+
+// DefaultRequiredHeaders are the (lower-cased) headers, and pseudo-headers, that must be included in a request signature.
+var DefaultRequiredHeaders = []string{
+	httpsig.RequestTarget,
+	"host",
+	"date",
+	"digest",
+}
+
+// DefaultRequiredGetHeaders are the (lower-cased) headers, and pseudo-headers, that must be included in the signature
+// for a request without a body (for example a GET request for an actor).
+var DefaultRequiredGetHeaders = []string{
+	httpsig.RequestTarget,
+	"host",
+	"date",
+}
+
+// Signature is a struct containing the parameters of a HTTP signature.
+type Signature struct {
+	// KeyId is the URI of the key used to sign the request.
+	KeyId string
+	// Algorithm is the (lower-cased) signature algorithm. It may be empty if no algorithm was specified.
+	Algorithm string
+	// Headers is the list of (lower-cased) headers, and pseudo-headers, included in the signature.
+	Headers []string
+	// Created is the Unix timestamp of the "created" signature parameter. It will be zero if no value was specified.
+	Created int64
+	// Expires is the Unix timestamp of the "expires" signature parameter. It will be zero if no value was specified.
+	Expires int64
+	// Signature is the base64-encoded signature.
+	Signature string
+}
+
+// ParseSignature returns a new `Signature` instance derived from the "Signature" (or "Authorization") header in 'req'.
+func ParseSignature(req *http.Request) (*Signature, error) {
+
+	str_sig := req.Header.Get("Signature")
+
+	if str_sig == "" {
+
+		auth := req.Header.Get("Authorization")
+
+		if strings.HasPrefix(auth, "Signature ") {
+			str_sig = strings.TrimPrefix(auth, "Signature ")
+		}
+	}
+
+	if str_sig == "" {
+		return nil, fmt.Errorf("Request is missing signature header")
+	}
+
+	params := make(map[string]string)
+
+	for _, kv := range strings.Split(str_sig, ",") {
+
+		k, v, ok := strings.Cut(strings.TrimSpace(kv), "=")
+
+		if !ok {
+			return nil, fmt.Errorf("Invalid signature parameter, %s", kv)
+		}
+
+		params[k] = strings.Trim(v, "\"")
+	}
+
+	s := &Signature{
+		KeyId:     params["keyId"],
+		Algorithm: strings.ToLower(params["algorithm"]),
+		Signature: params["signature"],
+	}
+
+	if s.KeyId == "" {
+		return nil, fmt.Errorf("Signature is missing keyId parameter")
+	}
+
+	if s.Signature == "" {
+		return nil, fmt.Errorf("Signature is missing signature parameter")
+	}
+
+	str_headers, ok := params["headers"]
+
+	if ok {
+		s.Headers = strings.Fields(strings.ToLower(str_headers))
+	} else {
+		// https://datatracker.ietf.org/doc/html/draft-cavage-http-signatures-12#section-2.1.6
+		s.Headers = []string{"(created)"}
+	}
+
+	for _, k := range []string{"created", "expires"} {
+
+		str_ts, ok := params[k]
+
+		if !ok {
+			continue
+		}
+
+		ts, err := strconv.ParseInt(str_ts, 10, 64)
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid %s parameter, %w", k, err)
+		}
+
+		switch k {
+		case "created":
+			s.Created = ts
+		default:
+			s.Expires = ts
+		}
+	}
+
+	return s, nil
+}
+
+// EnsureHeaders ensures that all of 'required' are included in the list of signed headers for 's'.
+func (s *Signature) EnsureHeaders(required ...string) error {
+
+	for _, h := range required {
+
+		if !slices.Contains(s.Headers, strings.ToLower(h)) {
+			return fmt.Errorf("Signature is missing required header '%s'", h)
+		}
+	}
+
+	return nil
+}
+
+// HttpSigAlgorithm returns the `httpsig.Algorithm` to use when verifying 's' with 'public_key' (which is expected to be
+// either a `*rsa.PublicKey` or an `ed25519.PublicKey` instance). If the signature algorithm is empty or "hs2019" then the
+// algorithm is derived from the type of 'public_key'. An error is returned if the signature algorithm is not supported or
+// does not match the type of 'public_key'.
+func (s *Signature) HttpSigAlgorithm(public_key any) (httpsig.Algorithm, error) {
+
+	var algo httpsig.Algorithm
+
+	switch public_key.(type) {
+	case *rsa.PublicKey:
+
+		switch s.Algorithm {
+		case "", HS2019, string(httpsig.RSA_SHA256):
+			algo = httpsig.RSA_SHA256
+		case string(httpsig.RSA_SHA512):
+			algo = httpsig.RSA_SHA512
+		}
+
+	case ed25519.PublicKey:
+
+		switch s.Algorithm {
+		case "", HS2019, string(httpsig.ED25519):
+			algo = httpsig.ED25519
+		}
+
+	default:
+		return "", fmt.Errorf("Unsupported public key type, %T", public_key)
+	}
+
+	if algo == "" {
+		return "", fmt.Errorf("Unsupported signature algorithm '%s' for %T key", s.Algorithm, public_key)
+	}
+
+	return algo, nil
+}
@@ -0,0 +1,200 @@
+package signatures
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-fed/httpsig"
+)
+
+func TestParseSignature(t *testing.T) {
+
+	req, err := http.NewRequest(http.MethodPost, "https://example.com/ap/alice/inbox", nil)
+
+	if err != nil {
+		t.Fatalf("Failed to create request, %v", err)
+	}
+
+	req.Header.Set("Signature", `keyId="https://example.com/users/bob#main-key",algorithm="hs2019",headers="(request-target) host date digest",created=1700000000,signature="c2lnbmF0dXJl"`)
+
+	s, err := ParseSignature(req)
+
+	if err != nil {
+		t.Fatalf("Failed to parse signature, %v", err)
+	}
+
+	if s.KeyId != "https://example.com/users/bob#main-key" {
+		t.Fatalf("Unexpected key ID, %s", s.KeyId)
+	}
+
+	if s.Algorithm != HS2019 {
+		t.Fatalf("Unexpected algorithm, %s", s.Algorithm)
+	}
+
+	if s.Created != 1700000000 {
+		t.Fatalf("Unexpected created value, %d", s.Created)
+	}
+
+	if s.Signature != "c2lnbmF0dXJl" {
+		t.Fatalf("Unexpected signature, %s", s.Signature)
+	}
+
+	err = s.EnsureHeaders(DefaultRequiredHeaders...)
+
+	if err != nil {
+		t.Fatalf("Expected required headers, %v", err)
+	}
+
+	err = s.EnsureHeaders("(expires)")
+
+	if err == nil {
+		t.Fatalf("Expected (expires) header to be missing")
+	}
+}
+
+func TestHttpSigAlgorithm(t *testing.T) {
+
+	rsa_key, err := rsa.GenerateKey(rand.Reader, 2048)
+
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key, %v", err)
+	}
+
+	ed_key, _, err := ed25519.GenerateKey(rand.Reader)
+
+	if err != nil {
+		t.Fatalf("Failed to generate ed25519 key, %v", err)
+	}
+
+	tests := []struct {
+		Algorithm string
+		Key       any
+		Expected  httpsig.Algorithm
+	}{
+		{"", &rsa_key.PublicKey, httpsig.RSA_SHA256},
+		{"hs2019", &rsa_key.PublicKey, httpsig.RSA_SHA256},
+		{"rsa-sha256", &rsa_key.PublicKey, httpsig.RSA_SHA256},
+		{"rsa-sha512", &rsa_key.PublicKey, httpsig.RSA_SHA512},
+		{"hs2019", ed_key, httpsig.ED25519},
+		{"ed25519", ed_key, httpsig.ED25519},
+		{"ed25519", &rsa_key.PublicKey, ""},
+		{"rsa-sha256", ed_key, ""},
+		{"hmac-sha256", &rsa_key.PublicKey, ""},
+	}
+
+	for idx, test := range tests {
+
+		s := &Signature{
+			Algorithm: test.Algorithm,
+		}
+
+		algo, err := s.HttpSigAlgorithm(test.Key)
+
+		if test.Expected == "" {
+
+			if err == nil {
+				t.Fatalf("Expected test %d (%s) to fail", idx, test.Algorithm)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("Failed to derive algorithm for test %d (%s), %v", idx, test.Algorithm, err)
+		}
+
+		if algo != test.Expected {
+			t.Fatalf("Unexpected algorithm for test %d (%s), %s", idx, test.Algorithm, algo)
+		}
+	}
+}
+
+func TestVerifyDigest(t *testing.T) {
+
+	body := []byte(`{"type":"Follow"}`)
+
+	sha256_digest := "GYwYnH3BiO6aICFt0ThC5bUIJ4byvqdpWtR8m5fNkww="
+	sha512_digest := "vQGMZNZBOZZ8BVm4X6SU+xRumIYdhE82AoDhrcRsvqb1ZhzSG5pTuNFTwj9G5nxvxgqPsEgmmbi/XTyI9WOhCA=="
+
+	tests := map[string]bool{
+		"SHA-256=" + sha256_digest:                               true,
+		"sha-256=" + sha256_digest:                               true,
+		"SHA-512=" + sha512_digest:                               true,
+		"SHA-256=" + sha256_digest + ",SHA-512=" + sha512_digest: true,
+		"SHA-256=47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=":   false,
+		"MD5=Ng5yB/Lx+d9eECKtuwTPaw==":                           false,
+		"":                                                       false,
+	}
+
+	for digest, expected := range tests {
+
+		req, err := http.NewRequest(http.MethodPost, "https://example.com/ap/alice/inbox", nil)
+
+		if err != nil {
+			t.Fatalf("Failed to create request, %v", err)
+		}
+
+		req.Header.Set("Digest", digest)
+
+		err = VerifyDigest(req, body)
+
+		if expected && err != nil {
+			t.Fatalf("Expected digest '%s' to verify, %v", digest, err)
+		}
+
+		if !expected && err == nil {
+			t.Fatalf("Expected digest '%s' to fail", digest)
+		}
+	}
+}
+
+func TestVerifyDate(t *testing.T) {
+
+	skew := 5 * time.Minute
+	now := time.Now()
+
+	tests := []struct {
+		Date     time.Time
+		Created  int64
+		Expires  int64
+		Expected bool
+	}{
+		{now, 0, 0, true},
+		{now.Add(-1 * time.Minute), now.Unix(), now.Add(5 * time.Minute).Unix(), true},
+		{now.Add(-1 * time.Hour), 0, 0, false},
+		{now.Add(1 * time.Hour), 0, 0, false},
+		{now, now.Add(-1 * time.Hour).Unix(), 0, false},
+		{now, now.Add(1 * time.Hour).Unix(), 0, false},
+		{now, 0, now.Add(-1 * time.Hour).Unix(), false},
+	}
+
+	for idx, test := range tests {
+
+		req, err := http.NewRequest(http.MethodPost, "https://example.com/ap/alice/inbox", nil)
+
+		if err != nil {
+			t.Fatalf("Failed to create request, %v", err)
+		}
+
+		req.Header.Set("Date", test.Date.UTC().Format(http.TimeFormat))
+
+		s := &Signature{
+			Created: test.Created,
+			Expires: test.Expires,
+		}
+
+		err = VerifyDate(req, s, skew)
+
+		if test.Expected && err != nil {
+			t.Fatalf("Expected test %d to verify, %v", idx, err)
+		}
+
+		if !test.Expected && err == nil {
+			t.Fatalf("Expected test %d to fail", idx)
+		}
+	}
+}
@@ -24,6 +24,7 @@ type Counts struct {
 	Messages   int64  `json:"messages"`
 	Notes      int64  `json:"notes"`
 	Posts      int64  `json:"posts"`
+	Reactions  int64  `json:"reactions"`
 }
 
 type CountsForDateOptions struct {
@@ -224,6 +225,27 @@ func CountsForDate(ctx context.Context, opts *CountsForDateOptions) (*Counts, er
 		counts.Likes = i
 	}()
 
+	go func() {
+
+		atomic.AddInt32(&remaining, 1)
+
+		defer func() {
+			done_ch <- true
+		}()
+
+		i, err := CountReactionsForDateRange(ctx, opts.LikesDatabase, start, end)
+
+		if err != nil {
+			err_ch <- fmt.Errorf("Failed to derive counts for reactions, %w", err)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		counts.Reactions = i
+	}()
+
 	go func() {
 
 		atomic.AddInt32(&remaining, 1)
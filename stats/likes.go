@@ -3,7 +3,9 @@ package stats
 import (
 	"context"
 	"fmt"
+	"sort"
 
+	"github.com/sfomuseum/go-activitypub"
 	"github.com/sfomuseum/go-activitypub/database"
 )
 
@@ -24,3 +26,93 @@ func CountLikesForDateRange(ctx context.Context, likes_db database.LikesDatabase
 
 	return count, nil
 }
+
+// CountReactionsForDateRange returns the number of emoji reactions (likes with an emoji) created between 'start' and 'end'.
+func CountReactionsForDateRange(ctx context.Context, likes_db database.LikesDatabase, start int64, end int64) (int64, error) {
+
+	count := int64(0)
+
+	cb := func(ctx context.Context, id int64) error {
+
+		l, err := likes_db.GetLikeWithId(ctx, id)
+
+		if err != nil {
+			return fmt.Errorf("Failed to retrieve like %d, %w", id, err)
+		}
+
+		if l.IsEmojiReaction() {
+			count += 1
+		}
+
+		return nil
+	}
+
+	err := likes_db.GetLikeIdsForDateRange(ctx, start, end, cb)
+
+	if err != nil {
+		return 0, fmt.Errorf("Failed to count reactions, %w", err)
+	}
+
+	return count, nil
+}
+
+// ReactionCount is the number of times a given emoji has been used to react to a post.
+type ReactionCount struct {
+	// The emoji (or custom emoji shortcode) used to react to a post.
+	Emoji string `json:"emoji"`
+	// The URL of the image for custom emoji. The image is hosted by the remote server that sent the reaction so it is not
+	// displayed by the HTML post template, which renders custom emoji by shortcode, to avoid leaking visitors to that server.
+	EmojiURL string `json:"emoji_url,omitempty"`
+	// The number of reactions using the emoji.
+	Count int64 `json:"count"`
+}
+
+// CountLikesForPost returns the number of plain likes for 'post_id' and the number of emoji reactions for
+// 'post_id' grouped by emoji and sorted by count (most used first).
+func CountLikesForPost(ctx context.Context, likes_db database.LikesDatabase, post_id int64) (int64, []*ReactionCount, error) {
+
+	likes := int64(0)
+
+	lookup := make(map[string]*ReactionCount)
+	reactions := make([]*ReactionCount, 0)
+
+	cb := func(ctx context.Context, l *activitypub.Like) error {
+
+		if !l.IsEmojiReaction() {
+			likes += 1
+			return nil
+		}
+
+		r, exists := lookup[l.Emoji]
+
+		if !exists {
+
+			r = &ReactionCount{
+				Emoji:    l.Emoji,
+				EmojiURL: l.EmojiURL,
+			}
+
+			lookup[l.Emoji] = r
+			reactions = append(reactions, r)
+		}
+
+		if r.EmojiURL == "" {
+			r.EmojiURL = l.EmojiURL
+		}
+
+		r.Count += 1
+		return nil
+	}
+
+	err := likes_db.GetLikesForPost(ctx, post_id, cb)
+
+	if err != nil {
+		return 0, nil, fmt.Errorf("Failed to retrieve likes for post, %w", err)
+	}
+
+	sort.SliceStable(reactions, func(i, j int) bool {
+		return reactions[i].Count > reactions[j].Count
+	})
+
+	return likes, reactions, nil
+}
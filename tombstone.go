@@ -0,0 +1,53 @@
+package activitypub
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sfomuseum/go-activitypub/id"
+)
+
+// Tombstone is a record of a post that has been deleted. It is used to tell people requesting the (deleted) post
+// that it existed but is gone rather than simply not found.
+type Tombstone struct {
+	// The unique ID for the tombstone.
+	Id int64 `json:"id"`
+	// The unique ID of the post that was deleted.
+	PostId int64 `json:"post_id"`
+	// The AccountsDatabase ID of the author of the post.
+	AccountId int64 `json:"account_id"`
+	// The (ActivityPub) type of the object that was deleted, for example "Note".
+	FormerType string `json:"former_type"`
+	// The Unix timestamp when the post was created.
+	Published int64 `json:"published"`
+	// The Unix timestamp when the post was deleted.
+	Created int64 `json:"created"`
+	// The addresses of the people who received the post (and any updates to it). These are recorded when the tombstone is
+	// created, before the activities for the post are removed, so that the deletion can still be delivered if it is retried.
+	Recipients []string `json:"recipients,omitempty"`
+}
+
+// NewTombstone returns a new `Tombstone` instance for 'post'.
+func NewTombstone(ctx context.Context, post *Post) (*Tombstone, error) {
+
+	tombstone_id, err := id.NewId()
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive new tombstone ID, %w", err)
+	}
+
+	now := time.Now()
+	ts := now.Unix()
+
+	t := &Tombstone{
+		Id:         tombstone_id,
+		PostId:     post.Id,
+		AccountId:  post.AccountId,
+		FormerType: "Note",
+		Published:  post.Created,
+		Created:    ts,
+	}
+
+	return t, nil
+}
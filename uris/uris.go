@@ -2,21 +2,27 @@ package uris
 
 import (
 	"net/url"
+	"regexp"
 	"strings"
 )
 
 type URIs struct {
 	// Webfinger is assigned automatically
 
-	Root      string `json:"root"`
-	Account   string `json:"account"`
-	Posts     string `json:"posts"`
-	Post      string `json:"post"`
-	Inbox     string `json:"inbox"`
-	Outbox    string `json:"outbox"`
-	Followers string `json:"followers"`
-	Following string `json:"following"`
-	Icon      string `json:"icon"`
+	Root    string `json:"root"`
+	Account string `json:"account"`
+	Posts   string `json:"posts"`
+	Post    string `json:"post"`
+	Inbox   string `json:"inbox"`
+	// SharedInbox is the inbox shared by all the accounts on the server. Remote servers
+	// can post an activity here once rather than posting it to each account's inbox.
+	SharedInbox string `json:"shared_inbox"`
+	Outbox      string `json:"outbox"`
+	Followers   string `json:"followers"`
+	Following   string `json:"following"`
+	Icon        string `json:"icon"`
+	// Media is the URI that media files attached to posts are served from.
+	Media string `json:"media"`
 
 	Hostname string `json:"hostname"`
 	Insecure bool   `json:"insecure"`
@@ -27,15 +33,20 @@ func DefaultURIs() *URIs {
 	uris_table := &URIs{
 		// Webfinger is assigned automatically
 
-		Root:      "/ap",
-		Account:   "/ap/{resource}",
-		Posts:     "/ap/{resource}/posts",
-		Post:      "/ap/{resource}/posts/{id}",
-		Inbox:     "/ap/{resource}/inbox",
-		Outbox:    "/ap/{resource}/outbox",
-		Followers: "/ap/{resource}/followers",
-		Following: "/ap/{resource}/following",
-		Icon:      "/ap/{resource}/icon.png",
+		Root:    "/ap",
+		Account: "/ap/{resource}",
+		Posts:   "/ap/{resource}/posts",
+		Post:    "/ap/{resource}/posts/{id}",
+		Inbox:   "/ap/{resource}/inbox",
+		// Note that this is not "/ap/inbox" since routes are resolved by the aaronland/go-http RouteHandler
+		// which tries the longest pattern first, regardless of method, so "POST /ap/inbox" would be matched
+		// by the (longer) "GET /ap/{resource}" route and rejected with a 405 Method Not Allowed response.
+		SharedInbox: "/inbox",
+		Outbox:      "/ap/{resource}/outbox",
+		Followers:   "/ap/{resource}/followers",
+		Following:   "/ap/{resource}/following",
+		Icon:        "/ap/{resource}/icon.png",
+		Media:       "/ap/{resource}/media/{id}",
 	}
 
 	return uris_table
@@ -49,6 +60,29 @@ func AssignId(uri string, id string) string {
 	return strings.Replace(uri, "{id}", id, -1)
 }
 
+// ResourceFromPath returns the value of the "{resource}" substitution in 'uri' that was used to
+// derive 'path'. If 'path' was not derived from 'uri' then the second return value will be false.
+func ResourceFromPath(uri string, path string) (string, bool) {
+
+	pat := regexp.QuoteMeta(uri)
+	pat = strings.Replace(pat, regexp.QuoteMeta("{resource}"), "([^/]+)", 1)
+	pat = strings.Replace(pat, regexp.QuoteMeta("{id}"), "[^/]+", -1)
+
+	re, err := regexp.Compile("^" + pat + "$")
+
+	if err != nil {
+		return "", false
+	}
+
+	m := re.FindStringSubmatch(path)
+
+	if len(m) != 2 {
+		return "", false
+	}
+
+	return m[1], true
+}
+
 func NewURL(uris_table *URIs, path string) *url.URL {
 
 	scheme := "https"
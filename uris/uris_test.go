@@ -0,0 +1,47 @@
+package uris
+
+import (
+	"testing"
+)
+
+func TestResourceFromPath(t *testing.T) {
+
+	uris_table := DefaultURIs()
+
+	tests := map[string]string{
+		"/ap/bob":               "bob",
+		"/ap/@bob":              "@bob",
+		"/ap/bob/posts/1234":    "",
+		"/ap/bob/inbox":         "",
+		"/inbox":                "",
+		"/ap/bob/posts/1234/xx": "",
+	}
+
+	for path, expected := range tests {
+
+		resource, ok := ResourceFromPath(uris_table.Account, path)
+
+		if expected == "" {
+
+			if ok {
+				t.Fatalf("Expected %s not to match account URI but got '%s'", path, resource)
+			}
+
+			continue
+		}
+
+		if !ok {
+			t.Fatalf("Expected %s to match account URI", path)
+		}
+
+		if resource != expected {
+			t.Fatalf("Unexpected resource for %s. Expected '%s' but got '%s'", path, expected, resource)
+		}
+	}
+
+	resource, ok := ResourceFromPath(uris_table.Post, "/ap/bob/posts/1234")
+
+	if !ok || resource != "bob" {
+		t.Fatalf("Expected post URI to match 'bob' but got '%s' (%t)", resource, ok)
+	}
+}
@@ -0,0 +1,169 @@
+package www
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/aaronland/go-http/v3/slog"
+	"github.com/sfomuseum/go-activitypub/blocks"
+	"github.com/sfomuseum/go-activitypub/database"
+	"github.com/sfomuseum/go-activitypub/signatures"
+	"github.com/sfomuseum/go-activitypub/uris"
+)
+
+type AllowlistHandlerOptions struct {
+	// DomainAllowsDatabase is the `database.DomainAllowsDatabase` instance containing the hosts that are allowed to make
+	// requests. If nil then the server is not running in allowlist mode and all requests are allowed.
+	DomainAllowsDatabase database.DomainAllowsDatabase
+	// ActivityStreamsOnly restricts checks to requests for ActivityStreams documents, allowing (human-facing) HTML pages
+	// to be served to anyone.
+	ActivityStreamsOnly bool
+	// ActorsDatabase is the `database.ActorsDatabase` instance used to cache the actors that sign requests.
+	ActorsDatabase database.ActorsDatabase
+	// ActorsTTL is the amount of time cached actors are considered valid before they are fetched again.
+	ActorsTTL time.Duration
+	// SignatureClockSkew is the maximum allowed difference between the time a request was signed and the current time.
+	SignatureClockSkew time.Duration
+	// URIs is the `uris.URIs` instance used to derive the server's hostname when verifying signatures.
+	URIs *uris.URIs
+}
+
+// AllowlistHandler returns a `http.Handler` instance that ensures requests are made by hosts that are on the server's
+// allowlist before invoking 'next'. Since (GET) requests have no actor the requesting host is derived from the key used to
+// sign the request. The host is checked before the request's HTTP signature (either RFC 9421 or draft-cavage) is verified
+// (see `verifyInboxRequest`) so that nothing is fetched from hosts that are not on the allowlist. Requests without a signature,
+// signed with a key on a host that is not on the allowlist or with a signature that can not be verified are refused with a
+// 403 Forbidden response.
+func AllowlistHandler(opts *AllowlistHandlerOptions, next http.Handler) http.Handler {
+
+	if opts.DomainAllowsDatabase == nil {
+		return next
+	}
+
+	verify_opts := &InboxPostHandlerOptions{
+		ActorsDatabase:     opts.ActorsDatabase,
+		ActorsTTL:          opts.ActorsTTL,
+		SignatureClockSkew: opts.SignatureClockSkew,
+		URIs:               opts.URIs,
+	}
+
+	fn := func(rsp http.ResponseWriter, req *http.Request) {
+
+		if opts.ActivityStreamsOnly && !IsActivityStreamRequest(req, "Accept") {
+			next.ServeHTTP(rsp, req)
+			return
+		}
+
+		ctx := req.Context()
+		logger := slog.LoggerWithRequest(req, nil)
+
+		// Check the host of the key used to sign the request before verifying the signature so that
+		// actors (and keys) on hosts that are not on the allowlist are never fetched.
+
+		key_id, err := requestSignatureKeyId(req)
+
+		if err != nil {
+			logger.Warn("Refused unsigned request (allowlist mode)", "error", err)
+			http.Error(rsp, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		key_u, err := url.Parse(key_id)
+
+		if err != nil || key_u.Host == "" {
+			logger.Warn("Refused request with invalid key ID (allowlist mode)", "key id", key_id, "error", err)
+			http.Error(rsp, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		host := key_u.Host
+
+		logger = logger.With("host", host, "key id", key_id)
+
+		is_allowed, err := blocks.IsAllowedHost(ctx, opts.DomainAllowsDatabase, host)
+
+		if err != nil {
+			logger.Error("Failed to determine if host is allowed", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		if !is_allowed {
+			logger.Warn("Refused request from host not on allowlist")
+			http.Error(rsp, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		// Verification updates the host (and URL) of the request so verify a copy rather than
+		// changing the request passed to 'next'. Verification also ensures that the key has the
+		// same origin as the actor that signed the request (see `checkInboxKeyOwner`).
+
+		requestor := &inboxRequestor{}
+
+		_, err = verifyInboxRequest(ctx, verify_opts, req.Clone(ctx), nil, requestor, logger)
+
+		if err != nil {
+			logger.Warn("Refused request from unverified host (allowlist mode)", "error", err)
+			http.Error(rsp, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(rsp, req)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// requestSignatureKeyId returns the ID of the key used to sign 'req' with either a RFC 9421 or a draft-cavage HTTP signature.
+// The signature itself is not verified.
+func requestSignatureKeyId(req *http.Request) (string, error) {
+
+	if signatures.IsRFC9421Request(req) {
+
+		sig, err := signatures.ParseRFC9421Signature(req)
+
+		if err != nil {
+			return "", fmt.Errorf("Failed to parse signature, %w", err)
+		}
+
+		return sig.KeyId, nil
+	}
+
+	sig, err := signatures.ParseSignature(req)
+
+	if err != nil {
+		return "", fmt.Errorf("Failed to parse signature, %w", err)
+	}
+
+	return sig.KeyId, nil
+}
+
+// checkInboxDomainAllow ensures that, if the server is running in allowlist mode, the host of 'address' (the actor
+// performing an activity) is on the allowlist. If not an error is returned along with the HTTP status code to return.
+func checkInboxDomainAllow(ctx context.Context, opts *InboxPostHandlerOptions, address string) (int, error) {
+
+	if opts.DomainAllowsDatabase == nil {
+		return 0, nil
+	}
+
+	host, err := blocks.HostFromAddress(address)
+
+	if err != nil {
+		return http.StatusBadRequest, fmt.Errorf("Failed to derive host for %s, %w", address, err)
+	}
+
+	is_allowed, err := blocks.IsAllowedHost(ctx, opts.DomainAllowsDatabase, host)
+
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("Failed to determine if host is allowed, %w", err)
+	}
+
+	if !is_allowed {
+		return http.StatusForbidden, fmt.Errorf("Host %s is not on the allowlist", host)
+	}
+
+	return 0, nil
+}
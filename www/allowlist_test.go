@@ -0,0 +1,166 @@
+package www
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-fed/httpsig"
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/crypto"
+	"github.com/sfomuseum/go-activitypub/signatures"
+	"github.com/sfomuseum/go-activitypub/uris"
+)
+
+func TestAllowlistHandler(t *testing.T) {
+
+	private_pem, public_pem, err := crypto.GenerateKeyPair(2048)
+
+	if err != nil {
+		t.Fatalf("Failed to generate key pair, %v", err)
+	}
+
+	private_key, err := crypto.RSAPrivateKeyFromPEM(string(private_pem))
+
+	if err != nil {
+		t.Fatalf("Failed to parse private key, %v", err)
+	}
+
+	var actor *ap.Actor
+	fetches := 0
+
+	actor_handler := func(rsp http.ResponseWriter, req *http.Request) {
+		fetches += 1
+		rsp.Header().Set("Content-Type", "application/activity+json")
+		json.NewEncoder(rsp).Encode(actor)
+	}
+
+	s := httptest.NewServer(http.HandlerFunc(actor_handler))
+	defer s.Close()
+
+	s_u, err := url.Parse(s.URL)
+
+	if err != nil {
+		t.Fatalf("Failed to parse server URL, %v", err)
+	}
+
+	actor_id := s.URL + "/users/alice"
+	key_id := actor_id + "#main-key"
+
+	actor = &ap.Actor{
+		Id:                actor_id,
+		Type:              "Person",
+		PreferredUsername: "alice",
+		PublicKey: ap.PublicKey{
+			Id:    key_id,
+			Owner: actor_id,
+			PEM:   string(public_pem),
+		},
+	}
+
+	uris_table := uris.DefaultURIs()
+	uris_table.Hostname = "social.example"
+	uris_table.Insecure = true
+
+	ok_handler := http.HandlerFunc(func(rsp http.ResponseWriter, req *http.Request) {
+		rsp.WriteHeader(http.StatusOK)
+	})
+
+	new_handler := func(domain string) http.Handler {
+
+		db := &testDomainAllowsDatabase{
+			domains: map[string]bool{
+				domain: true,
+			},
+		}
+
+		opts := &AllowlistHandlerOptions{
+			DomainAllowsDatabase: db,
+			ActivityStreamsOnly:  true,
+			SignatureClockSkew:   5 * time.Minute,
+			URIs:                 uris_table,
+		}
+
+		return AllowlistHandler(opts, ok_handler)
+	}
+
+	sign := func(req *http.Request) {
+
+		signer, _, err := httpsig.NewSigner([]httpsig.Algorithm{httpsig.RSA_SHA256}, httpsig.DigestSha256, signatures.DefaultRequiredGetHeaders, httpsig.Signature, 60)
+
+		if err != nil {
+			t.Fatalf("Failed to create signer, %v", err)
+		}
+
+		req.Header.Set("Host", req.Host)
+
+		err = signer.SignRequest(private_key, key_id, req, nil)
+
+		if err != nil {
+			t.Fatalf("Failed to sign request, %v", err)
+		}
+	}
+
+	tests := []struct {
+		Description string
+		Domain      string
+		Accept      string
+		Signature   func(*http.Request)
+		Status      int
+		Fetched     bool
+	}{
+		{"html request", "partner.museum", "text/html", nil, http.StatusOK, false},
+		{"unsigned request", s_u.Hostname(), "application/activity+json", nil, http.StatusForbidden, false},
+		{"unverified signature", s_u.Hostname(), "application/activity+json", func(req *http.Request) {
+			req.Header.Set("Host", req.Host)
+			req.Header.Set("Signature", fmt.Sprintf(`keyId="%s",algorithm="rsa-sha256",headers="(request-target) host date",signature="c2ln"`, key_id))
+		}, http.StatusForbidden, true},
+		{"allowed host", s_u.Hostname(), "application/activity+json", sign, http.StatusOK, true},
+		// Keys on hosts that are not on the allowlist are never fetched
+		{"other host", "partner.museum", "application/activity+json", sign, http.StatusForbidden, false},
+	}
+
+	for _, test := range tests {
+
+		h := new_handler(test.Domain)
+		fetches = 0
+
+		req := httptest.NewRequest(http.MethodGet, "http://social.example/ap/alice", nil)
+		req.Header.Set("Accept", test.Accept)
+		req.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+
+		if test.Signature != nil {
+			test.Signature(req)
+		}
+
+		rsp := httptest.NewRecorder()
+		h.ServeHTTP(rsp, req)
+
+		if rsp.Code != test.Status {
+			t.Fatalf("Unexpected status for %s, expected %d but got %d", test.Description, test.Status, rsp.Code)
+		}
+
+		if (fetches > 0) != test.Fetched {
+			t.Fatalf("Unexpected actor fetches for %s, %d", test.Description, fetches)
+		}
+	}
+
+	// Not running in allowlist mode
+
+	h := AllowlistHandler(&AllowlistHandlerOptions{}, ok_handler)
+
+	req := httptest.NewRequest(http.MethodGet, "/ap/alice", nil)
+	req.Header.Set("Accept", "application/activity+json")
+
+	rsp := httptest.NewRecorder()
+
+	h.ServeHTTP(rsp, req)
+
+	if rsp.Code != http.StatusOK {
+		t.Fatalf("Unexpected status when allowlist mode is disabled, %d", rsp.Code)
+	}
+}
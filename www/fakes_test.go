@@ -0,0 +1,408 @@
+package www
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/database"
+	"github.com/sfomuseum/go-activitypub/deliver"
+)
+
+type testDomainAllowsDatabase struct {
+	database.DomainAllowsDatabase
+	domains map[string]bool
+}
+
+func (db *testDomainAllowsDatabase) GetDomainAllowWithDomain(ctx context.Context, domain string) (*activitypub.DomainAllow, error) {
+
+	if !db.domains[domain] {
+		return nil, activitypub.ErrNotFound
+	}
+
+	return activitypub.NewDomainAllow(ctx, domain)
+}
+
+type testPostsDatabase struct {
+	database.PostsDatabase
+	posts map[int64]*activitypub.Post
+}
+
+func (db *testPostsDatabase) GetPostWithId(ctx context.Context, id int64) (*activitypub.Post, error) {
+
+	p, exists := db.posts[id]
+
+	if !exists {
+		return nil, activitypub.ErrNotFound
+	}
+
+	return p, nil
+}
+
+type testNotesDatabase struct {
+	database.NotesDatabase
+}
+
+func (db *testNotesDatabase) GetNoteWithUUIDAndAuthorAddress(ctx context.Context, uuid string, author string) (*activitypub.Note, error) {
+	return nil, activitypub.ErrNotFound
+}
+
+type testReportsDatabase struct {
+	database.ReportsDatabase
+	reports []*activitypub.Report
+}
+
+func (db *testReportsDatabase) AddReport(ctx context.Context, r *activitypub.Report) error {
+	db.reports = append(db.reports, r)
+	return nil
+}
+
+type testFollowersDatabase struct {
+	database.FollowersDatabase
+	followers map[string]*activitypub.Follower
+}
+
+func (db *testFollowersDatabase) GetFollower(ctx context.Context, account_id int64, address string) (*activitypub.Follower, error) {
+
+	f, exists := db.followers[address]
+
+	if !exists || f.AccountId != account_id {
+		return nil, activitypub.ErrNotFound
+	}
+
+	return f, nil
+}
+
+func (db *testFollowersDatabase) AddFollower(ctx context.Context, f *activitypub.Follower) error {
+	db.followers[f.FollowerAddress] = f
+	return nil
+}
+
+func (db *testFollowersDatabase) RemoveFollower(ctx context.Context, f *activitypub.Follower) error {
+	delete(db.followers, f.FollowerAddress)
+	return nil
+}
+
+type testDeliveryQueue struct {
+	recipients []string
+}
+
+func (q *testDeliveryQueue) DeliverActivity(ctx context.Context, opts *deliver.DeliverActivityOptions) error {
+	q.recipients = append(q.recipients, opts.To)
+	return nil
+}
+
+func (q *testDeliveryQueue) Close(ctx context.Context) error {
+	return nil
+}
+
+type testAccountsDatabase struct {
+	database.AccountsDatabase
+	account *activitypub.Account
+}
+
+func (db *testAccountsDatabase) GetAccountWithId(ctx context.Context, id int64) (*activitypub.Account, error) {
+
+	if db.account.Id != id {
+		return nil, activitypub.ErrNotFound
+	}
+
+	return db.account, nil
+}
+
+func (db *testAccountsDatabase) GetAccountWithName(ctx context.Context, name string) (*activitypub.Account, error) {
+
+	if db.account.Name != name {
+		return nil, activitypub.ErrNotFound
+	}
+
+	return db.account, nil
+}
+
+type testQueuedActivitiesDatabase struct {
+	database.QueuedActivitiesDatabase
+	activities map[int64]*activitypub.QueuedActivity
+}
+
+func (db *testQueuedActivitiesDatabase) AddQueuedActivity(ctx context.Context, q *activitypub.QueuedActivity) error {
+	db.activities[q.Id] = q
+	return nil
+}
+
+func (db *testQueuedActivitiesDatabase) GetQueuedActivityWithId(ctx context.Context, id int64) (*activitypub.QueuedActivity, error) {
+
+	q, exists := db.activities[id]
+
+	if !exists {
+		return nil, activitypub.ErrNotFound
+	}
+
+	return q, nil
+}
+
+func (db *testQueuedActivitiesDatabase) RemoveQueuedActivity(ctx context.Context, q *activitypub.QueuedActivity) error {
+	delete(db.activities, q.Id)
+	return nil
+}
+
+type testBlocksDatabase struct {
+	database.BlocksDatabase
+	blocked map[string]bool
+}
+
+func (db *testBlocksDatabase) GetBlockWithAccountIdAndAddress(ctx context.Context, account_id int64, host string, name string) (*activitypub.Block, error) {
+
+	if !db.blocked[name+"@"+host] {
+		return nil, activitypub.ErrNotFound
+	}
+
+	return activitypub.NewBlock(ctx, account_id, host, name)
+}
+
+type testReceivedActivitiesDatabase struct {
+	database.ReceivedActivitiesDatabase
+	received []*activitypub.ReceivedActivity
+}
+
+func (db *testReceivedActivitiesDatabase) AddReceivedActivity(ctx context.Context, r *activitypub.ReceivedActivity) error {
+
+	for _, existing := range db.received {
+
+		if existing.ActivityPubId == r.ActivityPubId && existing.AccountId == r.AccountId && existing.Actor == r.Actor {
+			return activitypub.ErrDuplicate
+		}
+	}
+
+	db.received = append(db.received, r)
+	return nil
+}
+
+func (db *testReceivedActivitiesDatabase) UpdateReceivedActivity(ctx context.Context, r *activitypub.ReceivedActivity) error {
+
+	for idx, existing := range db.received {
+
+		if existing.Id == r.Id {
+			db.received[idx] = r
+		}
+	}
+
+	return nil
+}
+
+func (db *testReceivedActivitiesDatabase) RemoveReceivedActivity(ctx context.Context, r *activitypub.ReceivedActivity) error {
+
+	db.received = slices.DeleteFunc(db.received, func(existing *activitypub.ReceivedActivity) bool {
+		return existing.Id == r.Id
+	})
+
+	return nil
+}
+
+func (db *testReceivedActivitiesDatabase) GetReceivedActivitiesWithActivityPubIdAndAccount(ctx context.Context, activity_id string, account_id int64, cb database.GetReceivedActivitiesCallbackFunc) error {
+
+	for _, r := range db.received {
+
+		if r.ActivityPubId != activity_id || r.AccountId != account_id {
+			continue
+		}
+
+		err := cb(ctx, r)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type testFollowingDatabase struct {
+	database.FollowingDatabase
+	following []*activitypub.Following
+}
+
+func (db *testFollowingDatabase) GetAccountIdsForFollowingAddress(ctx context.Context, address string, cb database.GetAccountIdsCallbackFunc) error {
+
+	for _, f := range db.following {
+
+		if f.FollowingAddress != address {
+			continue
+		}
+
+		err := cb(ctx, f.AccountId)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *testFollowingDatabase) GetFollowing(ctx context.Context, account_id int64, address string) (*activitypub.Following, error) {
+
+	for _, f := range db.following {
+
+		if f.AccountId == account_id && f.FollowingAddress == address {
+			return f, nil
+		}
+	}
+
+	return nil, activitypub.ErrNotFound
+}
+
+func (db *testFollowingDatabase) UpdateFollowing(ctx context.Context, f *activitypub.Following) error {
+	return nil
+}
+
+func (db *testFollowingDatabase) RemoveFollowing(ctx context.Context, f *activitypub.Following) error {
+
+	following := make([]*activitypub.Following, 0)
+
+	for _, other := range db.following {
+
+		if other.Id != f.Id {
+			following = append(following, other)
+		}
+	}
+
+	db.following = following
+	return nil
+}
+
+type testBoostsDatabase struct {
+	database.BoostsDatabase
+	boosts map[int64]*activitypub.Boost
+}
+
+func (db *testBoostsDatabase) GetBoostWithPostIdAndActor(ctx context.Context, post_id int64, actor string) (*activitypub.Boost, error) {
+
+	for _, b := range db.boosts {
+
+		if b.PostId == post_id && b.Actor == actor {
+			return b, nil
+		}
+	}
+
+	return nil, activitypub.ErrNotFound
+}
+
+func (db *testBoostsDatabase) RemoveBoost(ctx context.Context, b *activitypub.Boost) error {
+	delete(db.boosts, b.Id)
+	return nil
+}
+
+type testMediaDatabase struct {
+	database.MediaDatabase
+	media map[int64]*activitypub.Media
+}
+
+func (db *testMediaDatabase) GetMediaWithId(ctx context.Context, id int64) (*activitypub.Media, error) {
+
+	m, exists := db.media[id]
+
+	if !exists {
+		return nil, activitypub.ErrNotFound
+	}
+
+	return m, nil
+}
+
+type testActivitiesDatabase struct {
+	database.ActivitiesDatabase
+	activities []*activitypub.Activity
+}
+
+func (db *testActivitiesDatabase) GetActivitiesForAccountWithCursor(ctx context.Context, account_id int64, cursor *database.ActivitiesCursor, cb database.GetActivitiesCallbackFunc) error {
+
+	activities := make([]*activitypub.Activity, 0)
+
+	for _, a := range db.activities {
+
+		if a.AccountId != account_id || !slices.Contains(cursor.ActivityTypes, a.ActivityType) {
+			continue
+		}
+
+		if (cursor.MaxId > 0 && a.Id >= cursor.MaxId) || (cursor.MinId > 0 && a.Id <= cursor.MinId) {
+			continue
+		}
+
+		activities = append(activities, a)
+	}
+
+	sort.Slice(activities, func(i, j int) bool {
+
+		if cursor.Oldest {
+			return activities[i].Id < activities[j].Id
+		}
+
+		return activities[i].Id > activities[j].Id
+	})
+
+	if len(activities) > cursor.Limit {
+		activities = activities[:cursor.Limit]
+	}
+
+	if cursor.Oldest {
+		slices.Reverse(activities)
+	}
+
+	for _, a := range activities {
+
+		err := cb(ctx, a)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *testActivitiesDatabase) CountActivitiesForAccount(ctx context.Context, account_id int64, activity_types []activitypub.ActivityType) (int64, error) {
+
+	count := int64(0)
+
+	for _, a := range db.activities {
+
+		if a.AccountId == account_id && slices.Contains(activity_types, a.ActivityType) {
+			count += 1
+		}
+	}
+
+	return count, nil
+}
+
+type testAccessTokensDatabase struct {
+	database.AccessTokensDatabase
+	tokens map[string]*activitypub.AccessToken
+}
+
+func (db *testAccessTokensDatabase) GetAccessTokenWithToken(ctx context.Context, token string) (*activitypub.AccessToken, error) {
+
+	t, exists := db.tokens[token]
+
+	if !exists {
+		return nil, activitypub.ErrNotFound
+	}
+
+	return t, nil
+}
+
+type testTombstonesDatabase struct {
+	database.TombstonesDatabase
+	tombstones map[int64]*activitypub.Tombstone
+}
+
+func (db *testTombstonesDatabase) GetTombstoneWithPostId(ctx context.Context, post_id int64) (*activitypub.Tombstone, error) {
+
+	for _, t := range db.tombstones {
+
+		if t.PostId == post_id {
+			return t, nil
+		}
+	}
+
+	return nil, activitypub.ErrNotFound
+}
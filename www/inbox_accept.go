@@ -0,0 +1,141 @@
+package www
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/aaronland/go-http/v3/slog"
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/following"
+	"github.com/tidwall/gjson"
+)
+
+// InboxAcceptHandler returns a `http.Handler` for processing verified "Accept" activities posted to an account's inbox.
+// Accept activities are matched against the original "Follow" activity sent by the account and, if valid, are used to
+// mark the corresponding `activitypub.Following` record as accepted.
+func InboxAcceptHandler(opts *InboxPostHandlerOptions) (http.Handler, error) {
+	return inboxFollowResponseHandler(opts, activitypub.AcceptedFollowingState)
+}
+
+// inboxFollowResponseHandler returns a `http.Handler` for processing verified "Accept" or "Reject" activities sent
+// in response to a "Follow" activity and assigning 'state' to the corresponding `activitypub.Following` record.
+func inboxFollowResponseHandler(opts *InboxPostHandlerOptions, state activitypub.FollowingState) (http.Handler, error) {
+
+	fn := func(rsp http.ResponseWriter, req *http.Request) {
+
+		ctx := req.Context()
+
+		inbox_activity, err := InboxActivityFromContext(ctx)
+
+		if err != nil {
+			logger := slog.LoggerWithRequest(req, nil)
+			logger.Error("Failed to derive inbox activity from request", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		logger := inboxActivityLogger(req, inbox_activity)
+
+		acct := inbox_activity.Account
+		activity := inbox_activity.Activity
+		requestor_actor := inbox_activity.RequestorActor
+		requestor_address := inbox_activity.RequestorAddress
+
+		err = checkInboxActivityActor(activity.Actor, requestor_actor)
+
+		if err != nil {
+			logger.Error("Signature owner does not match activity actor", "error", err)
+			http.Error(rsp, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		var follow_id string
+		var follow_actor string
+
+		switch activity.Object.(type) {
+		case string:
+			follow_id = activity.Object.(string)
+		default:
+
+			enc_obj, err := json.Marshal(activity.Object)
+
+			if err != nil {
+				logger.Error("Failed to marshal activity object", "error", err)
+				http.Error(rsp, "Bad request", http.StatusBadRequest)
+				return
+			}
+
+			object_type := gjson.GetBytes(enc_obj, "type").String()
+
+			if object_type != "Follow" {
+				logger.Error("Unsupported activity object type", "type", object_type)
+				http.Error(rsp, "Not implemented", http.StatusNotImplemented)
+				return
+			}
+
+			follow_id = gjson.GetBytes(enc_obj, "id").String()
+			follow_actor = gjson.GetBytes(enc_obj, "actor").String()
+		}
+
+		logger = logger.With("follow id", follow_id)
+
+		if follow_id == "" {
+			logger.Error("Activity object is missing follow ID")
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		// Follow activities are sent with the account's address as the actor (see app/follow)
+		// but allow for the account URL as well
+
+		if follow_actor != "" {
+
+			account_address := acct.Address(opts.URIs.Hostname)
+			account_url := acct.AccountURL(ctx, opts.URIs).String()
+
+			if follow_actor != account_address && follow_actor != account_url {
+				logger.Error("Follow actor does not match account", "follow actor", follow_actor)
+				http.Error(rsp, "Bad request", http.StatusBadRequest)
+				return
+			}
+		}
+
+		f, err := following.GetFollowing(ctx, opts.FollowingDatabase, acct.Id, requestor_address)
+
+		if err != nil {
+
+			if err == activitypub.ErrNotFound {
+				logger.Error("Account is not following requestor")
+				http.Error(rsp, "Bad request", http.StatusBadRequest)
+				return
+			}
+
+			logger.Error("Failed to retrieve following", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		logger = logger.With("following id", f.Id)
+
+		if f.ActivityId != follow_id {
+			logger.Error("Follow ID does not match following record", "following activity id", f.ActivityId)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		_, err = following.UpdateFollowingState(ctx, opts.FollowingDatabase, f, state)
+
+		if err != nil {
+			logger.Error("Failed to update following state", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		logger.Info(fmt.Sprintf("Received '%s' activity", activity.Type), "state", state)
+		rsp.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	return http.HandlerFunc(fn), nil
+}
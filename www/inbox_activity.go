@@ -0,0 +1,125 @@
+package www
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	aa_slog "github.com/aaronland/go-http/v3/slog"
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/ap"
+)
+
+type inboxContextKey string
+
+const inbox_activity_context_key inboxContextKey = "inbox_activity"
+
+// InboxActivity encapsulates an activity posted to an account's inbox after the request signature
+// has been verified and block checks have been performed. It is passed to activity-specific handlers
+// using the request's `context.Context` instance.
+type InboxActivity struct {
+	// Activity is the (verified) activity that was posted to the inbox.
+	Activity *ap.Activity
+	// Account is the account whose inbox the activity was posted to.
+	Account *activitypub.Account
+	// RequestorActor is the actor whose key was used to sign the request.
+	RequestorActor *ap.Actor
+	// RequestorAddress is the "@name@host" address of the actor posting the activity.
+	RequestorAddress string
+	// DomainBlock is the server-wide domain block that applies to the host of the actor posting the activity.
+	// It is nil if the host is not blocked.
+	DomainBlock *activitypub.DomainBlock
+}
+
+// ContextWithInboxActivity returns a new `context.Context` instance derived from 'ctx' containing 'a'.
+func ContextWithInboxActivity(ctx context.Context, a *InboxActivity) context.Context {
+	return context.WithValue(ctx, inbox_activity_context_key, a)
+}
+
+// InboxActivityFromContext returns the `InboxActivity` instance stored in 'ctx'.
+func InboxActivityFromContext(ctx context.Context) (*InboxActivity, error) {
+
+	v := ctx.Value(inbox_activity_context_key)
+
+	if v == nil {
+		return nil, fmt.Errorf("Context is missing inbox activity")
+	}
+
+	a, ok := v.(*InboxActivity)
+
+	if !ok {
+		return nil, fmt.Errorf("Invalid inbox activity, %T", v)
+	}
+
+	return a, nil
+}
+
+// DefaultInboxActivityHandlers returns a map of activity-specific `http.Handler` instances, keyed by activity type,
+// used to process verified activities posted to an account's inbox. Handlers are only included for activity types
+// that have been enabled in 'opts'. A handler for "Flag" (report) activities is only included if 'opts' has a reports database.
+func DefaultInboxActivityHandlers(opts *InboxPostHandlerOptions) (map[string]http.Handler, error) {
+
+	type handlerFunc func(*InboxPostHandlerOptions) (http.Handler, error)
+
+	to_create := map[string]handlerFunc{
+		"Accept": InboxAcceptHandler,
+		"Reject": InboxRejectHandler,
+		"Delete": InboxDeleteHandler,
+		"Update": InboxUpdateHandler,
+	}
+
+	if opts.AllowFollow {
+		to_create["Follow"] = InboxFollowHandler
+		to_create["Move"] = InboxMoveHandler
+	}
+
+	if opts.AllowLikes {
+		to_create["Like"] = InboxLikeHandler
+		to_create["EmojiReact"] = InboxLikeHandler
+	}
+
+	if opts.AllowBoosts {
+		to_create["Announce"] = InboxAnnounceHandler
+	}
+
+	if opts.AllowCreate {
+		to_create["Create"] = InboxCreateHandler
+	}
+
+	if opts.ReportsDatabase != nil {
+		to_create["Flag"] = InboxFlagHandler
+	}
+
+	if opts.AllowFollow || opts.AllowLikes || opts.AllowBoosts {
+		to_create["Undo"] = InboxUndoHandler
+	}
+
+	activity_handlers := make(map[string]http.Handler)
+
+	for activity_type, fn := range to_create {
+
+		h, err := fn(opts)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create handler for '%s' activities, %w", activity_type, err)
+		}
+
+		activity_handlers[activity_type] = h
+	}
+
+	return activity_handlers, nil
+}
+
+// inboxActivityLogger returns a `slog.Logger` instance for 'req' with properties derived from 'a'.
+func inboxActivityLogger(req *http.Request, a *InboxActivity) *slog.Logger {
+
+	logger := aa_slog.LoggerWithRequest(req, nil)
+
+	logger = logger.With("requestor_address", a.RequestorAddress)
+	logger = logger.With("activity_type", a.Activity.Type)
+	logger = logger.With("account", a.Account.Name)
+	logger = logger.With("account id", a.Account.Id)
+
+	return logger
+}
@@ -0,0 +1,183 @@
+package www
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/aaronland/go-http/v3/slog"
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/posts"
+)
+
+// InboxAnnounceHandler returns a `http.Handler` for processing verified "Announce" (boost) activities posted to an account's inbox.
+// The object of the activity may be the URI of the post being boosted or an embedded copy of the post.
+func InboxAnnounceHandler(opts *InboxPostHandlerOptions) (http.Handler, error) {
+
+	fn := func(rsp http.ResponseWriter, req *http.Request) {
+
+		ctx := req.Context()
+
+		inbox_activity, err := InboxActivityFromContext(ctx)
+
+		if err != nil {
+			logger := slog.LoggerWithRequest(req, nil)
+			logger.Error("Failed to derive inbox activity from request", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		logger := inboxActivityLogger(req, inbox_activity)
+
+		acct := inbox_activity.Account
+		activity := inbox_activity.Activity
+
+		post, status, err := announceObjectPost(ctx, opts, activity.Object)
+
+		if err != nil {
+			logger.Error("Failed to derive post from announce object", "error", err)
+			http.Error(rsp, http.StatusText(status), status)
+			return
+		}
+
+		logger = logger.With("post id", post.Id)
+
+		if post.AccountId != acct.Id {
+			logger.Error("Trying to act on post for different account", "post account", post.AccountId)
+			http.Error(rsp, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		logger.Info("Get boost", "actor", activity.Actor)
+
+		boost, err := opts.BoostsDatabase.GetBoostWithPostIdAndActor(ctx, post.Id, activity.Actor)
+
+		if err != nil && err != activitypub.ErrNotFound {
+			logger.Error("Failed to derive boost from post and actor", "post id", post.Id, "actor", activity.Actor, "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		if boost == nil {
+
+			boost, err = activitypub.NewBoost(ctx, post, activity.Actor)
+
+			if err != nil {
+				logger.Error("Failed to create new boost for post and actor", "post id", post.Id, "actor", activity.Actor, "error", err)
+				http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+
+			err = opts.BoostsDatabase.AddBoost(ctx, boost)
+
+			if err != nil {
+				logger.Error("Failed to add new boost for post and actor", "post id", post.Id, "actor", activity.Actor, "error", err)
+				http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+
+			logger.Info("Create new boost", "post id", post.Id, "actor", activity.Actor, "boost", boost.Id)
+		}
+
+		// Do we need to send an accept here? Apparently not...
+
+		logger.Debug("Inbox post complete", "status", http.StatusAccepted)
+		rsp.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	return http.HandlerFunc(fn), nil
+}
+
+// announceObjectPost returns the local post that is the object of an "Announce" (or "Like") activity. If 'object' is an embedded object
+// (rather than a URI) its "id" property is checked first followed by its "url" property. Only URIs on this server are considered
+// so an embedded object can not claim to be one of our posts by borrowing its path. If there is an error the HTTP status code to
+// return is also included; for example 404 Not Found if none of the URIs resolve to a post.
+func announceObjectPost(ctx context.Context, opts *InboxPostHandlerOptions, object interface{}) (*activitypub.Post, int, error) {
+
+	object_uris, err := announceObjectURIs(object)
+
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	for _, uri := range object_uris {
+
+		u, err := url.Parse(uri)
+
+		if err != nil || u.Host != opts.URIs.Hostname {
+			continue
+		}
+
+		post, err := posts.GetPostFromObjectURI(ctx, opts.URIs, opts.PostsDatabase, uri)
+
+		if err != nil {
+
+			if err == activitypub.ErrNotFound {
+				continue
+			}
+
+			return nil, http.StatusInternalServerError, fmt.Errorf("Failed to derive post from object URI %s, %w", uri, err)
+		}
+
+		return post, 0, nil
+	}
+
+	return nil, http.StatusNotFound, fmt.Errorf("Announce object does not resolve to a local post, %w", activitypub.ErrNotFound)
+}
+
+// announceObjectURIs returns the list of candidate URIs for the object of an "Announce" activity. 'object' may be a URI or an
+// embedded object in which case its "id" property is returned first followed by its "url" property, which may itself be a URI,
+// a "Link" object or a list of either.
+func announceObjectURIs(object interface{}) ([]string, error) {
+
+	switch v := object.(type) {
+	case string:
+		return []string{v}, nil
+	case map[string]interface{}:
+
+		object_uris := make([]string, 0)
+
+		id, ok := v["id"].(string)
+
+		if ok && id != "" {
+			object_uris = append(object_uris, id)
+		}
+
+		var links []interface{}
+
+		switch u := v["url"].(type) {
+		case []interface{}:
+			links = u
+		case nil:
+			links = []interface{}{}
+		default:
+			links = []interface{}{u}
+		}
+
+		for _, l := range links {
+
+			switch link := l.(type) {
+			case string:
+				object_uris = append(object_uris, link)
+			case map[string]interface{}:
+
+				href, ok := link["href"].(string)
+
+				if ok && href != "" {
+					object_uris = append(object_uris, href)
+				}
+			}
+		}
+
+		if len(object_uris) == 0 {
+			return nil, fmt.Errorf("Announce object is missing 'id' and 'url' properties")
+		}
+
+		return object_uris, nil
+
+	default:
+		return nil, fmt.Errorf("Invalid or unsupported announce object type, %T", object)
+	}
+}
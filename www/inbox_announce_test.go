@@ -0,0 +1,70 @@
+package www
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/uris"
+)
+
+func TestAnnounceObjectPost(t *testing.T) {
+
+	ctx := context.Background()
+
+	uris_table := uris.DefaultURIs()
+	uris_table.Hostname = "example.com"
+
+	posts_db := &testPostsDatabase{
+		posts: map[int64]*activitypub.Post{
+			99: &activitypub.Post{Id: 99, AccountId: 1234},
+		},
+	}
+
+	opts := &InboxPostHandlerOptions{
+		PostsDatabase: posts_db,
+		URIs:          uris_table,
+	}
+
+	tests := []struct {
+		Description string
+		Object      interface{}
+		Status      int
+	}{
+		{"uri", "https://example.com/ap/@alice/posts/99", 0},
+		{"embedded id", map[string]interface{}{"type": "Note", "id": "https://example.com/ap/@alice/posts/99"}, 0},
+		{"embedded url", map[string]interface{}{"type": "Note", "url": "https://example.com/ap/@alice/posts/99"}, 0},
+		{"embedded link", map[string]interface{}{"type": "Note", "id": "https://remote.social/notes/1", "url": []interface{}{map[string]interface{}{"type": "Link", "href": "https://example.com/ap/@alice/posts/99"}}}, 0},
+		{"remote host", map[string]interface{}{"type": "Note", "id": "https://remote.social/ap/@alice/posts/99"}, http.StatusNotFound},
+		{"missing post", "https://example.com/ap/@alice/posts/100", http.StatusNotFound},
+		{"missing properties", map[string]interface{}{"type": "Note"}, http.StatusBadRequest},
+		{"invalid object", 42, http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+
+		post, status, err := announceObjectPost(ctx, opts, test.Object)
+
+		if test.Status == 0 {
+
+			if err != nil {
+				t.Fatalf("Failed to derive post for %s, %v", test.Description, err)
+			}
+
+			if post.Id != 99 {
+				t.Fatalf("Unexpected post for %s, %d", test.Description, post.Id)
+			}
+
+			continue
+		}
+
+		if err == nil {
+			t.Fatalf("Expected %s to fail", test.Description)
+		}
+
+		if status != test.Status {
+			t.Fatalf("Unexpected status for %s, expected %d but got %d", test.Description, test.Status, status)
+		}
+	}
+}
@@ -0,0 +1,319 @@
+package www
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/aaronland/go-http/v3/slog"
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/following"
+	"github.com/sfomuseum/go-activitypub/messages"
+	"github.com/sfomuseum/go-activitypub/notes"
+	"github.com/sfomuseum/go-activitypub/posts"
+	"github.com/tidwall/gjson"
+)
+
+// InboxCreateHandler returns a `http.Handler` for processing verified "Create" activities posted to an account's inbox.
+func InboxCreateHandler(opts *InboxPostHandlerOptions) (http.Handler, error) {
+
+	fn := func(rsp http.ResponseWriter, req *http.Request) {
+
+		ctx := req.Context()
+		wg := new(sync.WaitGroup)
+
+		inbox_activity, err := InboxActivityFromContext(ctx)
+
+		if err != nil {
+			logger := slog.LoggerWithRequest(req, nil)
+			logger.Error("Failed to derive inbox activity from request", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		logger := inboxActivityLogger(req, inbox_activity)
+
+		acct := inbox_activity.Account
+		activity := inbox_activity.Activity
+		requestor_address := inbox_activity.RequestorAddress
+
+		enc_obj, err := json.Marshal(activity.Object)
+
+		if err != nil {
+			logger.Error("Failed to marshal activity object", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		// Ensure we are creating a "Note" (or something that can be stored as a note)
+
+		object_type := gjson.GetBytes(enc_obj, "type").String()
+
+		if !ap.IsNoteObjectType(object_type) {
+			logger.Error("Unsupported create activity object type", "type", object_type)
+			http.Error(rsp, "Not implemented", http.StatusNotImplemented)
+			return
+		}
+
+		logger = logger.With("object type", object_type)
+
+		// The signing actor has already been checked to be one of the (possibly many) actors the object is
+		// attributed to so make sure it is the one the note is attributed to.
+
+		author := ""
+
+		if inbox_activity.RequestorActor != nil {
+			author = inbox_activity.RequestorActor.Id
+		}
+
+		enc_obj, err = ap.NormalizeNoteObject(enc_obj, author)
+
+		if err != nil {
+			logger.Error("Failed to normalize activity object", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		var note *ap.Note
+
+		err = json.Unmarshal(enc_obj, &note)
+
+		if err != nil {
+			logger.Error("Failed to unmarshal activity note", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		logger = logger.With("note id", note.Id)
+
+		is_following, f, err := following.IsFollowing(ctx, opts.FollowingDatabase, acct.Id, requestor_address)
+
+		if err != nil {
+			logger.Error("Failed to determine if following", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		// Only trust follow requests which have been accepted
+
+		if is_following && !f.IsAccepted() {
+			logger.Warn("Follow request has not been accepted", "following id", f.Id, "state", f.State)
+			is_following = false
+		}
+
+		logger = logger.With("is following", is_following)
+
+		// If we are following this account then it's all good
+
+		is_allowed := is_following
+
+		// Posts from silenced domains are only accepted from accounts that are already followed
+
+		is_silenced := inbox_activity.DomainBlock != nil && inbox_activity.DomainBlock.IsSilenced()
+
+		if !is_allowed && is_silenced {
+			logger.Error("Not following author on silenced domain", "domain", inbox_activity.DomainBlock.Domain)
+			http.Error(rsp, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		// If not following then check to see whether account (being posted to)
+		// is mentioned in post (being received)
+
+		if !is_allowed && opts.AllowMentions && len(note.Tags) > 0 {
+
+			// https://github.com/sfomuseum/go-activitypub/issues/3
+			// account_url := acct.URL
+
+			// And yet it appears to actually be {ACTOR}.id however this
+			// does not work (where "work" means open profile tab) in Ivory
+			// yet because... I have no idea
+			account_url := acct.AccountURL(ctx, opts.URIs).String()
+
+			for _, t := range note.Tags {
+
+				if t.Href == account_url {
+					logger.Info("Post author is not followed but account is mentioned")
+					is_allowed = true
+					break
+				}
+			}
+
+			if !is_allowed {
+				logger.Warn("Post author is not followed and not found in mentions", "account_url", account_url)
+			}
+		}
+
+		// If still not allowed (not following, not mentioned) then check to see if the post
+		// (being received) is in reply to something that the account (being posted to) wrote
+
+		if !is_allowed {
+
+			if note.InReplyTo == "" {
+
+				if !is_following {
+					logger.Error("Not following")
+					http.Error(rsp, "Forbidden", http.StatusForbidden)
+					return
+				}
+
+			} else {
+
+				logger = logger.With("in reply to", note.InReplyTo)
+
+				// If we are not already following the author then
+
+				// Fetch the (in-reply-to) post in question and check to see if it
+				// was authored by acct
+
+				is_own_post := false
+
+				post, err := posts.GetPostFromObjectURI(ctx, opts.URIs, opts.PostsDatabase, note.InReplyTo)
+
+				if err != nil && err != activitypub.ErrNotFound {
+					logger.Error("Failed to determine if object URI references post", "error", err)
+					http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+					return
+				}
+
+				if post != nil {
+
+					logger = logger.With("post id", post.Id)
+
+					if post.AccountId == acct.Id {
+						is_own_post = true
+					}
+				}
+
+				if !is_own_post {
+					logger.Error("Reply-to is not own post")
+					http.Error(rsp, "Forbidden", http.StatusForbidden)
+					return
+				}
+			}
+		}
+
+		// Discard media attachments from domains whose media is rejected
+
+		if inbox_activity.DomainBlock != nil && inbox_activity.DomainBlock.RejectsMedia() {
+
+			enc_obj, err = removeMediaAttachments(enc_obj)
+
+			if err != nil {
+				logger.Error("Failed to remove media attachments", "error", err)
+				http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+		}
+
+		// First store the activity pub note as a local "note" - that is store
+		// the message from person (x) exactly once regardless of how many
+		// different accounts (on this service) that the note is being delivered
+		// to
+
+		note_uuid := note.Id
+		logger = logger.With("note uuid", note_uuid)
+
+		db_note, err := opts.NotesDatabase.GetNoteWithUUIDAndAuthorAddress(ctx, note_uuid, requestor_address)
+
+		switch {
+		case err == activitypub.ErrNotFound:
+			// pass
+		case err != nil:
+			logger.Error("Failed to retrive note", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		default:
+			// pass
+		}
+
+		if db_note != nil {
+
+			logger = logger.With("note id", db_note.Id)
+
+		} else {
+
+			new_note, err := notes.AddNote(ctx, opts.NotesDatabase, note_uuid, requestor_address, string(enc_obj))
+
+			if err != nil {
+				logger.Error("Failed to create new note", "error", err)
+				http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+
+			db_note = new_note
+			logger = logger.With("note id", db_note.Id)
+		}
+
+		// Now store a "message" which is a pointer to the note associated with the account the
+		// note is being delivered to
+
+		db_message, err := messages.GetMessage(ctx, opts.MessagesDatabase, acct.Id, db_note.Id)
+
+		switch {
+		case err == activitypub.ErrNotFound:
+			// pass
+		case err != nil:
+			logger.Error("Failed to retrive message", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		default:
+			// pass
+		}
+
+		if db_message != nil {
+
+			logger = logger.With("message id", db_message.Id)
+
+			db_message, err = messages.UpdateMessage(ctx, opts.MessagesDatabase, db_message)
+
+			if err != nil {
+				logger.Error("Failed to update message", "error", err)
+				http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+
+		} else {
+
+			new_message, err := messages.AddMessage(ctx, opts.MessagesDatabase, acct.Id, db_note.Id, requestor_address)
+
+			if err != nil {
+				logger.Error("Failed to add message", "error", err)
+				http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+
+			db_message = new_message
+			logger = logger.With("message id", db_message.Id)
+		}
+
+		logger.Info("Note has been added to messages")
+
+		wg.Add(1)
+
+		logger.Info("Schedule process message queue")
+
+		go func() {
+
+			defer wg.Done()
+
+			err := opts.ProcessMessageQueue.ProcessMessage(ctx, db_message.Id)
+
+			if err != nil {
+				logger.Error("Failed to process message with process queue", "error", err)
+				return
+			}
+
+			logger.Info("Delivered message to processing queue")
+		}()
+
+		logger.Debug("Inbox post complete", "status", http.StatusAccepted)
+		rsp.WriteHeader(http.StatusAccepted)
+
+		wg.Wait()
+		return
+	}
+
+	return http.HandlerFunc(fn), nil
+}
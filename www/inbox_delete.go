@@ -0,0 +1,259 @@
+package www
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/aaronland/go-http/v3/slog"
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/actors"
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/followers"
+	"github.com/sfomuseum/go-activitypub/following"
+	"github.com/sfomuseum/go-activitypub/notes"
+	"github.com/tidwall/gjson"
+)
+
+// InboxDeleteHandler returns a `http.Handler` for processing verified "Delete" activities posted to an account's inbox.
+// Deleting a note will remove the note and all the messages (for any account) pointing to it. Deleting an actor will remove
+// that actor from the followers and following lists of all accounts and from the local cache of remote actors. In both cases
+// the activity is only acted on if the owner of the key used to sign the request is also (one of) the author(s) of the object being deleted.
+// Actors delete themselves after their profile has been removed so if their host reports them as "410 Gone" the request is verified
+// using the cached copy of the actor, or silently accepted (and ignored) if there is no cached copy.
+func InboxDeleteHandler(opts *InboxPostHandlerOptions) (http.Handler, error) {
+
+	fn := func(rsp http.ResponseWriter, req *http.Request) {
+
+		ctx := req.Context()
+
+		inbox_activity, err := InboxActivityFromContext(ctx)
+
+		if err != nil {
+			logger := slog.LoggerWithRequest(req, nil)
+			logger.Error("Failed to derive inbox activity from request", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		logger := inboxActivityLogger(req, inbox_activity)
+
+		activity := inbox_activity.Activity
+		requestor_actor := inbox_activity.RequestorActor
+		requestor_address := inbox_activity.RequestorAddress
+
+		// There is a not insignificant number of people crawling ActivityPub
+		// endpoints issuing "Delete" activities just to see if they will stick
+		// so make sure the owner of the key that signed the request is the
+		// actor performing the activity.
+
+		err = checkInboxActivityActor(activity.Actor, requestor_actor)
+
+		if err != nil {
+			logger.Error("Signature owner does not match activity actor", "error", err)
+			http.Error(rsp, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		logger = logger.With("signature owner", requestor_actor.Id)
+
+		var object_id string
+		var object_authors []string
+		var is_attributed bool
+
+		switch activity.Object.(type) {
+		case string:
+			object_id = activity.Object.(string)
+		default:
+
+			enc_obj, err := json.Marshal(activity.Object)
+
+			if err != nil {
+				logger.Error("Failed to marshal activity object", "error", err)
+				http.Error(rsp, "Bad request", http.StatusBadRequest)
+				return
+			}
+
+			object_id = gjson.GetBytes(enc_obj, "id").String()
+
+			// Objects may be attributed to a list of actors (or objects) so the signer only needs to be one of them
+
+			is_attributed = gjson.GetBytes(enc_obj, "attributedTo").Exists()
+
+			if is_attributed {
+
+				object_authors, err = ap.ObjectAttributions(enc_obj)
+
+				if err != nil {
+					logger.Error("Failed to derive object attributions", "error", err)
+					http.Error(rsp, "Bad request", http.StatusBadRequest)
+					return
+				}
+			}
+		}
+
+		if object_id == "" {
+			logger.Error("Activity object is missing ID")
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		logger = logger.With("object id", object_id)
+
+		if is_attributed && !slices.Contains(object_authors, requestor_actor.Id) {
+			logger.Error("Signature owner is not one of the object authors", "authors", object_authors)
+			http.Error(rsp, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		// Actor deletes
+
+		if object_id == requestor_actor.Id {
+
+			err := removeActorRelationships(ctx, opts, requestor_address)
+
+			if err != nil {
+				logger.Error("Failed to remove followers and following for actor", "error", err)
+				http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+
+			if opts.ActorsDatabase != nil {
+
+				err = actors.RemoveActor(ctx, opts.ActorsDatabase, requestor_actor.Id)
+
+				if err != nil {
+					logger.Error("Failed to remove cached actor", "error", err)
+					http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+					return
+				}
+			}
+
+			logger.Info("Removed followers and following for deleted actor")
+			rsp.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		// Note deletes
+
+		db_note, err := opts.NotesDatabase.GetNoteWithUUIDAndAuthorAddress(ctx, object_id, requestor_address)
+
+		if err != nil {
+
+			if err == activitypub.ErrNotFound {
+				logger.Debug("Note not found, nothing to delete")
+				rsp.WriteHeader(http.StatusAccepted)
+				return
+			}
+
+			logger.Error("Failed to retrieve note", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		logger = logger.With("note id", db_note.Id)
+
+		var note *ap.Note
+
+		err = json.Unmarshal([]byte(db_note.Body), &note)
+
+		if err != nil {
+			logger.Error("Failed to unmarshal note body", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		if note.AttributedTo != requestor_actor.Id {
+			logger.Error("Signature owner does not match note author", "author", note.AttributedTo)
+			http.Error(rsp, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		err = notes.RemoveNote(ctx, opts.NotesDatabase, opts.MessagesDatabase, db_note)
+
+		if err != nil {
+			logger.Error("Failed to remove note", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		logger.Info("Removed note and messages")
+		rsp.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	return http.HandlerFunc(fn), nil
+}
+
+// isSelfDeleteActivity returns a boolean value indicating whether 'activity' is a "Delete" activity whose object is the
+// actor performing the activity.
+func isSelfDeleteActivity(activity *ap.Activity) bool {
+
+	if activity.Type != "Delete" || activity.Actor == "" {
+		return false
+	}
+
+	switch obj := activity.Object.(type) {
+	case string:
+		return obj == activity.Actor
+	case map[string]interface{}:
+		id, _ := obj["id"].(string)
+		return id == activity.Actor
+	default:
+		return false
+	}
+}
+
+// isGoneSelfDelete returns a boolean value indicating whether 'err' was triggered because the actor for 'activity' has been
+// deleted (HTTP 410 Gone) and 'activity' is that actor deleting itself.
+func isGoneSelfDelete(activity *ap.Activity, err error) bool {
+	return errors.Is(err, ap.ErrGone) && isSelfDeleteActivity(activity)
+}
+
+// removeActorRelationships removes 'actor_address' from the list of followers, and the list of accounts being
+// followed, for every account.
+func removeActorRelationships(ctx context.Context, opts *InboxPostHandlerOptions, actor_address string) error {
+
+	accounts_cb := func(ctx context.Context, acct *activitypub.Account) error {
+
+		is_follower, follower, err := followers.IsFollower(ctx, opts.FollowersDatabase, acct.Id, actor_address)
+
+		if err != nil {
+			return fmt.Errorf("Failed to determine if %s is a follower of %s, %w", actor_address, acct.Name, err)
+		}
+
+		if is_follower {
+
+			err := opts.FollowersDatabase.RemoveFollower(ctx, follower)
+
+			if err != nil {
+				return fmt.Errorf("Failed to remove %s as a follower of %s, %w", actor_address, acct.Name, err)
+			}
+		}
+
+		is_following, f, err := following.IsFollowing(ctx, opts.FollowingDatabase, acct.Id, actor_address)
+
+		if err != nil {
+			return fmt.Errorf("Failed to determine if %s is following %s, %w", acct.Name, actor_address, err)
+		}
+
+		// Following records are removed whatever their state, including pending
+		// and rejected follow requests, since the actor no longer exists.
+
+		if is_following {
+
+			err := opts.FollowingDatabase.RemoveFollowing(ctx, f)
+
+			if err != nil {
+				return fmt.Errorf("Failed to remove %s from accounts %s is following, %w", actor_address, acct.Name, err)
+			}
+		}
+
+		return nil
+	}
+
+	return opts.AccountsDatabase.GetAccounts(ctx, accounts_cb)
+}
@@ -0,0 +1,86 @@
+package www
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/uris"
+)
+
+func TestInboxDeleteHandler(t *testing.T) {
+
+	uris_table := uris.DefaultURIs()
+	uris_table.Hostname = "example.com"
+
+	opts := &InboxPostHandlerOptions{
+		NotesDatabase: &testNotesDatabase{},
+		URIs:          uris_table,
+	}
+
+	h, err := InboxDeleteHandler(opts)
+
+	if err != nil {
+		t.Fatalf("Failed to create delete handler, %v", err)
+	}
+
+	acct := &activitypub.Account{
+		Id:   1234,
+		Name: "alice",
+	}
+
+	bob := &ap.Actor{
+		Id:                "https://video.example/accounts/bob",
+		PreferredUsername: "bob",
+	}
+
+	tests := []struct {
+		Description string
+		Actor       string
+		Object      interface{}
+		Status      int
+	}{
+		{"object uri", bob.Id, "https://video.example/videos/1", http.StatusAccepted},
+		{"single attribution", bob.Id, map[string]interface{}{"id": "https://video.example/videos/1", "type": "Video", "attributedTo": bob.Id}, http.StatusAccepted},
+		{"attribution list", bob.Id, map[string]interface{}{
+			"id":   "https://video.example/videos/1",
+			"type": "Video",
+			"attributedTo": []interface{}{
+				map[string]interface{}{"type": "Person", "id": bob.Id},
+				map[string]interface{}{"type": "Group", "id": "https://video.example/video-channels/bob_channel"},
+			},
+		}, http.StatusAccepted},
+		{"address actor", "@bob@video.example", "https://video.example/videos/1", http.StatusAccepted},
+		{"other author", bob.Id, map[string]interface{}{"id": "https://video.example/videos/1", "type": "Video", "attributedTo": []interface{}{"https://video.example/accounts/carol"}}, http.StatusForbidden},
+		{"other actor", "https://video.example/accounts/carol", "https://video.example/videos/1", http.StatusForbidden},
+	}
+
+	for _, test := range tests {
+
+		activity := &ap.Activity{
+			Id:     "https://video.example/delete/1",
+			Type:   "Delete",
+			Actor:  test.Actor,
+			Object: test.Object,
+		}
+
+		inbox_activity := &InboxActivity{
+			Activity:         activity,
+			Account:          acct,
+			RequestorActor:   bob,
+			RequestorAddress: "bob@video.example",
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/ap/alice/inbox", nil)
+		req = req.WithContext(ContextWithInboxActivity(req.Context(), inbox_activity))
+
+		rsp := httptest.NewRecorder()
+		h.ServeHTTP(rsp, req)
+
+		if rsp.Code != test.Status {
+			t.Fatalf("Unexpected status for %s, expected %d but got %d", test.Description, test.Status, rsp.Code)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package www
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/blocks"
+)
+
+// checkInboxDomainBlock returns the server-wide domain block, if any, that applies to the host of 'address' (the actor
+// performing an activity). If the host is suspended an error is returned along with the HTTP status code to return.
+// If no domain block applies then nil is returned.
+func checkInboxDomainBlock(ctx context.Context, opts *InboxPostHandlerOptions, address string) (*activitypub.DomainBlock, int, error) {
+
+	host, err := blocks.HostFromAddress(address)
+
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("Failed to derive host for %s, %w", address, err)
+	}
+
+	b, err := blocks.GetDomainBlockForHost(ctx, opts.DomainBlocksDatabase, host)
+
+	if err == activitypub.ErrNotFound {
+		return nil, 0, nil
+	}
+
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("Failed to determine if host is blocked, %w", err)
+	}
+
+	if b.IsSuspended() {
+		return b, http.StatusForbidden, fmt.Errorf("Host %s is suspended (blocked domain %s)", host, b.Domain)
+	}
+
+	return b, 0, nil
+}
+
+// removeMediaAttachments returns a copy of the JSON-encoded object 'enc_obj' with its "attachment" property removed.
+func removeMediaAttachments(enc_obj []byte) ([]byte, error) {
+
+	var obj map[string]interface{}
+
+	err := json.Unmarshal(enc_obj, &obj)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal object, %w", err)
+	}
+
+	_, exists := obj["attachment"]
+
+	if !exists {
+		return enc_obj, nil
+	}
+
+	delete(obj, "attachment")
+
+	return json.Marshal(obj)
+}
@@ -0,0 +1,182 @@
+package www
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/aaronland/go-http/v3/slog"
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/posts"
+	"github.com/sfomuseum/go-activitypub/uris"
+)
+
+// InboxFlagHandler returns a `http.Handler` for processing verified "Flag" (report) activities posted to an account's inbox.
+// Reports are only recorded if the account, or one of its posts, is included in the activity's objects. Reports from hosts
+// whose domain block rejects reports are ignored.
+func InboxFlagHandler(opts *InboxPostHandlerOptions) (http.Handler, error) {
+
+	if opts.ReportsDatabase == nil {
+		return nil, fmt.Errorf("Missing reports database")
+	}
+
+	fn := func(rsp http.ResponseWriter, req *http.Request) {
+
+		ctx := req.Context()
+
+		inbox_activity, err := InboxActivityFromContext(ctx)
+
+		if err != nil {
+			logger := slog.LoggerWithRequest(req, nil)
+			logger.Error("Failed to derive inbox activity from request", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		logger := inboxActivityLogger(req, inbox_activity)
+
+		acct := inbox_activity.Account
+		activity := inbox_activity.Activity
+
+		if inbox_activity.DomainBlock != nil && inbox_activity.DomainBlock.RejectReports {
+			logger.Info("Ignoring report from domain that rejects reports", "domain", inbox_activity.DomainBlock.Domain)
+			rsp.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		object_uris, err := flagObjectURIs(activity.Object)
+
+		if err != nil {
+			logger.Error("Invalid flag activity object", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		is_reported := false
+		post_ids := make([]int64, 0)
+
+		for _, uri := range object_uris {
+
+			account_name, ok := sharedInboxAccountName(opts.URIs, uri)
+
+			if !ok || account_name != acct.Name {
+				continue
+			}
+
+			is_reported = true
+
+			if isAccountURI(opts.URIs, uri) {
+				continue
+			}
+
+			post, err := posts.GetPostFromObjectURI(ctx, opts.URIs, opts.PostsDatabase, uri)
+
+			if err != nil {
+
+				if err == activitypub.ErrNotFound {
+					logger.Warn("Reported post not found", "object uri", uri)
+					continue
+				}
+
+				logger.Error("Failed to derive post from object URI", "object uri", uri, "error", err)
+				http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+
+			if post.AccountId != acct.Id {
+				logger.Warn("Reported post belongs to a different account", "object uri", uri, "post account", post.AccountId)
+				continue
+			}
+
+			post_ids = append(post_ids, post.Id)
+		}
+
+		if !is_reported {
+			logger.Debug("Flag activity does not reference account, skipping")
+			rsp.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		report, err := activitypub.NewReport(ctx, acct.Id, activity.Actor, activity.Id)
+
+		if err != nil {
+			logger.Error("Failed to create new report", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		report.PostIds = post_ids
+		report.Comment = activity.Content
+
+		err = opts.ReportsDatabase.AddReport(ctx, report)
+
+		if err != nil {
+			logger.Error("Failed to add report", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		logger.Info("Received report", "report id", report.Id, "reporter", activity.Actor, "posts", len(post_ids))
+
+		logger.Debug("Inbox post complete", "status", http.StatusAccepted)
+		rsp.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	return http.HandlerFunc(fn), nil
+}
+
+// flagObjectURIs returns the list of URIs for the objects being flagged by a "Flag" activity. The object of a "Flag" activity
+// may be a single URI or a list of URIs or objects with an "id" property.
+func flagObjectURIs(object interface{}) ([]string, error) {
+
+	object_uris := make([]string, 0)
+
+	var candidates []interface{}
+
+	switch v := object.(type) {
+	case []interface{}:
+		candidates = v
+	default:
+		candidates = []interface{}{v}
+	}
+
+	for _, c := range candidates {
+
+		switch v := c.(type) {
+		case string:
+			object_uris = append(object_uris, v)
+		case map[string]interface{}:
+
+			id, ok := v["id"].(string)
+
+			if !ok {
+				return nil, fmt.Errorf("Object is missing 'id' property")
+			}
+
+			object_uris = append(object_uris, id)
+
+		default:
+			return nil, fmt.Errorf("Invalid or unsupported object type, %T", c)
+		}
+	}
+
+	if len(object_uris) == 0 {
+		return nil, fmt.Errorf("Activity has no objects")
+	}
+
+	return object_uris, nil
+}
+
+// isAccountURI returns a boolean value indicating whether 'uri' is the URI of a local account (rather than one of its posts).
+func isAccountURI(uris_table *uris.URIs, uri string) bool {
+
+	u, err := url.Parse(uri)
+
+	if err != nil {
+		return false
+	}
+
+	_, ok := uris.ResourceFromPath(uris_table.Account, u.Path)
+	return ok
+}
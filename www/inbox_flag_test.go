@@ -0,0 +1,89 @@
+package www
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/uris"
+)
+
+func TestInboxFlagHandler(t *testing.T) {
+
+	uris_table := uris.DefaultURIs()
+	uris_table.Hostname = "example.com"
+
+	reports_db := &testReportsDatabase{
+		reports: make([]*activitypub.Report, 0),
+	}
+
+	opts := &InboxPostHandlerOptions{
+		ReportsDatabase: reports_db,
+		URIs:            uris_table,
+	}
+
+	h, err := InboxFlagHandler(opts)
+
+	if err != nil {
+		t.Fatalf("Failed to create flag handler, %v", err)
+	}
+
+	acct := &activitypub.Account{
+		Id:   1234,
+		Name: "alice",
+	}
+
+	tests := []struct {
+		Description string
+		Object      interface{}
+		DomainBlock *activitypub.DomainBlock
+		Status      int
+		Reports     int
+	}{
+		{"single account", "https://example.com/ap/alice", nil, http.StatusAccepted, 1},
+		{"list of accounts", []interface{}{"https://example.com/ap/alice", "https://example.com/ap/bob"}, nil, http.StatusAccepted, 2},
+		{"other account", []interface{}{"https://example.com/ap/bob"}, nil, http.StatusAccepted, 2},
+		{"rejected reports", "https://example.com/ap/alice", &activitypub.DomainBlock{Domain: "remote.social", RejectReports: true}, http.StatusAccepted, 2},
+		{"invalid object", 42, nil, http.StatusBadRequest, 2},
+	}
+
+	for _, test := range tests {
+
+		activity := &ap.Activity{
+			Id:      "https://remote.social/flags/1",
+			Type:    "Flag",
+			Actor:   "https://remote.social/actor",
+			Object:  test.Object,
+			Content: "Spam",
+		}
+
+		inbox_activity := &InboxActivity{
+			Activity:         activity,
+			Account:          acct,
+			RequestorAddress: "remote.social@remote.social",
+			DomainBlock:      test.DomainBlock,
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/ap/alice/inbox", nil)
+		req = req.WithContext(ContextWithInboxActivity(req.Context(), inbox_activity))
+
+		rsp := httptest.NewRecorder()
+		h.ServeHTTP(rsp, req)
+
+		if rsp.Code != test.Status {
+			t.Fatalf("Unexpected status for %s, expected %d but got %d", test.Description, test.Status, rsp.Code)
+		}
+
+		if len(reports_db.reports) != test.Reports {
+			t.Fatalf("Unexpected number of reports after %s, expected %d but got %d", test.Description, test.Reports, len(reports_db.reports))
+		}
+	}
+
+	r := reports_db.reports[0]
+
+	if r.AccountId != acct.Id || r.Comment != "Spam" || r.ReporterActor != "https://remote.social/actor" {
+		t.Fatalf("Unexpected report properties, %v", r)
+	}
+}
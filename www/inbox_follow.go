@@ -0,0 +1,139 @@
+package www
+
+import (
+	"net/http"
+
+	"github.com/aaronland/go-http/v3/slog"
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/followers"
+)
+
+// InboxFollowHandler returns a `http.Handler` for processing verified "Follow" activities posted to an account's inbox.
+func InboxFollowHandler(opts *InboxPostHandlerOptions) (http.Handler, error) {
+
+	fn := func(rsp http.ResponseWriter, req *http.Request) {
+
+		ctx := req.Context()
+
+		inbox_activity, err := InboxActivityFromContext(ctx)
+
+		if err != nil {
+			logger := slog.LoggerWithRequest(req, nil)
+			logger.Error("Failed to derive inbox activity from request", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		logger := inboxActivityLogger(req, inbox_activity)
+
+		acct := inbox_activity.Account
+		activity := inbox_activity.Activity
+		requestor_actor := inbox_activity.RequestorActor
+		requestor_address := inbox_activity.RequestorAddress
+
+		is_following, _, err := followers.IsFollower(ctx, opts.FollowersDatabase, acct.Id, requestor_address)
+
+		if err != nil {
+			logger.Error("Failed to determine if following", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		if is_following {
+			logger.Error("Already following")
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		// Accounts that manually approve followers record the follow activity as a pending
+		// request and wait for the account owner to approve (or reject) it. The Accept (or
+		// Reject) activity is sent at that point rather than here. Follow activities from
+		// silenced domains always require manual approval.
+
+		is_silenced := inbox_activity.DomainBlock != nil && inbox_activity.DomainBlock.IsSilenced()
+
+		if acct.ManuallyApproveFollowers || is_silenced {
+
+			r, err := followers.AddFollowRequest(ctx, opts.FollowRequestsDatabase, acct.Id, requestor_address, activity)
+
+			if err != nil {
+				logger.Error("Failed to add follow request", "error", err)
+				http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+
+			logger.Info("Follow request is pending approval", "follow request", r.Id)
+			rsp.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		follower_id, err := followers.AddFollower(ctx, opts.FollowersDatabase, acct.Id, requestor_address)
+
+		if err != nil {
+			logger.Error("Failed to create new follower", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		logger = logger.With("follower id", follower_id)
+
+		logger.Debug("Inbox post complete", "status", http.StatusAccepted)
+		rsp.WriteHeader(http.StatusAccepted)
+
+		// It is unclear whether it is really necessary to send this request after the HTTP 202
+		// response has been sent (or whether it can be sent inline before). On the other there
+		// are accept activities which are specifically meant to happen "out-of-band", like follower
+		// requests that are manually approved, so the easiest way to think about things is that
+		// they will (maybe?) get moved in to its own delivery queue (distinct from posts) to happen
+		// after the inbox handler has completed. Basically: Treat every message sent to the
+		// ActivityPub inbox as an offline task. I am still trying to determine if that's an accurate
+		// assumption.
+
+		accept_actor := acct.AccountURL(ctx, opts.URIs).String()
+		logger.Debug("Send Accept activity", "actor", accept_actor)
+
+		accept, err := ap.NewAcceptActivity(ctx, opts.URIs, accept_actor, activity)
+
+		if err != nil {
+			logger.Error("Failed to create new accept activity", "error", err)
+			return
+		}
+
+		logger = logger.With("accept", accept.Id)
+
+		err = acct.SendActivity(ctx, opts.URIs, requestor_actor.Inbox, accept)
+
+		if err != nil {
+
+			logger.Error("Failed to post accept activity to requestor, remove follower", "to", requestor_actor.Inbox, "error", err)
+
+			f, err := followers.GetFollower(ctx, opts.FollowersDatabase, acct.Id, requestor_address)
+
+			if err != nil {
+				logger.Error("Failed to retrieve newly created follower to remove", "error", err)
+			} else {
+
+				err = opts.FollowersDatabase.RemoveFollower(ctx, f)
+
+				if err != nil {
+					logger.Error("Failed to remove follower", "id", f.Id, "error", err)
+				}
+			}
+
+			// Note: We are not returning a HTTP response since we have already returned HTTP 202 above
+			return
+		}
+
+		// Schedule any custom post-processing for the follow event
+
+		err = opts.ProcessFollowerQueue.ProcessFollower(ctx, follower_id)
+
+		if err != nil {
+			logger.Error("Failed to queue process follower job", "error", err)
+		}
+
+		return
+	}
+
+	return http.HandlerFunc(fn), nil
+}
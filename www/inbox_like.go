@@ -0,0 +1,134 @@
+package www
+
+import (
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aaronland/go-http/v3/slog"
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/ap"
+)
+
+// The maximum length, in characters, of the emoji (or custom emoji shortcode) for emoji reactions.
+const MAX_EMOJI_LENGTH int = 64
+
+// likeActivityEmoji returns the emoji, and the image URL for custom emoji, for emoji reactions. Emoji reactions are
+// either "EmojiReact" activities or "Like" activities with a "content" property (as sent by Misskey, Pleroma and Akkoma).
+// The image URL for a custom emoji (for example ":blobcat:") is derived from the "Emoji" tag whose name matches the content.
+// If 'activity' is a plain like, or its content is longer than `MAX_EMOJI_LENGTH`, then both values will be empty.
+func likeActivityEmoji(activity *ap.Activity) (string, string) {
+
+	emoji := strings.TrimSpace(activity.Content)
+
+	if emoji == "" || utf8.RuneCountInString(emoji) > MAX_EMOJI_LENGTH {
+		return "", ""
+	}
+
+	emoji_url := ""
+
+	for _, t := range activity.Tags {
+
+		if t == nil || t.Type != "Emoji" || t.Icon == nil {
+			continue
+		}
+
+		if strings.Trim(t.Name, ":") != strings.Trim(emoji, ":") {
+			continue
+		}
+
+		emoji_url = t.Icon.URL
+		break
+	}
+
+	return emoji, emoji_url
+}
+
+// InboxLikeHandler returns a `http.Handler` for processing verified "Like" and "EmojiReact" activities posted to an account's inbox.
+// Like "Announce" activities the object of the activity may be the URI of the post being liked or an embedded copy of the post.
+func InboxLikeHandler(opts *InboxPostHandlerOptions) (http.Handler, error) {
+
+	fn := func(rsp http.ResponseWriter, req *http.Request) {
+
+		ctx := req.Context()
+
+		inbox_activity, err := InboxActivityFromContext(ctx)
+
+		if err != nil {
+			logger := slog.LoggerWithRequest(req, nil)
+			logger.Error("Failed to derive inbox activity from request", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		logger := inboxActivityLogger(req, inbox_activity)
+
+		acct := inbox_activity.Account
+		activity := inbox_activity.Activity
+
+		post, status, err := announceObjectPost(ctx, opts, activity.Object)
+
+		if err != nil {
+			logger.Error("Failed to derive post from like object", "error", err)
+			http.Error(rsp, http.StatusText(status), status)
+			return
+		}
+
+		logger = logger.With("post id", post.Id)
+
+		if post.AccountId != acct.Id {
+			logger.Error("Trying to act on post for different account", "post account", post.AccountId)
+			http.Error(rsp, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		emoji, emoji_url := likeActivityEmoji(activity)
+
+		if activity.Type == "EmojiReact" && emoji == "" {
+			logger.Error("Emoji reaction is missing content or its content is too long")
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		if emoji != "" {
+			logger = logger.With("emoji", emoji)
+		}
+
+		like, err := opts.LikesDatabase.GetLikeWithPostIdActorAndEmoji(ctx, post.Id, activity.Actor, emoji)
+
+		if err != nil && err != activitypub.ErrNotFound {
+			logger.Error("Failed to derive like from post and actor", "post id", post.Id, "actor", activity.Actor, "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		if like == nil {
+
+			like, err = activitypub.NewEmojiReaction(ctx, post, activity.Actor, emoji, emoji_url)
+
+			if err != nil {
+				logger.Error("Failed to create new like for post and actor", "post id", post.Id, "actor", activity.Actor, "error", err)
+				http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+
+			err = opts.LikesDatabase.AddLike(ctx, like)
+
+			if err != nil {
+				logger.Error("Failed to add new like for post and actor", "post id", post.Id, "actor", activity.Actor, "error", err)
+				http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+
+			logger.Info("Create new like", "post id", post.Id, "actor", activity.Actor, "like", like.Id)
+		}
+
+		// Do we need to send an accept here? Apparently not...
+
+		logger.Debug("Inbox post complete", "status", http.StatusAccepted)
+		rsp.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	return http.HandlerFunc(fn), nil
+}
@@ -0,0 +1,45 @@
+package www
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sfomuseum/go-activitypub/ap"
+)
+
+func TestLikeActivityEmoji(t *testing.T) {
+
+	tests := []struct {
+		Description string
+		Activity    *ap.Activity
+		Emoji       string
+		EmojiURL    string
+	}{
+		{"plain like", &ap.Activity{Type: "Like"}, "", ""},
+		{"misskey like", &ap.Activity{Type: "Like", Content: "👍"}, "👍", ""},
+		{"emoji react", &ap.Activity{Type: "EmojiReact", Content: " 🎉 "}, "🎉", ""},
+		{"custom emoji", &ap.Activity{
+			Type:    "EmojiReact",
+			Content: ":blobcat:",
+			Tags: []*ap.Tag{
+				&ap.Tag{Type: "Hashtag", Name: "#blobcat"},
+				&ap.Tag{Type: "Emoji", Name: "blobcat", Icon: &ap.Icon{Type: "Image", URL: "https://remote.social/emoji/blobcat.png"}},
+			},
+		}, ":blobcat:", "https://remote.social/emoji/blobcat.png"},
+		{"custom emoji without tag", &ap.Activity{Type: "Like", Content: ":blobcat:"}, ":blobcat:", ""},
+		{"too long", &ap.Activity{Type: "Like", Content: strings.Repeat("👍", MAX_EMOJI_LENGTH+1)}, "", ""},
+	}
+
+	for _, test := range tests {
+
+		emoji, emoji_url := likeActivityEmoji(test.Activity)
+
+		if emoji != test.Emoji {
+			t.Fatalf("Unexpected emoji for %s, expected '%s' but got '%s'", test.Description, test.Emoji, emoji)
+		}
+
+		if emoji_url != test.EmojiURL {
+			t.Fatalf("Unexpected emoji URL for %s, expected '%s' but got '%s'", test.Description, test.EmojiURL, emoji_url)
+		}
+	}
+}
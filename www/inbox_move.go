@@ -0,0 +1,331 @@
+package www
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	aa_slog "github.com/aaronland/go-http/v3/slog"
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/actors"
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/followers"
+	"github.com/sfomuseum/go-activitypub/following"
+	"github.com/sfomuseum/go-activitypub/queue"
+)
+
+// InboxMoveHandler returns a `http.Handler` for processing verified "Move" activities posted to an account's inbox. "Move" activities
+// are sent when a remote actor migrates to a new account; the activity's object is the old actor and its target is the new actor. The
+// new actor must list the old actor in its "alsoKnownAs" property. If the account is following the old actor its following record is
+// rewritten to the new actor's address and a new "Follow" activity is scheduled for delivery to the new actor (using the delivery queue).
+// If the old actor is following the account its follower record is replaced with one for the new actor's address.
+func InboxMoveHandler(opts *InboxPostHandlerOptions) (http.Handler, error) {
+
+	fn := func(rsp http.ResponseWriter, req *http.Request) {
+
+		ctx := req.Context()
+
+		inbox_activity, err := InboxActivityFromContext(ctx)
+
+		if err != nil {
+			logger := aa_slog.LoggerWithRequest(req, nil)
+			logger.Error("Failed to derive inbox activity from request", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		logger := inboxActivityLogger(req, inbox_activity)
+
+		acct := inbox_activity.Account
+		activity := inbox_activity.Activity
+		requestor_actor := inbox_activity.RequestorActor
+		requestor_address := inbox_activity.RequestorAddress
+
+		err = checkInboxActivityActor(activity.Actor, requestor_actor)
+
+		if err != nil {
+			logger.Error("Signature owner does not match activity actor", "error", err)
+			http.Error(rsp, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		object_uri, ok := moveActivityURI(activity.Object)
+
+		if !ok || object_uri != requestor_actor.Id {
+			logger.Error("Move activity object does not match activity actor", "object", object_uri)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		target_uri, ok := moveActivityURI(activity.Target)
+
+		if !ok {
+			logger.Error("Move activity is missing target")
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		logger = logger.With("target", target_uri)
+
+		target_u, err := url.Parse(target_uri)
+
+		if err != nil {
+			logger.Error("Failed to parse move target", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		// Always retrieve the target actor from its remote host since a cached copy may
+		// predate the alias (alsoKnownAs) being added
+
+		actor_opts := inboxRetrieveActorOptions(opts)
+		actor_opts.Refresh = true
+
+		target_actor, err := actors.RetrieveActorWithProfileURL(ctx, actor_opts, target_uri)
+
+		if err != nil {
+			logger.Error("Failed to retrieve move target actor", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		err = verifyMoveTarget(activity, target_actor)
+
+		if err != nil {
+			logger.Error("Failed to verify move target", "error", err)
+			http.Error(rsp, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		target_address := fmt.Sprintf("%s@%s", target_actor.PreferredUsername, target_u.Host)
+		logger = logger.With("target address", target_address)
+
+		err = moveFollowing(ctx, opts, acct, requestor_address, target_address, logger)
+
+		if err != nil {
+			logger.Error("Failed to move following", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		err = moveFollower(ctx, opts, acct, requestor_address, target_address, logger)
+
+		if err != nil {
+			logger.Error("Failed to move follower", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		logger.Info("Processed move activity")
+		rsp.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	return http.HandlerFunc(fn), nil
+}
+
+// moveActivityURI returns the URI for the object or target of a "Move" activity which may be a string
+// or an embedded object with an "id" property.
+func moveActivityURI(v interface{}) (string, bool) {
+
+	switch v.(type) {
+	case string:
+		uri := v.(string)
+		return uri, uri != ""
+	case map[string]interface{}:
+		uri, ok := v.(map[string]interface{})["id"].(string)
+		return uri, ok && uri != ""
+	default:
+		return "", false
+	}
+}
+
+// verifyMoveTarget ensures that 'target_actor' is the target of 'activity' and that it lists the actor
+// being moved (the object of 'activity') in its "alsoKnownAs" property.
+func verifyMoveTarget(activity *ap.Activity, target_actor *ap.Actor) error {
+
+	object_uri, ok := moveActivityURI(activity.Object)
+
+	if !ok {
+		return fmt.Errorf("Move activity is missing object")
+	}
+
+	target_uri, ok := moveActivityURI(activity.Target)
+
+	if !ok {
+		return fmt.Errorf("Move activity is missing target")
+	}
+
+	if target_actor.Id != target_uri {
+		return fmt.Errorf("Target actor ID (%s) does not match move target", target_actor.Id)
+	}
+
+	if target_actor.Id == object_uri {
+		return fmt.Errorf("Move target is the same as the activity object")
+	}
+
+	if !slices.Contains(target_actor.AlsoKnownAs, object_uri) {
+		return fmt.Errorf("Target actor does not list %s as an alias", object_uri)
+	}
+
+	return nil
+}
+
+// moveFollowing rewrites the record of 'acct' following 'old_address', if present and not rejected, to 'new_address' and schedules
+// a new "Follow" activity for delivery to 'new_address' using 'opts.DeliveryQueue'. If 'acct' is already following (or has a pending
+// request to follow) 'new_address' the record for 'old_address' is removed.
+func moveFollowing(ctx context.Context, opts *InboxPostHandlerOptions, acct *activitypub.Account, old_address string, new_address string, logger *slog.Logger) error {
+
+	logger = logger.With("following", old_address)
+
+	is_following, f, err := following.IsFollowing(ctx, opts.FollowingDatabase, acct.Id, old_address)
+
+	if err != nil {
+		return fmt.Errorf("Failed to determine if following %s, %w", old_address, err)
+	}
+
+	if !is_following {
+		return nil
+	}
+
+	logger = logger.With("following id", f.Id)
+
+	// A rejected follow request is not carried over to the new account
+
+	if f.State == activitypub.RejectedFollowingState {
+		logger.Info("Follow request was rejected, not moving following")
+		return nil
+	}
+
+	if opts.DeliveryQueue == nil || opts.DeliveriesDatabase == nil {
+		return fmt.Errorf("Unable to deliver follow activity, missing delivery queue or deliveries database")
+	}
+
+	is_following_new, f_new, err := following.IsFollowing(ctx, opts.FollowingDatabase, acct.Id, new_address)
+
+	if err != nil {
+		return fmt.Errorf("Failed to determine if following %s, %w", new_address, err)
+	}
+
+	if is_following_new && f_new.State != activitypub.RejectedFollowingState {
+
+		err := opts.FollowingDatabase.RemoveFollowing(ctx, f)
+
+		if err != nil {
+			return fmt.Errorf("Failed to remove following for %s, %w", old_address, err)
+		}
+
+		logger.Info("Already following move target, removed following")
+		return nil
+	}
+
+	// A previously rejected request to follow the move target is replaced by the moved record
+
+	if is_following_new {
+
+		err := opts.FollowingDatabase.RemoveFollowing(ctx, f_new)
+
+		if err != nil {
+			return fmt.Errorf("Failed to remove rejected following for %s, %w", new_address, err)
+		}
+	}
+
+	follow_address := acct.Address(opts.URIs.Hostname)
+
+	follow, err := ap.NewFollowActivity(ctx, opts.URIs, follow_address, new_address)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create follow activity, %w", err)
+	}
+
+	// Update the following record, in a pending state, before the follow activity is
+	// delivered since the remote server may respond with an "Accept" activity before
+	// delivery completes (see app/follow).
+
+	f.FollowingAddress = new_address
+	f.ActivityId = follow.Id
+	f.State = activitypub.PendingFollowingState
+	f.LastModified = time.Now().Unix()
+
+	err = opts.FollowingDatabase.UpdateFollowing(ctx, f)
+
+	if err != nil {
+		return fmt.Errorf("Failed to update following, %w", err)
+	}
+
+	logger.Info("Moved following", "address", new_address)
+
+	activity, err := activitypub.NewActivity(ctx, follow)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create new AP wrapper, %w", err)
+	}
+
+	activity.AccountId = acct.Id
+
+	deliver_opts := &queue.DeliverActivityToRecipientsOptions{
+		AccountsDatabase:     opts.AccountsDatabase,
+		DeliveriesDatabase:   opts.DeliveriesDatabase,
+		DeliveryQueue:        opts.DeliveryQueue,
+		Activity:             activity,
+		Recipients:           []string{new_address},
+		MaxAttempts:          opts.MaxAttempts,
+		URIs:                 opts.URIs,
+		ActorsDatabase:       opts.ActorsDatabase,
+		ActorsTTL:            opts.ActorsTTL,
+		DomainBlocksDatabase: opts.DomainBlocksDatabase,
+		DomainAllowsDatabase: opts.DomainAllowsDatabase,
+	}
+
+	err = queue.DeliverActivityToRecipients(ctx, deliver_opts)
+
+	if err != nil {
+		return fmt.Errorf("Failed to deliver follow activity to %s, %w", new_address, err)
+	}
+
+	return nil
+}
+
+// moveFollower replaces the record of 'old_address' following 'acct', if present, with one for 'new_address'.
+func moveFollower(ctx context.Context, opts *InboxPostHandlerOptions, acct *activitypub.Account, old_address string, new_address string, logger *slog.Logger) error {
+
+	logger = logger.With("follower", old_address)
+
+	is_follower, f, err := followers.IsFollower(ctx, opts.FollowersDatabase, acct.Id, old_address)
+
+	if err != nil {
+		return fmt.Errorf("Failed to determine if %s is following, %w", old_address, err)
+	}
+
+	if !is_follower {
+		return nil
+	}
+
+	is_follower_new, _, err := followers.IsFollower(ctx, opts.FollowersDatabase, acct.Id, new_address)
+
+	if err != nil {
+		return fmt.Errorf("Failed to determine if %s is following, %w", new_address, err)
+	}
+
+	if !is_follower_new {
+
+		_, err := followers.AddFollower(ctx, opts.FollowersDatabase, acct.Id, new_address)
+
+		if err != nil {
+			return fmt.Errorf("Failed to add follower for %s, %w", new_address, err)
+		}
+	}
+
+	err = opts.FollowersDatabase.RemoveFollower(ctx, f)
+
+	if err != nil {
+		return fmt.Errorf("Failed to remove follower for %s, %w", old_address, err)
+	}
+
+	logger.Info("Moved follower", "address", new_address)
+	return nil
+}
@@ -0,0 +1,166 @@
+package www
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/database"
+	"github.com/sfomuseum/go-activitypub/uris"
+)
+
+func TestVerifyMoveTarget(t *testing.T) {
+
+	activity := &ap.Activity{
+		Type:   "Move",
+		Actor:  "https://old.social/users/bob",
+		Object: "https://old.social/users/bob",
+		Target: "https://new.social/users/bob",
+	}
+
+	tests := []struct {
+		Description string
+		Actor       *ap.Actor
+		Ok          bool
+	}{
+		{"valid alias", &ap.Actor{Id: "https://new.social/users/bob", AlsoKnownAs: []string{"https://old.social/users/bob"}}, true},
+		{"missing alias", &ap.Actor{Id: "https://new.social/users/bob"}, false},
+		{"different alias", &ap.Actor{Id: "https://new.social/users/bob", AlsoKnownAs: []string{"https://other.social/users/bob"}}, false},
+		{"target mismatch", &ap.Actor{Id: "https://evil.social/users/bob", AlsoKnownAs: []string{"https://old.social/users/bob"}}, false},
+	}
+
+	for _, test := range tests {
+
+		err := verifyMoveTarget(activity, test.Actor)
+
+		if test.Ok && err != nil {
+			t.Fatalf("Expected %s to verify, %v", test.Description, err)
+		}
+
+		if !test.Ok && err == nil {
+			t.Fatalf("Expected %s to fail verification", test.Description)
+		}
+	}
+}
+
+func TestMoveFollower(t *testing.T) {
+
+	ctx := context.Background()
+
+	acct := &activitypub.Account{
+		Id:   1234,
+		Name: "alice",
+	}
+
+	followers_db := &testFollowersDatabase{
+		followers: map[string]*activitypub.Follower{
+			"bob@old.social": &activitypub.Follower{Id: 1, AccountId: acct.Id, FollowerAddress: "bob@old.social"},
+		},
+	}
+
+	opts := &InboxPostHandlerOptions{
+		FollowersDatabase: followers_db,
+	}
+
+	err := moveFollower(ctx, opts, acct, "bob@old.social", "bob@new.social", slog.Default())
+
+	if err != nil {
+		t.Fatalf("Failed to move follower, %v", err)
+	}
+
+	_, exists := followers_db.followers["bob@old.social"]
+
+	if exists {
+		t.Fatalf("Expected old follower to have been removed")
+	}
+
+	f, exists := followers_db.followers["bob@new.social"]
+
+	if !exists || f.AccountId != acct.Id {
+		t.Fatalf("Expected new follower to have been added")
+	}
+}
+
+func TestMoveFollowing(t *testing.T) {
+
+	ctx := context.Background()
+
+	uris_table := uris.DefaultURIs()
+	uris_table.Hostname = "example.com"
+
+	acct := &activitypub.Account{
+		Id:   1234,
+		Name: "alice",
+	}
+
+	deliveries_db, err := database.NewDeliveriesDatabase(ctx, "null://")
+
+	if err != nil {
+		t.Fatalf("Failed to create deliveries database, %v", err)
+	}
+
+	tests := []struct {
+		Description string
+		Following   []*activitypub.Following
+		Address     string
+		State       activitypub.FollowingState
+		Delivered   int
+	}{
+		{"accepted", []*activitypub.Following{
+			&activitypub.Following{Id: 1, AccountId: acct.Id, FollowingAddress: "bob@old.social", State: activitypub.AcceptedFollowingState},
+		}, "bob@new.social", activitypub.PendingFollowingState, 1},
+		{"rejected", []*activitypub.Following{
+			&activitypub.Following{Id: 1, AccountId: acct.Id, FollowingAddress: "bob@old.social", State: activitypub.RejectedFollowingState},
+		}, "bob@old.social", activitypub.RejectedFollowingState, 0},
+		{"already following target", []*activitypub.Following{
+			&activitypub.Following{Id: 1, AccountId: acct.Id, FollowingAddress: "bob@old.social", State: activitypub.AcceptedFollowingState},
+			&activitypub.Following{Id: 2, AccountId: acct.Id, FollowingAddress: "bob@new.social", State: activitypub.AcceptedFollowingState},
+		}, "bob@new.social", activitypub.AcceptedFollowingState, 0},
+		{"target rejected", []*activitypub.Following{
+			&activitypub.Following{Id: 1, AccountId: acct.Id, FollowingAddress: "bob@old.social", State: activitypub.AcceptedFollowingState},
+			&activitypub.Following{Id: 2, AccountId: acct.Id, FollowingAddress: "bob@new.social", State: activitypub.RejectedFollowingState},
+		}, "bob@new.social", activitypub.PendingFollowingState, 1},
+	}
+
+	for _, test := range tests {
+
+		following_db := &testFollowingDatabase{
+			following: test.Following,
+		}
+
+		delivery_q := &testDeliveryQueue{}
+
+		opts := &InboxPostHandlerOptions{
+			FollowingDatabase:  following_db,
+			DeliveriesDatabase: deliveries_db,
+			DeliveryQueue:      delivery_q,
+			URIs:               uris_table,
+		}
+
+		err := moveFollowing(ctx, opts, acct, "bob@old.social", "bob@new.social", slog.Default())
+
+		if err != nil {
+			t.Fatalf("Failed to move following for %s, %v", test.Description, err)
+		}
+
+		if len(following_db.following) != 1 {
+			t.Fatalf("Expected a single following record for %s, got %d", test.Description, len(following_db.following))
+		}
+
+		f := following_db.following[0]
+
+		if f.FollowingAddress != test.Address || f.State != test.State {
+			t.Fatalf("Unexpected following record for %s, %s (%d)", test.Description, f.FollowingAddress, f.State)
+		}
+
+		if len(delivery_q.recipients) != test.Delivered {
+			t.Fatalf("Unexpected number of deliveries for %s, %d", test.Description, len(delivery_q.recipients))
+		}
+
+		if test.Delivered > 0 && delivery_q.recipients[0] != "bob@new.social" {
+			t.Fatalf("Unexpected delivery recipient for %s, %s", test.Description, delivery_q.recipients[0])
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package www
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/tidwall/gjson"
+)
+
+// checkInboxKeyOwner ensures that 'key_id', the key used to sign a request, is the public key published by 'actor', that the
+// owner of that key is 'actor' and that 'key_id' has the same origin as 'actor'. Actors are retrieved from either 'key_id' or the
+// activity's actor and `actors.RetrieveActorWithProfileURL` ensures that the actor's ID matches the URL it was retrieved from so,
+// taken together, this means that an actor document hosted on one server can not claim a key (or an identity) on another server.
+func checkInboxKeyOwner(key_id string, actor *ap.Actor) error {
+
+	if actor == nil {
+		return fmt.Errorf("Missing actor for key %s", key_id)
+	}
+
+	key_u, err := url.Parse(key_id)
+
+	if err != nil {
+		return fmt.Errorf("Failed to parse key ID, %w", err)
+	}
+
+	actor_u, err := url.Parse(actor.Id)
+
+	if err != nil {
+		return fmt.Errorf("Failed to parse actor URI, %w", err)
+	}
+
+	if key_u.Host == "" || key_u.Scheme != actor_u.Scheme || !strings.EqualFold(key_u.Host, actor_u.Host) {
+		return fmt.Errorf("Key %s does not have the same origin as actor %s", key_id, actor.Id)
+	}
+
+	if actor.PublicKey.Id != key_id {
+		return fmt.Errorf("Key %s is not the public key (%s) of actor %s", key_id, actor.PublicKey.Id, actor.Id)
+	}
+
+	if actor.PublicKey.Owner != "" && actor.PublicKey.Owner != actor.Id {
+		return fmt.Errorf("Key %s is owned by %s and not actor %s", key_id, actor.PublicKey.Owner, actor.Id)
+	}
+
+	return nil
+}
+
+// checkInboxActivityActor ensures that 'activity_actor', the actor performing an activity, is 'actor', the owner of the key used
+// to sign a request. If 'activity_actor' is a "@name@host" address then the name must match the actor's preferred username and
+// the host must match the host of the actor's URI.
+func checkInboxActivityActor(activity_actor string, actor *ap.Actor) error {
+
+	if actor == nil {
+		return fmt.Errorf("Missing signing actor")
+	}
+
+	if strings.HasPrefix(activity_actor, "http") {
+
+		if activity_actor != actor.Id {
+			return fmt.Errorf("Activity actor %s does not match signing actor %s", activity_actor, actor.Id)
+		}
+
+		return nil
+	}
+
+	actor_u, err := url.Parse(actor.Id)
+
+	if err != nil {
+		return fmt.Errorf("Failed to parse actor URI, %w", err)
+	}
+
+	name, host, err := ap.ParseAddress(activity_actor)
+
+	if err != nil {
+		return fmt.Errorf("Failed to parse activity actor, %w", err)
+	}
+
+	if name != actor.PreferredUsername || !strings.EqualFold(host, actor_u.Host) {
+		return fmt.Errorf("Activity actor %s does not match signing actor %s", activity_actor, actor.Id)
+	}
+
+	return nil
+}
+
+// checkInboxActivityOrigin ensures that 'actor', the owner of the key used to sign a request, is the actor performing 'activity'
+// and that any objects embedded in 'activity' which that actor is asserting authorship of have the same origin as the actor.
+// Specifically:
+//   - The activity's actor must be the same as 'actor' (see `checkInboxActivityActor`).
+//   - The activity's ID, if present, must be hosted on the same host as 'actor'. Activity IDs are used to detect duplicate
+//     (or replayed) activities so an actor must not be able to claim the ID of an activity published on another server.
+//   - Embedded objects in "Create", "Update" and "Delete" activities must be hosted on the same host as 'actor' and, if they
+//     are attributed to anyone, 'actor' must be one of the "Person" actors they are attributed to (see `ap.ObjectAttributions`).
+//   - Embedded activities in "Undo" activities must have been performed by 'actor'.
+//
+// Embedded objects in other activities (for example an "Announce" activity for a note authored by someone else) are not checked.
+func checkInboxActivityOrigin(activity *ap.Activity, actor *ap.Actor) error {
+
+	if actor == nil {
+		return fmt.Errorf("Missing signing actor")
+	}
+
+	err := checkInboxActivityActor(activity.Actor, actor)
+
+	if err != nil {
+		return err
+	}
+
+	actor_u, err := url.Parse(actor.Id)
+
+	if err != nil {
+		return fmt.Errorf("Failed to parse actor URI, %w", err)
+	}
+
+	if activity.Id != "" {
+
+		activity_u, err := url.Parse(activity.Id)
+
+		if err != nil {
+			return fmt.Errorf("Failed to parse activity URI, %w", err)
+		}
+
+		if !strings.EqualFold(activity_u.Host, actor_u.Host) {
+			return fmt.Errorf("Activity %s does not have the same origin as actor %s", activity.Id, actor.Id)
+		}
+	}
+
+	enc_obj, err := json.Marshal(activity.Object)
+
+	if err != nil {
+		return fmt.Errorf("Failed to marshal activity object, %w", err)
+	}
+
+	// Object URIs (rather than embedded objects) are dereferenced, or looked up locally, by the activity-specific handlers.
+
+	if !gjson.ParseBytes(enc_obj).IsObject() {
+		return nil
+	}
+
+	switch activity.Type {
+	case "Create", "Update", "Delete":
+
+		object_id := gjson.GetBytes(enc_obj, "id").String()
+
+		if object_id != "" {
+
+			object_u, err := url.Parse(object_id)
+
+			if err != nil {
+				return fmt.Errorf("Failed to parse object URI, %w", err)
+			}
+
+			if !strings.EqualFold(object_u.Host, actor_u.Host) {
+				return fmt.Errorf("Object %s does not have the same origin as actor %s", object_id, actor.Id)
+			}
+		}
+
+		if gjson.GetBytes(enc_obj, "attributedTo").Exists() {
+
+			authors, err := ap.ObjectAttributions(enc_obj)
+
+			if err != nil {
+				return fmt.Errorf("Failed to derive object attributions, %w", err)
+			}
+
+			if !slices.Contains(authors, actor.Id) {
+				return fmt.Errorf("Object is attributed to %s and not actor %s", strings.Join(authors, ", "), actor.Id)
+			}
+		}
+
+	case "Undo":
+
+		object_actor := gjson.GetBytes(enc_obj, "actor")
+
+		if object_actor.Exists() {
+
+			if object_actor.String() != activity.Actor && object_actor.String() != actor.Id {
+				return fmt.Errorf("Undone activity was performed by %s and not actor %s", object_actor.String(), actor.Id)
+			}
+		}
+
+	default:
+		// pass
+	}
+
+	return nil
+}
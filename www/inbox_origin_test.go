@@ -0,0 +1,62 @@
+package www
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sfomuseum/go-activitypub/ap"
+)
+
+type spoofingFixture struct {
+	Description string       `json:"description"`
+	KeyId       string       `json:"key_id"`
+	Signer      *ap.Actor    `json:"signer"`
+	Activity    *ap.Activity `json:"activity"`
+	Valid       bool         `json:"valid"`
+}
+
+func TestInboxSpoofingFixtures(t *testing.T) {
+
+	paths, err := filepath.Glob("../fixtures/spoofing/*.json")
+
+	if err != nil {
+		t.Fatalf("Failed to glob fixtures, %v", err)
+	}
+
+	if len(paths) == 0 {
+		t.Fatalf("No spoofing fixtures found")
+	}
+
+	for _, path := range paths {
+
+		body, err := os.ReadFile(path)
+
+		if err != nil {
+			t.Fatalf("Failed to read %s, %v", path, err)
+		}
+
+		var f *spoofingFixture
+
+		err = json.Unmarshal(body, &f)
+
+		if err != nil {
+			t.Fatalf("Failed to unmarshal %s, %v", path, err)
+		}
+
+		err = checkInboxKeyOwner(f.KeyId, f.Signer)
+
+		if err == nil {
+			err = checkInboxActivityOrigin(f.Activity, f.Signer)
+		}
+
+		if f.Valid && err != nil {
+			t.Fatalf("Expected %s (%s) to be valid, %v", path, f.Description, err)
+		}
+
+		if !f.Valid && err == nil {
+			t.Fatalf("Expected %s (%s) to be rejected", path, f.Description)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package www
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/queue"
+)
+
+// queueInboxActivity stores 'body', the raw (JSON-encoded) representation of the activity in 'inbox_activity', in the
+// queued activities database and dispatches its unique ID to the inbox queue to be processed separately.
+func queueInboxActivity(ctx context.Context, opts *InboxPostHandlerOptions, inbox_activity *InboxActivity, body []byte) (int64, error) {
+
+	q, err := activitypub.NewQueuedActivity(ctx, inbox_activity.Activity, body, inbox_activity.Account.Id, inbox_activity.RequestorAddress, inbox_activity.RequestorActor)
+
+	if err != nil {
+		return 0, fmt.Errorf("Failed to create queued activity, %w", err)
+	}
+
+	err = opts.QueuedActivitiesDatabase.AddQueuedActivity(ctx, q)
+
+	if err != nil {
+		return 0, fmt.Errorf("Failed to add queued activity, %w", err)
+	}
+
+	err = opts.InboxQueue.ProcessInboxActivity(ctx, q.Id)
+
+	if err != nil {
+		return 0, fmt.Errorf("Failed to dispatch queued activity %d, %w", q.Id, err)
+	}
+
+	return q.Id, nil
+}
+
+// NewProcessQueuedInboxActivityFunc returns a `queue.ProcessInboxActivityFunc` function for processing activities that have
+// been queued by the `InboxPostHandler` or `SharedInboxPostHandler` handlers. The queued activity is retrieved from 'opts.QueuedActivitiesDatabase',
+// the domain block, domain allowlist, account block and duplicate checks are performed again (since things may have changed while the activity was queued)
+// and then it is passed to the activity-specific handler defined by 'opts'. Queued activities are removed once they have been processed
+// unless the handler returns a server error (5XX) status code in which case an error is returned and the activity is left in the
+// database so that it may be processed again.
+func NewProcessQueuedInboxActivityFunc(opts *InboxPostHandlerOptions) (queue.ProcessInboxActivityFunc, error) {
+
+	if opts.QueuedActivitiesDatabase == nil {
+		return nil, fmt.Errorf("Missing queued activities database")
+	}
+
+	activity_handlers, err := DefaultInboxActivityHandlers(opts)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create default activity handlers, %w", err)
+	}
+
+	for activity_type, h := range opts.Activities {
+		activity_handlers[activity_type] = h
+	}
+
+	fn := func(ctx context.Context, queued_activity_id int64) error {
+
+		logger := slog.Default()
+		logger = logger.With("queued activity id", queued_activity_id)
+
+		q, err := opts.QueuedActivitiesDatabase.GetQueuedActivityWithId(ctx, queued_activity_id)
+
+		if err != nil {
+
+			if err == activitypub.ErrNotFound {
+				logger.Warn("Queued activity not found, assuming it has already been processed")
+				return nil
+			}
+
+			return fmt.Errorf("Failed to retrieve queued activity, %w", err)
+		}
+
+		logger = logger.With("requestor_address", q.RequestorAddress)
+		logger = logger.With("activity_type", q.ActivityType)
+		logger = logger.With("account id", q.AccountId)
+
+		remove := func() {
+
+			err := opts.QueuedActivitiesDatabase.RemoveQueuedActivity(ctx, q)
+
+			if err != nil {
+				logger.Error("Failed to remove queued activity", "error", err)
+			}
+		}
+
+		acct, err := opts.AccountsDatabase.GetAccountWithId(ctx, q.AccountId)
+
+		if err != nil {
+
+			if err == activitypub.ErrNotFound {
+				logger.Warn("Account for queued activity no longer exists, skipping")
+				remove()
+				return nil
+			}
+
+			return fmt.Errorf("Failed to retrieve account %d, %w", q.AccountId, err)
+		}
+
+		logger = logger.With("account", acct.Name)
+
+		activity, err := q.UnmarshalActivity()
+
+		if err != nil {
+			remove()
+			return fmt.Errorf("Failed to derive activity for queued activity %d, %w", q.Id, err)
+		}
+
+		requestor_actor, err := q.UnmarshalRequestorActor()
+
+		if err != nil {
+			remove()
+			return fmt.Errorf("Failed to derive requestor actor for queued activity %d, %w", q.Id, err)
+		}
+
+		activity_handler, exists := activity_handlers[activity.Type]
+
+		if !exists {
+
+			logger.Warn("Unsupported activity type, skipping")
+
+			err = logInboxActivity(ctx, opts, acct, activity, http.StatusNotImplemented)
+
+			if err != nil {
+				logger.Error("Failed to log received activity", "error", err)
+			}
+
+			remove()
+			return nil
+		}
+
+		domain_block, status, err := checkInboxDomainBlock(ctx, opts, activity.Actor)
+
+		if err != nil {
+
+			if status != http.StatusForbidden {
+				return fmt.Errorf("Failed to check domain blocks, %w", err)
+			}
+
+			logger.Warn("Requestor host has been suspended since activity was queued, skipping", "error", err)
+
+			err = logInboxActivity(ctx, opts, acct, activity, status)
+
+			if err != nil {
+				logger.Error("Failed to log received activity", "error", err)
+			}
+
+			remove()
+			return nil
+		}
+
+		// Likewise the requestor's host may have been removed from the allowlist
+
+		status, err = checkInboxDomainAllow(ctx, opts, activity.Actor)
+
+		if err != nil {
+
+			if status >= http.StatusInternalServerError {
+				return fmt.Errorf("Failed to check domain allowlist, %w", err)
+			}
+
+			logger.Warn("Requestor host is no longer on the allowlist, skipping", "error", err)
+
+			err = logInboxActivity(ctx, opts, acct, activity, status)
+
+			if err != nil {
+				logger.Error("Failed to log received activity", "error", err)
+			}
+
+			remove()
+			return nil
+		}
+
+		// The account may have blocked the requestor since the activity was queued
+
+		requestor_name, requestor_host, err := ap.ParseAddress(q.RequestorAddress)
+
+		if err != nil {
+			remove()
+			return fmt.Errorf("Failed to parse requestor address for queued activity %d, %w", q.Id, err)
+		}
+
+		requestor := &inboxRequestor{
+			Actor:   requestor_actor,
+			Address: q.RequestorAddress,
+			Name:    requestor_name,
+			Host:    requestor_host,
+		}
+
+		status, err = checkInboxRequestor(ctx, opts, acct, activity, requestor)
+
+		if err != nil {
+
+			if status >= http.StatusInternalServerError {
+				return fmt.Errorf("Failed to check requestor, %w", err)
+			}
+
+			logger.Warn("Requestor is not allowed to post to inbox, skipping", "error", err)
+
+			err = logInboxActivity(ctx, opts, acct, activity, status)
+
+			if err != nil {
+				logger.Error("Failed to log received activity", "error", err)
+			}
+
+			remove()
+			return nil
+		}
+
+		// There is no need to check for duplicates here since the activity was recorded,
+		// by claimInboxActivity, before it was queued.
+
+		inbox_activity := &InboxActivity{
+			Activity:         activity,
+			Account:          acct,
+			RequestorActor:   requestor_actor,
+			RequestorAddress: q.RequestorAddress,
+			DomainBlock:      domain_block,
+		}
+
+		// Activity handlers are http.Handler instances so create a (synthetic) request
+		// for the account's inbox to pass the activity along to them
+
+		inbox_url := acct.InboxURL(ctx, opts.URIs)
+
+		req, err := http.NewRequestWithContext(ContextWithInboxActivity(ctx, inbox_activity), http.MethodPost, inbox_url.String(), nil)
+
+		if err != nil {
+			return fmt.Errorf("Failed to create inbox request, %w", err)
+		}
+
+		logger.Info("Process queued activity", "type", activity.Type)
+
+		rsp := newInboxResponseRecorder()
+		activity_handler.ServeHTTP(rsp, req)
+
+		status = rsp.Status()
+
+		logger.Debug("Dispatched queued activity to account", "status", status)
+
+		// Activities that fail are left in the queue to be retried so their record is left as
+		// "processing" rather than allowing a retry by the remote server to queue them again.
+
+		if status >= http.StatusInternalServerError {
+			return fmt.Errorf("Failed to process queued activity %d, handler returned status %d", q.Id, status)
+		}
+
+		err = logInboxActivity(ctx, opts, acct, activity, status)
+
+		if err != nil {
+			logger.Error("Failed to log received activity", "error", err)
+		}
+
+		remove()
+		return nil
+	}
+
+	return fn, nil
+}
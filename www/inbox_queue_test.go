@@ -0,0 +1,181 @@
+package www
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/database"
+	"github.com/sfomuseum/go-activitypub/queue"
+	"github.com/sfomuseum/go-activitypub/uris"
+)
+
+func TestQueueInboxActivity(t *testing.T) {
+
+	ctx := context.Background()
+
+	uris_table := uris.DefaultURIs()
+	uris_table.Hostname = "example.com"
+
+	acct := &activitypub.Account{
+		Id:   1234,
+		Name: "alice",
+	}
+
+	domain_blocks_db, err := database.NewDomainBlocksDatabase(ctx, "null://")
+
+	if err != nil {
+		t.Fatalf("Failed to create domain blocks database, %v", err)
+	}
+
+	queued_db := &testQueuedActivitiesDatabase{
+		activities: make(map[int64]*activitypub.QueuedActivity),
+	}
+
+	inbox_q, err := queue.NewInboxQueue(ctx, "synchronous://")
+
+	if err != nil {
+		t.Fatalf("Failed to create inbox queue, %v", err)
+	}
+
+	blocks_db := &testBlocksDatabase{
+		blocked: make(map[string]bool),
+	}
+
+	processed := make([]*InboxActivity, 0)
+
+	like_handler := func(rsp http.ResponseWriter, req *http.Request) {
+
+		inbox_activity, err := InboxActivityFromContext(req.Context())
+
+		if err != nil {
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		processed = append(processed, inbox_activity)
+		rsp.WriteHeader(http.StatusAccepted)
+	}
+
+	opts := &InboxPostHandlerOptions{
+		AccountsDatabase:         &testAccountsDatabase{account: acct},
+		BlocksDatabase:           blocks_db,
+		DomainBlocksDatabase:     domain_blocks_db,
+		QueuedActivitiesDatabase: queued_db,
+		InboxQueue:               inbox_q,
+		URIs:                     uris_table,
+		Activities: map[string]http.Handler{
+			"Like": http.HandlerFunc(like_handler),
+		},
+	}
+
+	process_func, err := NewProcessQueuedInboxActivityFunc(opts)
+
+	if err != nil {
+		t.Fatalf("Failed to create process function, %v", err)
+	}
+
+	inbox_q.(*queue.SynchronousInboxQueue).SetProcessInboxActivityFunc(process_func)
+
+	body := []byte(`{"id":"https://remote.social/likes/1","type":"Like","actor":"https://remote.social/actor","object":"https://example.com/ap/alice/posts/1"}`)
+
+	activity := &ap.Activity{
+		Id:     "https://remote.social/likes/1",
+		Type:   "Like",
+		Actor:  "https://remote.social/actor",
+		Object: "https://example.com/ap/alice/posts/1",
+	}
+
+	inbox_activity := &InboxActivity{
+		Activity:         activity,
+		Account:          acct,
+		RequestorActor:   &ap.Actor{Id: "https://remote.social/actor"},
+		RequestorAddress: "actor@remote.social",
+	}
+
+	queued_id, err := queueInboxActivity(ctx, opts, inbox_activity, body)
+
+	if err != nil {
+		t.Fatalf("Failed to queue activity, %v", err)
+	}
+
+	if len(processed) != 1 {
+		t.Fatalf("Expected queued activity to have been processed once, got %d", len(processed))
+	}
+
+	p := processed[0]
+
+	if p.Activity.Id != activity.Id || p.Account.Id != acct.Id || p.RequestorAddress != "actor@remote.social" || p.RequestorActor.Id != "https://remote.social/actor" {
+		t.Fatalf("Unexpected processed activity, %v", p)
+	}
+
+	_, exists := queued_db.activities[queued_id]
+
+	if exists {
+		t.Fatalf("Expected queued activity to have been removed after processing")
+	}
+
+	// The requestor is blocked after the activity is queued (but before it is processed)
+
+	inbox_q.(*queue.SynchronousInboxQueue).SetProcessInboxActivityFunc(func(ctx context.Context, id int64) error {
+		return nil
+	})
+
+	queued_id, err = queueInboxActivity(ctx, opts, inbox_activity, body)
+
+	if err != nil {
+		t.Fatalf("Failed to queue activity, %v", err)
+	}
+
+	blocks_db.blocked["actor@remote.social"] = true
+
+	err = process_func(ctx, queued_id)
+
+	if err != nil {
+		t.Fatalf("Failed to process queued activity, %v", err)
+	}
+
+	if len(processed) != 1 {
+		t.Fatalf("Expected activity from blocked requestor not to have been processed")
+	}
+
+	_, exists = queued_db.activities[queued_id]
+
+	if exists {
+		t.Fatalf("Expected queued activity from blocked requestor to have been removed")
+	}
+
+	// The requestor's host is removed from the allowlist after the activity is queued
+
+	delete(blocks_db.blocked, "actor@remote.social")
+
+	opts.DomainAllowsDatabase = &testDomainAllowsDatabase{
+		domains: map[string]bool{
+			"partner.museum": true,
+		},
+	}
+
+	queued_id, err = queueInboxActivity(ctx, opts, inbox_activity, body)
+
+	if err != nil {
+		t.Fatalf("Failed to queue activity, %v", err)
+	}
+
+	err = process_func(ctx, queued_id)
+
+	if err != nil {
+		t.Fatalf("Failed to process queued activity, %v", err)
+	}
+
+	if len(processed) != 1 {
+		t.Fatalf("Expected activity from host not on the allowlist not to have been processed")
+	}
+
+	_, exists = queued_db.activities[queued_id]
+
+	if exists {
+		t.Fatalf("Expected queued activity from host not on the allowlist to have been removed")
+	}
+}
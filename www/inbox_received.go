@@ -0,0 +1,186 @@
+package www
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/ap"
+)
+
+// claimInboxActivity records that 'activity' has been received by the inbox of 'acct' before it is processed, returning
+// a boolean value indicating whether the caller should go on to process it. Received activities are unique per activity
+// ID, account and actor so if the same activity has already been accepted, or is being processed by a concurrent delivery,
+// the record can not be added and the activity is a duplicate. Only activities from the same actor are considered so that
+// one actor can not suppress the activities of another by sending an activity with the same ID first. Activities whose
+// earlier deliveries were rejected or failed are claimed again. Activities without an ID, or when there is no
+// `ReceivedActivitiesDatabase`, are always claimed.
+func claimInboxActivity(ctx context.Context, opts *InboxPostHandlerOptions, acct *activitypub.Account, activity *ap.Activity) (bool, error) {
+
+	if opts.ReceivedActivitiesDatabase == nil || activity.Id == "" {
+		return true, nil
+	}
+
+	r, err := activitypub.NewProcessingReceivedActivity(ctx, activity, acct.Id)
+
+	if err != nil {
+		return false, fmt.Errorf("Failed to create received activity, %w", err)
+	}
+
+	err = opts.ReceivedActivitiesDatabase.AddReceivedActivity(ctx, r)
+
+	switch {
+	case err == nil:
+		return true, nil
+	case err != activitypub.ErrDuplicate:
+		return false, fmt.Errorf("Failed to add received activity, %w", err)
+	default:
+		//
+	}
+
+	existing, err := getInboxActivity(ctx, opts, acct, activity)
+
+	if err != nil {
+		return false, err
+	}
+
+	if existing == nil || existing.IsAccepted() || existing.IsProcessing() {
+		return false, nil
+	}
+
+	// Replace the record of the earlier, unsuccessful, delivery. If a concurrent delivery
+	// does the same thing first then adding the new record will fail again.
+
+	err = opts.ReceivedActivitiesDatabase.RemoveReceivedActivity(ctx, existing)
+
+	if err != nil {
+		return false, fmt.Errorf("Failed to remove received activity %d, %w", existing.Id, err)
+	}
+
+	err = opts.ReceivedActivitiesDatabase.AddReceivedActivity(ctx, r)
+
+	switch {
+	case err == nil:
+		return true, nil
+	case err == activitypub.ErrDuplicate:
+		return false, nil
+	default:
+		return false, fmt.Errorf("Failed to add received activity, %w", err)
+	}
+}
+
+// logInboxActivity records that processing 'activity', posted to the inbox of 'acct', returned the HTTP status code 'status'.
+// The record created by `claimInboxActivity` is updated if present. Activities that have already been accepted are not
+// updated.
+func logInboxActivity(ctx context.Context, opts *InboxPostHandlerOptions, acct *activitypub.Account, activity *ap.Activity, status int) error {
+
+	if opts.ReceivedActivitiesDatabase == nil || activity.Id == "" {
+		return nil
+	}
+
+	existing, err := getInboxActivity(ctx, opts, acct, activity)
+
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+
+		if existing.IsAccepted() {
+			return nil
+		}
+
+		existing.Status = status
+		existing.Outcome = activitypub.ReceivedActivityOutcome(status)
+
+		err = opts.ReceivedActivitiesDatabase.UpdateReceivedActivity(ctx, existing)
+
+		if err != nil {
+			return fmt.Errorf("Failed to update received activity %d, %w", existing.Id, err)
+		}
+
+		return nil
+	}
+
+	r, err := activitypub.NewReceivedActivity(ctx, activity, acct.Id, status)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create received activity, %w", err)
+	}
+
+	err = opts.ReceivedActivitiesDatabase.AddReceivedActivity(ctx, r)
+
+	if err != nil && err != activitypub.ErrDuplicate {
+		return fmt.Errorf("Failed to add received activity, %w", err)
+	}
+
+	return nil
+}
+
+// getInboxActivity returns the record of 'activity' posted to the inbox of 'acct' by the activity's actor or nil if there is no record.
+func getInboxActivity(ctx context.Context, opts *InboxPostHandlerOptions, acct *activitypub.Account, activity *ap.Activity) (*activitypub.ReceivedActivity, error) {
+
+	var received *activitypub.ReceivedActivity
+
+	received_cb := func(ctx context.Context, r *activitypub.ReceivedActivity) error {
+
+		if r.Actor == activity.Actor {
+			received = r
+		}
+
+		return nil
+	}
+
+	err := opts.ReceivedActivitiesDatabase.GetReceivedActivitiesWithActivityPubIdAndAccount(ctx, activity.Id, acct.Id, received_cb)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve received activities for %s, %w", activity.Id, err)
+	}
+
+	return received, nil
+}
+
+// inboxStatusWriter is a `http.ResponseWriter` implementation that wraps an existing `http.ResponseWriter` instance
+// in order to capture the status code returned by activity-specific handlers.
+type inboxStatusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func newInboxStatusWriter(rsp http.ResponseWriter) *inboxStatusWriter {
+
+	w := &inboxStatusWriter{
+		ResponseWriter: rsp,
+	}
+
+	return w
+}
+
+func (w *inboxStatusWriter) Write(b []byte) (int, error) {
+
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *inboxStatusWriter) WriteHeader(status int) {
+
+	if w.status == 0 {
+		w.status = status
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Status returns the status code written to 'w' or HTTP 200 OK if no status code was written.
+func (w *inboxStatusWriter) Status() int {
+
+	if w.status == 0 {
+		return http.StatusOK
+	}
+
+	return w.status
+}
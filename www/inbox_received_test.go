@@ -0,0 +1,119 @@
+package www
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/ap"
+)
+
+func TestClaimInboxActivity(t *testing.T) {
+
+	ctx := context.Background()
+
+	alice := &activitypub.Account{
+		Id:   1,
+		Name: "alice",
+	}
+
+	received_db := &testReceivedActivitiesDatabase{}
+
+	opts := &InboxPostHandlerOptions{
+		ReceivedActivitiesDatabase: received_db,
+	}
+
+	activity := &ap.Activity{
+		Id:     "https://example.com/activities/1",
+		Type:   "Follow",
+		Actor:  "https://example.com/users/bob",
+		Object: "https://social.example/ap/alice",
+	}
+
+	// Another actor claiming the same activity ID
+
+	other_activity := &ap.Activity{
+		Id:     activity.Id,
+		Type:   "Follow",
+		Actor:  "https://example.com/users/mallory",
+		Object: "https://social.example/ap/alice",
+	}
+
+	claimed, err := claimInboxActivity(ctx, opts, alice, other_activity)
+
+	if err != nil {
+		t.Fatalf("Failed to claim activity, %v", err)
+	}
+
+	if !claimed {
+		t.Fatalf("Expected activity to be claimed")
+	}
+
+	claimed, err = claimInboxActivity(ctx, opts, alice, activity)
+
+	if err != nil {
+		t.Fatalf("Failed to claim activity, %v", err)
+	}
+
+	if !claimed {
+		t.Fatalf("Expected activity from a different actor not to be considered a duplicate")
+	}
+
+	// A concurrent delivery of an activity which is still being processed
+
+	claimed, err = claimInboxActivity(ctx, opts, alice, activity)
+
+	if err != nil {
+		t.Fatalf("Failed to claim activity, %v", err)
+	}
+
+	if claimed {
+		t.Fatalf("Expected activity being processed to be considered a duplicate")
+	}
+
+	// A retry of an activity whose processing failed
+
+	err = logInboxActivity(ctx, opts, alice, activity, http.StatusInternalServerError)
+
+	if err != nil {
+		t.Fatalf("Failed to log activity, %v", err)
+	}
+
+	claimed, err = claimInboxActivity(ctx, opts, alice, activity)
+
+	if err != nil {
+		t.Fatalf("Failed to claim activity, %v", err)
+	}
+
+	if !claimed {
+		t.Fatalf("Expected failed activity to be claimed again")
+	}
+
+	err = logInboxActivity(ctx, opts, alice, activity, http.StatusAccepted)
+
+	if err != nil {
+		t.Fatalf("Failed to log activity, %v", err)
+	}
+
+	claimed, err = claimInboxActivity(ctx, opts, alice, activity)
+
+	if err != nil {
+		t.Fatalf("Failed to claim activity, %v", err)
+	}
+
+	if claimed {
+		t.Fatalf("Expected accepted activity to be considered a duplicate")
+	}
+
+	if len(received_db.received) != 2 {
+		t.Fatalf("Expected 2 received activities, got %d", len(received_db.received))
+	}
+
+	for _, r := range received_db.received {
+
+		if !r.IsAccepted() && r.Actor == activity.Actor {
+			t.Fatalf("Expected activity to be recorded as accepted, got %s", r.Outcome)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package www
+
+import (
+	"net/http"
+
+	"github.com/sfomuseum/go-activitypub"
+)
+
+// InboxRejectHandler returns a `http.Handler` for processing verified "Reject" activities posted to an account's inbox.
+// Reject activities are matched against the original "Follow" activity sent by the account and, if valid, are used to
+// mark the corresponding `activitypub.Following` record as rejected.
+func InboxRejectHandler(opts *InboxPostHandlerOptions) (http.Handler, error) {
+	return inboxFollowResponseHandler(opts, activitypub.RejectedFollowingState)
+}
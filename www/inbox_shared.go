@@ -0,0 +1,426 @@
+package www
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aaronland/go-http/v3/slog"
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/uris"
+	"github.com/tidwall/gjson"
+)
+
+// SharedInboxPostHandler returns a `http.Handler` for processing activities posted to the inbox shared by all the accounts
+// on this server. The request signature is verified once after which the activity is dispatched, using the same activity-specific
+// handlers as `InboxPostHandler`, to every local account that follows the actor posting the activity or that is addressed or
+// mentioned by the activity. A HTTP 202 Accepted response is returned once the activity has been dispatched to all the recipients.
+// If 'opts' has an inbox queue the activity is queued, separately, for each recipient rather than being processed immediately.
+func SharedInboxPostHandler(opts *InboxPostHandlerOptions) (http.Handler, error) {
+
+	if opts.InboxQueue != nil && opts.QueuedActivitiesDatabase == nil {
+		return nil, fmt.Errorf("Inbox queue requires a queued activities database")
+	}
+
+	activity_handlers, err := DefaultInboxActivityHandlers(opts)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create default activity handlers, %w", err)
+	}
+
+	for activity_type, h := range opts.Activities {
+		activity_handlers[activity_type] = h
+	}
+
+	fn := func(rsp http.ResponseWriter, req *http.Request) {
+
+		ctx := req.Context()
+
+		logger := slog.LoggerWithRequest(req, nil)
+
+		t1 := time.Now()
+
+		defer func() {
+			logger.Debug("Time to serve request", "ms", time.Since(t1).Milliseconds())
+		}()
+
+		activity, body, status, err := readInboxActivity(req, logger)
+
+		if err != nil {
+			logger.Error("Invalid inbox request", "error", err)
+			http.Error(rsp, http.StatusText(status), status)
+			return
+		}
+
+		logger = logger.With("requestor_address", activity.Actor)
+		logger = logger.With("activity_type", activity.Type)
+
+		// Ensure the remote address hasn't exceeded its rate limit before doing anything else. Nothing
+		// in the request can be trusted until it has been verified so this is the only limit applied here.
+
+		allowed, retry_after, rl_host, err := checkInboxAddressRateLimit(ctx, opts, req)
+
+		if err != nil {
+			logger.Error("Failed to check rate limits", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		if !allowed {
+			logger.Warn("Rate limit exceeded", "host", rl_host, "retry after", retry_after)
+			writeTooManyRequests(rsp, retry_after)
+			return
+		}
+
+		activity_handler, exists := activity_handlers[activity.Type]
+
+		if !exists {
+			logger.Debug("Unsupported activity type")
+			http.Error(rsp, "Not implemented", http.StatusNotImplemented)
+			return
+		}
+
+		logger.Info("Valid activity")
+
+		// Ensure the requestor's host hasn't been suspended before doing any more work
+
+		domain_block, status, err := checkInboxDomainBlock(ctx, opts, activity.Actor)
+
+		if err != nil {
+			logger.Error("Requestor host is not allowed to post to inbox", "error", err)
+			http.Error(rsp, http.StatusText(status), status)
+			return
+		}
+
+		// If the server is running in allowlist mode ensure the requestor's host is on the allowlist
+
+		status, err = checkInboxDomainAllow(ctx, opts, activity.Actor)
+
+		if err != nil {
+			logger.Warn("Refused activity from host not on allowlist", "actor", activity.Actor, "error", err)
+			http.Error(rsp, http.StatusText(status), status)
+			return
+		}
+
+		requestor, status, err := deriveInboxRequestor(ctx, opts, activity, logger)
+
+		if err != nil {
+
+			if isGoneSelfDelete(activity, err) {
+				logger.Info("Actor has been deleted and there is no cached copy to verify the request with, nothing to do")
+				rsp.WriteHeader(http.StatusAccepted)
+				return
+			}
+
+			logger.Error("Failed to derive requestor", "error", err)
+			http.Error(rsp, http.StatusText(status), status)
+			return
+		}
+
+		logger = logger.With("requestor_address", requestor.Address, "requestor_name", requestor.Name, "requestor_host", requestor.Host)
+
+		logger.Info("Valid requestor")
+
+		status, err = verifyInboxRequest(ctx, opts, req, body, requestor, logger)
+
+		if err != nil {
+
+			if isGoneSelfDelete(activity, err) {
+				logger.Info("Actor has been deleted and there is no cached copy to verify the request with, nothing to do")
+				rsp.WriteHeader(http.StatusAccepted)
+				return
+			}
+
+			logger.Error("Failed to verify request", "error", err)
+			http.Error(rsp, http.StatusText(status), status)
+			return
+		}
+
+		// Now that the request has been verified ensure the signing actor, and its host, haven't exceeded their rate limits
+
+		allowed, retry_after, rl_host, rl_actor, err := checkInboxRateLimits(ctx, opts, requestor)
+
+		if err != nil {
+			logger.Error("Failed to check rate limits", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		if !allowed {
+			logger.Warn("Rate limit exceeded", "host", rl_host, "actor", rl_actor, "retry after", retry_after)
+			writeTooManyRequests(rsp, retry_after)
+			return
+		}
+
+		err = checkInboxActivityOrigin(activity, requestor.Actor)
+
+		if err != nil {
+			logger.Error("Signing actor is not allowed to post activity", "error", err)
+			http.Error(rsp, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		logger.Info("Valid request")
+
+		recipients, err := sharedInboxRecipients(ctx, opts, activity, requestor)
+
+		if err != nil {
+			logger.Error("Failed to derive recipients for activity", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		logger.Info("Process activity", "type", activity.Type, "recipients", len(recipients))
+
+		for _, acct := range recipients {
+
+			acct_logger := logger.With("account", acct.Name, "account id", acct.Id)
+
+			status, err := checkInboxRequestor(ctx, opts, acct, activity, requestor)
+
+			if err != nil {
+				acct_logger.Warn("Requestor is not allowed to post to inbox, skipping", "status", status, "error", err)
+				continue
+			}
+
+			claimed, err := claimInboxActivity(ctx, opts, acct, activity)
+
+			if err != nil {
+				acct_logger.Error("Failed to record received activity, skipping", "error", err)
+				continue
+			}
+
+			if !claimed {
+
+				acct_logger.Info("Activity has already been received, skipping", "activity id", activity.Id)
+
+				continue
+			}
+
+			inbox_activity := &InboxActivity{
+				Activity:         activity,
+				Account:          acct,
+				RequestorActor:   requestor.Actor,
+				RequestorAddress: requestor.Address,
+				DomainBlock:      domain_block,
+			}
+
+			if opts.InboxQueue != nil {
+
+				queued_id, err := queueInboxActivity(ctx, opts, inbox_activity, body)
+
+				if err != nil {
+
+					acct_logger.Error("Failed to queue activity, skipping", "error", err)
+
+					err = logInboxActivity(ctx, opts, acct, activity, http.StatusInternalServerError)
+
+					if err != nil {
+						acct_logger.Error("Failed to log received activity", "error", err)
+					}
+
+					continue
+				}
+
+				acct_logger.Debug("Queued activity for account", "queued activity id", queued_id)
+				continue
+			}
+
+			acct_ctx := ContextWithInboxActivity(ctx, inbox_activity)
+			acct_req := req.WithContext(acct_ctx)
+
+			acct_rsp := newInboxResponseRecorder()
+			activity_handler.ServeHTTP(acct_rsp, acct_req)
+
+			acct_logger.Debug("Dispatched activity to account", "status", acct_rsp.Status())
+
+			err = logInboxActivity(ctx, opts, acct, activity, acct_rsp.Status())
+
+			if err != nil {
+				acct_logger.Error("Failed to log received activity", "error", err)
+			}
+		}
+
+		logger.Debug("Shared inbox post complete", "status", http.StatusAccepted)
+		rsp.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	return http.HandlerFunc(fn), nil
+}
+
+// sharedInboxRecipients returns the list of local accounts that 'activity' should be dispatched to. This is every account that
+// follows 'requestor' (and, for "Move" activities, every account followed by 'requestor') as well as every account whose URI (or the URI of one of its posts) is included in the "to" or "cc" properties
+// of 'activity' or its object, the "href" properties of the object's tags or as the object itself (or the "id" or "url" property of an embedded object).
+func sharedInboxRecipients(ctx context.Context, opts *InboxPostHandlerOptions, activity *ap.Activity, requestor *inboxRequestor) ([]*activitypub.Account, error) {
+
+	recipients := make([]*activitypub.Account, 0)
+	seen := make(map[int64]bool)
+
+	// First, accounts that are addressed or mentioned
+
+	enc_activity, err := json.Marshal(activity)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal activity, %w", err)
+	}
+
+	candidates := make([]string, 0)
+
+	for _, path := range []string{"to", "cc", "object", "object.id", "object.url", "object.to", "object.cc", "object.tag.#.href", "object.object", "object.object.id"} {
+
+		r := gjson.GetBytes(enc_activity, path)
+
+		switch {
+		case r.IsArray():
+			for _, v := range r.Array() {
+				candidates = append(candidates, v.String())
+			}
+		case r.Type == gjson.String:
+			candidates = append(candidates, r.String())
+		default:
+			// pass
+		}
+	}
+
+	for _, uri := range candidates {
+
+		account_name, ok := sharedInboxAccountName(opts.URIs, uri)
+
+		if !ok {
+			continue
+		}
+
+		acct, err := opts.AccountsDatabase.GetAccountWithName(ctx, account_name)
+
+		if err != nil {
+
+			if err == activitypub.ErrNotFound {
+				continue
+			}
+
+			return nil, fmt.Errorf("Failed to retrieve account %s, %w", account_name, err)
+		}
+
+		if !seen[acct.Id] {
+			recipients = append(recipients, acct)
+			seen[acct.Id] = true
+		}
+	}
+
+	// Then, accounts following the requestor
+
+	add_account := func(ctx context.Context, account_id int64) error {
+
+		if seen[account_id] {
+			return nil
+		}
+
+		acct, err := opts.AccountsDatabase.GetAccountWithId(ctx, account_id)
+
+		if err != nil {
+
+			if err == activitypub.ErrNotFound {
+				return nil
+			}
+
+			return fmt.Errorf("Failed to retrieve account %d, %w", account_id, err)
+		}
+
+		recipients = append(recipients, acct)
+		seen[acct.Id] = true
+		return nil
+	}
+
+	err = opts.FollowingDatabase.GetAccountIdsForFollowingAddress(ctx, requestor.Address, add_account)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive accounts following requestor, %w", err)
+	}
+
+	// "Move" activities are also dispatched to accounts the requestor is following
+
+	if activity.Type == "Move" {
+
+		err = opts.FollowersDatabase.GetAccountIdsForFollowerAddress(ctx, requestor.Address, add_account)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to derive accounts followed by requestor, %w", err)
+		}
+	}
+
+	return recipients, nil
+}
+
+// sharedInboxAccountName returns the name of the local account associated with 'uri', if it is an account or post URI
+// on this server.
+func sharedInboxAccountName(uris_table *uris.URIs, uri string) (string, bool) {
+
+	u, err := url.Parse(uri)
+
+	if err != nil || u.Host != uris_table.Hostname {
+		return "", false
+	}
+
+	for _, template := range []string{uris_table.Account, uris_table.Post} {
+
+		resource, ok := uris.ResourceFromPath(template, u.Path)
+
+		if ok {
+			return strings.TrimLeft(resource, "@"), true
+		}
+	}
+
+	return "", false
+}
+
+// inboxResponseRecorder is a minimal `http.ResponseWriter` implementation used to capture the status code returned by
+// activity-specific handlers when an activity posted to the shared inbox is dispatched to multiple accounts.
+type inboxResponseRecorder struct {
+	header http.Header
+	status int
+}
+
+func newInboxResponseRecorder() *inboxResponseRecorder {
+
+	r := &inboxResponseRecorder{
+		header: make(http.Header),
+	}
+
+	return r
+}
+
+func (r *inboxResponseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *inboxResponseRecorder) Write(b []byte) (int, error) {
+
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+
+	return len(b), nil
+}
+
+func (r *inboxResponseRecorder) WriteHeader(status int) {
+
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+// Status returns the status code written to 'r' or HTTP 200 OK if no status code was written.
+func (r *inboxResponseRecorder) Status() int {
+
+	if r.status == 0 {
+		return http.StatusOK
+	}
+
+	return r.status
+}
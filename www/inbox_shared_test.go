@@ -0,0 +1,70 @@
+package www
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/uris"
+)
+
+func TestSharedInboxRecipients(t *testing.T) {
+
+	ctx := context.Background()
+
+	alice := &activitypub.Account{
+		Id:   1,
+		Name: "alice",
+	}
+
+	uris_table := uris.DefaultURIs()
+	uris_table.Hostname = "social.example"
+
+	opts := &InboxPostHandlerOptions{
+		AccountsDatabase: &testAccountsDatabase{
+			account: alice,
+		},
+		FollowingDatabase: &testFollowingDatabase{
+			following: []*activitypub.Following{
+				{Id: 1, AccountId: alice.Id, FollowingAddress: "bob@example.com"},
+				// An account that no longer exists
+				{Id: 2, AccountId: 99, FollowingAddress: "bob@example.com"},
+			},
+		},
+		URIs: uris_table,
+	}
+
+	activity := &ap.Activity{
+		Id:     "https://example.com/activities/1",
+		Type:   "Create",
+		Actor:  "https://example.com/users/bob",
+		Object: "https://example.com/notes/1",
+	}
+
+	tests := map[string]int{
+		"bob@example.com":     1,
+		"mallory@example.com": 0,
+	}
+
+	for address, expected := range tests {
+
+		requestor := &inboxRequestor{
+			Address: address,
+		}
+
+		recipients, err := sharedInboxRecipients(ctx, opts, activity, requestor)
+
+		if err != nil {
+			t.Fatalf("Failed to derive recipients for %s, %v", address, err)
+		}
+
+		if len(recipients) != expected {
+			t.Fatalf("Unexpected number of recipients for %s, expected %d but got %d", address, expected, len(recipients))
+		}
+
+		if expected > 0 && recipients[0].Id != alice.Id {
+			t.Fatalf("Unexpected recipient for %s, %d", address, recipients[0].Id)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package www
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/aaronland/go-http/v3/slog"
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/followers"
+)
+
+// InboxUndoHandler returns a `http.Handler` for processing verified "Undo" activities posted to an account's inbox. As with
+// "Like" and "Announce" activities the object of an undone like or boost may be the URI of the post or an embedded copy of the post.
+func InboxUndoHandler(opts *InboxPostHandlerOptions) (http.Handler, error) {
+
+	fn := func(rsp http.ResponseWriter, req *http.Request) {
+
+		ctx := req.Context()
+
+		inbox_activity, err := InboxActivityFromContext(ctx)
+
+		if err != nil {
+			logger := slog.LoggerWithRequest(req, nil)
+			logger.Error("Failed to derive inbox activity from request", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		logger := inboxActivityLogger(req, inbox_activity)
+
+		acct := inbox_activity.Account
+		activity := inbox_activity.Activity
+		requestor_address := inbox_activity.RequestorAddress
+
+		enc_obj, err := json.Marshal(activity.Object)
+
+		if err != nil {
+			logger.Error("Failed to marshal activity object", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		var object_activity *ap.Activity
+
+		err = json.Unmarshal(enc_obj, &object_activity)
+
+		if err != nil {
+			logger.Error("Failed to derive activity from object", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		logger = logger.With("object type", object_activity.Type)
+
+		// Block activities are not supported (yet)
+
+		switch object_activity.Type {
+		case "Follow":
+
+			if !opts.AllowFollow {
+				logger.Error("Unsupported activity type, follows are disabled")
+				http.Error(rsp, "Not implemented", http.StatusNotImplemented)
+				return
+			}
+
+			is_following, f, err := followers.IsFollower(ctx, opts.FollowersDatabase, acct.Id, requestor_address)
+
+			if err != nil {
+				logger.Error("Failed to determine if following", "error", err)
+				http.Error(rsp, "Bad request", http.StatusBadRequest)
+				return
+			}
+
+			if is_following {
+
+				err = opts.FollowersDatabase.RemoveFollower(ctx, f)
+
+				if err != nil {
+					logger.Error("Failed to remove follower", "error", err)
+					http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+					return
+				}
+
+				break
+			}
+
+			// Follow requests which are still pending approval can be withdrawn too
+
+			r, err := opts.FollowRequestsDatabase.GetFollowRequest(ctx, acct.Id, requestor_address)
+
+			if err != nil && err != activitypub.ErrNotFound {
+				logger.Error("Failed to retrieve follow request", "error", err)
+				http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+
+			if r != nil {
+
+				err = opts.FollowRequestsDatabase.RemoveFollowRequest(ctx, r)
+
+				if err != nil {
+					logger.Error("Failed to remove follow request", "follow request", r.Id, "error", err)
+					http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+					return
+				}
+
+				logger.Info("Removed pending follow request", "follow request", r.Id)
+			}
+
+		case "Like", "EmojiReact":
+
+			if !opts.AllowLikes {
+				logger.Error("Unsupported activity type, likes are disabled")
+				http.Error(rsp, "Not implemented", http.StatusNotImplemented)
+				return
+			}
+
+			logger = logger.With("actor", activity.Actor)
+
+			post, status, err := announceObjectPost(ctx, opts, object_activity.Object)
+
+			if err != nil {
+				logger.Error("Failed to derive post from announce object", "error", err)
+				http.Error(rsp, http.StatusText(status), status)
+				return
+			}
+
+			logger = logger.With("post id", post.Id)
+
+			if post.AccountId != acct.Id {
+				logger.Error("Trying to act on post for different account", "post account", post.AccountId)
+				http.Error(rsp, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			emoji, _ := likeActivityEmoji(object_activity)
+
+			like, err := opts.LikesDatabase.GetLikeWithPostIdActorAndEmoji(ctx, post.Id, activity.Actor, emoji)
+
+			if err != nil && err != activitypub.ErrNotFound {
+				logger.Error("Failed to derive like from post and actor", "error", err)
+				http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+
+			if like != nil {
+
+				logger = logger.With("like", like.Id)
+
+				err := opts.LikesDatabase.RemoveLike(ctx, like)
+
+				if err != nil {
+					logger.Error("Failed to remove like", "error", err)
+					http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+					return
+				}
+
+				logger.Info("Removed like")
+			}
+
+		case "Announce":
+
+			if !opts.AllowBoosts {
+				logger.Error("Unsupported activity type, boosts are disabled")
+				http.Error(rsp, "Not implemented", http.StatusNotImplemented)
+				return
+			}
+
+			logger = logger.With("actor", activity.Actor)
+
+			post, status, err := announceObjectPost(ctx, opts, object_activity.Object)
+
+			if err != nil {
+				logger.Error("Failed to derive post from announce object", "error", err)
+				http.Error(rsp, http.StatusText(status), status)
+				return
+			}
+
+			logger = logger.With("post id", post.Id)
+
+			if post.AccountId != acct.Id {
+				logger.Error("Trying to act on post for different account", "post account", post.AccountId)
+				http.Error(rsp, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			boost, err := opts.BoostsDatabase.GetBoostWithPostIdAndActor(ctx, post.Id, activity.Actor)
+
+			if err != nil && err != activitypub.ErrNotFound {
+				logger.Error("Failed to derive boost from post and actor", "error", err)
+				http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+
+			if boost != nil {
+
+				logger = logger.With("boost", boost.Id)
+
+				err := opts.BoostsDatabase.RemoveBoost(ctx, boost)
+
+				if err != nil {
+					logger.Error("Failed to remove boost", "error", err)
+					http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+					return
+				}
+
+				logger.Info("Removed boost")
+			}
+
+		default:
+			logger.Error("Unsupported object type for undo", "type", object_activity.Type)
+			http.Error(rsp, "Not implemented", http.StatusNotImplemented)
+			return
+		}
+
+		logger.Debug("Inbox post complete", "status", http.StatusAccepted)
+		rsp.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	return http.HandlerFunc(fn), nil
+}
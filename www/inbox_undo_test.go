@@ -0,0 +1,96 @@
+package www
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/uris"
+)
+
+func TestInboxUndoHandlerAnnounce(t *testing.T) {
+
+	uris_table := uris.DefaultURIs()
+	uris_table.Hostname = "example.com"
+
+	acct := &activitypub.Account{
+		Id:   1234,
+		Name: "alice",
+	}
+
+	actor := "https://remote.social/users/bob"
+
+	boosts_db := &testBoostsDatabase{
+		boosts: map[int64]*activitypub.Boost{
+			10: &activitypub.Boost{Id: 10, PostId: 99, AccountId: acct.Id, Actor: actor},
+			11: &activitypub.Boost{Id: 11, PostId: 100, AccountId: acct.Id, Actor: actor},
+		},
+	}
+
+	opts := &InboxPostHandlerOptions{
+		AllowBoosts:    true,
+		BoostsDatabase: boosts_db,
+		PostsDatabase: &testPostsDatabase{
+			posts: map[int64]*activitypub.Post{
+				99:  &activitypub.Post{Id: 99, AccountId: acct.Id},
+				100: &activitypub.Post{Id: 100, AccountId: acct.Id},
+			},
+		},
+		URIs: uris_table,
+	}
+
+	h, err := InboxUndoHandler(opts)
+
+	if err != nil {
+		t.Fatalf("Failed to create undo handler, %v", err)
+	}
+
+	tests := []struct {
+		Description string
+		Object      interface{}
+		Status      int
+		Boosts      int
+	}{
+		// Misskey and Pleroma embed the post being boosted in the undone activity
+		{"embedded object", map[string]interface{}{"type": "Note", "id": "https://example.com/ap/@alice/posts/99"}, http.StatusAccepted, 1},
+		{"uri", "https://example.com/ap/@alice/posts/100", http.StatusAccepted, 0},
+		{"missing post", map[string]interface{}{"type": "Note", "id": "https://example.com/ap/@alice/posts/101"}, http.StatusNotFound, 0},
+	}
+
+	for _, test := range tests {
+
+		activity := &ap.Activity{
+			Id:    "https://remote.social/undo/1",
+			Type:  "Undo",
+			Actor: actor,
+			Object: map[string]interface{}{
+				"id":     "https://remote.social/announce/1",
+				"type":   "Announce",
+				"actor":  actor,
+				"object": test.Object,
+			},
+		}
+
+		inbox_activity := &InboxActivity{
+			Activity:         activity,
+			Account:          acct,
+			RequestorAddress: "bob@remote.social",
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/ap/alice/inbox", nil)
+		req = req.WithContext(ContextWithInboxActivity(req.Context(), inbox_activity))
+
+		rsp := httptest.NewRecorder()
+		h.ServeHTTP(rsp, req)
+
+		if rsp.Code != test.Status {
+			t.Fatalf("Unexpected status for %s, expected %d but got %d", test.Description, test.Status, rsp.Code)
+		}
+
+		if len(boosts_db.boosts) != test.Boosts {
+			t.Fatalf("Unexpected number of boosts after %s, expected %d but got %d", test.Description, test.Boosts, len(boosts_db.boosts))
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package www
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/aaronland/go-http/v3/slog"
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/actors"
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/messages"
+	"github.com/sfomuseum/go-activitypub/notes"
+	"github.com/tidwall/gjson"
+)
+
+// InboxUpdateHandler returns a `http.Handler` for processing verified "Update" activities posted to an account's inbox.
+// Updates to notes will replace the body of the (stored) note and re-dispatch the account's message for that note to the
+// process message queue. Updates to actors will replace the copy of that actor in the local cache of remote actors. In both
+// cases the activity is only acted on if the owner of the key used to sign the request is also the author of the object being updated.
+func InboxUpdateHandler(opts *InboxPostHandlerOptions) (http.Handler, error) {
+
+	fn := func(rsp http.ResponseWriter, req *http.Request) {
+
+		ctx := req.Context()
+		wg := new(sync.WaitGroup)
+
+		inbox_activity, err := InboxActivityFromContext(ctx)
+
+		if err != nil {
+			logger := slog.LoggerWithRequest(req, nil)
+			logger.Error("Failed to derive inbox activity from request", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		logger := inboxActivityLogger(req, inbox_activity)
+
+		acct := inbox_activity.Account
+		activity := inbox_activity.Activity
+		requestor_actor := inbox_activity.RequestorActor
+		requestor_address := inbox_activity.RequestorAddress
+
+		err = checkInboxActivityActor(activity.Actor, requestor_actor)
+
+		if err != nil {
+			logger.Error("Signature owner does not match activity actor", "error", err)
+			http.Error(rsp, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		logger = logger.With("signature owner", requestor_actor.Id)
+
+		enc_obj, err := json.Marshal(activity.Object)
+
+		if err != nil {
+			logger.Error("Failed to marshal activity object", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		object_type := gjson.GetBytes(enc_obj, "type").String()
+		object_id := gjson.GetBytes(enc_obj, "id").String()
+
+		logger = logger.With("object type", object_type, "object id", object_id)
+
+		switch object_type {
+		case "Person", "Service":
+
+			if object_id != requestor_actor.Id {
+				logger.Error("Signature owner does not match actor being updated")
+				http.Error(rsp, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			if opts.ActorsDatabase != nil {
+
+				var updated_actor *ap.Actor
+
+				err := json.Unmarshal(enc_obj, &updated_actor)
+
+				if err != nil {
+					logger.Error("Failed to unmarshal actor", "error", err)
+					http.Error(rsp, "Bad request", http.StatusBadRequest)
+					return
+				}
+
+				err = actors.CacheActor(ctx, opts.ActorsDatabase, inbox_activity.RequestorAddress, updated_actor)
+
+				if err != nil {
+					logger.Error("Failed to update cached actor", "error", err)
+					http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+					return
+				}
+			}
+
+			logger.Info("Actor updated")
+			rsp.WriteHeader(http.StatusAccepted)
+			return
+
+		default:
+
+			if !ap.IsNoteObjectType(object_type) {
+				logger.Error("Unsupported update activity type")
+				http.Error(rsp, "Not implemented", http.StatusNotImplemented)
+				return
+			}
+
+			if !opts.AllowCreate {
+				logger.Error("Unsupported activity type, creates are disabled")
+				http.Error(rsp, "Not implemented", http.StatusNotImplemented)
+				return
+			}
+		}
+
+		// Normalize the object the same way InboxCreateHandler does so that the comparison
+		// with the stored note body below is meaningful.
+
+		enc_obj, err = ap.NormalizeNoteObject(enc_obj, requestor_actor.Id)
+
+		if err != nil {
+			logger.Error("Failed to normalize activity object", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		var note *ap.Note
+
+		err = json.Unmarshal(enc_obj, &note)
+
+		if err != nil {
+			logger.Error("Failed to unmarshal activity note", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		if note.AttributedTo != requestor_actor.Id {
+			logger.Error("Signature owner does not match note author", "author", note.AttributedTo)
+			http.Error(rsp, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		db_note, err := opts.NotesDatabase.GetNoteWithUUIDAndAuthorAddress(ctx, note.Id, requestor_address)
+
+		if err != nil {
+
+			if err == activitypub.ErrNotFound {
+				logger.Debug("Note not found, nothing to update")
+				rsp.WriteHeader(http.StatusAccepted)
+				return
+			}
+
+			logger.Error("Failed to retrieve note", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		logger = logger.With("note id", db_note.Id)
+
+		var stored_note *ap.Note
+
+		err = json.Unmarshal([]byte(db_note.Body), &stored_note)
+
+		if err != nil {
+			logger.Error("Failed to unmarshal note body", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		if stored_note.AttributedTo != requestor_actor.Id {
+			logger.Error("Signature owner does not match stored note author", "author", stored_note.AttributedTo)
+			http.Error(rsp, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		// Only update the note if it has actually changed since the same update
+		// will be delivered to every account (on this service) that has received
+		// the note.
+
+		if db_note.Body != string(enc_obj) {
+
+			db_note, err = notes.UpdateNote(ctx, opts.NotesDatabase, db_note, string(enc_obj))
+
+			if err != nil {
+				logger.Error("Failed to update note", "error", err)
+				http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+
+			logger.Info("Note has been updated")
+		}
+
+		db_message, err := messages.GetMessage(ctx, opts.MessagesDatabase, acct.Id, db_note.Id)
+
+		if err != nil {
+
+			if err == activitypub.ErrNotFound {
+				logger.Debug("Note has not been delivered to account, nothing to update")
+				rsp.WriteHeader(http.StatusAccepted)
+				return
+			}
+
+			logger.Error("Failed to retrieve message", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		logger = logger.With("message id", db_message.Id)
+
+		db_message, err = messages.UpdateMessage(ctx, opts.MessagesDatabase, db_message)
+
+		if err != nil {
+			logger.Error("Failed to update message", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		wg.Add(1)
+
+		logger.Info("Schedule process message queue")
+
+		go func() {
+
+			defer wg.Done()
+
+			err := opts.ProcessMessageQueue.ProcessMessage(ctx, db_message.Id)
+
+			if err != nil {
+				logger.Error("Failed to process message with process queue", "error", err)
+				return
+			}
+
+			logger.Info("Delivered message to processing queue")
+		}()
+
+		logger.Debug("Inbox post complete", "status", http.StatusAccepted)
+		rsp.WriteHeader(http.StatusAccepted)
+
+		wg.Wait()
+		return
+	}
+
+	return http.HandlerFunc(fn), nil
+}
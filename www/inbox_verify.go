@@ -0,0 +1,413 @@
+package www
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-fed/httpsig"
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/actors"
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/blocks"
+	"github.com/sfomuseum/go-activitypub/crypto"
+	"github.com/sfomuseum/go-activitypub/signatures"
+)
+
+// inboxRequestor encapsulates details about the actor posting an activity to an inbox.
+type inboxRequestor struct {
+	// Actor is the actor whose key was used to sign the request. It may be nil until the request has been verified.
+	Actor *ap.Actor
+	// Address is the "@name@host" address of the actor posting the activity.
+	Address string
+	// Name is the name (preferred username) of the actor posting the activity.
+	Name string
+	// Host is the host of the actor posting the activity.
+	Host string
+	// SelfDelete is true if the activity being posted is an actor deleting itself in which case, if the actor's
+	// host reports that it has been deleted (HTTP 410 Gone), the cached copy of the actor is used to verify the request.
+	SelfDelete bool
+}
+
+// readInboxActivity ensures that 'req' is a POST request containing an ActivityStreams document and returns
+// that document as an `ap.Activity` instance along with the raw body of the request (which is needed to verify the
+// request's digest). If there is an error the HTTP status code to return is also included.
+func readInboxActivity(req *http.Request, logger *slog.Logger) (*ap.Activity, []byte, int, error) {
+
+	if req.Method != http.MethodPost {
+		return nil, nil, http.StatusMethodNotAllowed, fmt.Errorf("Method not allowed")
+	}
+
+	if !IsActivityStreamRequest(req, "Content-Type") {
+		return nil, nil, http.StatusBadRequest, fmt.Errorf("Not activitystream request, %s", req.Header.Get("Content-Type"))
+	}
+
+	limited_r := activitypub.DefaultLimitedReader(req.Body)
+
+	body, err := io.ReadAll(limited_r)
+
+	if err != nil {
+		return nil, nil, http.StatusInternalServerError, fmt.Errorf("Failed to read message body, %w", err)
+	}
+
+	// Make me a flag...
+	// Or maybe not...
+	log_body := false
+
+	if log_body {
+		logger.Debug("DEBUG", "body", string(body))
+	}
+
+	var activity *ap.Activity
+
+	err = json.Unmarshal(body, &activity)
+
+	if err != nil {
+		return nil, nil, http.StatusBadRequest, fmt.Errorf("Failed to decode message body, %w", err)
+	}
+
+	return activity, body, 0, nil
+}
+
+// deriveInboxRequestor returns an `inboxRequestor` instance for the actor of 'activity' which is expected to be either a
+// "@name@host" address or an actor (profile) URL. If there is an error the HTTP status code to return is also included.
+func deriveInboxRequestor(ctx context.Context, opts *InboxPostHandlerOptions, activity *ap.Activity, logger *slog.Logger) (*inboxRequestor, int, error) {
+
+	requestor_address := activity.Actor
+
+	requestor := &inboxRequestor{
+		Address:    requestor_address,
+		SelfDelete: isSelfDeleteActivity(activity),
+	}
+
+	if !strings.HasPrefix(requestor_address, "http") {
+
+		requestor_name, requestor_host, err := ap.ParseAddress(requestor_address)
+
+		if err != nil {
+			return nil, http.StatusBadRequest, fmt.Errorf("Failed to parse requestor address, %w", err)
+		}
+
+		if requestor_name == "" || requestor_host == "" {
+			return nil, http.StatusBadRequest, fmt.Errorf("Requestor address missing name or host")
+		}
+
+		requestor.Name = requestor_name
+		requestor.Host = requestor_host
+
+		return requestor, 0, nil
+	}
+
+	logger.Debug("Derive requestor address from URL")
+
+	requestor_u, err := url.Parse(requestor_address)
+
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("Failed to parse address URL for requestor, %w", err)
+	}
+
+	actor_opts := inboxRetrieveActorOptions(opts)
+	actor_opts.AllowGone = requestor.SelfDelete
+
+	actor, err := actors.RetrieveActorWithProfileURL(ctx, actor_opts, requestor_address)
+
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("Failed to retrieve actor/profile for requestor, %w", err)
+	}
+
+	requestor.Actor = actor
+	requestor.Name = actor.PreferredUsername
+	requestor.Host = requestor_u.Host
+	requestor.Address = fmt.Sprintf("%s@%s", requestor.Name, requestor.Host)
+
+	logger.Debug("Re-assign requestor address", "requestor_address", requestor.Address)
+	return requestor, 0, nil
+}
+
+// checkInboxRequestor ensures that 'requestor' is not blocked by 'acct' and that it is allowed to post 'activity'
+// to the inbox for 'acct'. If there is an error the HTTP status code to return is also included.
+func checkInboxRequestor(ctx context.Context, opts *InboxPostHandlerOptions, acct *activitypub.Account, activity *ap.Activity, requestor *inboxRequestor) (int, error) {
+
+	// Check if the requestor is being blocked
+
+	is_blocked, err := blocks.IsBlockedByAccount(ctx, opts.BlocksDatabase, acct.Id, requestor.Host, requestor.Name)
+
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("Failed to determine if requestor is blocked, %w", err)
+	}
+
+	if is_blocked {
+		return http.StatusForbidden, fmt.Errorf("Requestor is blocked")
+	}
+
+	// One final sanity check
+
+	switch activity.Type {
+	case "Follow", "Undo", "Like", "EmojiReact", "Announce":
+
+		// Note: We have prevented Block Undo activities above
+
+		if requestor.Name == acct.Name {
+			return http.StatusBadRequest, fmt.Errorf("Account name mismatch for activity type %s, requestor %s, account %s", activity.Type, requestor.Name, acct.Name)
+		}
+
+	default:
+		// pass
+	}
+
+	return 0, nil
+}
+
+// verifyInboxRequest verifies the HTTP signature for 'req' using the public key of the actor that signed it. Both RFC 9421
+// HTTP message signatures and draft-cavage HTTP signatures are supported. RFC 9421 signatures must cover the components defined
+// in `signatures.DefaultRequiredComponents` and the "Content-Digest" header must match 'body'. Draft-cavage signatures must
+// include the headers defined in `signatures.DefaultRequiredHeaders` and the "Digest" header must match 'body'. In both cases
+// the "Date" header (and signature "created" and "expires" parameters) must be within 'opts.SignatureClockSkew' of the current time.
+// GET and HEAD requests have no body so their signatures must only include the components (or headers) defined in
+// `signatures.DefaultRequiredGetComponents` (or `signatures.DefaultRequiredGetHeaders`) and no digest is verified.
+// If 'requestor.Actor' is nil, or its public key does not match the key used to sign the request, then the actor that
+// owns the signing key will be retrieved and assigned to 'requestor.Actor'. The signing key must be the public key published
+// by, and owned by, 'requestor.Actor' (see `checkInboxKeyOwner`). If verification fails the actor will be
+// retrieved again, bypassing any cached copy, in case its key has been rotated. If there is an error the HTTP status
+// code to return is also included.
+func verifyInboxRequest(ctx context.Context, opts *InboxPostHandlerOptions, req *http.Request, body []byte, requestor *inboxRequestor, logger *slog.Logger) (int, error) {
+
+	// This is important if the server is running behind some kind of proxy (for example Lambda)
+	// or the signature verification will fail
+
+	logger.Debug("Manually set hostname on request", "hostname", opts.URIs.Hostname)
+	req.Header.Set("Host", opts.URIs.Hostname)
+	req.Host = opts.URIs.Hostname
+
+	// Likewise for the RFC 9421 "@target-uri" component
+
+	req.URL.Host = opts.URIs.Hostname
+	req.URL.Scheme = "https"
+
+	if opts.URIs.Insecure {
+		req.URL.Scheme = "http"
+	}
+
+	has_body := req.Method != http.MethodGet && req.Method != http.MethodHead
+
+	var key_id string
+	var verify_func func(*ap.Actor) error
+
+	if signatures.IsRFC9421Request(req) {
+
+		logger = logger.With("signature scheme", signatures.RFC9421Scheme)
+
+		sig, err := signatures.ParseRFC9421Signature(req)
+
+		if err != nil {
+			return http.StatusUnauthorized, fmt.Errorf("Failed to parse signature, %w", err)
+		}
+
+		logger = logger.With("signature algorithm", sig.Algorithm)
+
+		required_components := signatures.DefaultRequiredComponents
+
+		if !has_body {
+			required_components = signatures.DefaultRequiredGetComponents
+		}
+
+		err = sig.EnsureComponents(required_components...)
+
+		if err != nil {
+			return http.StatusUnauthorized, fmt.Errorf("Invalid signature, %w", err)
+		}
+
+		if has_body {
+
+			err = signatures.VerifyContentDigest(req, body)
+
+			if err != nil {
+				return http.StatusUnauthorized, fmt.Errorf("Failed to verify content digest, %w", err)
+			}
+		}
+
+		err = signatures.VerifyRFC9421Date(req, sig, opts.SignatureClockSkew)
+
+		if err != nil {
+			return http.StatusUnauthorized, fmt.Errorf("Failed to verify date, %w", err)
+		}
+
+		key_id = sig.KeyId
+
+		verify_func = func(actor *ap.Actor) error {
+
+			public_key, err := inboxPublicKey(actor)
+
+			if err != nil {
+				return err
+			}
+
+			return sig.Verify(req, public_key)
+		}
+
+	} else {
+
+		logger = logger.With("signature scheme", signatures.CavageScheme)
+
+		sig, err := signatures.ParseSignature(req)
+
+		if err != nil {
+			return http.StatusUnauthorized, fmt.Errorf("Failed to parse signature, %w", err)
+		}
+
+		logger = logger.With("signature algorithm", sig.Algorithm)
+
+		required_headers := signatures.DefaultRequiredHeaders
+
+		if !has_body {
+			required_headers = signatures.DefaultRequiredGetHeaders
+		}
+
+		err = sig.EnsureHeaders(required_headers...)
+
+		if err != nil {
+			return http.StatusUnauthorized, fmt.Errorf("Invalid signature, %w", err)
+		}
+
+		if has_body {
+
+			err = signatures.VerifyDigest(req, body)
+
+			if err != nil {
+				return http.StatusUnauthorized, fmt.Errorf("Failed to verify digest, %w", err)
+			}
+		}
+
+		err = signatures.VerifyDate(req, sig, opts.SignatureClockSkew)
+
+		if err != nil {
+			return http.StatusUnauthorized, fmt.Errorf("Failed to verify date, %w", err)
+		}
+
+		verifier, err := httpsig.NewVerifier(req)
+
+		if err != nil {
+			return http.StatusUnauthorized, fmt.Errorf("Failed to create signature verifier, %w", err)
+		}
+
+		key_id = verifier.KeyId()
+
+		verify_func = func(actor *ap.Actor) error {
+
+			public_key, err := inboxPublicKey(actor)
+
+			if err != nil {
+				return err
+			}
+
+			algo, err := sig.HttpSigAlgorithm(public_key)
+
+			if err != nil {
+				return fmt.Errorf("Failed to derive signature algorithm, %w", err)
+			}
+
+			err = verifier.Verify(public_key, algo)
+
+			if err != nil {
+				return fmt.Errorf("Failed to verify signature, %w", err)
+			}
+
+			return nil
+		}
+	}
+
+	logger = logger.With("key id", key_id)
+
+	actor_opts := inboxRetrieveActorOptions(opts)
+	actor_opts.AllowGone = requestor.SelfDelete
+
+	if requestor.Actor != nil && requestor.Actor.PublicKey.Id == key_id {
+		logger.Debug("request public key ID is the same as signature key ID")
+	} else {
+
+		logger.Info("Fetch key for requestor", "key_id", key_id)
+
+		key_actor, err := actors.RetrieveActorWithProfileURL(ctx, actor_opts, key_id)
+
+		if err != nil {
+			return http.StatusBadRequest, fmt.Errorf("Failed to retrieve actor for key ID, %w", err)
+		}
+
+		requestor.Actor = key_actor
+	}
+
+	err := checkInboxKeyOwner(key_id, requestor.Actor)
+
+	if err == nil {
+		err = verify_func(requestor.Actor)
+	}
+
+	if err == nil {
+		return 0, nil
+	}
+
+	// The actor (and key) may have been cached before the key was rotated so fetch
+	// the actor again, bypassing the cache, and try one more time.
+
+	logger.Debug("Failed to verify signature, refetch actor", "error", err, "signature", req.Header.Get("Signature"))
+
+	actor_opts.Refresh = true
+
+	key_actor, err := actors.RetrieveActorWithProfileURL(ctx, actor_opts, key_id)
+
+	if err != nil {
+		return http.StatusForbidden, fmt.Errorf("Failed to refetch actor for key ID, %w", err)
+	}
+
+	requestor.Actor = key_actor
+
+	err = checkInboxKeyOwner(key_id, requestor.Actor)
+
+	if err != nil {
+		return http.StatusForbidden, err
+	}
+
+	err = verify_func(requestor.Actor)
+
+	if err != nil {
+		return http.StatusForbidden, err
+	}
+
+	return 0, nil
+}
+
+// inboxPublicKey returns the public key for 'actor'. The return value will be either a `*rsa.PublicKey`
+// or an `ed25519.PublicKey` instance.
+func inboxPublicKey(actor *ap.Actor) (any, error) {
+
+	public_key_str := actor.PublicKey.PEM
+
+	if public_key_str == "" {
+		return nil, fmt.Errorf("Other actor missing public key")
+	}
+
+	public_key, err := crypto.PublicKeyFromPEM(public_key_str)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse PEM block containing public key, %w", err)
+	}
+
+	return public_key, nil
+}
+
+// inboxRetrieveActorOptions returns a new `actors.RetrieveActorOptions` instance derived from 'opts'.
+func inboxRetrieveActorOptions(opts *InboxPostHandlerOptions) *actors.RetrieveActorOptions {
+
+	actor_opts := &actors.RetrieveActorOptions{
+		ActorsDatabase: opts.ActorsDatabase,
+		TTL:            opts.ActorsTTL,
+		Insecure:       opts.URIs.Insecure,
+	}
+
+	return actor_opts
+}
@@ -0,0 +1,176 @@
+package www
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aaronland/go-http/v3/slog"
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/database"
+	"github.com/sfomuseum/go-activitypub/media"
+	"github.com/sfomuseum/go-activitypub/uris"
+)
+
+type MediaHandlerOptions struct {
+	AccountsDatabase database.AccountsDatabase
+	MediaDatabase    database.MediaDatabase
+	URIs             *uris.URIs
+}
+
+// MediaHandler returns a new `http.Handler` for serving the media files (attachments) associated with posts
+// from the `gocloud.dev/blob` buckets they are stored in. Files which are not images, videos or audio files
+// are served as downloads.
+func MediaHandler(opts *MediaHandlerOptions) (http.Handler, error) {
+
+	media_pat := opts.URIs.Media
+	media_pat = strings.Replace(media_pat, "{resource}", "(?:[^\\/]+)", 1)
+	media_pat = strings.Replace(media_pat, "{id}", "(\\d+)", 1)
+
+	re_media, err := regexp.Compile(media_pat)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create media regular expression, %w", err)
+	}
+
+	fn := func(rsp http.ResponseWriter, req *http.Request) {
+
+		ctx := req.Context()
+
+		logger := slog.LoggerWithRequest(req, nil)
+
+		t1 := time.Now()
+
+		defer func() {
+			logger.Info("Time to serve request", "ms", time.Since(t1).Milliseconds())
+		}()
+
+		if req.Method != http.MethodGet {
+			logger.Error("Method not allowed")
+			http.Error(rsp, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		// Basic sanity checking of media ID
+
+		if !re_media.MatchString(req.URL.Path) {
+			http.Error(rsp, "Not found", http.StatusNotFound)
+			return
+		}
+
+		m := re_media.FindStringSubmatch(req.URL.Path)
+
+		str_id := m[1]
+		media_id, err := strconv.ParseInt(str_id, 10, 64)
+
+		if err != nil {
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		logger = logger.With("media id", media_id)
+
+		// Get account
+
+		account_name, host, err := ap.ParseAddressFromRequest(req)
+
+		if err != nil {
+			logger.Error("Failed to parse address from request", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		logger = logger.With("account name", account_name)
+
+		if host != "" && host != opts.URIs.Hostname {
+			logger.Error("Resouce has bunk hostname", "host", host)
+			http.Error(rsp, "Not found", http.StatusNotFound)
+			return
+		}
+
+		acct, err := opts.AccountsDatabase.GetAccountWithName(ctx, account_name)
+
+		if err != nil {
+
+			logger.Error("Failed to retrieve account", "error", err)
+
+			if err == activitypub.ErrNotFound {
+				http.Error(rsp, "Not found", http.StatusNotFound)
+				return
+			}
+
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		logger = logger.With("account id", acct.Id)
+
+		// Get media
+
+		post_media, err := opts.MediaDatabase.GetMediaWithId(ctx, media_id)
+
+		if err != nil {
+
+			logger.Error("Failed to retrieve media", "error", err)
+
+			if err == activitypub.ErrNotFound {
+				http.Error(rsp, "Not found", http.StatusNotFound)
+				return
+			}
+
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		if post_media.AccountId != acct.Id {
+			logger.Error("Media is owned by different account", "media account id", post_media.AccountId)
+			http.Error(rsp, "Not found", http.StatusNotFound)
+			return
+		}
+
+		r, err := media.NewReader(ctx, post_media)
+
+		if err != nil {
+			logger.Error("Failed to open media file for reading", "uri", post_media.URI, "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		defer r.Close()
+
+		rsp.Header().Set("Content-Type", post_media.MediaType)
+		rsp.Header().Set("X-Content-Type-Options", "nosniff")
+
+		// Only images, videos and audio files are displayed inline; everything else (and SVG
+		// documents, which may contain scripts) is served as a download.
+
+		if !isInlineMedia(post_media) {
+			rsp.Header().Set("Content-Disposition", "attachment")
+		}
+
+		_, err = io.Copy(rsp, r)
+
+		if err != nil {
+			logger.Error("Failed to copy media file", "error", err)
+		}
+
+		return
+	}
+
+	return http.HandlerFunc(fn), nil
+}
+
+// isInlineMedia returns a boolean value indicating whether 'm' is safe to be displayed inline by browsers.
+func isInlineMedia(m *activitypub.Media) bool {
+
+	if m.MediaType == "image/svg+xml" {
+		return false
+	}
+
+	return m.IsImage() || m.IsVideo() || m.IsAudio()
+}
@@ -0,0 +1,95 @@
+package www
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/uris"
+	_ "gocloud.dev/blob/fileblob"
+)
+
+func TestMediaHandler(t *testing.T) {
+
+	uris_table := uris.DefaultURIs()
+	uris_table.Hostname = "example.com"
+
+	acct := &activitypub.Account{
+		Id:   1234,
+		Name: "alice",
+	}
+
+	media_path := filepath.Join(t.TempDir(), "10.png")
+	media_body := "not really a PNG"
+
+	err := os.WriteFile(media_path, []byte(media_body), 0644)
+
+	if err != nil {
+		t.Fatalf("Failed to write media file, %v", err)
+	}
+
+	opts := &MediaHandlerOptions{
+		AccountsDatabase: &testAccountsDatabase{account: acct},
+		MediaDatabase: &testMediaDatabase{
+			media: map[int64]*activitypub.Media{
+				10: &activitypub.Media{Id: 10, PostId: 99, AccountId: acct.Id, URI: fmt.Sprintf("file://%s", media_path), MediaType: "image/png"},
+				11: &activitypub.Media{Id: 11, PostId: 100, AccountId: 9999, URI: fmt.Sprintf("file://%s", media_path), MediaType: "image/png"},
+				13: &activitypub.Media{Id: 13, PostId: 101, AccountId: acct.Id, URI: fmt.Sprintf("file://%s", media_path), MediaType: "text/html"},
+				14: &activitypub.Media{Id: 14, PostId: 102, AccountId: acct.Id, URI: fmt.Sprintf("file://%s", media_path), MediaType: "image/svg+xml"},
+			},
+		},
+		URIs: uris_table,
+	}
+
+	h, err := MediaHandler(opts)
+
+	if err != nil {
+		t.Fatalf("Failed to create media handler, %v", err)
+	}
+
+	tests := []struct {
+		Path        string
+		Status      int
+		ContentType string
+		Attachment  bool
+	}{
+		{"/ap/alice/media/10", http.StatusOK, "image/png", false},
+		{"/ap/alice/media/11", http.StatusNotFound, "", false},
+		{"/ap/alice/media/12", http.StatusNotFound, "", false},
+		{"/ap/alice/media/13", http.StatusOK, "text/html", true},
+		{"/ap/alice/media/14", http.StatusOK, "image/svg+xml", true},
+	}
+
+	for _, test := range tests {
+
+		req := httptest.NewRequest(http.MethodGet, test.Path, nil)
+		req.SetPathValue("resource", "alice")
+
+		rsp := httptest.NewRecorder()
+		h.ServeHTTP(rsp, req)
+
+		if rsp.Code != test.Status {
+			t.Fatalf("Unexpected status for %s, expected %d but got %d", test.Path, test.Status, rsp.Code)
+		}
+
+		if test.Status != http.StatusOK {
+			continue
+		}
+
+		if rsp.Header().Get("Content-Type") != test.ContentType || rsp.Body.String() != media_body {
+			t.Fatalf("Unexpected response for %s", test.Path)
+		}
+
+		if rsp.Header().Get("X-Content-Type-Options") != "nosniff" {
+			t.Fatalf("Expected nosniff header for %s", test.Path)
+		}
+
+		if (rsp.Header().Get("Content-Disposition") == "attachment") != test.Attachment {
+			t.Fatalf("Unexpected content disposition for %s, %s", test.Path, rsp.Header().Get("Content-Disposition"))
+		}
+	}
+}
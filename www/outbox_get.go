@@ -1,8 +1,13 @@
 package www
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
 	"time"
 
 	"github.com/aaronland/go-http/v3/slog"
@@ -12,14 +17,38 @@ import (
 	"github.com/sfomuseum/go-activitypub/uris"
 )
 
+// The default number of activities to include in each page of an outbox.
+const OUTBOX_PAGE_SIZE int = 20
+
+// outboxActivityTypes are the types of activities listed in an account's outbox. Posts, boosts, updates and deletes
+// are addressed to the public collection whereas likes and follows are addressed to individual actors.
+var outboxActivityTypes = []activitypub.ActivityType{
+	activitypub.PostActivityType,
+	activitypub.BoostActivityType,
+	activitypub.UpdateActivityType,
+	activitypub.DeleteActivityType,
+}
+
 type OutboxGetHandlerOptions struct {
-	AccountsDatabase database.AccountsDatabase
-	PostsDatabase    database.PostsDatabase
-	URIs             *uris.URIs
+	AccountsDatabase   database.AccountsDatabase
+	PostsDatabase      database.PostsDatabase
+	ActivitiesDatabase database.ActivitiesDatabase
+	URIs               *uris.URIs
+	PageSize           int
 }
 
+// OutboxGetHandler returns a `http.Handler` for serving an account's outbox. Requests without a "page" query parameter return an
+// `OrderedCollection` with pointers to the first and last pages. Pages are `OrderedCollectionPage` instances listing the public
+// activities delivered by the account, newest first. Pages are cursor-based using the "max_id" (activities older than) and "min_id"
+// (activities newer than) query parameters.
 func OutboxGetHandler(opts *OutboxGetHandlerOptions) (http.Handler, error) {
 
+	page_size := opts.PageSize
+
+	if page_size <= 0 {
+		page_size = OUTBOX_PAGE_SIZE
+	}
+
 	fn := func(rsp http.ResponseWriter, req *http.Request) {
 
 		ctx := req.Context()
@@ -79,19 +108,157 @@ func OutboxGetHandler(opts *OutboxGetHandlerOptions) (http.Handler, error) {
 
 		outbox_url := acct.OutboxURL(ctx, opts.URIs)
 
-		col := &ap.OrderedCollection{
-			Context: []interface{}{
-				"https://www.w3.org/ns/activitystreams",
-			},
-			Id:         outbox_url.String(),
-			Type:       "OrderedCollection",
-			TotalItems: 0,
+		var rsp_body interface{}
+
+		q := req.URL.Query()
+
+		if !q.Has("page") && !q.Has("max_id") && !q.Has("min_id") {
+
+			count := int64(0)
+
+			if opts.ActivitiesDatabase != nil {
+
+				count, err = opts.ActivitiesDatabase.CountActivitiesForAccount(ctx, acct.Id, outboxActivityTypes)
+
+				if err != nil {
+					logger.Error("Failed to count activities for account", "error", err)
+					http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+					return
+				}
+			}
+
+			col := &ap.OrderedCollection{
+				Context: []interface{}{
+					"https://www.w3.org/ns/activitystreams",
+				},
+				Id:         outbox_url.String(),
+				Type:       "OrderedCollection",
+				TotalItems: int(count),
+			}
+
+			if count > 0 {
+				col.First = outboxPageURL(outbox_url, "", 0)
+				col.Last = outboxPageURL(outbox_url, "min_id", 0)
+			}
+
+			rsp_body = col
+
+		} else {
+
+			cursor := ""
+			cursor_id := int64(0)
+
+			for _, k := range []string{"max_id", "min_id"} {
+
+				if !q.Has(k) {
+					continue
+				}
+
+				id, err := strconv.ParseInt(q.Get(k), 10, 64)
+
+				if err != nil {
+					logger.Error("Invalid cursor", "cursor", k, "error", err)
+					http.Error(rsp, "Bad request", http.StatusBadRequest)
+					return
+				}
+
+				cursor = k
+				cursor_id = id
+				break
+			}
+
+			activities_cursor := &database.ActivitiesCursor{
+				ActivityTypes: outboxActivityTypes,
+				Limit:         page_size,
+			}
+
+			switch cursor {
+			case "max_id":
+				activities_cursor.MaxId = cursor_id
+			case "min_id":
+				activities_cursor.MinId = cursor_id
+				activities_cursor.Oldest = true
+			}
+
+			activities := make([]*activitypub.Activity, 0)
+
+			if opts.ActivitiesDatabase != nil {
+
+				activities, err = outboxActivities(ctx, opts.ActivitiesDatabase, acct.Id, activities_cursor)
+
+				if err != nil {
+					logger.Error("Failed to retrieve activities for account", "error", err)
+					http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+					return
+				}
+			}
+
+			items := make([]interface{}, 0)
+
+			for _, a := range activities {
+				items = append(items, json.RawMessage(a.Body))
+			}
+
+			page := &ap.OrderedCollectionPage{
+				Context: []interface{}{
+					"https://www.w3.org/ns/activitystreams",
+				},
+				Id:           outboxPageURL(outbox_url, cursor, cursor_id),
+				Type:         "OrderedCollectionPage",
+				PartOf:       outbox_url.String(),
+				OrderedItems: items,
+			}
+
+			if len(activities) > 0 {
+
+				newest := activities[0]
+				oldest := activities[len(activities)-1]
+
+				older_cursor := &database.ActivitiesCursor{
+					ActivityTypes: outboxActivityTypes,
+					MaxId:         oldest.Id,
+					Limit:         1,
+				}
+
+				older, err := outboxActivities(ctx, opts.ActivitiesDatabase, acct.Id, older_cursor)
+
+				if err != nil {
+					logger.Error("Failed to determine older activities for account", "error", err)
+					http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+					return
+				}
+
+				if len(older) > 0 {
+					page.Next = outboxPageURL(outbox_url, "max_id", oldest.Id)
+				}
+
+				newer_cursor := &database.ActivitiesCursor{
+					ActivityTypes: outboxActivityTypes,
+					MinId:         newest.Id,
+					Oldest:        true,
+					Limit:         1,
+				}
+
+				newer, err := outboxActivities(ctx, opts.ActivitiesDatabase, acct.Id, newer_cursor)
+
+				if err != nil {
+					logger.Error("Failed to determine newer activities for account", "error", err)
+					http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+					return
+				}
+
+				if len(newer) > 0 {
+					page.Prev = outboxPageURL(outbox_url, "min_id", newest.Id)
+				}
+			}
+
+			rsp_body = page
 		}
 
 		rsp.Header().Set("Content-type", ap.ACTIVITY_CONTENT_TYPE)
 
 		enc := json.NewEncoder(rsp)
-		err = enc.Encode(col)
+		err = enc.Encode(rsp_body)
 
 		if err != nil {
 			logger.Error("Failed to encode collection resource", "error", err)
@@ -104,3 +271,51 @@ func OutboxGetHandler(opts *OutboxGetHandlerOptions) (http.Handler, error) {
 
 	return http.HandlerFunc(fn), nil
 }
+
+// outboxActivities returns the list of activities delivered by 'account_id' for the page defined by 'cursor', newest first.
+func outboxActivities(ctx context.Context, activities_db database.ActivitiesDatabase, account_id int64, cursor *database.ActivitiesCursor) ([]*activitypub.Activity, error) {
+
+	activities := make([]*activitypub.Activity, 0)
+
+	cb := func(ctx context.Context, a *activitypub.Activity) error {
+		activities = append(activities, a)
+		return nil
+	}
+
+	err := activities_db.GetActivitiesForAccountWithCursor(ctx, account_id, cursor, cb)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve activities, %w", err)
+	}
+
+	return activities, nil
+}
+
+// isPublicActivity returns a boolean value indicating whether 'activity' is addressed to the public collection.
+func isPublicActivity(activity *ap.Activity) bool {
+
+	for _, addr := range []string{ap.ACTIVITYSTREAMS_CONTEXT_PUBLIC, "as:Public", "Public"} {
+
+		if slices.Contains(activity.To, addr) || slices.Contains(activity.Cc, addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// outboxPageURL returns the URL for a page in the outbox at 'outbox_url' for 'cursor'.
+func outboxPageURL(outbox_url *url.URL, cursor string, cursor_id int64) string {
+
+	q := url.Values{}
+	q.Set("page", "true")
+
+	if cursor != "" {
+		q.Set(cursor, strconv.FormatInt(cursor_id, 10))
+	}
+
+	u := *outbox_url
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
@@ -0,0 +1,158 @@
+package www
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/uris"
+)
+
+func TestOutboxGetHandler(t *testing.T) {
+
+	uris_table := uris.DefaultURIs()
+	uris_table.Hostname = "example.com"
+
+	acct := &activitypub.Account{
+		Id:   1234,
+		Name: "alice",
+	}
+
+	activities_db := &testActivitiesDatabase{
+		activities: make([]*activitypub.Activity, 0),
+	}
+
+	for id := int64(1); id <= 10; id++ {
+
+		activity_type := activitypub.PostActivityType
+
+		// Likes are not addressed to the public so are not listed in the outbox
+
+		if id == 3 || id == 8 {
+			activity_type = activitypub.LikeActivityType
+		}
+
+		a := &activitypub.Activity{
+			Id:           id,
+			AccountId:    acct.Id,
+			ActivityType: activity_type,
+			Body:         fmt.Sprintf(`{"id":"https://example.com/activities/%d"}`, id),
+		}
+
+		activities_db.activities = append(activities_db.activities, a)
+	}
+
+	opts := &OutboxGetHandlerOptions{
+		AccountsDatabase:   &testAccountsDatabase{account: acct},
+		ActivitiesDatabase: activities_db,
+		URIs:               uris_table,
+		PageSize:           3,
+	}
+
+	h, err := OutboxGetHandler(opts)
+
+	if err != nil {
+		t.Fatalf("Failed to create outbox handler, %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/ap/alice/outbox", nil)
+	req.SetPathValue("resource", "alice")
+	req.Header.Set("Accept", ap.ACTIVITYSTREAMS_ACCEPT_HEADER)
+
+	rsp := httptest.NewRecorder()
+	h.ServeHTTP(rsp, req)
+
+	if rsp.Code != http.StatusOK {
+		t.Fatalf("Unexpected status for outbox, %d", rsp.Code)
+	}
+
+	var col *ap.OrderedCollection
+
+	err = json.Unmarshal(rsp.Body.Bytes(), &col)
+
+	if err != nil {
+		t.Fatalf("Failed to unmarshal outbox, %v", err)
+	}
+
+	if col.TotalItems != 8 {
+		t.Fatalf("Unexpected total items, expected 8 but got %d", col.TotalItems)
+	}
+
+	tests := []struct {
+		Query string
+		Items []int64
+		Next  bool
+		Prev  bool
+	}{
+		{"page=true", []int64{10, 9, 7}, true, false},
+		{"page=true&max_id=7", []int64{6, 5, 4}, true, true},
+		{"page=true&max_id=4", []int64{2, 1}, false, true},
+		{"page=true&max_id=1", []int64{}, false, false},
+		{"page=true&min_id=0", []int64{4, 2, 1}, false, true},
+		{"page=true&min_id=7", []int64{10, 9}, true, false},
+	}
+
+	for _, test := range tests {
+
+		req := httptest.NewRequest(http.MethodGet, "/ap/alice/outbox?"+test.Query, nil)
+		req.SetPathValue("resource", "alice")
+		req.Header.Set("Accept", ap.ACTIVITYSTREAMS_ACCEPT_HEADER)
+
+		rsp := httptest.NewRecorder()
+		h.ServeHTTP(rsp, req)
+
+		if rsp.Code != http.StatusOK {
+			t.Fatalf("Unexpected status for %s, %d", test.Query, rsp.Code)
+		}
+
+		var page *ap.OrderedCollectionPage
+
+		err := json.Unmarshal(rsp.Body.Bytes(), &page)
+
+		if err != nil {
+			t.Fatalf("Failed to unmarshal page for %s, %v", test.Query, err)
+		}
+
+		items := make([]int64, 0)
+
+		for _, i := range page.OrderedItems {
+
+			var id int64
+			_, err := fmt.Sscanf(i.(map[string]interface{})["id"].(string), "https://example.com/activities/%d", &id)
+
+			if err != nil {
+				t.Fatalf("Failed to parse item for %s, %v", test.Query, err)
+			}
+
+			items = append(items, id)
+		}
+
+		if !slices.Equal(items, test.Items) {
+			t.Fatalf("Unexpected items for %s, %v", test.Query, items)
+		}
+
+		if (page.Next != "") != test.Next || (page.Prev != "") != test.Prev {
+			t.Fatalf("Unexpected next (%s) or prev (%s) for %s", page.Next, page.Prev, test.Query)
+		}
+	}
+}
+
+func TestIsPublicActivity(t *testing.T) {
+
+	if !isPublicActivity(&ap.Activity{To: []string{ap.ACTIVITYSTREAMS_CONTEXT_PUBLIC}}) {
+		t.Fatalf("Expected activity addressed to public collection to be public")
+	}
+
+	if !isPublicActivity(&ap.Activity{Cc: []string{"as:Public"}}) {
+		t.Fatalf("Expected activity copied to public collection to be public")
+	}
+
+	if isPublicActivity(&ap.Activity{To: []string{"https://example.com/ap/@alice"}}) {
+		t.Fatalf("Expected direct activity not to be public")
+	}
+}
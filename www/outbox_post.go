@@ -1,25 +1,53 @@
 package www
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
-	"github.com/aaronland/go-http/v3/slog"
+	aa_slog "github.com/aaronland/go-http/v3/slog"
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/ap"
 	"github.com/sfomuseum/go-activitypub/database"
+	"github.com/sfomuseum/go-activitypub/id"
+	"github.com/sfomuseum/go-activitypub/posts"
+	"github.com/sfomuseum/go-activitypub/queue"
 	"github.com/sfomuseum/go-activitypub/uris"
 )
 
 type OutboxPostHandlerOptions struct {
-	AccountsDatabase database.AccountsDatabase
-	PostsDatabase    database.PostsDatabase
-	URIs             *uris.URIs
+	AccountsDatabase     database.AccountsDatabase
+	AccessTokensDatabase database.AccessTokensDatabase
+	ActivitiesDatabase   database.ActivitiesDatabase
+	PostsDatabase        database.PostsDatabase
+	PostTagsDatabase     database.PostTagsDatabase
+	FollowersDatabase    database.FollowersDatabase
+	FollowingDatabase    database.FollowingDatabase
+	DeliveriesDatabase   database.DeliveriesDatabase
+	DomainBlocksDatabase database.DomainBlocksDatabase
+	DomainAllowsDatabase database.DomainAllowsDatabase
+	DeliveryQueue        queue.DeliveryQueue
+	MaxAttempts          int
+	URIs                 *uris.URIs
 }
 
+// OutboxPostHandler returns a `http.Handler` for client-to-server (C2S) requests posting ActivityStreams objects to an account's
+// outbox. Requests must be authenticated with a "Bearer" access token belonging to the account. Supported objects are "Note" (or
+// "Create" activities wrapping a "Note"), "Like", "Announce" and "Follow". The server assigns new IDs to each activity, stores it in
+// the activities database and delivers it: posts (notes) and boosts (announcements) are delivered to the account's followers while
+// likes and follows are delivered to the inbox of the actor being liked or followed. The response has a HTTP 201 Created status and
+// a "Location" header containing the ID of the new activity.
 func OutboxPostHandler(opts *OutboxPostHandlerOptions) (http.Handler, error) {
 
 	fn := func(rsp http.ResponseWriter, req *http.Request) {
 
-		logger := slog.LoggerWithRequest(req, nil)
+		ctx := req.Context()
+
+		logger := aa_slog.LoggerWithRequest(req, nil)
 
 		t1 := time.Now()
 
@@ -27,22 +55,551 @@ func OutboxPostHandler(opts *OutboxPostHandlerOptions) (http.Handler, error) {
 			logger.Info("Time to serve request", "ms", time.Since(t1).Milliseconds())
 		}()
 
-		if req.Method != http.MethodGet {
+		if req.Method != http.MethodPost {
 			logger.Error("Method not allowed")
 			http.Error(rsp, "Method not allowed", http.StatusMethodNotAllowed)
 			return
 		}
 
-		if !IsActivityStreamRequest(req, "Content-Type") {
-			logger.Error("Not activitystream request")
+		account_name, host, err := ap.ParseAddressFromRequest(req)
+
+		if err != nil {
+			logger.Error("Failed to parse address from request", "error", err)
 			http.Error(rsp, "Bad request", http.StatusBadRequest)
 			return
 		}
 
-		logger.Error("Forbidden")
-		http.Error(rsp, "Forbidden", http.StatusForbidden)
+		logger = logger.With("account name", account_name)
+
+		if host != "" && host != opts.URIs.Hostname {
+			logger.Error("Resouce has bunk hostname", "host", host)
+			http.Error(rsp, "Not found", http.StatusNotFound)
+			return
+		}
+
+		// Authenticate the request before doing anything else
+
+		token, status, err := outboxAccessToken(ctx, opts, req)
+
+		if err != nil {
+
+			logger.Error("Failed to authenticate request", "error", err)
+
+			if status == http.StatusUnauthorized {
+				rsp.Header().Set("WWW-Authenticate", `Bearer realm="outbox"`)
+				http.Error(rsp, "Unauthorized", status)
+				return
+			}
+
+			http.Error(rsp, "Internal server error", status)
+			return
+		}
+
+		logger = logger.With("access token id", token.Id)
+
+		acct, err := opts.AccountsDatabase.GetAccountWithName(ctx, account_name)
+
+		if err != nil {
+
+			logger.Error("Failed to retrieve account", "error", err)
+
+			if err == activitypub.ErrNotFound {
+				http.Error(rsp, "Not found", http.StatusNotFound)
+				return
+			}
+
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		logger = logger.With("account id", acct.Id)
+
+		if token.AccountId != acct.Id {
+			logger.Error("Access token does not belong to account", "token account id", token.AccountId)
+			http.Error(rsp, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		ap_activity, body, status, err := readInboxActivity(req, logger)
+
+		if err != nil {
+			logger.Error("Failed to read activity", "error", err)
+			http.Error(rsp, http.StatusText(status), status)
+			return
+		}
+
+		logger = logger.With("type", ap_activity.Type)
+
+		var activity *activitypub.Activity
+
+		switch ap_activity.Type {
+		case "Note":
+			activity, status, err = outboxCreateNote(ctx, opts, acct, body, logger)
+		case ap.CREATE_ACTIVITY:
+
+			// The client's "Create" activity is replaced by one derived from the new post
+
+			if !outboxIsPublic(ap_activity.To, ap_activity.Cc) {
+				status = http.StatusBadRequest
+				err = fmt.Errorf("Only public notes are supported")
+				break
+			}
+
+			var enc_obj []byte
+			enc_obj, err = json.Marshal(ap_activity.Object)
+
+			if err != nil {
+				status = http.StatusBadRequest
+				break
+			}
+
+			activity, status, err = outboxCreateNote(ctx, opts, acct, enc_obj, logger)
+
+		case "Like":
+			activity, status, err = outboxLike(ctx, opts, acct, ap_activity, logger)
+		case "Announce":
+			activity, status, err = outboxAnnounce(ctx, opts, acct, ap_activity, logger)
+		case ap.FOLLOW_ACTIVITY:
+			activity, status, err = outboxFollow(ctx, opts, acct, ap_activity, logger)
+		default:
+			status = http.StatusBadRequest
+			err = fmt.Errorf("Unsupported activity type")
+		}
+
+		if err != nil {
+			logger.Error("Failed to process outbox activity", "error", err)
+			http.Error(rsp, http.StatusText(status), status)
+			return
+		}
+
+		logger.Info("Processed outbox activity", "activity id", activity.Id)
+
+		rsp.Header().Set("Location", activity.ActivityPubId)
+		rsp.Header().Set("Content-type", ap.ACTIVITY_CONTENT_TYPE)
+		rsp.WriteHeader(http.StatusCreated)
+
+		rsp.Write([]byte(activity.Body))
 		return
 	}
 
 	return http.HandlerFunc(fn), nil
 }
+
+// outboxAccessToken returns the `activitypub.AccessToken` instance matching the "Bearer" token in the
+// "Authorization" header of 'req'. If there is an error the HTTP status code to return is also included.
+func outboxAccessToken(ctx context.Context, opts *OutboxPostHandlerOptions, req *http.Request) (*activitypub.AccessToken, int, error) {
+
+	auth := req.Header.Get("Authorization")
+
+	scheme, token, ok := strings.Cut(auth, " ")
+
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return nil, http.StatusUnauthorized, fmt.Errorf("Missing or invalid authorization header")
+	}
+
+	token = strings.TrimSpace(token)
+
+	if token == "" {
+		return nil, http.StatusUnauthorized, fmt.Errorf("Missing access token")
+	}
+
+	t, err := opts.AccessTokensDatabase.GetAccessTokenWithToken(ctx, activitypub.HashAccessToken(token))
+
+	if err != nil {
+
+		if err == activitypub.ErrNotFound {
+			return nil, http.StatusUnauthorized, fmt.Errorf("Invalid access token")
+		}
+
+		return nil, http.StatusInternalServerError, fmt.Errorf("Failed to retrieve access token, %w", err)
+	}
+
+	return t, 0, nil
+}
+
+// outboxCreateNote adds a new post for the JSON-encoded "Note" object in 'enc_note' and delivers the corresponding
+// "Create" activity to the followers of 'acct'. Posts are always public so notes whose "to" and "cc" properties are
+// not addressed to the public collection are rejected. Properties other than the note's content and "inReplyTo" are ignored.
+func outboxCreateNote(ctx context.Context, opts *OutboxPostHandlerOptions, acct *activitypub.Account, enc_note []byte, logger *slog.Logger) (*activitypub.Activity, int, error) {
+
+	var note *ap.Note
+
+	err := json.Unmarshal(enc_note, &note)
+
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("Failed to decode note, %w", err)
+	}
+
+	if note == nil || note.Type != "Note" {
+		return nil, http.StatusBadRequest, fmt.Errorf("Unsupported object type")
+	}
+
+	if !outboxIsPublic(note.To, note.Cc) {
+		return nil, http.StatusBadRequest, fmt.Errorf("Only public notes are supported")
+	}
+
+	content := strings.TrimSpace(note.Content)
+
+	if content == "" {
+		return nil, http.StatusBadRequest, fmt.Errorf("Note is missing content")
+	}
+
+	post_opts := &posts.AddPostOptions{
+		URIs:             opts.URIs,
+		PostsDatabase:    opts.PostsDatabase,
+		PostTagsDatabase: opts.PostTagsDatabase,
+	}
+
+	post, mentions, err := posts.AddPost(ctx, post_opts, acct, content)
+
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("Failed to add post, %w", err)
+	}
+
+	logger.Debug("Added post", "post id", post.Id)
+
+	if note.InReplyTo != "" {
+
+		post.InReplyTo = note.InReplyTo
+
+		err = opts.PostsDatabase.UpdatePost(ctx, post)
+
+		if err != nil {
+			return nil, http.StatusInternalServerError, fmt.Errorf("Failed to update post, %w", err)
+		}
+	}
+
+	ap_activity, err := posts.ActivityFromPost(ctx, opts.URIs, acct, post, mentions, nil)
+
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("Failed to create new (create) activity, %w", err)
+	}
+
+	activity, err := outboxAddActivity(ctx, opts, acct, ap_activity, activitypub.PostActivityType, post.Id)
+
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	err = outboxDeliverActivity(ctx, opts, activity, mentions)
+
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return activity, 0, nil
+}
+
+// outboxAnnounce creates a new "Announce" (boost) activity for the object of 'ap_activity' and delivers it to the followers of 'acct'.
+func outboxAnnounce(ctx context.Context, opts *OutboxPostHandlerOptions, acct *activitypub.Account, ap_activity *ap.Activity, logger *slog.Logger) (*activitypub.Activity, int, error) {
+
+	object_uri, ok := outboxObjectURI(ap_activity.Object)
+
+	if !ok {
+		return nil, http.StatusBadRequest, fmt.Errorf("Announce activity is missing object")
+	}
+
+	from := acct.AccountURL(ctx, opts.URIs).String()
+
+	boost_activity, err := ap.NewBoostActivityForNote(ctx, opts.URIs, from, object_uri)
+
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("Failed to create new boost activity, %w", err)
+	}
+
+	// Like app/boost/note boosts are assigned their own ID since there is no local boost record
+
+	boost_id, err := id.NewId()
+
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("Failed to create new boost ID, %w", err)
+	}
+
+	activity, err := outboxAddActivity(ctx, opts, acct, boost_activity, activitypub.BoostActivityType, boost_id)
+
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	err = outboxDeliverActivity(ctx, opts, activity, nil)
+
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return activity, 0, nil
+}
+
+// outboxLike creates a new "Like" activity for the object (any of the types in `ap.NoteObjectTypes`) of 'ap_activity' and delivers it to the inbox of the actor the object is attributed to.
+func outboxLike(ctx context.Context, opts *OutboxPostHandlerOptions, acct *activitypub.Account, ap_activity *ap.Activity, logger *slog.Logger) (*activitypub.Activity, int, error) {
+
+	object_uri, ok := outboxObjectURI(ap_activity.Object)
+
+	if !ok {
+		return nil, http.StatusBadRequest, fmt.Errorf("Like activity is missing object")
+	}
+
+	note, err := ap.RetrieveNote(ctx, object_uri)
+
+	if err != nil {
+		return nil, http.StatusBadGateway, fmt.Errorf("Failed to retrieve note, %w", err)
+	}
+
+	if note.AttributedTo == "" {
+		return nil, http.StatusBadGateway, fmt.Errorf("Note is not attributed to an actor")
+	}
+
+	author, err := ap.RetrieveActorWithProfileURL(ctx, note.AttributedTo)
+
+	if err != nil {
+		return nil, http.StatusBadGateway, fmt.Errorf("Failed to retrieve author (actor) for note, %w", err)
+	}
+
+	author_address, err := author.Address()
+
+	if err != nil {
+		return nil, http.StatusBadGateway, fmt.Errorf("Failed to derive address for author, %w", err)
+	}
+
+	from := acct.AccountURL(ctx, opts.URIs).String()
+
+	like_activity, err := ap.NewLikeActivity(ctx, opts.URIs, from, object_uri)
+
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("Failed to create new like activity, %w", err)
+	}
+
+	like_id, err := id.NewId()
+
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("Failed to create new like ID, %w", err)
+	}
+
+	activity, err := outboxAddActivity(ctx, opts, acct, like_activity, activitypub.LikeActivityType, like_id)
+
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	err = outboxDeliverActivityToRecipients(ctx, opts, activity, []string{author_address})
+
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	logger.Debug("Scheduled like activity for delivery", "recipient", author_address)
+	return activity, 0, nil
+}
+
+// outboxFollow creates a new "Follow" activity for the actor (an address or profile URL) in the object of 'ap_activity', registers a
+// pending following record for 'acct' and delivers the activity to the inbox of the actor being followed.
+func outboxFollow(ctx context.Context, opts *OutboxPostHandlerOptions, acct *activitypub.Account, ap_activity *ap.Activity, logger *slog.Logger) (*activitypub.Activity, int, error) {
+
+	object_uri, ok := outboxObjectURI(ap_activity.Object)
+
+	if !ok {
+		return nil, http.StatusBadRequest, fmt.Errorf("Follow activity is missing object")
+	}
+
+	var following_address string
+
+	if strings.HasPrefix(object_uri, "https://") || strings.HasPrefix(object_uri, "http://") {
+
+		following_actor, err := ap.RetrieveActorWithProfileURL(ctx, object_uri)
+
+		if err != nil {
+			return nil, http.StatusBadGateway, fmt.Errorf("Failed to retrieve actor for %s, %w", object_uri, err)
+		}
+
+		following_address, err = following_actor.Address()
+
+		if err != nil {
+			return nil, http.StatusInternalServerError, fmt.Errorf("Failed to derive address for %s, %w", object_uri, err)
+		}
+
+	} else {
+
+		following_address = strings.TrimLeft(object_uri, "@")
+
+		// Ensure the actor exists before registering a following record
+
+		_, err := ap.RetrieveActor(ctx, following_address, opts.URIs.Insecure)
+
+		if err != nil {
+			return nil, http.StatusBadGateway, fmt.Errorf("Failed to retrieve actor for %s, %w", following_address, err)
+		}
+	}
+
+	logger = logger.With("following", following_address)
+
+	follower_address := acct.Address(opts.URIs.Hostname)
+
+	follow_activity, err := ap.NewFollowActivity(ctx, opts.URIs, follower_address, following_address)
+
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("Failed to create follow activity, %w", err)
+	}
+
+	// Register (or update) the following record, in a pending state, before the follow
+	// activity is delivered since the remote server may respond with an "Accept" activity
+	// before delivery completes (see app/follow).
+
+	f, err := opts.FollowingDatabase.GetFollowing(ctx, acct.Id, following_address)
+
+	switch {
+	case err == activitypub.ErrNotFound:
+
+		f, err = activitypub.NewFollowing(ctx, acct.Id, following_address)
+
+		if err != nil {
+			return nil, http.StatusInternalServerError, fmt.Errorf("Failed to create new following, %w", err)
+		}
+
+		f.ActivityId = follow_activity.Id
+
+		err = opts.FollowingDatabase.AddFollowing(ctx, f)
+
+		if err != nil {
+			return nil, http.StatusInternalServerError, fmt.Errorf("Failed to add following, %w", err)
+		}
+
+	case err != nil:
+		return nil, http.StatusInternalServerError, fmt.Errorf("Failed to retrieve following, %w", err)
+	default:
+
+		if f.IsAccepted() {
+			return nil, http.StatusConflict, fmt.Errorf("Already following %s", following_address)
+		}
+
+		f.ActivityId = follow_activity.Id
+		f.State = activitypub.PendingFollowingState
+		f.LastModified = time.Now().Unix()
+
+		err = opts.FollowingDatabase.UpdateFollowing(ctx, f)
+
+		if err != nil {
+			return nil, http.StatusInternalServerError, fmt.Errorf("Failed to update following, %w", err)
+		}
+	}
+
+	// The following record isn't used as the activity type ID since a pending follow may be sent more than once
+
+	follow_id, err := id.NewId()
+
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("Failed to create new follow ID, %w", err)
+	}
+
+	activity, err := outboxAddActivity(ctx, opts, acct, follow_activity, activitypub.FollowActivityType, follow_id)
+
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	err = outboxDeliverActivityToRecipients(ctx, opts, activity, []string{following_address})
+
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	logger.Debug("Scheduled follow activity for delivery")
+	return activity, 0, nil
+}
+
+// outboxAddActivity creates a new `activitypub.Activity` record for 'ap_activity' and adds it to the activities database.
+func outboxAddActivity(ctx context.Context, opts *OutboxPostHandlerOptions, acct *activitypub.Account, ap_activity *ap.Activity, activity_type activitypub.ActivityType, activity_type_id int64) (*activitypub.Activity, error) {
+
+	activity, err := activitypub.NewActivity(ctx, ap_activity)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create new activity, %w", err)
+	}
+
+	activity.ActivityType = activity_type
+	activity.ActivityTypeId = activity_type_id
+	activity.AccountId = acct.Id
+
+	err = opts.ActivitiesDatabase.AddActivity(ctx, activity)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to add activity, %w", err)
+	}
+
+	return activity, nil
+}
+
+// outboxDeliverActivity delivers 'activity' to the followers of the account that created it (and any accounts in 'mentions').
+func outboxDeliverActivity(ctx context.Context, opts *OutboxPostHandlerOptions, activity *activitypub.Activity, mentions []*activitypub.PostTag) error {
+
+	deliver_opts := &queue.DeliverActivityToFollowersOptions{
+		AccountsDatabase:     opts.AccountsDatabase,
+		FollowersDatabase:    opts.FollowersDatabase,
+		DeliveriesDatabase:   opts.DeliveriesDatabase,
+		DeliveryQueue:        opts.DeliveryQueue,
+		Activity:             activity,
+		Mentions:             mentions,
+		URIs:                 opts.URIs,
+		MaxAttempts:          opts.MaxAttempts,
+		DomainBlocksDatabase: opts.DomainBlocksDatabase,
+		DomainAllowsDatabase: opts.DomainAllowsDatabase,
+	}
+
+	err := queue.DeliverActivityToFollowers(ctx, deliver_opts)
+
+	if err != nil {
+		return fmt.Errorf("Failed to deliver activity, %w", err)
+	}
+
+	return nil
+}
+
+// outboxDeliverActivityToRecipients delivers 'activity' to each of the addresses in 'recipients' rather than the followers of the account that created it.
+func outboxDeliverActivityToRecipients(ctx context.Context, opts *OutboxPostHandlerOptions, activity *activitypub.Activity, recipients []string) error {
+
+	deliver_opts := &queue.DeliverActivityToRecipientsOptions{
+		AccountsDatabase:     opts.AccountsDatabase,
+		DeliveriesDatabase:   opts.DeliveriesDatabase,
+		DeliveryQueue:        opts.DeliveryQueue,
+		Activity:             activity,
+		Recipients:           recipients,
+		URIs:                 opts.URIs,
+		MaxAttempts:          opts.MaxAttempts,
+		DomainBlocksDatabase: opts.DomainBlocksDatabase,
+		DomainAllowsDatabase: opts.DomainAllowsDatabase,
+	}
+
+	err := queue.DeliverActivityToRecipients(ctx, deliver_opts)
+
+	if err != nil {
+		return fmt.Errorf("Failed to deliver activity, %w", err)
+	}
+
+	return nil
+}
+
+// outboxIsPublic returns a boolean value indicating whether an object posted to an outbox with the addresses in 'to' and 'cc' is public.
+// Objects without any addresses are considered public since they are assigned the default (public) addresses for new posts.
+func outboxIsPublic(to []string, cc []string) bool {
+
+	if len(to) == 0 && len(cc) == 0 {
+		return true
+	}
+
+	return isPublicActivity(&ap.Activity{To: to, Cc: cc})
+}
+
+// outboxObjectURI returns the URI for the object of an activity posted to an outbox which may be a string
+// or an embedded object with an "id" property.
+func outboxObjectURI(v interface{}) (string, bool) {
+
+	switch v.(type) {
+	case string:
+		uri := v.(string)
+		return uri, uri != ""
+	case map[string]interface{}:
+		uri, ok := v.(map[string]interface{})["id"].(string)
+		return uri, ok && uri != ""
+	default:
+		return "", false
+	}
+}
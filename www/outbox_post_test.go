@@ -0,0 +1,164 @@
+package www
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/uris"
+)
+
+func TestOutboxPostHandlerAuthentication(t *testing.T) {
+
+	ctx := context.Background()
+
+	uris_table := uris.DefaultURIs()
+	uris_table.Hostname = "example.com"
+
+	alice := &activitypub.Account{
+		Id:   1234,
+		Name: "alice",
+	}
+
+	bob := &activitypub.Account{
+		Id:   5678,
+		Name: "bob",
+	}
+
+	alice_token, alice_secret, err := activitypub.NewAccessToken(ctx, alice)
+
+	if err != nil {
+		t.Fatalf("Failed to create access token, %v", err)
+	}
+
+	bob_token, bob_secret, err := activitypub.NewAccessToken(ctx, bob)
+
+	if err != nil {
+		t.Fatalf("Failed to create access token, %v", err)
+	}
+
+	if alice_token.Token == alice_secret {
+		t.Fatalf("Expected access token to be hashed")
+	}
+
+	tokens_db := &testAccessTokensDatabase{
+		tokens: map[string]*activitypub.AccessToken{
+			alice_token.Token: alice_token,
+			bob_token.Token:   bob_token,
+		},
+	}
+
+	opts := &OutboxPostHandlerOptions{
+		AccountsDatabase:     &testAccountsDatabase{account: alice},
+		AccessTokensDatabase: tokens_db,
+		URIs:                 uris_table,
+	}
+
+	h, err := OutboxPostHandler(opts)
+
+	if err != nil {
+		t.Fatalf("Failed to create outbox handler, %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("POST "+uris_table.Outbox, h)
+
+	tests := []struct {
+		Description   string
+		Authorization string
+		Status        int
+	}{
+		{"missing token", "", http.StatusUnauthorized},
+		{"wrong scheme", "Basic " + alice_secret, http.StatusUnauthorized},
+		{"unknown token", "Bearer bunk", http.StatusUnauthorized},
+		{"hashed token", "Bearer " + alice_token.Token, http.StatusUnauthorized},
+		{"other account", "Bearer " + bob_secret, http.StatusForbidden},
+		// Authenticated but "Question" objects aren't supported
+		{"valid token", "Bearer " + alice_secret, http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+
+		body := strings.NewReader(`{"type":"Question","content":"Hello world"}`)
+
+		req := httptest.NewRequest(http.MethodPost, "/ap/alice/outbox", body)
+		req.Header.Set("Content-Type", "application/activity+json")
+
+		if test.Authorization != "" {
+			req.Header.Set("Authorization", test.Authorization)
+		}
+
+		rsp := httptest.NewRecorder()
+		mux.ServeHTTP(rsp, req)
+
+		if rsp.Code != test.Status {
+			t.Fatalf("Unexpected status for %s, expected %d but got %d", test.Description, test.Status, rsp.Code)
+		}
+
+		if test.Status == http.StatusUnauthorized && rsp.Header().Get("WWW-Authenticate") == "" {
+			t.Fatalf("Expected WWW-Authenticate header for %s", test.Description)
+		}
+	}
+}
+
+func TestOutboxPostHandlerNonPublicNote(t *testing.T) {
+
+	ctx := context.Background()
+
+	uris_table := uris.DefaultURIs()
+	uris_table.Hostname = "example.com"
+
+	alice := &activitypub.Account{
+		Id:   1234,
+		Name: "alice",
+	}
+
+	alice_token, alice_secret, err := activitypub.NewAccessToken(ctx, alice)
+
+	if err != nil {
+		t.Fatalf("Failed to create access token, %v", err)
+	}
+
+	opts := &OutboxPostHandlerOptions{
+		AccountsDatabase: &testAccountsDatabase{account: alice},
+		AccessTokensDatabase: &testAccessTokensDatabase{
+			tokens: map[string]*activitypub.AccessToken{
+				alice_token.Token: alice_token,
+			},
+		},
+		URIs: uris_table,
+	}
+
+	h, err := OutboxPostHandler(opts)
+
+	if err != nil {
+		t.Fatalf("Failed to create outbox handler, %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("POST "+uris_table.Outbox, h)
+
+	tests := map[string]string{
+		"followers only note":   `{"type":"Note","to":["https://example.com/ap/alice/followers"],"content":"Hello world"}`,
+		"direct note":           `{"type":"Note","to":["https://social.example/users/bob"],"cc":[],"content":"Hello world"}`,
+		"followers only create": `{"type":"Create","to":["https://example.com/ap/alice/followers"],"object":{"type":"Note","content":"Hello world"}}`,
+		"direct create object":  `{"type":"Create","object":{"type":"Note","to":["https://social.example/users/bob"],"content":"Hello world"}}`,
+	}
+
+	for label, enc_body := range tests {
+
+		req := httptest.NewRequest(http.MethodPost, "/ap/alice/outbox", strings.NewReader(enc_body))
+		req.Header.Set("Content-Type", "application/activity+json")
+		req.Header.Set("Authorization", "Bearer "+alice_secret)
+
+		rsp := httptest.NewRecorder()
+		mux.ServeHTTP(rsp, req)
+
+		if rsp.Code != http.StatusBadRequest {
+			t.Fatalf("Unexpected status for %s, expected %d but got %d", label, http.StatusBadRequest, rsp.Code)
+		}
+	}
+}
@@ -15,6 +15,9 @@ import (
 	"github.com/sfomuseum/go-activitypub"
 	"github.com/sfomuseum/go-activitypub/ap"
 	"github.com/sfomuseum/go-activitypub/database"
+	"github.com/sfomuseum/go-activitypub/media"
+	"github.com/sfomuseum/go-activitypub/posts"
+	"github.com/sfomuseum/go-activitypub/stats"
 	"github.com/sfomuseum/go-activitypub/uris"
 )
 
@@ -22,17 +25,26 @@ type PostHandlerOptions struct {
 	AccountsDatabase database.AccountsDatabase
 	PostsDatabase    database.PostsDatabase
 	PostTagsDatabase database.PostTagsDatabase
-	URIs             *uris.URIs
-	Templates        *template.Template
+	LikesDatabase    database.LikesDatabase
+	// TombstonesDatabase is used to determine whether a post that can not be found has been deleted, in which
+	// case a "410 Gone" response (with a `Tombstone` object for ActivityStreams requests) is returned. Optional.
+	TombstonesDatabase database.TombstonesDatabase
+	// MediaDatabase is used to include media attachments with posts. Optional.
+	MediaDatabase database.MediaDatabase
+	URIs          *uris.URIs
+	Templates     *template.Template
 }
 
 type PostHandlerVars struct {
-	Post       *activitypub.Post
-	PostBody   template.HTML
-	Account    *activitypub.Account
-	AccountURL string
-	PostURL    string
-	IconURL    string
+	Post        *activitypub.Post
+	PostBody    template.HTML
+	Account     *activitypub.Account
+	AccountURL  string
+	PostURL     string
+	IconURL     string
+	Likes       int64
+	Reactions   []*stats.ReactionCount
+	Attachments []*ap.Attachment
 }
 
 func PostHandler(opts *PostHandlerOptions) (http.Handler, error) {
@@ -130,6 +142,12 @@ func PostHandler(opts *PostHandlerOptions) (http.Handler, error) {
 		post, err := opts.PostsDatabase.GetPostWithId(ctx, post_id)
 
 		if err != nil {
+
+			if err == activitypub.ErrNotFound {
+				servePostNotFound(ctx, rsp, req, opts, acct, post_id)
+				return
+			}
+
 			logger.Error("Failed to retrieve post", "error", err)
 			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
 			return
@@ -141,6 +159,22 @@ func PostHandler(opts *PostHandlerOptions) (http.Handler, error) {
 			return
 		}
 
+		attachments := make([]*ap.Attachment, 0)
+
+		if opts.MediaDatabase != nil {
+
+			post_media, err := media.GetMediaForPost(ctx, opts.MediaDatabase, post.Id)
+
+			if err != nil {
+				logger.Error("Failed to retrieve media for post", "error", err)
+			} else {
+
+				for _, m := range post_media {
+					attachments = append(attachments, media.AttachmentFromMedia(ctx, opts.URIs, acct, m))
+				}
+			}
+		}
+
 		// AM I JSON?
 
 		if IsActivityStreamRequest(req, "Accept") {
@@ -162,6 +196,10 @@ func PostHandler(opts *PostHandlerOptions) (http.Handler, error) {
 				URL:       post_url.String(),
 			}
 
+			if post.IsEdited() {
+				note.Updated = time.Unix(post.LastModified, 0).Format(http.TimeFormat)
+			}
+
 			tags := make([]*ap.Tag, 0)
 
 			tags_cb := func(ctx context.Context, pt *activitypub.PostTag) error {
@@ -184,6 +222,10 @@ func PostHandler(opts *PostHandlerOptions) (http.Handler, error) {
 				note.Tags = tags
 			}
 
+			if len(attachments) > 0 {
+				note.Attachments = attachments
+			}
+
 			// to do: mentions (tags)
 
 			// what to do about cc...
@@ -214,12 +256,25 @@ func PostHandler(opts *PostHandlerOptions) (http.Handler, error) {
 		// Render template
 
 		vars := PostHandlerVars{
-			Account:    acct,
-			Post:       post,
-			PostBody:   template.HTML(post.Body),
-			IconURL:    icon_url.String(),
-			AccountURL: account_url.String(),
-			PostURL:    post_url.String(),
+			Account:     acct,
+			Post:        post,
+			PostBody:    template.HTML(post.Body),
+			IconURL:     icon_url.String(),
+			AccountURL:  account_url.String(),
+			PostURL:     post_url.String(),
+			Attachments: attachments,
+		}
+
+		if opts.LikesDatabase != nil {
+
+			likes, reactions, err := stats.CountLikesForPost(ctx, opts.LikesDatabase, post.Id)
+
+			if err != nil {
+				logger.Error("Failed to count likes for post", "error", err)
+			} else {
+				vars.Likes = likes
+				vars.Reactions = reactions
+			}
 		}
 
 		rsp.Header().Set("Content-Type", "text/html")
@@ -236,3 +291,55 @@ func PostHandler(opts *PostHandlerOptions) (http.Handler, error) {
 
 	return http.HandlerFunc(fn), nil
 }
+
+// servePostNotFound returns a "410 Gone" response if there is a tombstone for 'post_id' belonging to 'acct' and a
+// "404 Not found" response otherwise. ActivityStreams requests for deleted posts receive a `Tombstone` object.
+func servePostNotFound(ctx context.Context, rsp http.ResponseWriter, req *http.Request, opts *PostHandlerOptions, acct *activitypub.Account, post_id int64) {
+
+	logger := slog.LoggerWithRequest(req, nil)
+	logger = logger.With("post id", post_id)
+	logger = logger.With("account id", acct.Id)
+
+	if opts.TombstonesDatabase == nil {
+		http.Error(rsp, "Not found", http.StatusNotFound)
+		return
+	}
+
+	tombstone, err := opts.TombstonesDatabase.GetTombstoneWithPostId(ctx, post_id)
+
+	if err != nil {
+
+		if err == activitypub.ErrNotFound {
+			http.Error(rsp, "Not found", http.StatusNotFound)
+			return
+		}
+
+		logger.Error("Failed to retrieve tombstone for post", "error", err)
+		http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if tombstone.AccountId != acct.Id {
+		logger.Error("Tombstone is owned by different account", "tombstone account id", tombstone.AccountId)
+		http.Error(rsp, "Not found", http.StatusNotFound)
+		return
+	}
+
+	if !IsActivityStreamRequest(req, "Accept") {
+		http.Error(rsp, "Gone", http.StatusGone)
+		return
+	}
+
+	t := posts.TombstoneObject(ctx, opts.URIs, acct, tombstone)
+	t.Context = ap.ACTIVITYSTREAMS_CONTEXT
+
+	rsp.Header().Set("Content-type", "application/json")
+	rsp.WriteHeader(http.StatusGone)
+
+	enc := json.NewEncoder(rsp)
+	err = enc.Encode(t)
+
+	if err != nil {
+		logger.Error("Failed to encode tombstone response for resource", "error", err)
+	}
+}
@@ -0,0 +1,95 @@
+package www
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sfomuseum/go-activitypub"
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/templates/html"
+	"github.com/sfomuseum/go-activitypub/uris"
+)
+
+func TestPostHandlerTombstone(t *testing.T) {
+
+	ctx := context.Background()
+
+	uris_table := uris.DefaultURIs()
+	uris_table.Hostname = "example.com"
+
+	acct := &activitypub.Account{
+		Id:   1234,
+		Name: "alice",
+	}
+
+	templates, err := html.LoadTemplates(ctx)
+
+	if err != nil {
+		t.Fatalf("Failed to load templates, %v", err)
+	}
+
+	opts := &PostHandlerOptions{
+		AccountsDatabase: &testAccountsDatabase{account: acct},
+		PostsDatabase: &testPostsDatabase{
+			posts: make(map[int64]*activitypub.Post),
+		},
+		TombstonesDatabase: &testTombstonesDatabase{
+			tombstones: map[int64]*activitypub.Tombstone{
+				1: &activitypub.Tombstone{Id: 1, PostId: 99, AccountId: acct.Id, FormerType: "Note", Published: 1700000000, Created: 1700000100},
+			},
+		},
+		URIs:      uris_table,
+		Templates: templates,
+	}
+
+	h, err := PostHandler(opts)
+
+	if err != nil {
+		t.Fatalf("Failed to create post handler, %v", err)
+	}
+
+	tests := []struct {
+		Path   string
+		Accept string
+		Status int
+	}{
+		{"/ap/alice/posts/99", ap.ACTIVITYSTREAMS_ACCEPT_HEADER, http.StatusGone},
+		{"/ap/alice/posts/99", "text/html", http.StatusGone},
+		{"/ap/alice/posts/100", ap.ACTIVITYSTREAMS_ACCEPT_HEADER, http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+
+		req := httptest.NewRequest(http.MethodGet, test.Path, nil)
+		req.SetPathValue("resource", "alice")
+		req.Header.Set("Accept", test.Accept)
+
+		rsp := httptest.NewRecorder()
+		h.ServeHTTP(rsp, req)
+
+		if rsp.Code != test.Status {
+			t.Fatalf("Unexpected status for %s (%s), expected %d but got %d", test.Path, test.Accept, test.Status, rsp.Code)
+		}
+
+		if test.Status != http.StatusGone || test.Accept != ap.ACTIVITYSTREAMS_ACCEPT_HEADER {
+			continue
+		}
+
+		var tombstone *ap.Tombstone
+
+		err := json.Unmarshal(rsp.Body.Bytes(), &tombstone)
+
+		if err != nil {
+			t.Fatalf("Failed to unmarshal tombstone, %v", err)
+		}
+
+		post_url := acct.PostURL(ctx, uris_table, &activitypub.Post{Id: 99})
+
+		if tombstone.Type != "Tombstone" || tombstone.FormerType != "Note" || tombstone.Id != post_url.String() {
+			t.Fatalf("Unexpected tombstone, %v", tombstone)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package www
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aaronland/go-http/v3/slog"
+	"github.com/sfomuseum/go-activitypub/ratelimit"
+)
+
+type RateLimitHandlerOptions struct {
+	// RateLimiter is the `ratelimit.RateLimiter` instance used to store token buckets. If nil then requests are not limited.
+	RateLimiter ratelimit.RateLimiter
+	// HostLimit is the limit applied to all the requests from a given remote address.
+	HostLimit *ratelimit.Limit
+}
+
+// RateLimitHandler returns a `http.Handler` instance that applies per-host rate limits to requests before invoking 'next'.
+// The signatures of the requests handled by 'next' (if they are signed at all) are not verified so the host is derived from
+// the request's remote address rather than the key ID of an HTTP signature which anyone can claim. Requests which exceed
+// the limit are refused with a 429 Too Many Requests response and a "Retry-After" header.
+func RateLimitHandler(opts *RateLimitHandlerOptions, next http.Handler) http.Handler {
+
+	if opts.RateLimiter == nil {
+		return next
+	}
+
+	fn := func(rsp http.ResponseWriter, req *http.Request) {
+
+		ctx := req.Context()
+		logger := slog.LoggerWithRequest(req, nil)
+
+		host := rateLimitRemoteAddress(req)
+
+		allowed, retry_after, err := checkRateLimits(ctx, opts.RateLimiter, opts.HostLimit, nil, host, "")
+
+		if err != nil {
+			logger.Error("Failed to check rate limits", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		if !allowed {
+			logger.Warn("Rate limit exceeded", "host", host, "retry after", retry_after)
+			writeTooManyRequests(rsp, retry_after)
+			return
+		}
+
+		next.ServeHTTP(rsp, req)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// checkInboxAddressRateLimit applies the per-host rate limit defined in 'opts' to the remote address of 'req'. This is
+// applied before the request's signature has been verified so nothing in the request itself is trusted. It returns a
+// boolean value indicating whether the request is allowed and, if not, the amount of time the requestor should wait
+// before trying again.
+func checkInboxAddressRateLimit(ctx context.Context, opts *InboxPostHandlerOptions, req *http.Request) (bool, time.Duration, string, error) {
+
+	if opts.RateLimiter == nil {
+		return true, 0, "", nil
+	}
+
+	host := rateLimitRemoteAddress(req)
+
+	allowed, retry_after, err := checkRateLimits(ctx, opts.RateLimiter, opts.HostRateLimit, nil, host, "")
+	return allowed, retry_after, host, err
+}
+
+// checkInboxRateLimits applies the per-host and per-actor rate limits defined in 'opts' to 'requestor'. It should only be
+// called after the request has been verified (see `verifyInboxRequest`) since the host and actor are derived from the
+// actor that signed the request. It returns a boolean value indicating whether the request is allowed and, if not, the
+// amount of time the requestor should wait before trying again.
+func checkInboxRateLimits(ctx context.Context, opts *InboxPostHandlerOptions, requestor *inboxRequestor) (bool, time.Duration, string, string, error) {
+
+	if opts.RateLimiter == nil || requestor.Actor == nil {
+		return true, 0, "", "", nil
+	}
+
+	actor := requestor.Actor.Id
+
+	actor_u, err := url.Parse(actor)
+
+	if err != nil {
+		return false, 0, "", "", fmt.Errorf("Failed to parse actor URI, %w", err)
+	}
+
+	host := strings.ToLower(actor_u.Host)
+
+	allowed, retry_after, err := checkRateLimits(ctx, opts.RateLimiter, opts.HostRateLimit, opts.ActorRateLimit, host, actor)
+	return allowed, retry_after, host, actor, err
+}
+
+// checkRateLimits consumes a token from the bucket for 'host' and, if not empty, the bucket for 'actor'.
+func checkRateLimits(ctx context.Context, rl ratelimit.RateLimiter, host_limit *ratelimit.Limit, actor_limit *ratelimit.Limit, host string, actor string) (bool, time.Duration, error) {
+
+	if host != "" {
+
+		allowed, retry_after, err := ratelimit.Allow(ctx, rl, "host:"+host, host_limit)
+
+		if err != nil {
+			return false, 0, fmt.Errorf("Failed to check rate limit for host %s, %w", host, err)
+		}
+
+		if !allowed {
+			return false, retry_after, nil
+		}
+	}
+
+	if actor != "" {
+
+		allowed, retry_after, err := ratelimit.Allow(ctx, rl, "actor:"+actor, actor_limit)
+
+		if err != nil {
+			return false, 0, fmt.Errorf("Failed to check rate limit for actor %s, %w", actor, err)
+		}
+
+		if !allowed {
+			return false, retry_after, nil
+		}
+	}
+
+	return true, 0, nil
+}
+
+// rateLimitRemoteAddress returns the remote address (without a port) of 'req' used to key rate limits for requests
+// that have not been verified.
+func rateLimitRemoteAddress(req *http.Request) string {
+
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+
+	if err != nil {
+		host = req.RemoteAddr
+	}
+
+	return host
+}
+
+// writeTooManyRequests writes a 429 Too Many Requests response to 'rsp' with a "Retry-After" header derived from 'retry_after'.
+func writeTooManyRequests(rsp http.ResponseWriter, retry_after time.Duration) {
+
+	seconds := int(math.Ceil(retry_after.Seconds()))
+
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	rsp.Header().Set("Retry-After", strconv.Itoa(seconds))
+	http.Error(rsp, "Too many requests", http.StatusTooManyRequests)
+}
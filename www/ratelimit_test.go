@@ -0,0 +1,142 @@
+package www
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sfomuseum/go-activitypub/ap"
+	"github.com/sfomuseum/go-activitypub/ratelimit"
+)
+
+func TestRateLimitHandler(t *testing.T) {
+
+	ctx := context.Background()
+
+	rl, err := ratelimit.NewRateLimiter(ctx, "memory://")
+
+	if err != nil {
+		t.Fatalf("Failed to create rate limiter, %v", err)
+	}
+
+	defer rl.Close(ctx)
+
+	ok_handler := http.HandlerFunc(func(rsp http.ResponseWriter, req *http.Request) {
+		rsp.WriteHeader(http.StatusOK)
+	})
+
+	opts := &RateLimitHandlerOptions{
+		RateLimiter: rl,
+		HostLimit:   ratelimit.PerMinute(6, 2),
+	}
+
+	h := RateLimitHandler(opts, ok_handler)
+
+	// Unverified signatures are ignored so claiming to be someone else doesn't help
+
+	alice := `keyId="https://example.com/users/alice#main-key",algorithm="rsa-sha256",headers="(request-target) host date",signature="c2ln"`
+	bob := `keyId="https://example.com/users/bob#main-key",algorithm="rsa-sha256",headers="(request-target) host date",signature="c2ln"`
+
+	tests := []struct {
+		Description string
+		RemoteAddr  string
+		Signature   string
+		Status      int
+	}{
+		{"first request", "192.0.2.1:1234", alice, http.StatusOK},
+		{"second request", "192.0.2.1:1234", "", http.StatusOK},
+		{"address limit exhausted", "192.0.2.1:1234", bob, http.StatusTooManyRequests},
+		{"other address", "192.0.2.2:1234", alice, http.StatusOK},
+	}
+
+	for _, test := range tests {
+
+		req := httptest.NewRequest(http.MethodGet, "/ap/doug", nil)
+		req.RemoteAddr = test.RemoteAddr
+
+		if test.Signature != "" {
+			req.Header.Set("Signature", test.Signature)
+		}
+
+		rsp := httptest.NewRecorder()
+		h.ServeHTTP(rsp, req)
+
+		if rsp.Code != test.Status {
+			t.Fatalf("Unexpected status for %s, expected %d but got %d", test.Description, test.Status, rsp.Code)
+		}
+
+		if rsp.Code == http.StatusTooManyRequests && rsp.Header().Get("Retry-After") == "" {
+			t.Fatalf("Missing Retry-After header for %s", test.Description)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/webfinger", nil)
+
+	// No rate limiter
+
+	h = RateLimitHandler(&RateLimitHandlerOptions{}, ok_handler)
+
+	for i := 0; i < 10; i++ {
+
+		rsp := httptest.NewRecorder()
+		h.ServeHTTP(rsp, req)
+
+		if rsp.Code != http.StatusOK {
+			t.Fatalf("Unexpected status when rate limiting is disabled, %d", rsp.Code)
+		}
+	}
+}
+
+func TestCheckInboxRateLimits(t *testing.T) {
+
+	ctx := context.Background()
+
+	rl, err := ratelimit.NewRateLimiter(ctx, "memory://")
+
+	if err != nil {
+		t.Fatalf("Failed to create rate limiter, %v", err)
+	}
+
+	defer rl.Close(ctx)
+
+	opts := &InboxPostHandlerOptions{
+		RateLimiter:    rl,
+		HostRateLimit:  ratelimit.PerMinute(60, 5),
+		ActorRateLimit: ratelimit.PerMinute(6, 2),
+	}
+
+	alice := &inboxRequestor{
+		Actor: &ap.Actor{Id: "https://example.com/users/alice"},
+	}
+
+	bob := &inboxRequestor{
+		Actor: &ap.Actor{Id: "https://example.com/users/bob"},
+	}
+
+	tests := []struct {
+		Description string
+		Requestor   *inboxRequestor
+		Allowed     bool
+	}{
+		{"alice first request", alice, true},
+		{"alice second request", alice, true},
+		{"alice exceeds actor limit", alice, false},
+		{"bob first request", bob, true},
+		{"bob second request", bob, true},
+		{"host limit exhausted", bob, false},
+	}
+
+	for _, test := range tests {
+
+		allowed, _, _, _, err := checkInboxRateLimits(ctx, opts, test.Requestor)
+
+		if err != nil {
+			t.Fatalf("Failed to check rate limits for %s, %v", test.Description, err)
+		}
+
+		if allowed != test.Allowed {
+			t.Fatalf("Unexpected result for %s, expected %t", test.Description, test.Allowed)
+		}
+	}
+}